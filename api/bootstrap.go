@@ -1,14 +1,30 @@
 package api
 
 import (
+	"context"
+	"fmt"
+
 	"github.com/go-playground/validator/v10"
 
 	"github.com/fmiskovic/cash-me-if-you-can/config"
 	"github.com/fmiskovic/cash-me-if-you-can/database"
+	"github.com/fmiskovic/cash-me-if-you-can/database/memory"
+	"github.com/fmiskovic/cash-me-if-you-can/database/sqlite"
+	"github.com/fmiskovic/cash-me-if-you-can/pkg/clock"
 
 	// core services
 	"github.com/fmiskovic/cash-me-if-you-can/internal"
 	"github.com/fmiskovic/cash-me-if-you-can/internal/account"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/balance"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/batch"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/event"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/export"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/fee"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/imports"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/ledger"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/reconciliation"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/schedule"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/statement"
 	"github.com/fmiskovic/cash-me-if-you-can/internal/transaction"
 
 	// repositories
@@ -21,36 +37,133 @@ import (
 type repositories struct {
 	account     account.Repository
 	transaction transaction.Repository
+	batch       batch.Repository
+	schedule    schedule.Repository
+	fee         fee.Repository
+	statement   statement.Repository
+	balance     balance.Repository
+	ledger      ledger.Repository
+
+	reconciliation reconciliation.Repository
+	imports        imports.Repository
+	export         export.Repository
+	event          event.Repository
 }
 
-func (r *Router) initRepositories(cfg config.DatabaseConfig) repositories {
-	db := database.New(cfg)
+func (r *Router) initRepositories(ctx context.Context, storage config.StorageConfig, cfg config.DatabaseConfig) (repositories, error) {
+	// the memory and sqlite drivers provide accounts and transactions only, the other repositories are left unset
+	switch r.storageDriver {
+	case config.MemoryDriver:
+		store := memory.NewStore(clock.System())
+		return repositories{
+			account:     memory.NewAccountRepository(store),
+			transaction: memory.NewTransactionRepository(store),
+		}, nil
+	case config.SQLiteDriver:
+		db, err := sqlite.Open(storage.Path)
+		if err != nil {
+			return repositories{}, fmt.Errorf("failed to open sqlite database: %w", err)
+		}
+		r.storage = db
+		return repositories{
+			account:     sqlite.NewAccountRepository(db),
+			transaction: sqlite.NewTransactionRepository(db),
+		}, nil
+	}
+
+	db, err := database.New(ctx, cfg)
+	if err != nil {
+		return repositories{}, err
+	}
+	r.storage = db
+	r.replicated = db.Replicated()
 	return repositories{
 		account:     repos.NewAccountRepository(db),
 		transaction: repos.NewTransactionRepository(db),
-	}
+		batch:       repos.NewBatchRepository(db),
+		schedule:    repos.NewScheduleRepository(db),
+		fee:         repos.NewFeeRepository(db),
+		statement:   repos.NewStatementRepository(db),
+		balance:     repos.NewBalanceRepository(db),
+		ledger:      repos.NewLedgerRepository(db),
+
+		reconciliation: repos.NewReconciliationRepository(db),
+		imports:        repos.NewImportRepository(db),
+		export:         repos.NewExportRepository(db),
+		event:          repos.NewEventRepository(db),
+	}, nil
 }
 
 type services struct {
 	account     account.Service
 	transaction transaction.Service
+	batch       batch.Service
+	schedule    schedule.Service
+	fee         fee.Service
+	statement   statement.Service
+	balance     balance.Service
+	ledger      ledger.Service
+
+	reconciliation reconciliation.Service
+	imports        imports.Service
+	export         export.Service
+	event          event.Service
 }
 
 func (r *Router) initServices(repo repositories) services {
+	transactionService := transaction.NewService(repo.transaction)
 	return services{
 		account:     account.NewService(repo.account),
-		transaction: transaction.NewService(repo.transaction),
+		transaction: transactionService,
+		batch:       batch.NewService(repo.batch),
+		schedule:    schedule.NewService(repo.schedule, transactionService),
+		fee:         fee.NewService(repo.fee),
+		statement:   statement.NewService(repo.statement, clock.System()),
+		balance:     balance.NewService(repo.balance, clock.System()),
+		ledger:      ledger.NewService(repo.ledger, clock.System()),
+
+		reconciliation: reconciliation.NewService(repo.reconciliation, clock.System()),
+		imports:        imports.NewService(repo.imports),
+		export:         export.NewService(repo.export, clock.System()),
+		event:          event.NewService(repo.event, clock.System()),
 	}
 }
 
 type handlers struct {
 	accountCreate  Handler[account.CreateRequest, *account.Details]
 	accountDetails Handler[string, *account.Details]
-	accountList    Handler[internal.PageRequest, internal.Page[account.Details]]
+	accountList    Handler[account.ListRequest, internal.Page[account.Details]]
+
+	accountStatement Handler[statement.Request, *statement.Statement]
+	accountBalance   Handler[balance.AsOfRequest, *balance.Details]
 
 	transactionCreate   Handler[transaction.CreateRequest, *transaction.Details]
 	transactionTransfer Handler[transaction.TransferRequest, *transaction.TransferResponse]
-	transactionList     Handler[string, []transaction.Details]
+	transactionList     Handler[transaction.ListRequest, []transaction.Details]
+	transactionDetails  Handler[string, *transaction.Details]
+
+	batchTransfer Handler[batch.TransferRequest, *batch.Details]
+	batchDetails  Handler[string, *batch.Details]
+
+	scheduleCreate  Handler[schedule.CreateRequest, *schedule.Details]
+	scheduleDetails Handler[string, *schedule.Details]
+	scheduleCancel  Handler[string, *schedule.Details]
+	scheduleList    Handler[schedule.ListRequest, []schedule.Details]
+
+	feeQuote Handler[fee.QuoteRequest, *fee.Quote]
+
+	ledgerVerify Handler[ledger.VerifyRequest, *ledger.ReportDetails]
+
+	reconciliationImport  Handler[reconciliation.ImportRequest, *reconciliation.ReportDetails]
+	reconciliationDetails Handler[string, *reconciliation.ReportDetails]
+	reconciliationMatch   Handler[reconciliation.MatchRequest, *reconciliation.LineDetails]
+	reconciliationUnmatch Handler[reconciliation.LineRequest, *reconciliation.LineDetails]
+
+	imports            Handler[imports.ImportRequest, *imports.ReportDetails]
+	exportTransactions Handler[export.Request, *export.Export]
+
+	accountEvents Handler[event.StreamRequest, *event.Stream]
+	events        Handler[event.StreamRequest, *event.Stream]
 }
 
 func (r *Router) initHandlers(s services) handlers {
@@ -77,6 +190,20 @@ func (r *Router) initHandlers(s services) handlers {
 		vld,
 	)
 
+	accountStatementHandler := NewHandler(
+		&mappers.AccountStatementRequestMapper{},
+		&mappers.AccountStatementResponseMapper{},
+		s.statement.Generate,
+		vld,
+	)
+
+	accountBalanceHandler := NewHandler(
+		&mappers.AccountBalanceRequestMapper{},
+		&mappers.AccountBalanceResponseMapper{},
+		s.balance.AsOf,
+		vld,
+	)
+
 	transactionCreateHandler := NewHandler(
 		&mappers.TransactionCreateRequestMapper{},
 		&mappers.TransactionCreateResponseMapper{},
@@ -95,15 +222,156 @@ func (r *Router) initHandlers(s services) handlers {
 		&mappers.TransactionListRequestMapper{},
 		&mappers.TransactionListResponseMapper{},
 		s.transaction.GetByAccountId,
+		vld,
+	)
+
+	transactionDetailsHandler := NewHandler(
+		&mappers.TransactionGetRequestMapper{},
+		&mappers.TransactionGetResponseMapper{},
+		s.transaction.GetById,
+		nil, //validation not needed for id as a string
+	)
+
+	batchTransferHandler := NewHandler(
+		&mappers.BatchTransferRequestMapper{},
+		&mappers.BatchTransferResponseMapper{},
+		s.batch.Transfer,
+		vld,
+	)
+
+	batchDetailsHandler := NewHandler(
+		&mappers.BatchGetRequestMapper{},
+		&mappers.BatchGetResponseMapper{},
+		s.batch.Get,
+		nil, //validation not needed for id as a string
+	)
+
+	scheduleCreateHandler := NewHandler(
+		&mappers.ScheduleCreateRequestMapper{},
+		&mappers.ScheduleCreateResponseMapper{},
+		s.schedule.Create,
+		vld,
+	)
+
+	scheduleDetailsHandler := NewHandler(
+		&mappers.ScheduleGetRequestMapper{},
+		&mappers.ScheduleGetResponseMapper{},
+		s.schedule.Get,
 		nil, //validation not needed for id as a string
 	)
 
+	scheduleCancelHandler := NewHandler(
+		&mappers.ScheduleGetRequestMapper{},
+		&mappers.ScheduleGetResponseMapper{},
+		s.schedule.Cancel,
+		nil, //validation not needed for id as a string
+	)
+
+	scheduleListHandler := NewHandler(
+		&mappers.ScheduleListRequestMapper{},
+		&mappers.ScheduleListResponseMapper{},
+		s.schedule.List,
+		vld,
+	)
+
+	feeQuoteHandler := NewHandler(
+		&mappers.FeeQuoteRequestMapper{},
+		&mappers.FeeQuoteResponseMapper{},
+		s.fee.Quote,
+		vld,
+	)
+
+	ledgerVerifyHandler := NewHandler(
+		&mappers.LedgerVerifyRequestMapper{},
+		&mappers.LedgerVerifyResponseMapper{},
+		s.ledger.Verify,
+		nil, //validation not needed for an empty request
+	)
+
+	reconciliationImportHandler := NewHandler(
+		&mappers.ReconciliationImportRequestMapper{},
+		&mappers.ReconciliationImportResponseMapper{},
+		s.reconciliation.Import,
+		vld,
+	)
+
+	reconciliationDetailsHandler := NewHandler(
+		&mappers.ReconciliationGetRequestMapper{},
+		&mappers.ReconciliationGetResponseMapper{},
+		s.reconciliation.Get,
+		nil, //validation not needed for id as a string
+	)
+
+	reconciliationMatchHandler := NewHandler(
+		&mappers.ReconciliationMatchRequestMapper{},
+		&mappers.ReconciliationLineResponseMapper{},
+		s.reconciliation.Match,
+		vld,
+	)
+
+	reconciliationUnmatchHandler := NewHandler(
+		&mappers.ReconciliationUnmatchRequestMapper{},
+		&mappers.ReconciliationLineResponseMapper{},
+		s.reconciliation.Unmatch,
+		vld,
+	)
+
+	importsHandler := NewHandler(
+		&mappers.ImportRequestMapper{},
+		&mappers.ImportResponseMapper{},
+		s.imports.Import,
+		vld,
+	)
+
+	exportTransactionsHandler := NewHandler(
+		&mappers.ExportTransactionsRequestMapper{},
+		&mappers.ExportTransactionsResponseMapper{},
+		s.export.Export,
+		vld,
+	)
+
+	accountEventsHandler := NewHandler(
+		&mappers.AccountEventsRequestMapper{},
+		&mappers.EventStreamResponseMapper{},
+		s.event.Subscribe,
+		vld,
+	)
+
+	eventsHandler := NewHandler(
+		&mappers.EventsRequestMapper{},
+		&mappers.EventStreamResponseMapper{},
+		s.event.Subscribe,
+		vld,
+	)
+
 	return handlers{
 		accountCreate:       accountCreateHandler,
 		accountDetails:      accountDetailsHandler,
 		accountList:         accountListHandler,
+		accountStatement:    accountStatementHandler,
+		accountBalance:      accountBalanceHandler,
 		transactionCreate:   transactionCreateHandler,
 		transactionTransfer: transactionTransferHandler,
 		transactionList:     transactionListHandler,
+		transactionDetails:  transactionDetailsHandler,
+		batchTransfer:       batchTransferHandler,
+		batchDetails:        batchDetailsHandler,
+		scheduleCreate:      scheduleCreateHandler,
+		scheduleDetails:     scheduleDetailsHandler,
+		scheduleCancel:      scheduleCancelHandler,
+		scheduleList:        scheduleListHandler,
+		feeQuote:            feeQuoteHandler,
+		ledgerVerify:        ledgerVerifyHandler,
+
+		reconciliationImport:  reconciliationImportHandler,
+		reconciliationDetails: reconciliationDetailsHandler,
+		reconciliationMatch:   reconciliationMatchHandler,
+		reconciliationUnmatch: reconciliationUnmatchHandler,
+
+		imports:            importsHandler,
+		exportTransactions: exportTransactionsHandler,
+
+		accountEvents: accountEventsHandler,
+		events:        eventsHandler,
 	}
 }
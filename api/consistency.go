@@ -0,0 +1,50 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/fmiskovic/cash-me-if-you-can/database"
+)
+
+// ConsistencyTokenHeader carries the log position of the writes of a request.
+// Responses of requests which wrote set it, requests sending it back read those writes
+// even if they are served by a read replica.
+const ConsistencyTokenHeader = "X-Consistency-Token"
+
+// consistency runs every request in a read-your-writes session continuing the session of the request token.
+func consistency(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := database.WithSession(r.Context(), r.Header.Get(ConsistencyTokenHeader))
+		next.ServeHTTP(&tokenWriter{ResponseWriter: w, ctx: ctx}, r.WithContext(ctx))
+	})
+}
+
+// tokenWriter sets the consistency token header of the session before the response is written.
+type tokenWriter struct {
+	http.ResponseWriter
+	ctx         context.Context
+	wroteHeader bool
+}
+
+func (w *tokenWriter) WriteHeader(code int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		if token := database.SessionToken(w.ctx); token != "" {
+			w.Header().Set(ConsistencyTokenHeader, token)
+		}
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *tokenWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController, so streaming responses can flush.
+func (w *tokenWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -37,6 +37,8 @@ func newServiceError(err error) Error {
 			code = http.StatusForbidden
 		case errorx.ErrNotFound:
 			code = http.StatusNotFound
+		case errorx.ErrConflict:
+			code = http.StatusConflict
 		default:
 			code = http.StatusInternalServerError
 		}
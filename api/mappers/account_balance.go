@@ -0,0 +1,34 @@
+package mappers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/fmiskovic/cash-me-if-you-can/internal/balance"
+)
+
+type AccountBalanceRequestMapper struct{}
+
+func (m *AccountBalanceRequestMapper) Map(r *http.Request) (balance.AsOfRequest, error) {
+	id := r.PathValue("id")
+	if id == "" {
+		return balance.AsOfRequest{}, errors.New("path is missing id parameter")
+	}
+
+	asOf, err := parseOptionalDateTime(r.URL.Query().Get("as_of"))
+	if err != nil {
+		return balance.AsOfRequest{}, fmt.Errorf("invalid as_of: %w", err)
+	}
+
+	return balance.AsOfRequest{AccountID: id, AsOf: asOf}, nil
+}
+
+type AccountBalanceResponseMapper struct{}
+
+func (m *AccountBalanceResponseMapper) Map(w http.ResponseWriter, res *balance.Details) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(res)
+}
@@ -2,8 +2,10 @@ package mappers
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/fmiskovic/cash-me-if-you-can/internal"
 	"github.com/fmiskovic/cash-me-if-you-can/internal/account"
@@ -11,22 +13,59 @@ import (
 
 type AccountListRequestMapper struct{}
 
-func (m *AccountListRequestMapper) Map(r *http.Request) (internal.PageRequest, error) {
+func (m *AccountListRequestMapper) Map(r *http.Request) (account.ListRequest, error) {
+	query := r.URL.Query()
+
 	offset := 0
-	offsetStr := r.URL.Query().Get("offset")
+	offsetStr := query.Get("offset")
 	if offsetStr != "" {
 		offset, _ = strconv.Atoi(offsetStr)
 	}
 
 	limit := 10
-	limitStr := r.URL.Query().Get("limit")
+	limitStr := query.Get("limit")
 	if limitStr != "" {
 		limit, _ = strconv.Atoi(limitStr)
 	}
 
-	return internal.PageRequest{
-		Limit:  limit,
-		Offset: offset,
+	minBalance, err := parseOptionalFloat(query.Get("min_balance"))
+	if err != nil {
+		return account.ListRequest{}, fmt.Errorf("invalid min_balance: %w", err)
+	}
+
+	maxBalance, err := parseOptionalFloat(query.Get("max_balance"))
+	if err != nil {
+		return account.ListRequest{}, fmt.Errorf("invalid max_balance: %w", err)
+	}
+
+	timeFilter, err := parseTimeFilter(query)
+	if err != nil {
+		return account.ListRequest{}, err
+	}
+
+	sortBy := account.SortByCreatedAt
+	if v := query.Get("sort_by"); v != "" {
+		sortBy = account.SortField(v)
+	}
+
+	sortOrder := account.Asc
+	if v := query.Get("order"); v != "" {
+		sortOrder = account.SortOrder(v)
+	}
+
+	return account.ListRequest{
+		PageRequest: internal.PageRequest{
+			Limit:  limit,
+			Offset: offset,
+		},
+		TimeFilter: timeFilter,
+		Owner:      query.Get("owner"),
+		Status:     account.Status(query.Get("status")),
+		Currency:   strings.ToUpper(query.Get("currency")),
+		MinBalance: minBalance,
+		MaxBalance: maxBalance,
+		SortBy:     sortBy,
+		SortOrder:  sortOrder,
 	}, nil
 }
 
@@ -0,0 +1,67 @@
+package mappers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/softika/slogging"
+
+	"github.com/fmiskovic/cash-me-if-you-can/internal/statement"
+)
+
+type AccountStatementRequestMapper struct{}
+
+func (m *AccountStatementRequestMapper) Map(r *http.Request) (statement.Request, error) {
+	id := r.PathValue("id")
+	if id == "" {
+		return statement.Request{}, errors.New("path is missing id parameter")
+	}
+
+	query := r.URL.Query()
+
+	from, err := parseOptionalDateTime(query.Get("from"))
+	if err != nil {
+		return statement.Request{}, fmt.Errorf("invalid from: %w", err)
+	}
+	if from == nil {
+		return statement.Request{}, errors.New("query is missing from parameter")
+	}
+
+	to, err := parseOptionalDateTime(query.Get("to"))
+	if err != nil {
+		return statement.Request{}, fmt.Errorf("invalid to: %w", err)
+	}
+
+	return statement.Request{
+		AccountID: id,
+		From:      *from,
+		To:        to,
+		Format:    statement.Format(query.Get("format")),
+	}, nil
+}
+
+type AccountStatementResponseMapper struct{}
+
+// Map streams the statement to the client. The status is sent before the entries are read,
+// so a failure while streaming aborts the response instead of turning it into an error response.
+func (m *AccountStatementResponseMapper) Map(w http.ResponseWriter, res *statement.Statement) error {
+	filename := fmt.Sprintf("statement-%s-%s-%s.%s",
+		res.AccountID,
+		res.From.UTC().Format(time.DateOnly),
+		res.To.UTC().Format(time.DateOnly),
+		res.Format.Extension(),
+	)
+
+	w.Header().Set("Content-Type", res.Format.ContentType())
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.WriteHeader(http.StatusOK)
+
+	if err := statement.Write(w, res); err != nil {
+		logger := slogging.Slogger()
+		logger.Error("failed to write statement", "account_id", res.AccountID, "error", err)
+		panic(http.ErrAbortHandler)
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+package mappers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/fmiskovic/cash-me-if-you-can/internal/batch"
+)
+
+type BatchGetRequestMapper struct{}
+
+func (m *BatchGetRequestMapper) Map(r *http.Request) (string, error) {
+	id := r.PathValue("id")
+	if id == "" {
+		return "", errors.New("path is missing id parameter")
+	}
+	return id, nil
+}
+
+type BatchGetResponseMapper struct{}
+
+func (m *BatchGetResponseMapper) Map(w http.ResponseWriter, res *batch.Details) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(res)
+}
@@ -0,0 +1,24 @@
+package mappers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/fmiskovic/cash-me-if-you-can/internal/batch"
+)
+
+type BatchTransferRequestMapper struct{}
+
+func (m *BatchTransferRequestMapper) Map(r *http.Request) (batch.TransferRequest, error) {
+	var req batch.TransferRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	return req, err
+}
+
+type BatchTransferResponseMapper struct{}
+
+func (m *BatchTransferResponseMapper) Map(w http.ResponseWriter, res *batch.Details) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	return json.NewEncoder(w).Encode(res)
+}
@@ -0,0 +1,113 @@
+package mappers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/softika/slogging"
+
+	"github.com/fmiskovic/cash-me-if-you-can/internal/event"
+)
+
+// eventStreamHeartbeat is the interval of the comments sent to keep idle streams open through proxies.
+const eventStreamHeartbeat = 15 * time.Second
+
+type AccountEventsRequestMapper struct{}
+
+func (m *AccountEventsRequestMapper) Map(r *http.Request) (event.StreamRequest, error) {
+	id := r.PathValue("id")
+	if id == "" {
+		return event.StreamRequest{}, errors.New("path is missing id parameter")
+	}
+
+	lastEventID, err := parseLastEventID(r)
+	if err != nil {
+		return event.StreamRequest{}, err
+	}
+
+	return event.StreamRequest{
+		AccountID:   id,
+		LastEventID: lastEventID,
+	}, nil
+}
+
+type EventsRequestMapper struct{}
+
+func (m *EventsRequestMapper) Map(r *http.Request) (event.StreamRequest, error) {
+	lastEventID, err := parseLastEventID(r)
+	if err != nil {
+		return event.StreamRequest{}, err
+	}
+
+	return event.StreamRequest{
+		LastEventID: lastEventID,
+	}, nil
+}
+
+// parseLastEventID reads the id a reconnecting client has seen last from the Last-Event-ID header,
+// or from the last_event_id query parameter of clients that cannot set headers.
+func parseLastEventID(r *http.Request) (*int64, error) {
+	s := r.Header.Get("Last-Event-ID")
+	if s == "" {
+		s = r.URL.Query().Get("last_event_id")
+	}
+	if s == "" {
+		return nil, nil
+	}
+
+	id, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid last event id: %w", err)
+	}
+	return &id, nil
+}
+
+type EventStreamResponseMapper struct{}
+
+// Map sends the events as server-sent events until the client goes away.
+// The write timeout of the server does not apply to the stream.
+func (m *EventStreamResponseMapper) Map(w http.ResponseWriter, res *event.Stream) error {
+	logger := slogging.Slogger()
+
+	rc := http.NewResponseController(w)
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return err
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	heartbeat := time.NewTicker(eventStreamHeartbeat)
+	defer heartbeat.Stop()
+
+	var err error
+	for err == nil {
+		if err = rc.Flush(); err != nil {
+			break
+		}
+
+		select {
+		case e, ok := <-res.Events:
+			if !ok {
+				if err = res.Err(); err != nil {
+					logger.Error("failed to stream events", "account_id", res.AccountID, "error", err)
+					panic(http.ErrAbortHandler)
+				}
+				return nil
+			}
+			_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", e.ID, e.Type, e.Data)
+		case <-heartbeat.C:
+			_, err = fmt.Fprint(w, ": heartbeat\n\n")
+		}
+	}
+
+	// the client is gone, the stream ends with the request context
+	logger.Debug("event stream closed", "account_id", res.AccountID, "error", err)
+	return nil
+}
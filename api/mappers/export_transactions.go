@@ -0,0 +1,73 @@
+package mappers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/softika/slogging"
+
+	"github.com/fmiskovic/cash-me-if-you-can/internal/export"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/transaction"
+)
+
+// ExportTransactionsRequestMapper maps the export filters from the query.
+// The export is compressed if the gzip parameter is set or the client accepts the gzip encoding.
+type ExportTransactionsRequestMapper struct{}
+
+func (m *ExportTransactionsRequestMapper) Map(r *http.Request) (export.Request, error) {
+	query := r.URL.Query()
+
+	from, err := parseOptionalDateTime(query.Get("from"))
+	if err != nil {
+		return export.Request{}, fmt.Errorf("invalid from: %w", err)
+	}
+
+	to, err := parseOptionalDateTime(query.Get("to"))
+	if err != nil {
+		return export.Request{}, fmt.Errorf("invalid to: %w", err)
+	}
+
+	gzip := acceptsEncoding(r.Header.Values("Accept-Encoding"), "gzip")
+	if s := query.Get("gzip"); s != "" {
+		if gzip, err = strconv.ParseBool(s); err != nil {
+			return export.Request{}, fmt.Errorf("invalid gzip: %w", err)
+		}
+	}
+
+	return export.Request{
+		AccountID: query.Get("account_id"),
+		From:      from,
+		To:        to,
+		Type:      transaction.Type(query.Get("type")),
+		Format:    export.Format(query.Get("format")),
+		Gzip:      gzip,
+	}, nil
+}
+
+type ExportTransactionsResponseMapper struct{}
+
+// Map streams the export to the client. The status is sent before the transactions are read,
+// so a failure while streaming aborts the response instead of turning it into an error response.
+func (m *ExportTransactionsResponseMapper) Map(w http.ResponseWriter, res *export.Export) error {
+	filename := fmt.Sprintf("transactions-%s.%s",
+		res.CreatedAt.UTC().Format(time.DateOnly),
+		res.Format.Extension(),
+	)
+
+	w.Header().Set("Content-Type", res.Format.ContentType())
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.Header().Set("Vary", "Accept-Encoding")
+	if res.Gzip {
+		w.Header().Set("Content-Encoding", "gzip")
+	}
+	w.WriteHeader(http.StatusOK)
+
+	if err := export.Write(w, res); err != nil {
+		logger := slogging.Slogger()
+		logger.Error("failed to write transactions export", "error", err)
+		panic(http.ErrAbortHandler)
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package mappers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/fmiskovic/cash-me-if-you-can/internal/fee"
+)
+
+type FeeQuoteRequestMapper struct{}
+
+func (m *FeeQuoteRequestMapper) Map(r *http.Request) (fee.QuoteRequest, error) {
+	query := r.URL.Query()
+
+	var amount float64
+	if v := query.Get("amount"); v != "" {
+		var err error
+		if amount, err = strconv.ParseFloat(v, 64); err != nil {
+			return fee.QuoteRequest{}, fmt.Errorf("invalid amount: %w", err)
+		}
+	}
+
+	return fee.QuoteRequest{
+		AccountID: query.Get("account_id"),
+		Operation: fee.Operation(query.Get("operation")),
+		Amount:    amount,
+	}, nil
+}
+
+type FeeQuoteResponseMapper struct{}
+
+func (m *FeeQuoteResponseMapper) Map(w http.ResponseWriter, res *fee.Quote) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(res)
+}
@@ -0,0 +1,58 @@
+package mappers
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/fmiskovic/cash-me-if-you-can/internal/imports"
+)
+
+// maxImportSize limits the size of an imported file.
+const maxImportSize = 256 << 20
+
+// importFormats maps the content types of imported files to their formats.
+var importFormats = map[string]imports.Format{
+	"text/csv":             imports.CSV,
+	"application/jsonl":    imports.JSONL,
+	"application/x-ndjson": imports.JSONL,
+}
+
+// ImportRequestMapper passes the request body on as the imported file, so it is read while the rows are imported.
+// The format is taken from the format query parameter or else from the content type.
+type ImportRequestMapper struct{}
+
+func (m *ImportRequestMapper) Map(r *http.Request) (imports.ImportRequest, error) {
+	query := r.URL.Query()
+
+	format := imports.Format(query.Get("format"))
+	if format == "" {
+		contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		format = importFormats[contentType]
+	}
+
+	var dryRun bool
+	if s := query.Get("dry_run"); s != "" {
+		var err error
+		if dryRun, err = strconv.ParseBool(s); err != nil {
+			return imports.ImportRequest{}, fmt.Errorf("invalid dry_run: %w", err)
+		}
+	}
+
+	return imports.ImportRequest{
+		Kind:   imports.Kind(query.Get("kind")),
+		Format: format,
+		File:   http.MaxBytesReader(nil, r.Body, maxImportSize),
+		DryRun: dryRun,
+	}, nil
+}
+
+type ImportResponseMapper struct{}
+
+func (m *ImportResponseMapper) Map(w http.ResponseWriter, res *imports.ReportDetails) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(res)
+}
@@ -0,0 +1,22 @@
+package mappers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/fmiskovic/cash-me-if-you-can/internal/ledger"
+)
+
+type LedgerVerifyRequestMapper struct{}
+
+func (m *LedgerVerifyRequestMapper) Map(*http.Request) (ledger.VerifyRequest, error) {
+	return ledger.VerifyRequest{}, nil
+}
+
+type LedgerVerifyResponseMapper struct{}
+
+func (m *LedgerVerifyResponseMapper) Map(w http.ResponseWriter, res *ledger.ReportDetails) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(res)
+}
@@ -0,0 +1,104 @@
+package mappers
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fmiskovic/cash-me-if-you-can/internal"
+)
+
+func parseOptionalFloat(s string) (*float64, error) {
+	if s == "" {
+		return nil, nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+// parseOptionalTime parses an RFC 3339 timestamp, e.g. 2024-08-16T21:51:58Z.
+func parseOptionalTime(s string) (*time.Time, error) {
+	if s == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// parseOptionalDateTime parses an RFC 3339 timestamp or a date, e.g. 2024-08-16, as midnight UTC.
+func parseOptionalDateTime(s string) (*time.Time, error) {
+	if d, err := time.Parse(time.DateOnly, s); err == nil {
+		return &d, nil
+	}
+	return parseOptionalTime(s)
+}
+
+func parseTimeFilter(query url.Values) (internal.TimeFilter, error) {
+	createdFrom, err := parseOptionalTime(query.Get("created_from"))
+	if err != nil {
+		return internal.TimeFilter{}, fmt.Errorf("invalid created_from: %w", err)
+	}
+
+	createdTo, err := parseOptionalTime(query.Get("created_to"))
+	if err != nil {
+		return internal.TimeFilter{}, fmt.Errorf("invalid created_to: %w", err)
+	}
+
+	updatedSince, err := parseOptionalTime(query.Get("updated_since"))
+	if err != nil {
+		return internal.TimeFilter{}, fmt.Errorf("invalid updated_since: %w", err)
+	}
+
+	return internal.TimeFilter{
+		CreatedFrom:  createdFrom,
+		CreatedTo:    createdTo,
+		UpdatedSince: updatedSince,
+	}, nil
+}
+
+// acceptsEncoding reports whether the Accept-Encoding header values accept the content coding.
+// A coding is accepted if it is listed, or matched by "*", with a quality above zero.
+func acceptsEncoding(values []string, coding string) bool {
+	var listed, accepted, wildcard bool
+	for _, value := range values {
+		for _, item := range strings.Split(value, ",") {
+			name, params, _ := strings.Cut(item, ";")
+			name = strings.ToLower(strings.TrimSpace(name))
+			if name != coding && name != "*" {
+				continue
+			}
+
+			q := 1.0
+			for _, param := range strings.Split(params, ";") {
+				key, v, ok := strings.Cut(param, "=")
+				if !ok || !strings.EqualFold(strings.TrimSpace(key), "q") {
+					continue
+				}
+				parsed, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+				if err != nil {
+					parsed = 0
+				}
+				q = parsed
+			}
+
+			if name == coding {
+				listed, accepted = true, q > 0
+			} else {
+				wildcard = q > 0
+			}
+		}
+	}
+
+	if listed {
+		return accepted
+	}
+	return wildcard
+}
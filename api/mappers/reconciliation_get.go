@@ -0,0 +1,27 @@
+package mappers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/fmiskovic/cash-me-if-you-can/internal/reconciliation"
+)
+
+type ReconciliationGetRequestMapper struct{}
+
+func (m *ReconciliationGetRequestMapper) Map(r *http.Request) (string, error) {
+	id := r.PathValue("id")
+	if id == "" {
+		return "", errors.New("path is missing id parameter")
+	}
+	return id, nil
+}
+
+type ReconciliationGetResponseMapper struct{}
+
+func (m *ReconciliationGetResponseMapper) Map(w http.ResponseWriter, res *reconciliation.ReportDetails) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(res)
+}
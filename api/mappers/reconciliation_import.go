@@ -0,0 +1,63 @@
+package mappers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/fmiskovic/cash-me-if-you-can/internal/reconciliation"
+)
+
+// maxStatementSize limits the size of an uploaded bank statement.
+const maxStatementSize = 10 << 20
+
+// ReconciliationImportRequestMapper parses the bank statement sent as the request body,
+// the format and the tolerances are given as query parameters.
+type ReconciliationImportRequestMapper struct{}
+
+func (m *ReconciliationImportRequestMapper) Map(r *http.Request) (reconciliation.ImportRequest, error) {
+	id := r.PathValue("id")
+	if id == "" {
+		return reconciliation.ImportRequest{}, errors.New("path is missing id parameter")
+	}
+
+	query := r.URL.Query()
+
+	amountTolerance, err := parseOptionalFloat(query.Get("amount_tolerance"))
+	if err != nil {
+		return reconciliation.ImportRequest{}, fmt.Errorf("invalid amount_tolerance: %w", err)
+	}
+
+	var dateTolerance *int
+	if s := query.Get("date_tolerance_days"); s != "" {
+		days, err := strconv.Atoi(s)
+		if err != nil {
+			return reconciliation.ImportRequest{}, fmt.Errorf("invalid date_tolerance_days: %w", err)
+		}
+		dateTolerance = &days
+	}
+
+	format := reconciliation.Format(query.Get("format"))
+	lines, err := reconciliation.Parse(format, http.MaxBytesReader(nil, r.Body, maxStatementSize))
+	if err != nil {
+		return reconciliation.ImportRequest{}, fmt.Errorf("invalid statement: %w", err)
+	}
+
+	return reconciliation.ImportRequest{
+		AccountID:           id,
+		Format:              format,
+		AmountTolerance:     amountTolerance,
+		DateToleranceInDays: dateTolerance,
+		Lines:               lines,
+	}, nil
+}
+
+type ReconciliationImportResponseMapper struct{}
+
+func (m *ReconciliationImportResponseMapper) Map(w http.ResponseWriter, res *reconciliation.ReportDetails) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	return json.NewEncoder(w).Encode(res)
+}
@@ -0,0 +1,50 @@
+package mappers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/fmiskovic/cash-me-if-you-can/internal/reconciliation"
+)
+
+type ReconciliationMatchRequestMapper struct{}
+
+func (m *ReconciliationMatchRequestMapper) Map(r *http.Request) (reconciliation.MatchRequest, error) {
+	line, err := mapLineRequest(r)
+	if err != nil {
+		return reconciliation.MatchRequest{}, err
+	}
+
+	req := reconciliation.MatchRequest{LineRequest: line}
+	err = json.NewDecoder(r.Body).Decode(&req)
+	return req, err
+}
+
+// ReconciliationUnmatchRequestMapper maps the line selected by the path.
+type ReconciliationUnmatchRequestMapper struct{}
+
+func (m *ReconciliationUnmatchRequestMapper) Map(r *http.Request) (reconciliation.LineRequest, error) {
+	return mapLineRequest(r)
+}
+
+// ReconciliationLineResponseMapper is used by both match and unmatch handlers.
+type ReconciliationLineResponseMapper struct{}
+
+func (m *ReconciliationLineResponseMapper) Map(w http.ResponseWriter, res *reconciliation.LineDetails) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(res)
+}
+
+func mapLineRequest(r *http.Request) (reconciliation.LineRequest, error) {
+	runID := r.PathValue("id")
+	if runID == "" {
+		return reconciliation.LineRequest{}, errors.New("path is missing id parameter")
+	}
+	lineID := r.PathValue("line_id")
+	if lineID == "" {
+		return reconciliation.LineRequest{}, errors.New("path is missing line_id parameter")
+	}
+	return reconciliation.LineRequest{RunID: runID, LineID: lineID}, nil
+}
@@ -0,0 +1,24 @@
+package mappers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/fmiskovic/cash-me-if-you-can/internal/schedule"
+)
+
+type ScheduleCreateRequestMapper struct{}
+
+func (m *ScheduleCreateRequestMapper) Map(r *http.Request) (schedule.CreateRequest, error) {
+	var req schedule.CreateRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	return req, err
+}
+
+type ScheduleCreateResponseMapper struct{}
+
+func (m *ScheduleCreateResponseMapper) Map(w http.ResponseWriter, res *schedule.Details) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	return json.NewEncoder(w).Encode(res)
+}
@@ -0,0 +1,28 @@
+package mappers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/fmiskovic/cash-me-if-you-can/internal/schedule"
+)
+
+// ScheduleGetRequestMapper maps the schedule id from the path, it is used by both get and cancel handlers.
+type ScheduleGetRequestMapper struct{}
+
+func (m *ScheduleGetRequestMapper) Map(r *http.Request) (string, error) {
+	id := r.PathValue("id")
+	if id == "" {
+		return "", errors.New("path is missing id parameter")
+	}
+	return id, nil
+}
+
+type ScheduleGetResponseMapper struct{}
+
+func (m *ScheduleGetResponseMapper) Map(w http.ResponseWriter, res *schedule.Details) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(res)
+}
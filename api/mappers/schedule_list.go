@@ -0,0 +1,27 @@
+package mappers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/fmiskovic/cash-me-if-you-can/internal/schedule"
+)
+
+type ScheduleListRequestMapper struct{}
+
+func (m *ScheduleListRequestMapper) Map(r *http.Request) (schedule.ListRequest, error) {
+	id := r.PathValue("id")
+	if id == "" {
+		return schedule.ListRequest{}, errors.New("path is missing id parameter")
+	}
+	return schedule.ListRequest{AccountID: id}, nil
+}
+
+type ScheduleListResponseMapper struct{}
+
+func (m *ScheduleListResponseMapper) Map(w http.ResponseWriter, res []schedule.Details) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	return json.NewEncoder(w).Encode(res)
+}
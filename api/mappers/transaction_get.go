@@ -28,7 +28,7 @@ func NewTransactionGetResponse() *TransactionGetResponseMapper {
 	return &TransactionGetResponseMapper{}
 }
 
-func (m *TransactionGetResponseMapper) Map(w http.ResponseWriter, res []transaction.Details) error {
+func (m *TransactionGetResponseMapper) Map(w http.ResponseWriter, res *transaction.Details) error {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	return json.NewEncoder(w).Encode(res)
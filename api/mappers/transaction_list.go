@@ -10,12 +10,22 @@ import (
 
 type TransactionListRequestMapper struct{}
 
-func (m *TransactionListRequestMapper) Map(r *http.Request) (string, error) {
+func (m *TransactionListRequestMapper) Map(r *http.Request) (transaction.ListRequest, error) {
 	id := r.PathValue("id")
 	if id == "" {
-		return "", errors.New("path is missing id parameter")
+		return transaction.ListRequest{}, errors.New("path is missing id parameter")
 	}
-	return id, nil
+
+	timeFilter, err := parseTimeFilter(r.URL.Query())
+	if err != nil {
+		return transaction.ListRequest{}, err
+	}
+
+	return transaction.ListRequest{
+		AccountID:         id,
+		TimeFilter:        timeFilter,
+		ExternalReference: r.URL.Query().Get("reference"),
+	}, nil
 }
 
 type TransactionListResponseMapper struct{}
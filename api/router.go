@@ -1,7 +1,9 @@
 package api
 
 import (
+	"context"
 	"errors"
+	"io"
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
@@ -13,24 +15,46 @@ import (
 type Router struct {
 	chi.Router
 
-	environment string
+	environment   string
+	storageDriver string
+	// storage is the database opened for the repositories, nil for the memory driver.
+	storage io.Closer
+	// replicated is set when reads are served by a replica of the database.
+	replicated bool
 }
 
-func NewRouter(cfg *config.Config) *Router {
+// NewRouter connects to the storage of the config and registers the routes of its services.
+func NewRouter(ctx context.Context, cfg *config.Config) (*Router, error) {
 	r := chi.NewRouter()
 	defaultMiddlewares(r)
 
 	api := &Router{
-		Router:      r,
-		environment: cfg.App.Environment,
+		Router:        r,
+		environment:   cfg.App.Environment,
+		storageDriver: cfg.Storage.Driver,
 	}
 
-	s := api.initServices(api.initRepositories(cfg.Database))
-	h := api.initHandlers(s)
+	repo, err := api.initRepositories(ctx, cfg.Storage, cfg.Database)
+	if err != nil {
+		return nil, err
+	}
+	h := api.initHandlers(api.initServices(repo))
+
+	if api.replicated {
+		r.Use(consistency)
+	}
 
 	api.initRoutes(h)
 
-	return api
+	return api, nil
+}
+
+// Close closes the storage of the router.
+func (r *Router) Close() error {
+	if r.storage == nil {
+		return nil
+	}
+	return r.storage.Close()
 }
 
 func defaultMiddlewares(r *chi.Mux) {
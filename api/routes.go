@@ -1,10 +1,37 @@
 package api
 
+import "github.com/fmiskovic/cash-me-if-you-can/config"
+
 func (r *Router) initRoutes(h handlers) {
+	// accounts and transactions are served by every storage driver
 	r.Post("/accounts", r.MakeHttpHandlerFunc(h.accountCreate.Handle))
 	r.Get("/accounts/{id}", r.MakeHttpHandlerFunc(h.accountDetails.Handle))
 	r.Get("/accounts", r.MakeHttpHandlerFunc(h.accountList.Handle))
 	r.Post("/accounts/{id}/transactions", r.MakeHttpHandlerFunc(h.transactionCreate.Handle))
 	r.Get("/accounts/{id}/transactions", r.MakeHttpHandlerFunc(h.transactionList.Handle))
+	r.Get("/transactions/{id}", r.MakeHttpHandlerFunc(h.transactionDetails.Handle))
 	r.Post("/transfer", r.MakeHttpHandlerFunc(h.transactionTransfer.Handle))
+
+	if r.storageDriver == config.MemoryDriver || r.storageDriver == config.SQLiteDriver {
+		return
+	}
+
+	r.Get("/accounts/{id}/balance", r.MakeHttpHandlerFunc(h.accountBalance.Handle))
+	r.Get("/accounts/{id}/statements", r.MakeHttpHandlerFunc(h.accountStatement.Handle))
+	r.Get("/accounts/{id}/events", r.MakeHttpHandlerFunc(h.accountEvents.Handle))
+	r.Post("/transfers/batch", r.MakeHttpHandlerFunc(h.batchTransfer.Handle))
+	r.Get("/transfers/batch/{id}", r.MakeHttpHandlerFunc(h.batchDetails.Handle))
+	r.Post("/schedules", r.MakeHttpHandlerFunc(h.scheduleCreate.Handle))
+	r.Get("/schedules/{id}", r.MakeHttpHandlerFunc(h.scheduleDetails.Handle))
+	r.Delete("/schedules/{id}", r.MakeHttpHandlerFunc(h.scheduleCancel.Handle))
+	r.Get("/accounts/{id}/schedules", r.MakeHttpHandlerFunc(h.scheduleList.Handle))
+	r.Get("/fees/quote", r.MakeHttpHandlerFunc(h.feeQuote.Handle))
+	r.Post("/accounts/{id}/reconciliations", r.MakeHttpHandlerFunc(h.reconciliationImport.Handle))
+	r.Get("/reconciliations/{id}", r.MakeHttpHandlerFunc(h.reconciliationDetails.Handle))
+	r.Post("/reconciliations/{id}/lines/{line_id}/match", r.MakeHttpHandlerFunc(h.reconciliationMatch.Handle))
+	r.Delete("/reconciliations/{id}/lines/{line_id}/match", r.MakeHttpHandlerFunc(h.reconciliationUnmatch.Handle))
+	r.Post("/imports", r.MakeHttpHandlerFunc(h.imports.Handle))
+	r.Get("/exports/transactions", r.MakeHttpHandlerFunc(h.exportTransactions.Handle))
+	r.Get("/admin/events", r.MakeHttpHandlerFunc(h.events.Handle))
+	r.Get("/admin/ledger/verify", r.MakeHttpHandlerFunc(h.ledgerVerify.Handle))
 }
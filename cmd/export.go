@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/fmiskovic/cash-me-if-you-can/cmd/export"
+)
+
+func init() {
+	exportCmd.Flags().StringP("output", "o", "-", "output file, stdout if -")
+	exportCmd.Flags().String("format", "ndjson", "export format, csv or ndjson")
+	exportCmd.Flags().Bool("gzip", false, "compress the export with gzip")
+	exportCmd.Flags().String("account", "", "export only the transactions of the account")
+	exportCmd.Flags().String("from", "", "export transactions created from the date or RFC 3339 time")
+	exportCmd.Flags().String("to", "", "export transactions created before the date or RFC 3339 time")
+	exportCmd.Flags().String("type", "", "export only transactions of the type")
+
+	rootCmd.AddCommand(exportCmd)
+}
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "export command",
+	Long:  `export command streams all transactions, optionally filtered, as CSV or JSON lines`,
+	Run: func(cmd *cobra.Command, args []string) {
+		flags := cmd.Flags()
+
+		var opts export.Options
+		opts.Output, _ = flags.GetString("output")
+		opts.Format, _ = flags.GetString("format")
+		opts.Gzip, _ = flags.GetBool("gzip")
+		opts.AccountID, _ = flags.GetString("account")
+		opts.From, _ = flags.GetString("from")
+		opts.To, _ = flags.GetString("to")
+		opts.Type, _ = flags.GetString("type")
+
+		export.Run(cmd.Context(), opts)
+	},
+}
@@ -0,0 +1,141 @@
+package export
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/softika/slogging"
+
+	"github.com/fmiskovic/cash-me-if-you-can/config"
+	"github.com/fmiskovic/cash-me-if-you-can/database"
+	"github.com/fmiskovic/cash-me-if-you-can/database/repositories"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/export"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/transaction"
+	"github.com/fmiskovic/cash-me-if-you-can/pkg/clock"
+)
+
+// Options are the flags of the export command.
+type Options struct {
+	Output    string
+	Format    string
+	Gzip      bool
+	AccountID string
+	From      string
+	To        string
+	Type      string
+}
+
+// Run writes the export and exits with status 1 if it fails.
+func Run(ctx context.Context, opts Options) {
+	if opts.Output == "-" {
+		// keep the logs out of the export written to stdout
+		slogging.Slogger(slog.NewJSONHandler(os.Stderr, nil))
+	}
+	if err := run(ctx, opts); err != nil {
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, opts Options) error {
+	lgr := slogging.Slogger()
+
+	req, err := newRequest(opts)
+	if err != nil {
+		lgr.Error("invalid export options", "error", err)
+		return err
+	}
+
+	cfg, err := config.New()
+	if err != nil {
+		lgr.Error("failed to read config", "error", err)
+		return err
+	}
+
+	db, err := database.New(ctx, cfg.Database.WithoutStatementTimeout())
+	if err != nil {
+		lgr.Error("failed to connect to database", "error", err)
+		return err
+	}
+	defer db.Close()
+
+	svc := export.NewService(repositories.NewExportRepository(db), clock.System())
+
+	e, err := svc.Export(ctx, req)
+	if err != nil {
+		lgr.Error("failed to export transactions", "error", err)
+		return err
+	}
+
+	var out io.Writer = os.Stdout
+	if opts.Output != "-" {
+		f, err := os.Create(opts.Output)
+		if err != nil {
+			lgr.Error("failed to create output file", "path", opts.Output, "error", err)
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+
+	// count the written transactions on the way
+	n := 0
+	transactions := e.Transactions
+	e.Transactions = func(fn func(transaction.Transaction) error) error {
+		return transactions(func(t transaction.Transaction) error {
+			n++
+			return fn(t)
+		})
+	}
+
+	w := bufio.NewWriter(out)
+	if err = export.Write(w, e); err == nil {
+		err = w.Flush()
+	}
+	if err != nil {
+		lgr.Error("failed to write transactions export", "error", err)
+		return err
+	}
+
+	lgr.Info("transactions exported", "transactions", n, "output", opts.Output)
+	return nil
+}
+
+func newRequest(opts Options) (export.Request, error) {
+	req := export.Request{
+		AccountID: opts.AccountID,
+		Type:      transaction.Type(opts.Type),
+		Format:    export.Format(opts.Format),
+		Gzip:      opts.Gzip,
+	}
+
+	var err error
+	if req.From, err = parseTime(opts.From); err != nil {
+		return req, fmt.Errorf("invalid from: %w", err)
+	}
+	if req.To, err = parseTime(opts.To); err != nil {
+		return req, fmt.Errorf("invalid to: %w", err)
+	}
+
+	return req, validator.New().Struct(req)
+}
+
+// parseTime parses a date as midnight UTC or an RFC 3339 time, nil if s is empty.
+func parseTime(s string) (*time.Time, error) {
+	if s == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.DateOnly, s); err == nil {
+		return &t, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/fmiskovic/cash-me-if-you-can/cmd/imports"
+)
+
+func init() {
+	importCmd.Flags().String("kind", "", "kind of the imported records, accounts or transactions")
+	importCmd.Flags().String("format", "", "format of the file, csv or jsonl, taken from the file extension if omitted")
+	importCmd.Flags().Bool("dry-run", false, "validate the file without storing anything")
+	_ = importCmd.MarkFlagRequired("kind")
+
+	rootCmd.AddCommand(importCmd)
+}
+
+var importCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "import command",
+	Long: `import command loads accounts or transactions from a CSV or JSON lines file, or from stdin if the file is -,
+and prints the per row report`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		kind, _ := cmd.Flags().GetString("kind")
+		format, _ := cmd.Flags().GetString("format")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		imports.Run(cmd.Context(), args[0], kind, format, dryRun)
+	},
+}
@@ -0,0 +1,98 @@
+package imports
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/softika/slogging"
+
+	"github.com/fmiskovic/cash-me-if-you-can/config"
+	"github.com/fmiskovic/cash-me-if-you-can/database"
+	"github.com/fmiskovic/cash-me-if-you-can/database/repositories"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/imports"
+)
+
+// formats maps the file extensions to the import formats.
+var formats = map[string]imports.Format{
+	".csv":    imports.CSV,
+	".jsonl":  imports.JSONL,
+	".ndjson": imports.JSONL,
+}
+
+// Run imports the file and exits with status 1 if the import fails.
+func Run(ctx context.Context, path, kind, format string, dryRun bool) {
+	if err := run(ctx, path, kind, format, dryRun, os.Stdin, os.Stdout); err != nil {
+		os.Exit(1)
+	}
+}
+
+// run reads the file, or stdin if the path is -, and writes the JSON report to out.
+func run(ctx context.Context, path, kind, format string, dryRun bool, stdin io.Reader, out io.Writer) error {
+	lgr := slogging.Slogger()
+
+	if format == "" {
+		format = string(formats[strings.ToLower(filepath.Ext(path))])
+	}
+	if format == "" {
+		err := fmt.Errorf("unknown format of %s", path)
+		lgr.Error("failed to import", "error", err)
+		return err
+	}
+
+	file := stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			lgr.Error("failed to open file", "path", path, "error", err)
+			return err
+		}
+		defer f.Close()
+		file = f
+	}
+
+	cfg, err := config.New()
+	if err != nil {
+		lgr.Error("failed to read config", "error", err)
+		return err
+	}
+
+	db, err := database.New(ctx, cfg.Database.WithoutStatementTimeout())
+	if err != nil {
+		lgr.Error("failed to connect to database", "error", err)
+		return err
+	}
+	defer db.Close()
+
+	svc := imports.NewService(repositories.NewImportRepository(db))
+
+	report, err := svc.Import(ctx, imports.ImportRequest{
+		Kind:   imports.Kind(kind),
+		Format: imports.Format(format),
+		File:   file,
+		DryRun: dryRun,
+	})
+	if err != nil {
+		lgr.Error("failed to import", "path", path, "error", err)
+		return err
+	}
+
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	if err = enc.Encode(report); err != nil {
+		return err
+	}
+
+	lgr.Info("import finished",
+		"kind", report.Kind,
+		"dry_run", report.DryRun,
+		"imported", report.Imported,
+		"valid", report.Valid,
+		"rejected", report.Rejected,
+	)
+	return nil
+}
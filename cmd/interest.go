@@ -0,0 +1,20 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/fmiskovic/cash-me-if-you-can/cmd/interest"
+)
+
+func init() {
+	interest.AccrueCmd.Flags().String("date", "", "accrual date as YYYY-MM-DD, all due days until yesterday if omitted")
+	interestCmd.AddCommand(interest.AccrueCmd)
+
+	rootCmd.AddCommand(interestCmd)
+}
+
+var interestCmd = &cobra.Command{
+	Use:   "interest",
+	Short: "Interest commands",
+	Long:  `Interest commands accrue and capitalize the interest of savings accounts`,
+}
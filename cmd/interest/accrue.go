@@ -0,0 +1,77 @@
+package interest
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/softika/slogging"
+
+	"github.com/fmiskovic/cash-me-if-you-can/config"
+	"github.com/fmiskovic/cash-me-if-you-can/database"
+	"github.com/fmiskovic/cash-me-if-you-can/database/repositories"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/interest"
+	"github.com/fmiskovic/cash-me-if-you-can/pkg/clock"
+)
+
+var AccrueCmd = &cobra.Command{
+	Use:   "accrue",
+	Short: "accrues interest",
+	Long:  "accrues the daily interest of interest-bearing accounts and capitalizes it at the end of a period",
+	Run: func(cmd *cobra.Command, args []string) {
+		date, _ := cmd.Flags().GetString("date")
+		if err := accrue(cmd.Context(), date); err != nil {
+			os.Exit(1)
+		}
+	},
+}
+
+func accrue(ctx context.Context, date string) error {
+	lgr := slogging.Slogger()
+
+	cfg, err := config.New()
+	if err != nil {
+		lgr.Error("failed to read config", "error", err)
+		return err
+	}
+
+	db, err := database.New(ctx, cfg.Database.WithoutStatementTimeout())
+	if err != nil {
+		lgr.Error("failed to connect to database", "error", err)
+		return err
+	}
+	defer db.Close()
+
+	svc := interest.NewService(repositories.NewInterestRepository(db), clock.System())
+
+	var runs []interest.Run
+	if date == "" {
+		runs, err = svc.AccrueDue(ctx)
+	} else {
+		var d time.Time
+		if d, err = time.Parse(time.DateOnly, date); err != nil {
+			lgr.Error("invalid accrual date", "date", date, "error", err)
+			return err
+		}
+		var run *interest.Run
+		if run, err = svc.Accrue(ctx, d); err == nil {
+			runs = append(runs, *run)
+		}
+	}
+
+	for _, run := range runs {
+		lgr.Info("interest accrued",
+			"date", run.Date.Format(time.DateOnly),
+			"accounts", run.Accrued,
+			"capitalized", run.Capitalized,
+		)
+	}
+	if err != nil {
+		lgr.Error("failed to accrue interest", "error", err)
+		return err
+	}
+
+	return nil
+}
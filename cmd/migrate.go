@@ -7,8 +7,19 @@ import (
 )
 
 func init() {
+	for _, c := range []*cobra.Command{migrate.UpCmd, migrate.UpToCmd, migrate.DownCmd, migrate.DownToCmd, migrate.RedoCmd} {
+		c.Flags().Bool("dry-run", false, "print the SQL of the migrations that would run instead of running them")
+	}
+	migrate.CreateCmd.Flags().String("dir", "database/migrations", "directory the migration is written to")
+
 	migrateCmd.AddCommand(migrate.UpCmd)
+	migrateCmd.AddCommand(migrate.UpToCmd)
 	migrateCmd.AddCommand(migrate.DownCmd)
+	migrateCmd.AddCommand(migrate.DownToCmd)
+	migrateCmd.AddCommand(migrate.RedoCmd)
+	migrateCmd.AddCommand(migrate.StatusCmd)
+	migrateCmd.AddCommand(migrate.VersionCmd)
+	migrateCmd.AddCommand(migrate.CreateCmd)
 
 	rootCmd.AddCommand(migrateCmd)
 }
@@ -16,5 +27,5 @@ func init() {
 var migrateCmd = &cobra.Command{
 	Use:   "migrate",
 	Short: "Migrate commands",
-	Long:  `Migrate commands run the migrations`,
+	Long:  `Migrate commands run, roll back, inspect and create the migrations of the configured storage`,
 }
@@ -0,0 +1,74 @@
+package migrate
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/softika/slogging"
+
+	"github.com/fmiskovic/cash-me-if-you-can/pkg/clock"
+)
+
+const migrationTemplate = `-- +goose Up
+-- +goose StatementBegin
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+-- +goose StatementEnd
+`
+
+var CreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "creates a migration",
+	Long:  "writes an empty SQL migration named after the current UTC time and the name to the migrations directory",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		dir, _ := cmd.Flags().GetString("dir")
+		if err := create(clock.System(), dir, args[0]); err != nil {
+			os.Exit(1)
+		}
+	},
+}
+
+var nonWord = regexp.MustCompile(`[^a-z0-9]+`)
+
+func create(clk clock.Clock, dir, name string) error {
+	lgr := slogging.Slogger()
+
+	// names are snake case like the existing migrations
+	name = strings.Trim(nonWord.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if name == "" {
+		err := errors.New("migration name must contain letters or digits")
+		lgr.Error("invalid migration name", "error", err)
+		return err
+	}
+
+	path := filepath.Join(dir, fmt.Sprintf("%s_%s.sql", clk.Now().UTC().Format("20060102150405"), name))
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		lgr.Error("failed to create migration", "path", path, "error", err)
+		return err
+	}
+	if _, err = f.WriteString(migrationTemplate); err == nil {
+		err = f.Close()
+	} else {
+		_ = f.Close()
+	}
+	if err != nil {
+		lgr.Error("failed to write migration", "path", path, "error", err)
+		return err
+	}
+
+	lgr.Info("migration created", "path", path)
+	return nil
+}
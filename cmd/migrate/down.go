@@ -1,39 +1,62 @@
 package migrate
 
 import (
+	"context"
+	"os"
+
 	"github.com/spf13/cobra"
 
 	"github.com/softika/slogging"
-
-	"github.com/fmiskovic/cash-me-if-you-can/config"
-	"github.com/fmiskovic/cash-me-if-you-can/database"
 )
 
 var DownCmd = &cobra.Command{
 	Use:   "down",
 	Short: "rollback database migrations",
-	Long:  "rollback database migrations for all tables",
+	Long:  "rolls back the latest applied migration of the configured storage",
 	Run: func(cmd *cobra.Command, args []string) {
-		down()
+		if err := down(cmd.Context(), dryRun(cmd), 0, true); err != nil {
+			os.Exit(1)
+		}
 	},
 }
 
-func down() {
-	lgr := slogging.Slogger()
-
-	cfg, err := config.New()
-	if err != nil {
-		lgr.Error("failed to read config", "error", err)
-		return
-	}
+var DownToCmd = &cobra.Command{
+	Use:   "down-to <version>",
+	Short: "rollback database migrations to a version",
+	Long:  "rolls back the applied migrations after the version, 0 rolls back all of them",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		version, err := parseVersion(args[0])
+		if err == nil {
+			err = down(cmd.Context(), dryRun(cmd), version, false)
+		}
+		if err != nil {
+			os.Exit(1)
+		}
+	},
+}
 
-	dvSvc := database.New(cfg.Database)
+var RedoCmd = &cobra.Command{
+	Use:   "redo",
+	Short: "reruns the latest database migration",
+	Long:  "rolls back the latest applied migration and applies it again",
+	Run: func(cmd *cobra.Command, args []string) {
+		err := run(cmd.Context(), dryRun(cmd), func(ctx context.Context, m migrator) error {
+			return m.redo(ctx)
+		})
+		if err != nil {
+			slogging.Slogger().Error("failed to redo migration", "error", err)
+			os.Exit(1)
+		}
+	},
+}
 
-	lgr.Info("rollback database migrations")
-	if err := rollback(dvSvc.DB()); err != nil {
-		lgr.Error("failed to rollback database migrations", "error", err)
-		return
+func down(ctx context.Context, dryRun bool, version int64, last bool) error {
+	err := run(ctx, dryRun, func(ctx context.Context, m migrator) error {
+		return m.down(ctx, version, last)
+	})
+	if err != nil {
+		slogging.Slogger().Error("failed to rollback migrations", "error", err)
 	}
-
-	lgr.Info("database migrations rollback completed successfully")
+	return err
 }
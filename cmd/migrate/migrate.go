@@ -1,38 +1,262 @@
 package migrate
 
 import (
+	"bufio"
+	"cmp"
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 
 	"github.com/pressly/goose/v3"
+	"github.com/softika/slogging"
 
+	"github.com/fmiskovic/cash-me-if-you-can/config"
 	"github.com/fmiskovic/cash-me-if-you-can/database"
+	"github.com/fmiskovic/cash-me-if-you-can/database/sqlite"
 )
 
-// migrate runs all migration scripts inside the migrations folder.
-func migrate(db *sql.DB) error {
-	if err := goose.SetDialect(database.GetDialect()); err != nil {
-		return fmt.Errorf("failed to set goose dialect: %w", err)
+// migrator runs the migrations of the configured storage.
+// In a dry run nothing is applied, the SQL of the migrations that would run is written to out instead.
+type migrator struct {
+	provider *goose.Provider
+	fsys     fs.FS
+	dryRun   bool
+	out      io.Writer
+}
+
+// run connects to the configured storage and runs fn with a migrator of its migrations.
+// Errors are returned to the command, which logs them.
+func run(ctx context.Context, dryRun bool, fn func(context.Context, migrator) error) error {
+	cfg, err := config.New()
+	if err != nil {
+		return fmt.Errorf("failed to read config: %w", err)
+	}
+
+	var (
+		db      *sql.DB
+		dialect goose.Dialect
+		fsys    fs.FS
+	)
+	if cfg.Storage.Driver == config.SQLiteDriver {
+		if db, err = sqlite.Open(cfg.Storage.Path); err != nil {
+			return err
+		}
+		defer db.Close()
+		dialect, fsys = goose.DialectSQLite3, sqlite.GetMigrationFS()
+	} else {
+		// migrations may rewrite whole tables
+		dbSvc, err := database.New(ctx, cfg.Database.WithoutStatementTimeout())
+		if err != nil {
+			return fmt.Errorf("failed to connect to database: %w", err)
+		}
+		defer dbSvc.Close()
+		db, dialect, fsys = dbSvc.DB(), goose.DialectPostgres, database.GetMigrationFS()
+	}
+
+	if fsys, err = fs.Sub(fsys, "migrations"); err != nil {
+		return err
+	}
+	p, err := goose.NewProvider(dialect, db, fsys, goose.WithAllowOutofOrder(true))
+	if err != nil {
+		return fmt.Errorf("failed to load migrations: %w", err)
+	}
+
+	out := bufio.NewWriter(os.Stdout)
+	defer out.Flush()
+
+	return fn(ctx, migrator{provider: p, fsys: fsys, dryRun: dryRun, out: out})
+}
+
+// up applies the pending migrations up to and including the version.
+func (m migrator) up(ctx context.Context, version int64) error {
+	if m.dryRun {
+		pending, err := m.plan(ctx, goose.StatePending)
+		if err != nil {
+			return err
+		}
+		pending = slices.DeleteFunc(pending, func(s *goose.Source) bool { return s.Version > version })
+		return m.print(pending, true)
+	}
+
+	results, err := m.provider.UpTo(ctx, version)
+	if pErr := (*goose.PartialError)(nil); errors.As(err, &pErr) {
+		results = pErr.Applied
+	}
+	logResults(results)
+	if err != nil {
+		return err
+	}
+	if len(results) == 0 {
+		slogging.Slogger().Info("no migrations to apply")
+	}
+	return nil
+}
+
+// down rolls back the applied migrations after the version, or only the latest one if last is set.
+func (m migrator) down(ctx context.Context, version int64, last bool) error {
+	if m.dryRun {
+		applied, err := m.plan(ctx, goose.StateApplied)
+		if err != nil {
+			return err
+		}
+		applied = slices.DeleteFunc(applied, func(s *goose.Source) bool { return s.Version <= version })
+		if last && len(applied) > 1 {
+			applied = applied[:1]
+		}
+		return m.print(applied, false)
+	}
+
+	var (
+		results []*goose.MigrationResult
+		err     error
+	)
+	if last {
+		var res *goose.MigrationResult
+		if res, err = m.provider.Down(ctx); err == nil {
+			results = append(results, res)
+		}
+	} else {
+		results, err = m.provider.DownTo(ctx, version)
+	}
+	if pErr := (*goose.PartialError)(nil); errors.As(err, &pErr) {
+		results = pErr.Applied
+	}
+	logResults(results)
+	if errors.Is(err, goose.ErrNoNextVersion) || (err == nil && len(results) == 0) {
+		slogging.Slogger().Info("no migrations to roll back")
+		return nil
+	}
+	return err
+}
+
+// redo rolls back the latest applied migration and applies it again.
+func (m migrator) redo(ctx context.Context) error {
+	if m.dryRun {
+		applied, err := m.plan(ctx, goose.StateApplied)
+		if err != nil || len(applied) == 0 {
+			return err
+		}
+		if err = m.print(applied[:1], false); err != nil {
+			return err
+		}
+		return m.print(applied[:1], true)
 	}
 
-	goose.SetBaseFS(database.GetMigrationFS())
-	if err := goose.Up(db, "migrations", goose.WithAllowMissing()); err != nil {
-		return fmt.Errorf("failed to run database migrations: %w", err)
+	res, err := m.provider.Down(ctx)
+	if errors.Is(err, goose.ErrNoNextVersion) {
+		slogging.Slogger().Info("no migrations to redo")
+		return nil
 	}
+	if err != nil {
+		return err
+	}
+	logResults([]*goose.MigrationResult{res})
 
+	if res, err = m.provider.ApplyVersion(ctx, res.Source.Version, true); err != nil {
+		return err
+	}
+	logResults([]*goose.MigrationResult{res})
 	return nil
 }
 
-// rollback runs all migration scripts inside the migrations folder.
-func rollback(db *sql.DB) error {
-	if err := goose.SetDialect(database.GetDialect()); err != nil {
-		return fmt.Errorf("failed to set goose dialect: %w", err)
+// status writes the state of every migration.
+func (m migrator) status(ctx context.Context) error {
+	statuses, err := m.provider.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, s := range statuses {
+		appliedAt := "pending"
+		if s.State == goose.StateApplied {
+			appliedAt = s.AppliedAt.UTC().Format("2006-01-02 15:04:05")
+		}
+		if _, err = fmt.Fprintf(m.out, "%-20s %s\n", appliedAt, filepath.Base(s.Source.Path)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// version writes the version of the latest applied migration, 0 if none is applied.
+func (m migrator) version(ctx context.Context) error {
+	v, err := m.provider.GetDBVersion(ctx)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(m.out, v)
+	return err
+}
+
+// plan returns the sources of the migrations in the state in the order they would run,
+// pending ones by version and applied ones latest first.
+func (m migrator) plan(ctx context.Context, state goose.State) ([]*goose.Source, error) {
+	statuses, err := m.provider.Status(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	goose.SetBaseFS(database.GetMigrationFS())
-	if err := goose.Down(db, "migrations"); err != nil {
-		return fmt.Errorf("failed to run database migrations: %w", err)
+	statuses = slices.DeleteFunc(statuses, func(s *goose.MigrationStatus) bool { return s.State != state })
+	if state == goose.StateApplied {
+		// migrations are rolled back in the order they were applied
+		slices.SortStableFunc(statuses, func(a, b *goose.MigrationStatus) int {
+			if c := b.AppliedAt.Compare(a.AppliedAt); c != 0 {
+				return c
+			}
+			return cmp.Compare(b.Source.Version, a.Source.Version)
+		})
 	}
 
+	sources := make([]*goose.Source, len(statuses))
+	for i, s := range statuses {
+		sources[i] = s.Source
+	}
+	return sources, nil
+}
+
+// print writes the SQL each of the migrations runs in the direction.
+func (m migrator) print(sources []*goose.Source, up bool) error {
+	direction := "down"
+	if up {
+		direction = "up"
+	}
+
+	for _, s := range sources {
+		b, err := fs.ReadFile(m.fsys, s.Path)
+		if err != nil {
+			return err
+		}
+		if _, err = fmt.Fprintf(m.out, "-- %s %s\n%s\n", direction, filepath.Base(s.Path), section(string(b), up)); err != nil {
+			return err
+		}
+	}
 	return nil
 }
+
+// section returns the up or the down part of a SQL migration.
+func section(migration string, up bool) string {
+	before, after, _ := strings.Cut(migration, "-- +goose Down")
+	if !up {
+		return strings.TrimSpace(after)
+	}
+	_, after, _ = strings.Cut(before, "-- +goose Up")
+	return strings.TrimSpace(after)
+}
+
+func logResults(results []*goose.MigrationResult) {
+	lgr := slogging.Slogger()
+	for _, res := range results {
+		lgr.Info("migration "+res.Direction,
+			"version", res.Source.Version,
+			"file", filepath.Base(res.Source.Path),
+			"duration", res.Duration,
+		)
+	}
+}
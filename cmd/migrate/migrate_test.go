@@ -0,0 +1,214 @@
+package migrate
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/pressly/goose/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fmiskovic/cash-me-if-you-can/database/sqlite"
+	"github.com/fmiskovic/cash-me-if-you-can/pkg/clock"
+)
+
+func TestSection(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		migration string
+		wantUp    string
+		wantDown  string
+	}{
+		{
+			name: "up and down",
+			migration: "-- +goose Up\n-- +goose StatementBegin\nCREATE TABLE a (id INT);\n-- +goose StatementEnd\n\n" +
+				"-- +goose Down\n-- +goose StatementBegin\nDROP TABLE a;\n-- +goose StatementEnd\n",
+			wantUp:   "-- +goose StatementBegin\nCREATE TABLE a (id INT);\n-- +goose StatementEnd",
+			wantDown: "-- +goose StatementBegin\nDROP TABLE a;\n-- +goose StatementEnd",
+		},
+		{
+			name:      "no down section",
+			migration: "-- +goose Up\nCREATE INDEX a_id_idx ON a (id);\n",
+			wantUp:    "CREATE INDEX a_id_idx ON a (id);",
+			wantDown:  "",
+		},
+		{
+			name:      "comments before the up section are left out",
+			migration: "-- creates a\n-- +goose Up\nCREATE TABLE a (id INT);\n-- +goose Down\nDROP TABLE a;\n",
+			wantUp:    "CREATE TABLE a (id INT);",
+			wantDown:  "DROP TABLE a;",
+		},
+	}
+
+	for _, tc := range tests {
+		tt := tc
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.wantUp, section(tt.migration, true))
+			assert.Equal(t, tt.wantDown, section(tt.migration, false))
+		})
+	}
+}
+
+func TestParseVersion(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		version string
+		want    int64
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{name: "timestamp", version: "20240816215158", want: 20240816215158, wantErr: assert.NoError},
+		{name: "zero rolls back everything", version: "0", want: 0, wantErr: assert.NoError},
+		{name: "negative", version: "-1", wantErr: assert.Error},
+		{name: "not a number", version: "latest", wantErr: assert.Error},
+		{name: "file name", version: "20240816215158_init.sql", wantErr: assert.Error},
+		{name: "empty", version: "", wantErr: assert.Error},
+	}
+
+	for _, tc := range tests {
+		tt := tc
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := parseVersion(tt.version)
+			if tt.wantErr(t, err) && err == nil {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestCreate(t *testing.T) {
+	t.Parallel()
+
+	clk := clock.Fixed(time.Date(2024, time.August, 16, 21, 51, 58, 0, time.UTC))
+
+	tests := []struct {
+		name     string
+		arg      string
+		existing bool
+		want     string
+		wantErr  assert.ErrorAssertionFunc
+	}{
+		{name: "snake case", arg: "add_users", want: "20240816215158_add_users.sql", wantErr: assert.NoError},
+		{name: "normalised", arg: "  Add Users: Email-Index! ", want: "20240816215158_add_users_email_index.sql", wantErr: assert.NoError},
+		{name: "no letters or digits", arg: "--- !", wantErr: assert.Error},
+		{name: "existing file is kept", arg: "add_users", existing: true, want: "20240816215158_add_users.sql", wantErr: assert.Error},
+	}
+
+	for _, tc := range tests {
+		tt := tc
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+			if tt.existing {
+				require.NoError(t, os.WriteFile(filepath.Join(dir, tt.want), []byte("-- existing"), 0o644))
+			}
+
+			tt.wantErr(t, create(clk, dir, tt.arg))
+
+			files, err := os.ReadDir(dir)
+			require.NoError(t, err)
+			if tt.want == "" {
+				assert.Empty(t, files)
+				return
+			}
+			require.Len(t, files, 1)
+			assert.Equal(t, tt.want, files[0].Name())
+
+			b, err := os.ReadFile(filepath.Join(dir, tt.want))
+			require.NoError(t, err)
+			if tt.existing {
+				assert.Equal(t, "-- existing", string(b))
+			} else {
+				assert.Equal(t, migrationTemplate, string(b))
+			}
+		})
+	}
+}
+
+func TestDryRun(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	fsys := fstest.MapFS{
+		"00001_a.sql": {Data: []byte("-- +goose Up\nCREATE TABLE a (id INT);\n-- +goose Down\nDROP TABLE a;\n")},
+		"00002_b.sql": {Data: []byte("-- +goose Up\nCREATE TABLE b (id INT);\n-- +goose Down\nDROP TABLE b;\n")},
+		"00003_c.sql": {Data: []byte("-- +goose Up\nCREATE INDEX b_id_idx ON b (id);\n")},
+	}
+
+	// two of the three migrations are applied
+	newMigrator := func(t *testing.T) (migrator, *bytes.Buffer) {
+		db, err := sqlite.Open(filepath.Join(t.TempDir(), "migrate.db"))
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = db.Close() })
+
+		p, err := goose.NewProvider(goose.DialectSQLite3, db, fsys)
+		require.NoError(t, err)
+		_, err = p.UpTo(ctx, 2)
+		require.NoError(t, err)
+
+		out := new(bytes.Buffer)
+		return migrator{provider: p, fsys: fsys, dryRun: true, out: out}, out
+	}
+
+	tests := []struct {
+		name string
+		fn   func(migrator) error
+		want string
+	}{
+		{
+			name: "up prints the pending migrations",
+			fn:   func(m migrator) error { return m.up(ctx, goose.MaxVersion) },
+			want: "-- up 00003_c.sql\nCREATE INDEX b_id_idx ON b (id);\n",
+		},
+		{
+			name: "up to a version before the pending migrations prints nothing",
+			fn:   func(m migrator) error { return m.up(ctx, 2) },
+			want: "",
+		},
+		{
+			name: "down to a version prints the applied migrations after it, latest first",
+			fn:   func(m migrator) error { return m.down(ctx, 0, false) },
+			want: "-- down 00002_b.sql\nDROP TABLE b;\n-- down 00001_a.sql\nDROP TABLE a;\n",
+		},
+		{
+			name: "down prints the latest applied migration",
+			fn:   func(m migrator) error { return m.down(ctx, 0, true) },
+			want: "-- down 00002_b.sql\nDROP TABLE b;\n",
+		},
+		{
+			name: "redo prints the latest applied migration down and up",
+			fn:   func(m migrator) error { return m.redo(ctx) },
+			want: "-- down 00002_b.sql\nDROP TABLE b;\n-- up 00002_b.sql\nCREATE TABLE b (id INT);\n",
+		},
+	}
+
+	for _, tc := range tests {
+		tt := tc
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			m, out := newMigrator(t)
+			require.NoError(t, tt.fn(m))
+			assert.Equal(t, tt.want, out.String())
+
+			// nothing was applied or rolled back
+			v, err := m.provider.GetDBVersion(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, int64(2), v)
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package migrate
+
+import (
+	"context"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/softika/slogging"
+)
+
+var StatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "prints the migration status",
+	Long:  "prints every migration with the time it was applied, or pending",
+	Run: func(cmd *cobra.Command, args []string) {
+		err := run(cmd.Context(), false, func(ctx context.Context, m migrator) error {
+			return m.status(ctx)
+		})
+		if err != nil {
+			slogging.Slogger().Error("failed to get migration status", "error", err)
+			os.Exit(1)
+		}
+	},
+}
+
+var VersionCmd = &cobra.Command{
+	Use:   "version",
+	Short: "prints the database version",
+	Long:  "prints the version of the latest applied migration, 0 if none is applied",
+	Run: func(cmd *cobra.Command, args []string) {
+		err := run(cmd.Context(), false, func(ctx context.Context, m migrator) error {
+			return m.version(ctx)
+		})
+		if err != nil {
+			slogging.Slogger().Error("failed to get database version", "error", err)
+			os.Exit(1)
+		}
+	},
+}
@@ -1,39 +1,67 @@
 package migrate
 
 import (
+	"context"
+	"os"
+	"strconv"
+
+	"github.com/pressly/goose/v3"
 	"github.com/spf13/cobra"
 
 	"github.com/softika/slogging"
-
-	"github.com/fmiskovic/cash-me-if-you-can/config"
-	"github.com/fmiskovic/cash-me-if-you-can/database"
 )
 
 var UpCmd = &cobra.Command{
 	Use:   "up",
 	Short: "runs up database migrations",
-	Long:  "runs up database migrations for all storage options defined in go-template",
+	Long:  "applies all pending migrations of the configured storage",
 	Run: func(cmd *cobra.Command, args []string) {
-		up()
+		if err := up(cmd.Context(), dryRun(cmd), goose.MaxVersion); err != nil {
+			os.Exit(1)
+		}
 	},
 }
 
-func up() {
-	lgr := slogging.Slogger()
+var UpToCmd = &cobra.Command{
+	Use:   "up-to <version>",
+	Short: "runs up database migrations to a version",
+	Long:  "applies the pending migrations up to and including the version",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		version, err := parseVersion(args[0])
+		if err == nil {
+			err = up(cmd.Context(), dryRun(cmd), version)
+		}
+		if err != nil {
+			os.Exit(1)
+		}
+	},
+}
 
-	cfg, err := config.New()
+func up(ctx context.Context, dryRun bool, version int64) error {
+	err := run(ctx, dryRun, func(ctx context.Context, m migrator) error {
+		return m.up(ctx, version)
+	})
 	if err != nil {
-		lgr.Error("failed to read config", "error", err)
-		return
+		slogging.Slogger().Error("failed to run migrations", "error", err)
 	}
+	return err
+}
 
-	dvSvc := database.New(cfg.Database)
-
-	lgr.Info("running database migrations")
-	if err := migrate(dvSvc.DB()); err != nil {
-		lgr.Error("failed to run database migrations", "error", err)
-		return
+// parseVersion parses the version of a migration, the timestamp its file name starts with.
+func parseVersion(s string) (int64, error) {
+	version, err := strconv.ParseInt(s, 10, 64)
+	if err == nil && version < 0 {
+		err = strconv.ErrRange
 	}
+	if err != nil {
+		slogging.Slogger().Error("invalid migration version", "version", s, "error", err)
+	}
+	return version, err
+}
 
-	lgr.Info("database migrations completed successfully")
+// dryRun reports whether the migrations should only be printed.
+func dryRun(cmd *cobra.Command) bool {
+	v, _ := cmd.Flags().GetBool("dry-run")
+	return v
 }
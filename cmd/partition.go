@@ -0,0 +1,23 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/fmiskovic/cash-me-if-you-can/cmd/partition"
+)
+
+func init() {
+	partition.MaintainCmd.Flags().Int("ahead", 3, "number of months after the current one to create partitions for")
+	partition.MaintainCmd.Flags().Int("retain", 0, "number of months, the current one included, to keep in the database, 0 keeps all")
+	partition.MaintainCmd.Flags().Int("event-retention", 90, "number of days to keep account events, 0 keeps all")
+	partition.MaintainCmd.Flags().String("archive-dir", "archive", "directory the archived partitions are written to")
+	partitionCmd.AddCommand(partition.MaintainCmd)
+
+	rootCmd.AddCommand(partitionCmd)
+}
+
+var partitionCmd = &cobra.Command{
+	Use:   "partition",
+	Short: "Partition commands",
+	Long:  `Partition commands maintain the monthly partitions of the transactions table`,
+}
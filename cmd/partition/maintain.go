@@ -0,0 +1,151 @@
+package partition
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/spf13/cobra"
+
+	"github.com/softika/slogging"
+
+	"github.com/fmiskovic/cash-me-if-you-can/config"
+	"github.com/fmiskovic/cash-me-if-you-can/database"
+	"github.com/fmiskovic/cash-me-if-you-can/database/repositories"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/event"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/partition"
+	"github.com/fmiskovic/cash-me-if-you-can/pkg/clock"
+)
+
+var MaintainCmd = &cobra.Command{
+	Use:   "maintain",
+	Short: "maintains transaction partitions",
+	Long: "creates the monthly partitions of the transactions table ahead of time " +
+		"and archives the partitions older than the retained months to gzip-compressed JSON lines files. " +
+		"Account events older than the event retention are deleted",
+	Run: func(cmd *cobra.Command, args []string) {
+		flags := cmd.Flags()
+
+		var req partition.MaintainRequest
+		req.Ahead, _ = flags.GetInt("ahead")
+		req.Retain, _ = flags.GetInt("retain")
+		req.EventRetention, _ = flags.GetInt("event-retention")
+		dir, _ := flags.GetString("archive-dir")
+
+		if err := maintain(cmd.Context(), req, dir); err != nil {
+			os.Exit(1)
+		}
+	},
+}
+
+func maintain(ctx context.Context, req partition.MaintainRequest, dir string) error {
+	lgr := slogging.Slogger()
+
+	if err := validator.New().Struct(req); err != nil {
+		lgr.Error("invalid partition options", "error", err)
+		return err
+	}
+
+	cfg, err := config.New()
+	if err != nil {
+		lgr.Error("failed to read config", "error", err)
+		return err
+	}
+
+	db, err := database.New(ctx, cfg.Database.WithoutStatementTimeout())
+	if err != nil {
+		lgr.Error("failed to connect to database", "error", err)
+		return err
+	}
+	defer db.Close()
+
+	svc := partition.NewService(repositories.NewPartitionRepository(db), clock.System())
+
+	created, err := svc.Prepare(ctx, req.Ahead)
+	for _, p := range created {
+		lgr.Info("partition created", "partition", p.Name)
+	}
+	if err != nil {
+		lgr.Error("failed to create partitions", "error", err)
+		return err
+	}
+
+	if req.EventRetention > 0 {
+		events := event.NewService(repositories.NewEventRepository(db), clock.System())
+		n, err := events.Prune(ctx, req.EventRetention)
+		if err != nil {
+			lgr.Error("failed to prune events", "error", err)
+			return err
+		}
+		lgr.Info("events pruned", "events", n, "retention_days", req.EventRetention)
+	}
+
+	if req.Retain == 0 {
+		return nil
+	}
+
+	due, err := svc.Due(ctx, req.Retain)
+	if err != nil {
+		lgr.Error("failed to list partitions", "error", err)
+		return err
+	}
+	if len(due) == 0 {
+		return nil
+	}
+
+	if err = os.MkdirAll(dir, 0o750); err != nil {
+		lgr.Error("failed to create archive directory", "path", dir, "error", err)
+		return err
+	}
+
+	for _, p := range due {
+		path := filepath.Join(dir, p.Name+".ndjson.gz")
+
+		n, err := export(ctx, svc, p, path)
+		if err != nil {
+			lgr.Error("failed to export partition", "partition", p.Name, "error", err)
+			return err
+		}
+
+		a, err := svc.Archive(ctx, p, path, n)
+		if err != nil {
+			lgr.Error("failed to archive partition", "partition", p.Name, "error", err)
+			return err
+		}
+
+		lgr.Info("partition archived", "partition", p.Name, "transactions", a.Transactions, "path", a.Location)
+	}
+
+	return nil
+}
+
+// export writes the transactions of the partition to the file at path.
+// The file is written under a temporary name and synced before it is renamed,
+// so the partition is only dropped once its archive is complete on disk.
+func export(ctx context.Context, svc partition.Service, p partition.Partition, path string) (int64, error) {
+	tmp := path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = os.Remove(tmp) }()
+
+	w := bufio.NewWriter(f)
+	n, err := svc.Export(ctx, p, w)
+	if err == nil {
+		err = w.Flush()
+	}
+	if err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return n, os.Rename(tmp, path)
+}
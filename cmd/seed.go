@@ -0,0 +1,13 @@
+package cmd
+
+import (
+	"github.com/fmiskovic/cash-me-if-you-can/cmd/seed"
+)
+
+func init() {
+	seed.SeedCmd.Flags().Int("customers", 20, "number of customers an account is created for")
+	seed.SeedCmd.Flags().Int("transactions", 200, "number of deposits, withdrawals and transfers made")
+	seed.SeedCmd.Flags().Int64("seed", 0, "seed of the random generator, the same seed generates the same data (default random)")
+
+	rootCmd.AddCommand(seed.SeedCmd, seed.ResetCmd)
+}
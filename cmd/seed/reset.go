@@ -0,0 +1,52 @@
+package seed
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/softika/slogging"
+
+	"github.com/fmiskovic/cash-me-if-you-can/config"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/seed"
+)
+
+var ResetCmd = &cobra.Command{
+	Use:   "reset",
+	Short: "deletes all data",
+	Long: "deletes all accounts, their transactions and the data derived from them, products are kept. " +
+		"It is refused unless the environment is one of " + strings.Join(seed.ResettableEnvironments, ", "),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := reset(cmd.Context()); err != nil {
+			os.Exit(1)
+		}
+	},
+}
+
+func reset(ctx context.Context) error {
+	lgr := slogging.Slogger()
+
+	cfg, err := config.New()
+	if err != nil {
+		lgr.Error("failed to read config", "error", err)
+		return err
+	}
+
+	svc, closeFn, err := newService(ctx, cfg)
+	if err != nil {
+		lgr.Error("failed to open storage", "driver", cfg.Storage.Driver, "error", err)
+		return err
+	}
+	defer closeFn()
+
+	req := seed.ResetRequest{Environment: cfg.App.Environment}
+	if err = svc.Reset(ctx, req); err != nil {
+		lgr.Error("failed to reset data", "environment", req.Environment, "error", err)
+		return err
+	}
+
+	lgr.Info("data reset", "environment", req.Environment)
+	return nil
+}
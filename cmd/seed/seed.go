@@ -0,0 +1,121 @@
+package seed
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/spf13/cobra"
+
+	"github.com/softika/slogging"
+
+	"github.com/fmiskovic/cash-me-if-you-can/config"
+	"github.com/fmiskovic/cash-me-if-you-can/database"
+	"github.com/fmiskovic/cash-me-if-you-can/database/repositories"
+	"github.com/fmiskovic/cash-me-if-you-can/database/sqlite"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/account"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/seed"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/transaction"
+)
+
+var SeedCmd = &cobra.Command{
+	Use:   "seed",
+	Short: "seeds demo data",
+	Long: "creates customers' accounts with realistic names and makes random deposits, withdrawals and transfers " +
+		"between them through the services, so balances, fees and the ledger stay consistent",
+	Run: func(cmd *cobra.Command, args []string) {
+		flags := cmd.Flags()
+
+		var req seed.Request
+		req.Customers, _ = flags.GetInt("customers")
+		req.Transactions, _ = flags.GetInt("transactions")
+		req.Seed, _ = flags.GetInt64("seed")
+
+		// without a seed every run generates different data, the seed is logged to repeat it
+		if !flags.Changed("seed") {
+			req.Seed = time.Now().UnixNano()
+		}
+
+		if err := run(cmd.Context(), req); err != nil {
+			os.Exit(1)
+		}
+	},
+}
+
+func run(ctx context.Context, req seed.Request) error {
+	lgr := slogging.Slogger()
+
+	if err := validator.New().Struct(req); err != nil {
+		lgr.Error("invalid seed options", "error", err)
+		return err
+	}
+
+	cfg, err := config.New()
+	if err != nil {
+		lgr.Error("failed to read config", "error", err)
+		return err
+	}
+
+	svc, closeFn, err := newService(ctx, cfg)
+	if err != nil {
+		lgr.Error("failed to open storage", "driver", cfg.Storage.Driver, "error", err)
+		return err
+	}
+	defer closeFn()
+
+	lgr.Info("seeding demo data", "customers", req.Customers, "transactions", req.Transactions, "seed", req.Seed)
+
+	report, err := svc.Seed(ctx, req)
+	if err != nil {
+		lgr.Error("failed to seed demo data", "seed", req.Seed, "error", err)
+		return err
+	}
+
+	lgr.Info("demo data seeded",
+		"accounts", report.Accounts,
+		"deposits", report.Deposits,
+		"withdrawals", report.Withdrawals,
+		"transfers", report.Transfers,
+		"rejected", report.Rejected,
+	)
+	return nil
+}
+
+// newService returns the seed service of the configured storage and a function closing the storage.
+// The memory driver is not supported, its data would be lost when the command exits.
+func newService(ctx context.Context, cfg *config.Config) (seed.Service, func(), error) {
+	switch cfg.Storage.Driver {
+	case config.MemoryDriver:
+		return seed.Service{}, nil, errors.New("the memory driver keeps no data between runs")
+	case config.SQLiteDriver:
+		db, err := sqlite.Open(cfg.Storage.Path)
+		if err != nil {
+			return seed.Service{}, nil, fmt.Errorf("failed to open sqlite database: %w", err)
+		}
+		return seed.NewService(
+			sqlite.NewSeedRepository(db),
+			account.NewService(sqlite.NewAccountRepository(db)),
+			transaction.NewService(sqlite.NewTransactionRepository(db)),
+		), func() { closeDB(db) }, nil
+	}
+
+	db, err := database.New(ctx, cfg.Database)
+	if err != nil {
+		return seed.Service{}, nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+	return seed.NewService(
+		repositories.NewSeedRepository(db),
+		account.NewService(repositories.NewAccountRepository(db)),
+		transaction.NewService(repositories.NewTransactionRepository(db)),
+	), func() { _ = db.Close() }, nil
+}
+
+func closeDB(db *sql.DB) {
+	if err := db.Close(); err != nil {
+		slogging.Slogger().Warn("failed to close sqlite database", "error", err)
+	}
+}
@@ -22,7 +22,15 @@ func Run() {
 		os.Exit(1)
 	}
 
-	router := api.NewRouter(cfg)
+	// Interrupt signal aborts connecting to the storage as well as shuts down the server.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, os.Kill)
+	defer stop()
+
+	router, err := api.NewRouter(ctx, cfg)
+	if err != nil {
+		log.Error("failed to create router", "error", err)
+		os.Exit(1)
+	}
 
 	srv := api.NewServer(cfg.Http)
 
@@ -37,8 +45,6 @@ func Run() {
 	}()
 
 	// Wait for interrupt signal to gracefully shut down the server with a timeout.
-	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, os.Kill)
-	defer stop()
 	<-ctx.Done()
 
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
@@ -47,6 +53,9 @@ func Run() {
 	if err = srv.Shutdown(ctx); err != nil {
 		log.Error("server shutdown error", "error", err)
 	}
+	if err = router.Close(); err != nil {
+		log.Error("failed to close the storage", "error", err)
+	}
 	log.Info("Graceful shutdown completed.")
 
 }
@@ -0,0 +1,21 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/fmiskovic/cash-me-if-you-can/cmd/verify"
+)
+
+func init() {
+	rootCmd.AddCommand(verifyCmd)
+}
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "verify command",
+	Long: `verify command recomputes the account balances from the transactions, checks that the transfer legs balance
+and exits with a non-zero status if any discrepancy is found`,
+	Run: func(cmd *cobra.Command, args []string) {
+		verify.Run(cmd.Context())
+	},
+}
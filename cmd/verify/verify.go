@@ -0,0 +1,71 @@
+package verify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+
+	"github.com/softika/slogging"
+
+	"github.com/fmiskovic/cash-me-if-you-can/config"
+	"github.com/fmiskovic/cash-me-if-you-can/database"
+	"github.com/fmiskovic/cash-me-if-you-can/database/repositories"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/ledger"
+	"github.com/fmiskovic/cash-me-if-you-can/pkg/clock"
+)
+
+// errDiscrepancies is returned when the ledger does not verify.
+var errDiscrepancies = errors.New("ledger discrepancies found")
+
+// Run verifies the ledger and exits with status 1 if it fails or finds discrepancies.
+func Run(ctx context.Context) {
+	if err := verify(ctx, os.Stdout); err != nil {
+		os.Exit(1)
+	}
+}
+
+// verify writes the JSON report to out.
+func verify(ctx context.Context, out io.Writer) error {
+	lgr := slogging.Slogger()
+
+	cfg, err := config.New()
+	if err != nil {
+		lgr.Error("failed to read config", "error", err)
+		return err
+	}
+
+	db, err := database.New(ctx, cfg.Database.WithoutStatementTimeout())
+	if err != nil {
+		lgr.Error("failed to connect to database", "error", err)
+		return err
+	}
+	defer db.Close()
+
+	svc := ledger.NewService(repositories.NewLedgerRepository(db), clock.System())
+
+	report, err := svc.Verify(ctx, ledger.VerifyRequest{})
+	if err != nil {
+		lgr.Error("failed to verify the ledger", "error", err)
+		return err
+	}
+
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	if err = enc.Encode(report); err != nil {
+		return err
+	}
+
+	if !report.OK {
+		lgr.Error(errDiscrepancies.Error(),
+			"accounts", report.Accounts,
+			"drifts", len(report.Drifts),
+			"unmatched_legs", len(report.UnmatchedLegs),
+		)
+		return errDiscrepancies
+	}
+
+	lgr.Info("ledger verified", "accounts", report.Accounts)
+	return nil
+}
@@ -0,0 +1,20 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/fmiskovic/cash-me-if-you-can/cmd/worker"
+)
+
+func init() {
+	rootCmd.AddCommand(workerCmd)
+}
+
+var workerCmd = &cobra.Command{
+	Use:   "worker",
+	Short: "worker command",
+	Long:  `worker command runs the background jobs, it executes due scheduled transfers, accrues interest and takes balance snapshots`,
+	Run: func(cmd *cobra.Command, args []string) {
+		worker.Run()
+	},
+}
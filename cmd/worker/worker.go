@@ -0,0 +1,117 @@
+package worker
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/softika/slogging"
+
+	"github.com/fmiskovic/cash-me-if-you-can/config"
+	"github.com/fmiskovic/cash-me-if-you-can/database"
+	"github.com/fmiskovic/cash-me-if-you-can/database/repositories"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/balance"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/interest"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/schedule"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/transaction"
+	"github.com/fmiskovic/cash-me-if-you-can/pkg/clock"
+)
+
+const (
+	defaultPollInterval = 30 * time.Second
+	defaultBatchSize    = 100
+	defaultLease        = 5 * time.Minute
+)
+
+func Run() {
+	log := slogging.Slogger()
+	cfg, err := config.New()
+	if err != nil {
+		log.Error("failed to read config", "error", err)
+		os.Exit(1)
+	}
+
+	// Stop polling on interrupt signal, the running execution is completed first.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, os.Kill)
+	defer stop()
+
+	db, err := database.New(ctx, cfg.Database.WithoutStatementTimeout())
+	if err != nil {
+		log.Error("failed to connect to database", "error", err)
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	schedules := schedule.NewService(
+		repositories.NewScheduleRepository(db),
+		transaction.NewService(repositories.NewTransactionRepository(db)),
+	)
+	interests := interest.NewService(repositories.NewInterestRepository(db), clock.System())
+	balances := balance.NewService(repositories.NewBalanceRepository(db), clock.System())
+
+	sc := withDefaults(cfg.Scheduler)
+
+	log.Info("starting the worker...", "poll_interval", sc.PollInterval, "batch_size", sc.BatchSize)
+
+	ticker := time.NewTicker(sc.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		executeDue(ctx, schedules, sc)
+		accrueInterest(ctx, interests)
+		snapshotBalances(ctx, balances)
+
+		select {
+		case <-ctx.Done():
+			log.Info("Graceful shutdown completed.")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// executeDue executes due schedules until there are none left, so a backlog is not limited by the batch size.
+func executeDue(ctx context.Context, schedules schedule.Service, cfg config.SchedulerConfig) {
+	// executions are not cancelled in the middle of a transfer
+	execCtx := context.WithoutCancel(ctx)
+	for ctx.Err() == nil {
+		n, err := schedules.ExecuteDue(execCtx, time.Now().UTC(), cfg.BatchSize, cfg.Lease)
+		if err != nil || n < cfg.BatchSize {
+			return
+		}
+	}
+}
+
+// accrueInterest accrues the interest of the days passed since the last run, it is a no-op for the rest of the day.
+func accrueInterest(ctx context.Context, interests interest.Service) {
+	runs, err := interests.AccrueDue(context.WithoutCancel(ctx))
+	if err != nil {
+		return // logged by the service, retried on the next tick
+	}
+	for _, run := range runs {
+		slogging.Slogger().Info("interest accrued", "date", run.Date, "accounts", run.Accrued, "capitalized", run.Capitalized)
+	}
+}
+
+// snapshotBalances takes the daily balance snapshot, it is a no-op for the rest of the day.
+func snapshotBalances(ctx context.Context, balances balance.Service) {
+	n, err := balances.Snapshot(context.WithoutCancel(ctx))
+	if err != nil || n == 0 {
+		return // errors are logged by the service, retried on the next tick
+	}
+	slogging.Slogger().Info("balance snapshot taken", "accounts", n)
+}
+
+func withDefaults(cfg config.SchedulerConfig) config.SchedulerConfig {
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = defaultPollInterval
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = defaultBatchSize
+	}
+	if cfg.Lease <= 0 {
+		cfg.Lease = defaultLease
+	}
+	return cfg
+}
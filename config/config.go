@@ -11,9 +11,11 @@ import (
 )
 
 type Config struct {
-	App      AppConfig      `mapstructure:"app"`
-	Http     HTTPConfig     `mapstructure:"http"`
-	Database DatabaseConfig `mapstructure:"database" validate:"required"`
+	App       AppConfig       `mapstructure:"app"`
+	Http      HTTPConfig      `mapstructure:"http"`
+	Storage   StorageConfig   `mapstructure:"storage"`
+	Database  DatabaseConfig  `mapstructure:"database" validate:"-"`
+	Scheduler SchedulerConfig `mapstructure:"scheduler"`
 }
 
 func New() (*Config, error) {
@@ -22,6 +24,11 @@ func New() (*Config, error) {
 	viper.SetConfigType("ini")
 	viper.SetEnvKeyReplacer(strings.NewReplacer(`.`, `_`))
 	viper.AutomaticEnv()
+	// defaults make the keys known to viper, so they can be set by the environment alone
+	viper.SetDefault("storage.driver", PostgresDriver)
+	viper.SetDefault("storage.path", "")
+	viper.SetDefault("database.replica_host", "")
+	viper.SetDefault("database.replica_port", "")
 
 	if err := viper.ReadInConfig(); err != nil {
 		slogging.Slogger().Error("Error reading config file", "error", err.Error())
@@ -37,6 +44,13 @@ func New() (*Config, error) {
 	if err := validate.Struct(config); err != nil {
 		return nil, err
 	}
+	// the memory and sqlite drivers serve the api without the database, commands connecting to it
+	// validate the section in database.New
+	if config.Storage.Driver != MemoryDriver && config.Storage.Driver != SQLiteDriver {
+		if err := config.Database.Validate(); err != nil {
+			return nil, err
+		}
+	}
 	return config, nil
 }
 
@@ -59,6 +73,22 @@ type HTTPConfig struct {
 	} `mapstructure:"cors"`
 }
 
+const (
+	// PostgresDriver stores the data in the configured database, it is the default driver.
+	PostgresDriver = "postgres"
+	// MemoryDriver keeps accounts and transactions in memory, only their endpoints are served.
+	MemoryDriver = "memory"
+	// SQLiteDriver stores accounts and transactions in the sqlite database file, only their endpoints are served.
+	SQLiteDriver = "sqlite"
+)
+
+// StorageConfig selects the backend of the http api repositories.
+type StorageConfig struct {
+	Driver string `mapstructure:"driver" validate:"omitempty,oneof=postgres memory sqlite"`
+	// Path is the database file of the sqlite driver.
+	Path string `mapstructure:"path" validate:"required_if=Driver sqlite"`
+}
+
 type DatabaseConfig struct {
 	Host            string `mapstructure:"host" validate:"required"`
 	Port            string `mapstructure:"port" validate:"required"`
@@ -66,4 +96,45 @@ type DatabaseConfig struct {
 	Password        string `mapstructure:"password" validate:"required"`
 	User            string `mapstructure:"user" validate:"required"`
 	SSLModeDisabled bool   `mapstructure:"sslmode_disabled"`
+	// ReplicaHost is the host of the read replica serving read-only queries, empty for none.
+	// The replica shares the database name and credentials of the primary.
+	ReplicaHost string `mapstructure:"replica_host"`
+	// ReplicaPort is the port of the read replica, it defaults to the port of the primary.
+	ReplicaPort string `mapstructure:"replica_port"`
+	// ConnectTimeout is the time connecting at startup is retried before giving up.
+	ConnectTimeout time.Duration `mapstructure:"connect_timeout"`
+
+	// Pool limits, zero values keep the pgx defaults.
+	MaxConns          int32         `mapstructure:"max_conns" validate:"gte=0"`
+	MinConns          int32         `mapstructure:"min_conns" validate:"gte=0"`
+	MaxConnLifetime   time.Duration `mapstructure:"max_conn_lifetime"`
+	MaxConnIdleTime   time.Duration `mapstructure:"max_conn_idle_time"`
+	HealthCheckPeriod time.Duration `mapstructure:"health_check_period"`
+
+	// Session settings of every connection, zero values keep the server defaults.
+	StatementTimeout time.Duration `mapstructure:"statement_timeout"`
+	LockTimeout      time.Duration `mapstructure:"lock_timeout"`
+	ApplicationName  string        `mapstructure:"application_name"`
+}
+
+// Validate checks that the config has the settings required to connect to the database.
+func (c DatabaseConfig) Validate() error {
+	return validator.New().Struct(c)
+}
+
+// WithoutStatementTimeout returns the config for batch commands, whose statements run as long as the data requires.
+func (c DatabaseConfig) WithoutStatementTimeout() DatabaseConfig {
+	c.StatementTimeout = 0
+	return c
+}
+
+// SchedulerConfig configures the worker executing scheduled transfers.
+type SchedulerConfig struct {
+	// PollInterval is the time between two checks for due schedules.
+	PollInterval time.Duration `mapstructure:"poll_interval"`
+	// BatchSize is the maximum number of schedules claimed by a single check.
+	BatchSize int `mapstructure:"batch_size"`
+	// Lease is the time a claimed schedule is reserved for the worker,
+	// after it expires the schedule can be claimed by another worker.
+	Lease time.Duration `mapstructure:"lease"`
 }
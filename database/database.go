@@ -7,7 +7,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
-	"sync"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/jackc/pgx/v5/stdlib"
@@ -41,99 +41,185 @@ type Service interface {
 	// DB returns the database connection.
 	DB() *sql.DB
 
-	// Pool returns the pgx connection pool.
+	// Pool returns the pgx connection pool of the primary.
 	Pool() *pgxpool.Pool
+
+	// ReadPool returns the pool read-only queries of ctx run on.
+	// It is the replica, unless no replica is configured, ctx requires the primary
+	// or the replica has not replayed the writes of the ctx session yet.
+	ReadPool(ctx context.Context) *pgxpool.Pool
+
+	// Replicated reports whether a read replica is configured.
+	Replicated() bool
 }
 
 type service struct {
 	pool *pgxpool.Pool
+	// replica serves read-only queries, nil when no replica is configured.
+	replica *pgxpool.Pool
 }
 
-var (
-	dbService *service
+const (
+	// defaultConnectTimeout limits the startup retries when the config sets no connect timeout.
+	defaultConnectTimeout = 30 * time.Second
 
-	once sync.Once
+	minRetryBackoff = 250 * time.Millisecond
+	maxRetryBackoff = 5 * time.Second
 )
 
-func New(cfg config.DatabaseConfig) Service {
-	once.Do(func() {
-		logger := slogging.Slogger()
-		logger.Info("creating a new database connection pool...")
+// New creates a connection pool of the database config and of its read replica, if configured.
+// Every call creates new pools, so a process can connect to several databases.
+// Connecting is retried with exponential backoff until the database is reachable,
+// the connect timeout of the config passes or ctx is done.
+func New(ctx context.Context, cfg config.DatabaseConfig) (Service, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid database config: %w", err)
+	}
 
-		ctx := context.Background()
+	timeout := cfg.ConnectTimeout
+	if timeout <= 0 {
+		timeout = defaultConnectTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
 
-		pool, err := pgxpool.New(ctx, dsnFromConfig(cfg))
-		if err != nil {
-			logger.Error("failed to create db connection pool", "error", err)
-			panic(err)
-		}
+	pool, err := connect(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	s := &service{
+		pool: pool,
+	}
 
-		if err = pool.Ping(ctx); err != nil {
-			logger.Error("failed to ping db", "error", err)
-			panic(err)
+	if cfg.ReplicaHost != "" {
+		if s.replica, err = connect(ctx, replicaConfig(cfg)); err != nil {
+			pool.Close()
+			return nil, fmt.Errorf("replica: %w", err)
 		}
+	}
+
+	return s, nil
+}
+
+// connect creates the connection pool of the database config and pings it until the database is reachable.
+func connect(ctx context.Context, cfg config.DatabaseConfig) (*pgxpool.Pool, error) {
+	logger := slogging.Slogger()
+	logger.InfoContext(ctx, "creating a new database connection pool...", "host", cfg.Host, "dbname", cfg.DBName)
+
+	poolCfg, err := poolConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	pool, err := pgxpool.NewWithConfig(ctx, poolCfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create db connection pool: %w", err)
+	}
+
+	if err = ping(ctx, pool); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("failed to connect to db: %w", err)
+	}
+
+	return pool, nil
+}
+
+// replicaConfig returns the config of the replica, it shares the database, credentials and pool settings of the primary.
+func replicaConfig(cfg config.DatabaseConfig) config.DatabaseConfig {
+	cfg.Host = cfg.ReplicaHost
+	if cfg.ReplicaPort != "" {
+		cfg.Port = cfg.ReplicaPort
+	}
+	return cfg
+}
 
-		dbService = &service{
-			pool: pool,
+// ping pings the database until it answers or ctx is done.
+func ping(ctx context.Context, pool *pgxpool.Pool) error {
+	backoff := minRetryBackoff
+	for {
+		err := pool.Ping(ctx)
+		if err == nil {
+			return nil
 		}
-	})
 
-	return dbService
+		slogging.Slogger().WarnContext(ctx, "db is not reachable, retrying", "error", err, "backoff", backoff)
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w, last error: %w", ctx.Err(), err)
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, maxRetryBackoff)
+	}
 }
 
-// Health checks the health of the database connection by pinging the database.
-// It returns a map with keys indicating various health statistics.
+// Health checks the health of the database connections by pinging the primary and the replica.
+// It returns a map with keys indicating various health statistics, the replica keys are prefixed by replica_.
 func (s *service) Health(ctx context.Context) map[string]string {
-	log := slogging.Slogger()
-
 	stats := make(map[string]string)
 
+	poolHealth(ctx, s.pool, "", stats)
+	if s.replica != nil && poolHealth(ctx, s.replica, "replica_", stats) {
+		var lag float64
+		if err := s.replica.QueryRow(ctx, replicaLagSql).Scan(&lag); err == nil {
+			stats["replica_lag"] = time.Duration(lag * float64(time.Second)).String()
+		}
+	}
+
+	return stats
+}
+
+// poolHealth pings the database of the pool and adds the pool statistics to stats with the key prefix.
+// It reports whether the database is up.
+func poolHealth(ctx context.Context, pool *pgxpool.Pool, prefix string, stats map[string]string) bool {
 	// Ping the database
-	err := s.pool.Ping(ctx)
+	err := pool.Ping(ctx)
 	if err != nil {
-		stats["status"] = "down"
-		stats["error"] = fmt.Sprintf("db down: %v", err)
-		log.ErrorContext(ctx, "db is down", "error", err)
-		return stats
+		stats[prefix+"status"] = "down"
+		stats[prefix+"error"] = fmt.Sprintf("db down: %v", err)
+		slogging.Slogger().ErrorContext(ctx, "db is down", "prefix", prefix, "error", err)
+		return false
 	}
 
 	// Database is up, add more statistics
-	stats["status"] = "up"
-	stats["message"] = "It's healthy"
+	stats[prefix+"status"] = "up"
+	stats[prefix+"message"] = "It's healthy"
 
 	// Logger database stats (like open connections, in use, idle, etc.)
-	poolStat := s.pool.Stat()
-	stats["max_connections"] = strconv.Itoa(int(poolStat.MaxConns()))
-	stats["total_connections"] = strconv.Itoa(int(poolStat.TotalConns()))
-	stats["acquired_connections"] = strconv.Itoa(int(poolStat.AcquiredConns()))
-	stats["new_acquired_connections"] = strconv.FormatInt(poolStat.NewConnsCount(), 10)
-	stats["empty_acquire_count"] = strconv.FormatInt(poolStat.EmptyAcquireCount(), 10)
-	stats["canceled_acquire_count"] = strconv.FormatInt(poolStat.CanceledAcquireCount(), 10)
-	stats["acquire_count"] = strconv.FormatInt(poolStat.AcquireCount(), 10)
-	stats["acquire_duration"] = poolStat.AcquireDuration().String()
-	stats["idle_connections"] = strconv.Itoa(int(poolStat.IdleConns()))
-	stats["constructing_connections"] = strconv.Itoa(int(poolStat.ConstructingConns()))
-	stats["max_idle_destroy_count"] = strconv.FormatInt(poolStat.MaxIdleDestroyCount(), 10)
-	stats["max_lifetime_destroy_count"] = strconv.FormatInt(poolStat.MaxLifetimeDestroyCount(), 10)
-
-	// Evaluate stats to provide a health message
-	if poolStat.TotalConns() > 40 { // Assuming 50 is the max for this example
-		stats["message"] = "The database is experiencing heavy load."
+	poolStat := pool.Stat()
+	stats[prefix+"max_connections"] = strconv.Itoa(int(poolStat.MaxConns()))
+	stats[prefix+"min_connections"] = strconv.Itoa(int(pool.Config().MinConns))
+	stats[prefix+"total_connections"] = strconv.Itoa(int(poolStat.TotalConns()))
+	stats[prefix+"acquired_connections"] = strconv.Itoa(int(poolStat.AcquiredConns()))
+	stats[prefix+"new_acquired_connections"] = strconv.FormatInt(poolStat.NewConnsCount(), 10)
+	stats[prefix+"empty_acquire_count"] = strconv.FormatInt(poolStat.EmptyAcquireCount(), 10)
+	stats[prefix+"canceled_acquire_count"] = strconv.FormatInt(poolStat.CanceledAcquireCount(), 10)
+	stats[prefix+"acquire_count"] = strconv.FormatInt(poolStat.AcquireCount(), 10)
+	stats[prefix+"acquire_duration"] = poolStat.AcquireDuration().String()
+	stats[prefix+"idle_connections"] = strconv.Itoa(int(poolStat.IdleConns()))
+	stats[prefix+"constructing_connections"] = strconv.Itoa(int(poolStat.ConstructingConns()))
+	stats[prefix+"max_idle_destroy_count"] = strconv.FormatInt(poolStat.MaxIdleDestroyCount(), 10)
+	stats[prefix+"max_lifetime_destroy_count"] = strconv.FormatInt(poolStat.MaxLifetimeDestroyCount(), 10)
+
+	// Evaluate stats against the configured pool limits to provide a health message
+	maxConns := poolStat.MaxConns()
+	if poolStat.TotalConns() >= maxConns*heavyLoadPercent/100 {
+		stats[prefix+"message"] = "The database is experiencing heavy load."
 	}
 
-	if poolStat.ConstructingConns() > 1000 {
-		stats["message"] = "The database has a high number of wait events, indicating potential bottlenecks."
+	if poolStat.ConstructingConns() > maxConns/2 {
+		stats[prefix+"message"] = "The database has a high number of wait events, indicating potential bottlenecks."
 	}
 
 	if poolStat.MaxIdleDestroyCount() > int64(poolStat.TotalConns())/2 {
-		stats["message"] = "Many idle connections are being closed, consider revising the connection pool settings."
+		stats[prefix+"message"] = "Many idle connections are being closed, consider revising the connection pool settings."
 	}
 
 	if poolStat.MaxLifetimeDestroyCount() > int64(poolStat.TotalConns())/2 {
-		stats["message"] = "Many connections are being closed due to max lifetime, consider increasing max lifetime or revising the connection usage pattern."
+		stats[prefix+"message"] = "Many connections are being closed due to max lifetime, consider increasing max lifetime or revising the connection usage pattern."
 	}
 
-	return stats
+	return true
 }
 
 // Close closes the database connection.
@@ -142,9 +228,56 @@ func (s *service) Health(ctx context.Context) map[string]string {
 func (s *service) Close() error {
 	slogging.Slogger().Info("closing the database connection...")
 	s.pool.Close()
+	if s.replica != nil {
+		s.replica.Close()
+	}
 	return nil
 }
 
+// heavyLoadPercent is the share of the max connections in use from which the pool is reported as heavily loaded.
+const heavyLoadPercent = 80
+
+// poolConfig returns the pool config of the database config, limits and timeouts not set keep the pgx defaults.
+func poolConfig(cfg config.DatabaseConfig) (*pgxpool.Config, error) {
+	poolCfg, err := pgxpool.ParseConfig(dsnFromConfig(cfg))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse db config: %w", err)
+	}
+
+	if cfg.MaxConns > 0 {
+		poolCfg.MaxConns = cfg.MaxConns
+	}
+	if cfg.MinConns > 0 {
+		poolCfg.MinConns = cfg.MinConns
+	}
+	if poolCfg.MinConns > poolCfg.MaxConns {
+		return nil, fmt.Errorf("min conns %d exceed max conns %d", poolCfg.MinConns, poolCfg.MaxConns)
+	}
+	if cfg.MaxConnLifetime > 0 {
+		poolCfg.MaxConnLifetime = cfg.MaxConnLifetime
+	}
+	if cfg.MaxConnIdleTime > 0 {
+		poolCfg.MaxConnIdleTime = cfg.MaxConnIdleTime
+	}
+	if cfg.HealthCheckPeriod > 0 {
+		poolCfg.HealthCheckPeriod = cfg.HealthCheckPeriod
+	}
+
+	// session parameters are sent on connect, so they apply to every statement of the pool
+	params := poolCfg.ConnConfig.RuntimeParams
+	if cfg.StatementTimeout > 0 {
+		params["statement_timeout"] = strconv.FormatInt(cfg.StatementTimeout.Milliseconds(), 10)
+	}
+	if cfg.LockTimeout > 0 {
+		params["lock_timeout"] = strconv.FormatInt(cfg.LockTimeout.Milliseconds(), 10)
+	}
+	if cfg.ApplicationName != "" {
+		params["application_name"] = cfg.ApplicationName
+	}
+
+	return poolCfg, nil
+}
+
 func dsnFromConfig(config config.DatabaseConfig) string {
 	dsn := fmt.Sprintf(
 		"postgresql://%s:%s@%s:%s/%s?sslmode=require",
@@ -2,8 +2,12 @@ package database
 
 import (
 	"context"
+	"errors"
 	"log"
 	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
 
 	"github.com/fmiskovic/cash-me-if-you-can/config"
 	"github.com/fmiskovic/cash-me-if-you-can/pkg/testinfra"
@@ -27,14 +31,55 @@ func TestMain(m *testing.M) {
 }
 
 func TestNew(t *testing.T) {
-	srv := New(dbCfg)
-	if srv == nil {
-		t.Fatal("New() returned nil")
+	srv, err := New(context.Background(), dbCfg)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer srv.Close()
+
+	// every call creates its own pool
+	other, err := New(context.Background(), dbCfg)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer other.Close()
+
+	if srv.Pool() == other.Pool() {
+		t.Fatal("expected New() to create a new pool")
+	}
+}
+
+func TestNewUnreachable(t *testing.T) {
+	cfg := dbCfg
+	cfg.Port = "1"
+	cfg.ConnectTimeout = time.Second
+
+	start := time.Now()
+	_, err := New(context.Background(), cfg)
+	if err == nil {
+		t.Fatal("expected New() to fail")
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second || elapsed > 5*time.Second {
+		t.Fatalf("expected New() to retry until the connect timeout, took %s", elapsed)
+	}
+
+	// a cancelled context stops retrying right away
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err = New(ctx, cfg); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context canceled, got %v", err)
 	}
 }
 
 func TestHealth(t *testing.T) {
-	srv := New(dbCfg)
+	srv, err := New(context.Background(), dbCfg)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer srv.Close()
 
 	stats := srv.Health(context.Background())
 
@@ -52,9 +97,140 @@ func TestHealth(t *testing.T) {
 }
 
 func TestClose(t *testing.T) {
-	srv := New(dbCfg)
+	srv, err := New(context.Background(), dbCfg)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
 
 	if srv.Close() != nil {
 		t.Fatalf("expected Close() to return nil")
 	}
 }
+
+func TestPoolConfig(t *testing.T) {
+	cfg := dbCfg
+	cfg.MaxConns = 8
+	cfg.MinConns = 2
+	cfg.MaxConnLifetime = time.Hour
+	cfg.MaxConnIdleTime = time.Minute
+	cfg.HealthCheckPeriod = 10 * time.Second
+	cfg.StatementTimeout = 1500 * time.Millisecond
+	cfg.LockTimeout = time.Second
+	cfg.ApplicationName = "test"
+
+	poolCfg, err := poolConfig(cfg)
+	if err != nil {
+		t.Fatalf("poolConfig() error = %v", err)
+	}
+	if poolCfg.MaxConns != 8 || poolCfg.MinConns != 2 {
+		t.Fatalf("expected 2-8 connections, got %d-%d", poolCfg.MinConns, poolCfg.MaxConns)
+	}
+	if poolCfg.MaxConnLifetime != time.Hour || poolCfg.MaxConnIdleTime != time.Minute || poolCfg.HealthCheckPeriod != 10*time.Second {
+		t.Fatalf("unexpected connection lifetimes %+v", poolCfg)
+	}
+
+	params := poolCfg.ConnConfig.RuntimeParams
+	if params["statement_timeout"] != "1500" || params["lock_timeout"] != "1000" || params["application_name"] != "test" {
+		t.Fatalf("unexpected runtime params %v", params)
+	}
+
+	// batch commands run without statement timeout
+	poolCfg, err = poolConfig(cfg.WithoutStatementTimeout())
+	if err != nil {
+		t.Fatalf("poolConfig() error = %v", err)
+	}
+	if _, ok := poolCfg.ConnConfig.RuntimeParams["statement_timeout"]; ok {
+		t.Fatalf("expected no statement timeout, got %v", poolCfg.ConnConfig.RuntimeParams)
+	}
+
+	// the settings reach the server
+	srv, err := New(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer srv.Close()
+
+	var timeout string
+	if err = srv.Pool().QueryRow(context.Background(), "SHOW statement_timeout").Scan(&timeout); err != nil {
+		t.Fatalf("failed to show statement timeout: %v", err)
+	}
+	if timeout != "1500ms" {
+		t.Fatalf("expected statement timeout 1500ms, got %s", timeout)
+	}
+
+	if stats := srv.Health(context.Background()); stats["max_connections"] != "8" || stats["min_connections"] != "2" {
+		t.Fatalf("unexpected health stats %v", stats)
+	}
+
+	cfg.MinConns = 10
+	if _, err = poolConfig(cfg); err == nil {
+		t.Fatal("expected min conns above max conns to fail")
+	}
+}
+
+func TestReplica(t *testing.T) {
+	ctx := context.Background()
+
+	// the primary serves as its own replica, it is never behind
+	cfg := dbCfg
+	cfg.ReplicaHost = dbCfg.Host
+
+	srv, err := New(ctx, cfg)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer srv.Close()
+
+	if !srv.Replicated() {
+		t.Fatal("expected the service to be replicated")
+	}
+	if stats := srv.Health(ctx); stats["replica_status"] != "up" || stats["replica_lag"] == "" {
+		t.Fatalf("expected replica health stats, got %v", stats)
+	}
+
+	if srv.ReadPool(ctx) == srv.Pool() {
+		t.Fatal("expected reads to be served by the replica")
+	}
+	if srv.ReadPool(WithPrimary(ctx)) != srv.Pool() {
+		t.Fatal("expected reads to be served by the primary")
+	}
+
+	// committed writes are tracked in the session and the replica is checked against them
+	session := WithSession(ctx, "")
+	if err = NewTxManager(srv).Execute(session, func(tx pgx.Tx) error {
+		_, err := tx.Exec(session, "SELECT 1")
+		return err
+	}); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	token := SessionToken(session)
+	if token == "" {
+		t.Fatal("expected the session to track the write")
+	}
+	if srv.ReadPool(session) == srv.Pool() {
+		t.Fatal("expected reads to be served by the replica which is not behind")
+	}
+
+	// the token continues the session
+	if got := SessionToken(WithSession(ctx, token)); got != token {
+		t.Fatalf("expected token %s, got %s", token, got)
+	}
+	if got := SessionToken(WithSession(ctx, "not a token")); got != "" {
+		t.Fatalf("expected an invalid token to be ignored, got %s", got)
+	}
+}
+
+func TestNotReplicated(t *testing.T) {
+	srv, err := New(context.Background(), dbCfg)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer srv.Close()
+
+	if srv.Replicated() || srv.ReadPool(context.Background()) != srv.Pool() {
+		t.Fatal("expected reads to be served by the primary")
+	}
+	if _, ok := srv.Health(context.Background())["replica_status"]; ok {
+		t.Fatal("expected no replica health stats")
+	}
+}
@@ -0,0 +1,174 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/fmiskovic/cash-me-if-you-can/internal"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/account"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/transaction"
+	"github.com/fmiskovic/cash-me-if-you-can/pkg/errorx"
+)
+
+type AccountRepository struct {
+	store *Store
+}
+
+func NewAccountRepository(store *Store) AccountRepository {
+	return AccountRepository{store: store}
+}
+
+func (r AccountRepository) Get(_ context.Context, id string) (*account.Account, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	acc, err := r.store.account(id)
+	if err != nil {
+		return nil, err
+	}
+
+	a := *acc
+	return &a, nil
+}
+
+func (r AccountRepository) Create(_ context.Context, acc *account.Account) (*account.Account, error) {
+	if acc.Currency == "" {
+		acc.Currency = account.DefaultCurrency
+	}
+	if acc.Product == "" {
+		acc.Product = account.DefaultProduct
+	}
+	if strings.TrimSpace(acc.Owner) == "" {
+		return nil, errorx.NewError(
+			errors.New("owner is blank"),
+			errorx.ErrInvalidInput,
+		)
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.products[acc.Product]; !ok {
+		return nil, errorx.NewError(
+			fmt.Errorf("product %s does not exist", acc.Product),
+			errorx.ErrInvalidInput,
+		)
+	}
+	if _, ok := r.store.owners[acc.Owner]; ok {
+		return nil, errorx.NewError(
+			fmt.Errorf("account with owner %s already exists", acc.Owner),
+			errorx.ErrInvalidInput,
+		)
+	}
+
+	now := r.store.clock.Now()
+	acc.ID = uuid.NewString()
+	acc.Status = account.Active
+	acc.CreatedAt, acc.UpdatedAt = now, now
+
+	stored := *acc
+	r.store.accounts[acc.ID] = &stored
+	r.store.owners[acc.Owner] = acc.ID
+
+	// deposit of the initial balance
+	if acc.Balance != 0 {
+		r.store.insertTransaction(transaction.New(
+			transaction.WithAccountID(acc.ID),
+			transaction.WithType(transaction.Deposit),
+			transaction.WithAmount(acc.Balance),
+			transaction.WithDescription("initial balance"),
+		))
+	}
+
+	return acc, nil
+}
+
+func (r AccountRepository) List(_ context.Context, req account.ListRequest) (internal.Page[account.Account], error) {
+	r.store.mu.RLock()
+	accounts := make([]account.Account, 0, len(r.store.accounts))
+	for _, acc := range r.store.accounts {
+		if matches(acc, req) {
+			accounts = append(accounts, *acc)
+		}
+	}
+	r.store.mu.RUnlock()
+
+	slices.SortFunc(accounts, compareBy(req.SortBy, req.SortOrder))
+
+	count := len(accounts)
+	totalPages := 0
+	if count != 0 && req.Limit != 0 {
+		totalPages = (count + req.Limit - 1) / req.Limit
+	}
+
+	start := min(max(req.Offset, 0), count)
+	end := min(start+req.Limit, count)
+
+	return internal.Page[account.Account]{
+		Items:      accounts[start:end],
+		TotalItems: count,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// matches applies the filters of the request like the account page query.
+func matches(acc *account.Account, req account.ListRequest) bool {
+	switch {
+	case req.Owner != "" && !strings.Contains(strings.ToLower(acc.Owner), strings.ToLower(req.Owner)):
+		return false
+	case req.Status != "" && acc.Status != req.Status:
+		return false
+	case req.Currency != "" && acc.Currency != req.Currency:
+		return false
+	case req.MinBalance != nil && acc.Balance < *req.MinBalance:
+		return false
+	case req.MaxBalance != nil && acc.Balance > *req.MaxBalance:
+		return false
+	case req.CreatedFrom != nil && acc.CreatedAt.Before(*req.CreatedFrom):
+		return false
+	case req.CreatedTo != nil && !acc.CreatedAt.Before(*req.CreatedTo):
+		return false
+	case req.UpdatedSince != nil && !acc.UpdatedAt.After(*req.UpdatedSince):
+		return false
+	}
+	return true
+}
+
+// compareBy orders accounts by the sort field, created at by default, and by id to break ties.
+func compareBy(field account.SortField, order account.SortOrder) func(a, b account.Account) int {
+	return func(a, b account.Account) int {
+		var c int
+		switch field {
+		case account.SortByOwner:
+			c = strings.Compare(a.Owner, b.Owner)
+		case account.SortByBalance:
+			c = cmpFloat(a.Balance, b.Balance)
+		case account.SortByUpdatedAt:
+			c = a.UpdatedAt.Compare(b.UpdatedAt)
+		default:
+			c = a.CreatedAt.Compare(b.CreatedAt)
+		}
+		if order == account.Desc {
+			c = -c
+		}
+		if c == 0 {
+			c = strings.Compare(a.ID, b.ID)
+		}
+		return c
+	}
+}
+
+func cmpFloat(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
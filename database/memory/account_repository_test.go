@@ -0,0 +1,168 @@
+package memory_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fmiskovic/cash-me-if-you-can/database/memory"
+	"github.com/fmiskovic/cash-me-if-you-can/internal"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/account"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/transaction"
+	"github.com/fmiskovic/cash-me-if-you-can/pkg/clock"
+	"github.com/fmiskovic/cash-me-if-you-can/pkg/errorx"
+)
+
+var now = time.Date(2024, time.March, 15, 10, 30, 0, 0, time.UTC)
+
+func assertErrorType(t *testing.T, err error, want errorx.ErrorType) {
+	t.Helper()
+
+	var errx *errorx.Error
+	require.ErrorAs(t, err, &errx)
+	assert.Equal(t, want, errx.Type)
+}
+
+func TestAccountRepositoryCreate(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store := memory.NewStore(clock.Fixed(now))
+	repo := memory.NewAccountRepository(store)
+	transactionRepo := memory.NewTransactionRepository(store)
+
+	acc, err := repo.Create(ctx, account.New(account.WithOwner("Alice"), account.WithBalance(100)))
+	require.NoError(t, err)
+	assert.NotEmpty(t, acc.ID)
+	assert.Equal(t, account.DefaultCurrency, acc.Currency)
+	assert.Equal(t, account.DefaultProduct, acc.Product)
+	assert.Equal(t, account.Active, acc.Status)
+	assert.Equal(t, now, acc.CreatedAt)
+
+	got, err := repo.Get(ctx, acc.ID)
+	require.NoError(t, err)
+	assert.Equal(t, acc, got)
+
+	// the initial balance is booked as a deposit
+	trs, err := transactionRepo.GetByAccountId(ctx, transaction.ListRequest{AccountID: acc.ID})
+	require.NoError(t, err)
+	require.Len(t, trs, 1)
+	assert.Equal(t, transaction.Deposit, trs[0].Type)
+	assert.InDelta(t, 100, trs[0].Amount, 1e-9)
+
+	// the stored account is not shared with the caller
+	got.Balance = 0
+	got, err = repo.Get(ctx, acc.ID)
+	require.NoError(t, err)
+	assert.InDelta(t, 100, got.Balance, 1e-9)
+
+	_, err = repo.Create(ctx, account.New(account.WithOwner("Alice"), account.WithBalance(1)))
+	assertErrorType(t, err, errorx.ErrInvalidInput)
+
+	_, err = repo.Create(ctx, account.New(account.WithOwner("Bob"), account.WithProduct("platinum")))
+	assertErrorType(t, err, errorx.ErrInvalidInput)
+
+	_, err = repo.Get(ctx, "missing")
+	assertErrorType(t, err, errorx.ErrNotFound)
+}
+
+func TestAccountRepositoryList(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	minute := 0
+	store := memory.NewStore(clock.Func(func() time.Time {
+		minute++
+		return now.Add(time.Duration(minute) * time.Minute)
+	}))
+	repo := memory.NewAccountRepository(store)
+
+	for i, owner := range []string{"Charlie", "alice", "Bob", "Alicia"} {
+		_, err := repo.Create(ctx, account.New(
+			account.WithOwner(owner),
+			account.WithBalance(float64(10*(i+1))),
+			account.WithCurrency([]string{"EUR", "USD"}[i%2]),
+		))
+		require.NoError(t, err)
+	}
+
+	minBalance := 20.0
+
+	tests := []struct {
+		name      string
+		req       func(*account.ListRequest)
+		wantOwner []string
+		wantTotal int
+	}{
+		{
+			name:      "created first",
+			req:       func(*account.ListRequest) {},
+			wantOwner: []string{"Charlie", "alice", "Bob", "Alicia"},
+			wantTotal: 4,
+		},
+		{
+			name: "owner substring ignoring case",
+			req: func(req *account.ListRequest) {
+				req.Owner = "ALI"
+				req.SortBy = account.SortByOwner
+			},
+			wantOwner: []string{"Alicia", "alice"},
+			wantTotal: 2,
+		},
+		{
+			name: "currency and balance",
+			req: func(req *account.ListRequest) {
+				req.Currency = "USD"
+				req.MinBalance = &minBalance
+				req.SortBy = account.SortByBalance
+				req.SortOrder = account.Desc
+			},
+			wantOwner: []string{"Alicia", "alice"},
+			wantTotal: 2,
+		},
+		{
+			name: "second page",
+			req: func(req *account.ListRequest) {
+				req.Limit = 3
+				req.Offset = 3
+			},
+			wantOwner: []string{"Alicia"},
+			wantTotal: 4,
+		},
+		{
+			name: "offset past the end",
+			req: func(req *account.ListRequest) {
+				req.Offset = 10
+			},
+			wantOwner: []string{},
+			wantTotal: 4,
+		},
+	}
+
+	for _, tc := range tests {
+		tt := tc
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := account.DefaultListRequest()
+			tt.req(&req)
+
+			page, err := repo.List(ctx, req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantTotal, page.TotalItems)
+
+			owners := []string{}
+			for _, acc := range page.Items {
+				owners = append(owners, acc.Owner)
+			}
+			assert.Equal(t, tt.wantOwner, owners)
+		})
+	}
+
+	page, err := repo.List(ctx, account.ListRequest{PageRequest: internal.PageRequest{Limit: 3}})
+	require.NoError(t, err)
+	assert.Equal(t, 2, page.TotalPages)
+}
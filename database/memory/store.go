@@ -0,0 +1,118 @@
+// Package memory implements the account and transaction repositories without a database,
+// for demos and tests that should not depend on Postgres.
+package memory
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"sync"
+
+	"github.com/google/uuid"
+
+	"github.com/fmiskovic/cash-me-if-you-can/internal/account"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/transaction"
+	"github.com/fmiskovic/cash-me-if-you-can/pkg/clock"
+	"github.com/fmiskovic/cash-me-if-you-can/pkg/errorx"
+)
+
+// products are the products seeded by the database migrations.
+var products = []string{account.DefaultProduct, "savings"}
+
+// Store keeps accounts and transactions in memory. A single lock guards both,
+// so the transactions of an operation and the balance changes they cause are stored together or not at all.
+// Repositories sharing a store see the same data.
+type Store struct {
+	mu    sync.RWMutex
+	clock clock.Clock
+
+	products map[string]struct{}
+	accounts map[string]*account.Account
+	// owners maps the owner to the account id, owners are unique
+	owners map[string]string
+
+	transactions map[string]*transaction.Transaction
+	// byAccount holds the transaction ids of every account in the order they were stored
+	byAccount map[string][]string
+	// references maps the external references to the transaction ids, references are unique per account
+	references map[reference]string
+}
+
+type reference struct {
+	accountID string
+	value     string
+}
+
+func NewStore(clk clock.Clock) *Store {
+	s := &Store{
+		clock:        clk,
+		products:     make(map[string]struct{}, len(products)),
+		accounts:     make(map[string]*account.Account),
+		owners:       make(map[string]string),
+		transactions: make(map[string]*transaction.Transaction),
+		byAccount:    make(map[string][]string),
+		references:   make(map[reference]string),
+	}
+	for _, p := range products {
+		s.products[p] = struct{}{}
+	}
+	return s
+}
+
+// account returns the stored account, the caller must hold the lock.
+func (s *Store) account(id string) (*account.Account, error) {
+	acc, ok := s.accounts[id]
+	if !ok {
+		return nil, errorx.NewError(
+			fmt.Errorf("account with id %s not found", id),
+			errorx.ErrNotFound,
+		)
+	}
+	return acc, nil
+}
+
+// checkReference fails if the account already has a transaction with the external reference of t,
+// the caller must hold the lock.
+func (s *Store) checkReference(t *transaction.Transaction) error {
+	if t.ExternalReference == "" {
+		return nil
+	}
+	if _, ok := s.references[reference{t.AccountID, t.ExternalReference}]; ok {
+		return errorx.NewError(
+			fmt.Errorf("transaction with external reference %s already exists", t.ExternalReference),
+			errorx.ErrConflict,
+		)
+	}
+	return nil
+}
+
+// insertTransaction stores a copy of the transaction and fills in the generated id and timestamps,
+// the caller must hold the write lock and check the reference first.
+func (s *Store) insertTransaction(t *transaction.Transaction) {
+	now := s.clock.Now()
+	t.ID = uuid.NewString()
+	t.Timestamp, t.CreatedAt, t.UpdatedAt = now, now, now
+
+	stored := copyTransaction(t)
+	s.transactions[t.ID] = stored
+	s.byAccount[t.AccountID] = append(s.byAccount[t.AccountID], t.ID)
+	if t.ExternalReference != "" {
+		s.references[reference{t.AccountID, t.ExternalReference}] = t.ID
+	}
+}
+
+// applyBalance changes the balance of the account by delta, the caller must hold the write lock.
+func (s *Store) applyBalance(acc *account.Account, delta float64) {
+	acc.Balance += delta
+	acc.UpdatedAt = s.clock.Now()
+}
+
+// copyTransaction returns a copy not sharing the metadata with the original.
+func copyTransaction(t *transaction.Transaction) *transaction.Transaction {
+	c := *t
+	c.Metadata = maps.Clone(t.Metadata)
+	c.Fee = nil
+	return &c
+}
+
+var errNilTransaction = errorx.NewError(errors.New("transaction input is nil"), errorx.ErrInvalidInput)
@@ -0,0 +1,167 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/fmiskovic/cash-me-if-you-can/internal/transaction"
+	"github.com/fmiskovic/cash-me-if-you-can/pkg/errorx"
+)
+
+// TransactionRepository books transactions against the accounts of its store.
+// Products have no fee rules in memory, so no fees are charged.
+type TransactionRepository struct {
+	store *Store
+}
+
+func NewTransactionRepository(store *Store) TransactionRepository {
+	return TransactionRepository{store: store}
+}
+
+func (r TransactionRepository) Create(_ context.Context, t *transaction.Transaction) (*transaction.Transaction, error) {
+	if t == nil {
+		return nil, errNilTransaction
+	}
+	if t.Amount <= 0 {
+		return nil, errorx.NewError(
+			errors.New("invalid amount"),
+			errorx.ErrInvalidInput,
+		)
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	acc, err := r.store.account(t.AccountID)
+	if err != nil {
+		return t, err
+	}
+
+	delta := t.Amount
+	if t.Type.IsDebit() {
+		delta = -t.Amount
+	}
+
+	// fail if account does not have enough funds
+	if acc.Balance+delta < 0 {
+		return t, errorx.NewError(
+			fmt.Errorf("%s failed - insufficient funds", t.Type),
+			errorx.ErrInvalidInput,
+		)
+	}
+	if err = r.store.checkReference(t); err != nil {
+		return t, err
+	}
+
+	r.store.applyBalance(acc, delta)
+	r.store.insertTransaction(t)
+
+	return t, nil
+}
+
+func (r TransactionRepository) Transfer(_ context.Context, from *transaction.Transaction, to *transaction.Transaction) error {
+	if from == nil || to == nil {
+		return errNilTransaction
+	}
+	if from.Amount <= 0 || to.Amount <= 0 {
+		return errorx.NewError(
+			errors.New("invalid amount"),
+			errorx.ErrInvalidInput,
+		)
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	accFrom, err := r.store.account(from.AccountID)
+	if err != nil {
+		return err
+	}
+	accTo, err := r.store.account(to.AccountID)
+	if err != nil {
+		return err
+	}
+
+	// transfers between different currencies are not supported
+	if accFrom.Currency != accTo.Currency {
+		return errorx.NewError(
+			errors.New("transfer failed - currency mismatch"),
+			errorx.ErrInvalidInput,
+		)
+	}
+
+	// check if account has enough funds to make a transfer
+	if accFrom.Balance < from.Amount {
+		return errorx.NewError(
+			errors.New("transfer failed - insufficient funds"),
+			errorx.ErrInvalidInput,
+		)
+	}
+
+	// every check passes before anything is stored, so the transfer is applied completely or not at all
+	if err = r.store.checkReference(from); err != nil {
+		return err
+	}
+	if err = r.store.checkReference(to); err != nil {
+		return err
+	}
+
+	r.store.applyBalance(accFrom, -from.Amount)
+	r.store.applyBalance(accTo, to.Amount)
+	r.store.insertTransaction(from)
+	r.store.insertTransaction(to)
+
+	return nil
+}
+
+func (r TransactionRepository) GetByAccountId(_ context.Context, req transaction.ListRequest) ([]transaction.Transaction, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	if _, ok := r.store.accounts[req.AccountID]; !ok {
+		return nil, errorx.NewError(
+			errors.New("account not found"),
+			errorx.ErrNotFound,
+		)
+	}
+
+	var trs []transaction.Transaction
+	for _, id := range r.store.byAccount[req.AccountID] {
+		t := r.store.transactions[id]
+		switch {
+		case req.CreatedFrom != nil && t.CreatedAt.Before(*req.CreatedFrom):
+			continue
+		case req.CreatedTo != nil && !t.CreatedAt.Before(*req.CreatedTo):
+			continue
+		case req.UpdatedSince != nil && !t.UpdatedAt.After(*req.UpdatedSince):
+			continue
+		case req.ExternalReference != "" && t.ExternalReference != req.ExternalReference:
+			continue
+		}
+		trs = append(trs, *copyTransaction(t))
+	}
+
+	// latest first, transactions stored at the same time in reverse insertion order
+	slices.Reverse(trs)
+	slices.SortStableFunc(trs, func(a, b transaction.Transaction) int {
+		return b.Timestamp.Compare(a.Timestamp)
+	})
+
+	return trs, nil
+}
+
+func (r TransactionRepository) GetById(_ context.Context, id string) (*transaction.Transaction, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	t, ok := r.store.transactions[id]
+	if !ok {
+		return nil, errorx.NewError(
+			fmt.Errorf("transaction with id %s not found", id),
+			errorx.ErrNotFound,
+		)
+	}
+	return copyTransaction(t), nil
+}
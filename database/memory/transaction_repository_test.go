@@ -0,0 +1,205 @@
+package memory_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fmiskovic/cash-me-if-you-can/database/memory"
+	"github.com/fmiskovic/cash-me-if-you-can/database/repotest"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/account"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/transaction"
+	"github.com/fmiskovic/cash-me-if-you-can/pkg/clock"
+	"github.com/fmiskovic/cash-me-if-you-can/pkg/errorx"
+)
+
+// newAccounts creates accounts of the owners with the balance and returns their ids.
+func newAccounts(t *testing.T, repo memory.AccountRepository, balance float64, owners ...string) []string {
+	t.Helper()
+
+	ids := make([]string, len(owners))
+	for i, owner := range owners {
+		acc, err := repo.Create(context.Background(), account.New(account.WithOwner(owner), account.WithBalance(balance)))
+		require.NoError(t, err)
+		ids[i] = acc.ID
+	}
+	return ids
+}
+
+func TestTransactionRepositoryCreate(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store := memory.NewStore(clock.System())
+	accountRepo := memory.NewAccountRepository(store)
+	repo := memory.NewTransactionRepository(store)
+
+	id := newAccounts(t, accountRepo, 50, "Alice")[0]
+
+	created, err := repo.Create(ctx, transaction.New(
+		transaction.WithAccountID(id),
+		transaction.WithType(transaction.Withdrawal),
+		transaction.WithAmount(20),
+		transaction.WithExternalReference("ref-1"),
+		transaction.WithMetadata(map[string]any{"order": "1"}),
+	))
+	require.NoError(t, err)
+	assert.NotEmpty(t, created.ID)
+
+	got, err := repo.GetById(ctx, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, created, got)
+
+	acc, err := accountRepo.Get(ctx, id)
+	require.NoError(t, err)
+	assert.InDelta(t, 30, acc.Balance, 1e-9)
+
+	_, err = repo.Create(ctx, transaction.New(
+		transaction.WithAccountID(id),
+		transaction.WithType(transaction.Deposit),
+		transaction.WithAmount(1),
+		transaction.WithExternalReference("ref-1"),
+	))
+	assertErrorType(t, err, errorx.ErrConflict)
+
+	_, err = repo.Create(ctx, transaction.New(
+		transaction.WithAccountID(id),
+		transaction.WithType(transaction.Withdrawal),
+		transaction.WithAmount(31),
+	))
+	assertErrorType(t, err, errorx.ErrInvalidInput)
+
+	_, err = repo.Create(ctx, transaction.New(
+		transaction.WithAccountID("missing"),
+		transaction.WithType(transaction.Deposit),
+		transaction.WithAmount(1),
+	))
+	assertErrorType(t, err, errorx.ErrNotFound)
+
+	// failed transactions leave the balance and history untouched
+	acc, err = accountRepo.Get(ctx, id)
+	require.NoError(t, err)
+	assert.InDelta(t, 30, acc.Balance, 1e-9)
+
+	trs, err := repo.GetByAccountId(ctx, transaction.ListRequest{AccountID: id})
+	require.NoError(t, err)
+	require.Len(t, trs, 2)
+	assert.Equal(t, created.ID, trs[0].ID, "latest first")
+
+	trs, err = repo.GetByAccountId(ctx, transaction.ListRequest{AccountID: id, ExternalReference: "ref-1"})
+	require.NoError(t, err)
+	assert.Len(t, trs, 1)
+
+	_, err = repo.GetByAccountId(ctx, transaction.ListRequest{AccountID: "missing"})
+	assertErrorType(t, err, errorx.ErrNotFound)
+
+	_, err = repo.GetById(ctx, "missing")
+	assertErrorType(t, err, errorx.ErrNotFound)
+}
+
+func TestTransactionRepositoryTransfer(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store := memory.NewStore(clock.System())
+	accountRepo := memory.NewAccountRepository(store)
+	repo := memory.NewTransactionRepository(store)
+
+	ids := newAccounts(t, accountRepo, 100, "Alice", "Bob")
+	euro, err := accountRepo.Create(ctx, account.New(
+		account.WithOwner("Charlie"),
+		account.WithBalance(100),
+		account.WithCurrency("EUR"),
+	))
+	require.NoError(t, err)
+
+	transfer := func(from, to string, amount float64) error {
+		legFrom, legTo := transaction.NewTransferLegs(transaction.TransferRequest{
+			FromAccountID: from,
+			ToAccountID:   to,
+			Amount:        amount,
+		})
+		return repo.Transfer(ctx, legFrom, legTo)
+	}
+	balance := func(id string) float64 {
+		acc, err := accountRepo.Get(ctx, id)
+		require.NoError(t, err)
+		return acc.Balance
+	}
+
+	require.NoError(t, transfer(ids[0], ids[1], 40))
+	assert.InDelta(t, 60, balance(ids[0]), 1e-9)
+	assert.InDelta(t, 140, balance(ids[1]), 1e-9)
+
+	assertErrorType(t, transfer(ids[0], ids[1], 61), errorx.ErrInvalidInput)
+	assertErrorType(t, transfer(ids[0], euro.ID, 1), errorx.ErrInvalidInput)
+	assertErrorType(t, transfer(ids[0], "missing", 1), errorx.ErrNotFound)
+
+	// failed transfers change nothing
+	assert.InDelta(t, 60, balance(ids[0]), 1e-9)
+	assert.InDelta(t, 140, balance(ids[1]), 1e-9)
+	assert.InDelta(t, 100, balance(euro.ID), 1e-9)
+}
+
+func TestTransactionRepositoryConcurrency(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store := memory.NewStore(clock.System())
+	accountRepo := memory.NewAccountRepository(store)
+	repo := memory.NewTransactionRepository(store)
+
+	ids := newAccounts(t, accountRepo, 100, "Alice", "Bob", "Charlie")
+
+	// concurrent transfers in every direction and withdrawals competing for the same funds
+	var wg sync.WaitGroup
+	for i := 0; i < 300; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			from, to := transaction.NewTransferLegs(transaction.TransferRequest{
+				FromAccountID: ids[i%3],
+				ToAccountID:   ids[(i+1)%3],
+				Amount:        1,
+			})
+			_ = repo.Transfer(ctx, from, to)
+		}()
+		go func() {
+			defer wg.Done()
+			_, _ = repo.Create(ctx, transaction.New(
+				transaction.WithAccountID(ids[i%3]),
+				transaction.WithType(transaction.Withdrawal),
+				transaction.WithAmount(2),
+			))
+		}()
+	}
+	wg.Wait()
+
+	// no update is lost, every balance is the sum of its transactions and never negative
+	for _, id := range ids {
+		acc, err := accountRepo.Get(ctx, id)
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, acc.Balance, 0.0)
+
+		trs, err := repo.GetByAccountId(ctx, transaction.ListRequest{AccountID: id})
+		require.NoError(t, err)
+
+		sum := 0.0
+		for _, tr := range trs {
+			if tr.Type.IsDebit() {
+				sum -= tr.Amount
+			} else {
+				sum += tr.Amount
+			}
+		}
+		assert.InDelta(t, sum, acc.Balance, 1e-9)
+	}
+}
+
+func TestRepositoryConformance(t *testing.T) {
+	store := memory.NewStore(clock.System())
+	repotest.Run(t, memory.NewAccountRepository(store), memory.NewTransactionRepository(store))
+}
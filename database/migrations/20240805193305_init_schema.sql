@@ -21,5 +21,16 @@ CREATE INDEX idx_transactions_account_id ON transactions(account_id);
 
 -- +goose Down
 -- +goose StatementBegin
-DROP TABLE IF EXISTS accounts, transactions;
+-- rolling back never deletes money data, the tables must be emptied on purpose first
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM transactions) OR EXISTS (SELECT 1 FROM accounts) THEN
+        RAISE EXCEPTION 'accounts and transactions are not empty, they are not dropped';
+    END IF;
+END
+$$;
+
+-- transactions reference accounts, so they are dropped first
+DROP TABLE IF EXISTS transactions;
+DROP TABLE IF EXISTS accounts;
 -- +goose StatementEnd
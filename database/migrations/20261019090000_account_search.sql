@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+ALTER TABLE accounts
+    ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'USD',
+    ADD COLUMN IF NOT EXISTS status VARCHAR(15) NOT NULL DEFAULT 'active';
+
+-- supports case-insensitive substring search on owner
+CREATE INDEX IF NOT EXISTS idx_accounts_owner_trgm ON accounts USING GIN (owner gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_accounts_created_at ON accounts(created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_accounts_created_at;
+DROP INDEX IF EXISTS idx_accounts_owner_trgm;
+
+ALTER TABLE accounts
+    DROP COLUMN IF EXISTS status,
+    DROP COLUMN IF EXISTS currency;
+-- +goose StatementEnd
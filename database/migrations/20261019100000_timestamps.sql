@@ -0,0 +1,68 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION set_updated_at() RETURNS TRIGGER AS $$
+BEGIN
+    NEW.updated_at = NOW();
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+UPDATE accounts SET created_at = NOW() WHERE created_at IS NULL;
+
+ALTER TABLE accounts
+    ALTER COLUMN created_at SET NOT NULL,
+    ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP WITH TIME ZONE;
+
+UPDATE accounts SET updated_at = created_at;
+
+ALTER TABLE accounts
+    ALTER COLUMN updated_at SET NOT NULL,
+    ALTER COLUMN updated_at SET DEFAULT NOW();
+
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS created_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN IF NOT EXISTS updated_at TIMESTAMP WITH TIME ZONE;
+
+UPDATE transactions SET
+    created_at = COALESCE(timestamp, NOW()),
+    updated_at = COALESCE(timestamp, NOW());
+
+ALTER TABLE transactions
+    ALTER COLUMN created_at SET NOT NULL,
+    ALTER COLUMN created_at SET DEFAULT NOW(),
+    ALTER COLUMN updated_at SET NOT NULL,
+    ALTER COLUMN updated_at SET DEFAULT NOW();
+
+CREATE TRIGGER accounts_set_updated_at
+    BEFORE UPDATE ON accounts
+    FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+CREATE TRIGGER transactions_set_updated_at
+    BEFORE UPDATE ON transactions
+    FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+-- supports change-data feeds polling by modification time
+CREATE INDEX IF NOT EXISTS idx_accounts_updated_at ON accounts(updated_at);
+CREATE INDEX IF NOT EXISTS idx_transactions_created_at ON transactions(created_at);
+CREATE INDEX IF NOT EXISTS idx_transactions_updated_at ON transactions(updated_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_transactions_updated_at;
+DROP INDEX IF EXISTS idx_transactions_created_at;
+DROP INDEX IF EXISTS idx_accounts_updated_at;
+
+DROP TRIGGER IF EXISTS transactions_set_updated_at ON transactions;
+DROP TRIGGER IF EXISTS accounts_set_updated_at ON accounts;
+
+ALTER TABLE transactions
+    DROP COLUMN IF EXISTS updated_at,
+    DROP COLUMN IF EXISTS created_at;
+
+ALTER TABLE accounts
+    DROP COLUMN IF EXISTS updated_at,
+    ALTER COLUMN created_at DROP NOT NULL;
+
+DROP FUNCTION IF EXISTS set_updated_at();
+-- +goose StatementEnd
@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE transactions
+    ADD COLUMN IF NOT EXISTS description VARCHAR(255),
+    ADD COLUMN IF NOT EXISTS external_reference VARCHAR(64),
+    ADD COLUMN IF NOT EXISTS counterparty VARCHAR(255),
+    ADD COLUMN IF NOT EXISTS metadata JSONB,
+    ADD CONSTRAINT transactions_metadata_check
+        CHECK (metadata IS NULL OR (jsonb_typeof(metadata) = 'object' AND pg_column_size(metadata) <= 8192));
+
+-- external references are used to deduplicate transactions of an account
+CREATE UNIQUE INDEX IF NOT EXISTS transactions_account_id_external_reference_key
+    ON transactions(account_id, external_reference)
+    WHERE external_reference IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS transactions_account_id_external_reference_key;
+
+ALTER TABLE transactions
+    DROP CONSTRAINT IF EXISTS transactions_metadata_check,
+    DROP COLUMN IF EXISTS metadata,
+    DROP COLUMN IF EXISTS counterparty,
+    DROP COLUMN IF EXISTS external_reference,
+    DROP COLUMN IF EXISTS description;
+-- +goose StatementEnd
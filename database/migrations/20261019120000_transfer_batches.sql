@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS transfer_batches (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    mode VARCHAR(15) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS transfer_batch_items (
+    batch_id UUID NOT NULL REFERENCES transfer_batches(id) ON DELETE CASCADE,
+    position INT NOT NULL,
+    from_account_id UUID NOT NULL,
+    to_account_id UUID NOT NULL,
+    amount DECIMAL(38, 16) NOT NULL,
+    status VARCHAR(15) NOT NULL,
+    error TEXT,
+    from_transaction_id UUID REFERENCES transactions(id),
+    to_transaction_id UUID REFERENCES transactions(id),
+    PRIMARY KEY (batch_id, position)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS transfer_batch_items;
+DROP TABLE IF EXISTS transfer_batches;
+-- +goose StatementEnd
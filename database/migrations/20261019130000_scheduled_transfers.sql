@@ -0,0 +1,47 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS scheduled_transfers (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    from_account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    to_account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    amount DECIMAL(38, 16) NOT NULL CHECK (amount > 0),
+    description VARCHAR(255),
+    start_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    frequency VARCHAR(10) NOT NULL DEFAULT '',
+    interval_count INT NOT NULL DEFAULT 1 CHECK (interval_count > 0),
+    until TIMESTAMP WITH TIME ZONE,
+    max_runs INT NOT NULL DEFAULT 0,
+    runs INT NOT NULL DEFAULT 0,
+    next_run_at TIMESTAMP WITH TIME ZONE,
+    status VARCHAR(15) NOT NULL DEFAULT 'active',
+    claimed_until TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CHECK (from_account_id <> to_account_id)
+);
+
+CREATE INDEX IF NOT EXISTS scheduled_transfers_next_run_at_idx
+    ON scheduled_transfers (next_run_at) WHERE status = 'active';
+CREATE INDEX IF NOT EXISTS scheduled_transfers_from_account_id_idx ON scheduled_transfers (from_account_id);
+CREATE INDEX IF NOT EXISTS scheduled_transfers_to_account_id_idx ON scheduled_transfers (to_account_id);
+
+CREATE TRIGGER scheduled_transfers_set_updated_at
+    BEFORE UPDATE ON scheduled_transfers
+    FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+CREATE TABLE IF NOT EXISTS scheduled_transfer_executions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    schedule_id UUID NOT NULL REFERENCES scheduled_transfers(id) ON DELETE CASCADE,
+    scheduled_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    executed_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    status VARCHAR(15) NOT NULL,
+    error TEXT,
+    UNIQUE (schedule_id, scheduled_at)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS scheduled_transfer_executions;
+DROP TABLE IF EXISTS scheduled_transfers;
+-- +goose StatementEnd
@@ -0,0 +1,57 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS products (
+    code VARCHAR(30) PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    annual_rate DECIMAL(9, 6) NOT NULL DEFAULT 0 CHECK (annual_rate >= 0),
+    day_count VARCHAR(10) NOT NULL DEFAULT 'act/365'
+        CHECK (day_count IN ('act/365', 'act/360', 'act/act', '30/360')),
+    capitalization VARCHAR(10) NOT NULL DEFAULT 'monthly'
+        CHECK (capitalization IN ('daily', 'monthly', 'quarterly', 'yearly')),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE TRIGGER products_set_updated_at
+    BEFORE UPDATE ON products
+    FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+INSERT INTO products (code, name, annual_rate) VALUES
+    ('current', 'Current account', 0),
+    ('savings', 'Savings account', 0.025)
+ON CONFLICT (code) DO NOTHING;
+
+ALTER TABLE accounts
+    ADD COLUMN IF NOT EXISTS product VARCHAR(30) NOT NULL DEFAULT 'current' REFERENCES products(code);
+
+-- daily accrued interest, capitalized when transaction_id is set
+CREATE TABLE IF NOT EXISTS interest_accruals (
+    account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    accrual_date DATE NOT NULL,
+    balance DECIMAL(38, 16) NOT NULL,
+    annual_rate DECIMAL(9, 6) NOT NULL,
+    day_count VARCHAR(10) NOT NULL,
+    amount DECIMAL(38, 16) NOT NULL,
+    transaction_id UUID REFERENCES transactions(id),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (account_id, accrual_date)
+);
+
+CREATE INDEX IF NOT EXISTS interest_accruals_uncapitalized_idx
+    ON interest_accruals (account_id, accrual_date) WHERE transaction_id IS NULL;
+
+-- completed accrual runs, one per accrual date
+CREATE TABLE IF NOT EXISTS interest_runs (
+    accrual_date DATE PRIMARY KEY,
+    accounts INT NOT NULL,
+    completed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS interest_runs;
+DROP TABLE IF EXISTS interest_accruals;
+ALTER TABLE accounts DROP COLUMN IF EXISTS product;
+DROP TABLE IF EXISTS products;
+-- +goose StatementEnd
@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS fee_rules (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    product VARCHAR(30) NOT NULL REFERENCES products(code) ON DELETE CASCADE,
+    operation VARCHAR(15) NOT NULL CHECK (operation IN ('deposit', 'withdrawal', 'transfer')),
+    kind VARCHAR(10) NOT NULL CHECK (kind IN ('flat', 'percent', 'tiered')),
+    amount DECIMAL(38, 16) NOT NULL DEFAULT 0 CHECK (amount >= 0),
+    rate DECIMAL(9, 6) NOT NULL DEFAULT 0 CHECK (rate >= 0),
+    tiers JSONB CHECK (tiers IS NULL OR jsonb_typeof(tiers) = 'array'),
+    min_fee DECIMAL(38, 16) CHECK (min_fee >= 0),
+    max_fee DECIMAL(38, 16) CHECK (max_fee >= 0),
+    revenue_account_id UUID NOT NULL REFERENCES accounts(id),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (product, operation),
+    CHECK (kind <> 'tiered' OR tiers IS NOT NULL),
+    CHECK (min_fee IS NULL OR max_fee IS NULL OR min_fee <= max_fee)
+);
+
+CREATE TRIGGER fee_rules_set_updated_at
+    BEFORE UPDATE ON fee_rules
+    FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS fee_rules;
+-- +goose StatementEnd
@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- statements read the movements of an account in booking order
+CREATE INDEX IF NOT EXISTS idx_transactions_account_id_created_at ON transactions(account_id, created_at, id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_transactions_account_id_created_at;
+-- +goose StatementEnd
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+-- balance of an account at a point in time, historical balances are computed from the nearest snapshot
+CREATE TABLE IF NOT EXISTS balance_snapshots (
+    account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    taken_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    balance DECIMAL(38, 16) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (account_id, taken_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_balance_snapshots_taken_at ON balance_snapshots(taken_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS balance_snapshots;
+-- +goose StatementEnd
@@ -0,0 +1,54 @@
+-- +goose Up
+-- +goose StatementBegin
+-- a reconciliation run imports an external bank statement of an account
+CREATE TABLE IF NOT EXISTS reconciliation_runs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    format VARCHAR(10) NOT NULL CHECK (format IN ('csv', 'camt.053')),
+    amount_tolerance DECIMAL(38, 16) NOT NULL DEFAULT 0 CHECK (amount_tolerance >= 0),
+    date_tolerance INTERVAL NOT NULL DEFAULT '0',
+    -- transactions booked within the period are reconciled against the statement lines
+    period_start TIMESTAMP WITH TIME ZONE NOT NULL,
+    period_end TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CHECK (period_start <= period_end)
+);
+
+CREATE INDEX IF NOT EXISTS reconciliation_runs_account_id_idx ON reconciliation_runs (account_id);
+
+CREATE TRIGGER reconciliation_runs_set_updated_at
+    BEFORE UPDATE ON reconciliation_runs
+    FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+-- staged lines of the imported statement, a matched line points to the transaction it settles
+CREATE TABLE IF NOT EXISTS reconciliation_lines (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    run_id UUID NOT NULL REFERENCES reconciliation_runs(id) ON DELETE CASCADE,
+    line_no INT NOT NULL,
+    booked_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    amount DECIMAL(38, 16) NOT NULL,
+    reference VARCHAR(255),
+    description TEXT,
+    transaction_id UUID REFERENCES transactions(id) ON DELETE SET NULL,
+    match_kind VARCHAR(10) CHECK (match_kind IN ('auto', 'manual')),
+    matched_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (run_id, line_no)
+);
+
+-- a transaction settles at most one statement line
+CREATE UNIQUE INDEX IF NOT EXISTS reconciliation_lines_transaction_id_key
+    ON reconciliation_lines (transaction_id) WHERE transaction_id IS NOT NULL;
+
+CREATE TRIGGER reconciliation_lines_set_updated_at
+    BEFORE UPDATE ON reconciliation_lines
+    FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS reconciliation_lines;
+DROP TABLE IF EXISTS reconciliation_runs;
+-- +goose StatementEnd
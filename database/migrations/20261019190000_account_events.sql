@@ -0,0 +1,88 @@
+-- +goose Up
+-- +goose StatementBegin
+-- append-only log of account activity, streamed to clients that resume from the last event id they have seen
+CREATE TABLE IF NOT EXISTS account_events (
+    id BIGSERIAL PRIMARY KEY,
+    account_id UUID NOT NULL,
+    type VARCHAR(15) NOT NULL CHECK (type IN ('transaction', 'balance')),
+    data JSONB NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS account_events_account_id_idx ON account_events (account_id, id);
+
+-- listeners are notified once per account and statement, the notification is delivered on commit
+CREATE OR REPLACE FUNCTION notify_account_events() RETURNS TRIGGER AS $$
+BEGIN
+    PERFORM pg_notify('account_events', account_id::text)
+    FROM (SELECT DISTINCT account_id FROM inserted) AS accounts;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER account_events_notify
+    AFTER INSERT ON account_events
+    REFERENCING NEW TABLE AS inserted
+    FOR EACH STATEMENT EXECUTE FUNCTION notify_account_events();
+
+-- every new transaction is recorded with the fields of the transaction details response
+CREATE OR REPLACE FUNCTION record_transaction_events() RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO account_events (account_id, type, data)
+    SELECT account_id, 'transaction', jsonb_strip_nulls(jsonb_build_object(
+        'transaction_id', id,
+        'account_id', account_id,
+        'type', type,
+        'amount', amount::float8,
+        'timestamp', timestamp,
+        'created_at', created_at,
+        'updated_at', updated_at,
+        'description', description,
+        'external_reference', external_reference,
+        'counterparty', counterparty,
+        'metadata', metadata
+    ))
+    FROM inserted
+    ORDER BY created_at, id;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER transactions_record_events
+    AFTER INSERT ON transactions
+    REFERENCING NEW TABLE AS inserted
+    FOR EACH STATEMENT EXECUTE FUNCTION record_transaction_events();
+
+-- balance changes are recorded with the balance before and after the change
+CREATE OR REPLACE FUNCTION record_balance_events() RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO account_events (account_id, type, data)
+    SELECT n.id, 'balance', jsonb_build_object(
+        'account_id', n.id,
+        'currency', n.currency,
+        'balance', n.balance::float8,
+        'previous_balance', o.balance::float8
+    )
+    FROM updated AS n
+    JOIN previous AS o ON o.id = n.id
+    WHERE n.balance <> o.balance
+    ORDER BY n.id;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER accounts_record_balance_events
+    AFTER UPDATE ON accounts
+    REFERENCING OLD TABLE AS previous NEW TABLE AS updated
+    FOR EACH STATEMENT EXECUTE FUNCTION record_balance_events();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER IF EXISTS accounts_record_balance_events ON accounts;
+DROP TRIGGER IF EXISTS transactions_record_events ON transactions;
+DROP FUNCTION IF EXISTS record_balance_events();
+DROP FUNCTION IF EXISTS record_transaction_events();
+DROP TABLE IF EXISTS account_events;
+DROP FUNCTION IF EXISTS notify_account_events();
+-- +goose StatementEnd
@@ -0,0 +1,208 @@
+-- +goose Up
+-- +goose StatementBegin
+-- references to transactions are kept as plain ids, archived transactions leave the database
+ALTER TABLE transfer_batch_items
+    DROP CONSTRAINT IF EXISTS transfer_batch_items_from_transaction_id_fkey,
+    DROP CONSTRAINT IF EXISTS transfer_batch_items_to_transaction_id_fkey;
+ALTER TABLE interest_accruals DROP CONSTRAINT IF EXISTS interest_accruals_transaction_id_fkey;
+ALTER TABLE reconciliation_lines DROP CONSTRAINT IF EXISTS reconciliation_lines_transaction_id_fkey;
+
+DROP TRIGGER IF EXISTS transactions_record_events ON transactions;
+DROP TRIGGER IF EXISTS transactions_set_updated_at ON transactions;
+
+-- index names are unique per schema, the indexes of the old table are dropped before the new ones are created
+ALTER TABLE transactions RENAME TO transactions_unpartitioned;
+ALTER TABLE transactions_unpartitioned DROP CONSTRAINT transactions_pkey;
+DROP INDEX IF EXISTS idx_transactions_account_id;
+DROP INDEX IF EXISTS idx_transactions_created_at;
+DROP INDEX IF EXISTS idx_transactions_updated_at;
+DROP INDEX IF EXISTS idx_transactions_account_id_created_at;
+DROP INDEX IF EXISTS transactions_account_id_external_reference_key;
+
+-- monthly range partitions on the booking time, the primary key has to include it
+CREATE TABLE transactions (
+    id UUID NOT NULL DEFAULT gen_random_uuid(),
+    account_id UUID NOT NULL REFERENCES accounts(id),
+    amount DECIMAL(38, 16) NOT NULL,
+    type VARCHAR(15) NOT NULL,
+    timestamp TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    description VARCHAR(255),
+    external_reference VARCHAR(64),
+    counterparty VARCHAR(255),
+    metadata JSONB,
+    CONSTRAINT transactions_metadata_check
+        CHECK (metadata IS NULL OR (jsonb_typeof(metadata) = 'object' AND pg_column_size(metadata) <= 8192)),
+    PRIMARY KEY (id, timestamp)
+) PARTITION BY RANGE (timestamp);
+
+-- transactions booked in a month without partition are kept here until the partition is created
+CREATE TABLE transactions_default PARTITION OF transactions DEFAULT;
+
+CREATE INDEX idx_transactions_account_id ON transactions(account_id);
+CREATE INDEX idx_transactions_created_at ON transactions(created_at);
+CREATE INDEX idx_transactions_updated_at ON transactions(updated_at);
+CREATE INDEX idx_transactions_account_id_created_at ON transactions(account_id, created_at, id);
+
+-- creates the partition of the month in UTC, transactions of the month in the default partition are moved into it.
+-- It returns the name of the created partition or NULL if it exists.
+CREATE OR REPLACE FUNCTION create_transactions_partition(month DATE) RETURNS TEXT AS $$
+DECLARE
+    range_start TIMESTAMP WITH TIME ZONE := date_trunc('month', month::timestamp) AT TIME ZONE 'UTC';
+    range_end TIMESTAMP WITH TIME ZONE := (date_trunc('month', month::timestamp) + INTERVAL '1 month') AT TIME ZONE 'UTC';
+    partition_name TEXT := 'transactions_' || to_char(month, 'YYYY_MM');
+BEGIN
+    IF to_regclass(partition_name) IS NOT NULL THEN
+        RETURN NULL;
+    END IF;
+
+    EXECUTE format('CREATE TABLE %I (LIKE transactions INCLUDING DEFAULTS INCLUDING CONSTRAINTS)', partition_name);
+    EXECUTE format(
+        'WITH moved AS (DELETE FROM transactions_default WHERE timestamp >= $1 AND timestamp < $2 RETURNING *) '
+            'INSERT INTO %I SELECT * FROM moved',
+        partition_name
+    ) USING range_start, range_end;
+    EXECUTE format(
+        'ALTER TABLE transactions ATTACH PARTITION %I FOR VALUES FROM (%L) TO (%L)',
+        partition_name, range_start, range_end
+    );
+
+    RETURN partition_name;
+END;
+$$ LANGUAGE plpgsql;
+
+-- partitions of the booked months until three months ahead
+SELECT create_transactions_partition(month::date)
+FROM generate_series(
+    date_trunc('month', LEAST(
+        (SELECT MIN(COALESCE(timestamp, created_at)) FROM transactions_unpartitioned),
+        NOW()
+    ) AT TIME ZONE 'UTC'),
+    date_trunc('month', NOW() AT TIME ZONE 'UTC') + INTERVAL '3 months',
+    INTERVAL '1 month'
+) AS month;
+
+INSERT INTO transactions (
+    id, account_id, amount, type, timestamp, created_at, updated_at,
+    description, external_reference, counterparty, metadata
+)
+SELECT id, account_id, amount, type, COALESCE(timestamp, created_at), created_at, updated_at,
+       description, external_reference, counterparty, metadata
+FROM transactions_unpartitioned;
+
+DROP TABLE transactions_unpartitioned;
+
+-- unique indexes of partitioned tables must include the partition key,
+-- so external references are deduplicated per account in a table of their own, archived ones included
+CREATE TABLE IF NOT EXISTS transaction_references (
+    account_id UUID NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+    external_reference VARCHAR(64) NOT NULL,
+    PRIMARY KEY (account_id, external_reference)
+);
+
+INSERT INTO transaction_references (account_id, external_reference)
+SELECT DISTINCT account_id, external_reference
+FROM transactions
+WHERE external_reference IS NOT NULL;
+
+CREATE OR REPLACE FUNCTION record_transaction_reference() RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO transaction_references (account_id, external_reference)
+    VALUES (NEW.account_id, NEW.external_reference);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER transactions_record_reference
+    AFTER INSERT ON transactions
+    FOR EACH ROW WHEN (NEW.external_reference IS NOT NULL) EXECUTE FUNCTION record_transaction_reference();
+
+CREATE TRIGGER transactions_set_updated_at
+    BEFORE UPDATE ON transactions
+    FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+CREATE TRIGGER transactions_record_events
+    AFTER INSERT ON transactions
+    REFERENCING NEW TABLE AS inserted
+    FOR EACH STATEMENT EXECUTE FUNCTION record_transaction_events();
+
+-- detached partitions whose transactions are kept in an archive file only
+CREATE TABLE IF NOT EXISTS transaction_archives (
+    partition VARCHAR(63) PRIMARY KEY,
+    range_start TIMESTAMP WITH TIME ZONE NOT NULL,
+    range_end TIMESTAMP WITH TIME ZONE NOT NULL,
+    transactions BIGINT NOT NULL,
+    location TEXT NOT NULL,
+    archived_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- signed sum of the archived transactions of an account, the ledger adds it to the remaining transactions
+CREATE TABLE IF NOT EXISTS transaction_archive_balances (
+    account_id UUID PRIMARY KEY REFERENCES accounts(id) ON DELETE CASCADE,
+    amount DECIMAL(38, 16) NOT NULL
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS transaction_archive_balances;
+DROP TABLE IF EXISTS transaction_archives;
+
+DROP TRIGGER IF EXISTS transactions_record_events ON transactions;
+DROP TRIGGER IF EXISTS transactions_set_updated_at ON transactions;
+DROP TRIGGER IF EXISTS transactions_record_reference ON transactions;
+DROP FUNCTION IF EXISTS record_transaction_reference();
+DROP TABLE IF EXISTS transaction_references;
+
+ALTER TABLE transactions RENAME TO transactions_partitioned;
+ALTER TABLE transactions_partitioned DROP CONSTRAINT transactions_pkey;
+DROP INDEX IF EXISTS idx_transactions_account_id;
+DROP INDEX IF EXISTS idx_transactions_created_at;
+DROP INDEX IF EXISTS idx_transactions_updated_at;
+DROP INDEX IF EXISTS idx_transactions_account_id_created_at;
+
+CREATE TABLE transactions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    account_id UUID NOT NULL REFERENCES accounts(id),
+    amount DECIMAL(38, 16) NOT NULL,
+    type VARCHAR(15) NOT NULL,
+    timestamp TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    description VARCHAR(255),
+    external_reference VARCHAR(64),
+    counterparty VARCHAR(255),
+    metadata JSONB,
+    CONSTRAINT transactions_metadata_check
+        CHECK (metadata IS NULL OR (jsonb_typeof(metadata) = 'object' AND pg_column_size(metadata) <= 8192))
+);
+
+INSERT INTO transactions SELECT * FROM transactions_partitioned;
+DROP TABLE transactions_partitioned;
+DROP FUNCTION IF EXISTS create_transactions_partition(DATE);
+
+CREATE INDEX idx_transactions_account_id ON transactions(account_id);
+CREATE INDEX idx_transactions_created_at ON transactions(created_at);
+CREATE INDEX idx_transactions_updated_at ON transactions(updated_at);
+CREATE INDEX idx_transactions_account_id_created_at ON transactions(account_id, created_at, id);
+CREATE UNIQUE INDEX transactions_account_id_external_reference_key
+    ON transactions(account_id, external_reference)
+    WHERE external_reference IS NOT NULL;
+
+CREATE TRIGGER transactions_set_updated_at
+    BEFORE UPDATE ON transactions
+    FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+
+CREATE TRIGGER transactions_record_events
+    AFTER INSERT ON transactions
+    REFERENCING NEW TABLE AS inserted
+    FOR EACH STATEMENT EXECUTE FUNCTION record_transaction_events();
+
+ALTER TABLE transfer_batch_items
+    ADD FOREIGN KEY (from_transaction_id) REFERENCES transactions(id),
+    ADD FOREIGN KEY (to_transaction_id) REFERENCES transactions(id);
+ALTER TABLE interest_accruals ADD FOREIGN KEY (transaction_id) REFERENCES transactions(id);
+ALTER TABLE reconciliation_lines
+    ADD FOREIGN KEY (transaction_id) REFERENCES transactions(id) ON DELETE SET NULL;
+-- +goose StatementEnd
@@ -0,0 +1,60 @@
+-- +goose Up
+-- +goose StatementBegin
+-- the constraints are added NOT VALID first, so they hold for new writes while existing rows are cleaned up
+
+-- balances never go below zero, every debit is checked against the funds before it is booked
+ALTER TABLE accounts
+    ADD CONSTRAINT accounts_balance_check CHECK (balance >= 0) NOT VALID;
+
+-- amounts are positive, the type tells whether a transaction is a debit or a credit.
+-- The timestamp is the partition key of transactions and is NOT NULL already
+ALTER TABLE transactions
+    ADD CONSTRAINT transactions_amount_check CHECK (amount > 0) NOT VALID,
+    ADD CONSTRAINT transactions_type_check CHECK (type IN ('deposit', 'withdrawal', 'interest', 'fee')) NOT VALID;
+
+-- zero amount transactions moved no funds, the statement lines matched to them are unmatched again
+UPDATE reconciliation_lines
+SET transaction_id = NULL, match_kind = NULL, matched_at = NULL
+WHERE transaction_id IN (SELECT id FROM transactions WHERE amount = 0);
+
+DELETE FROM transactions WHERE amount = 0;
+
+-- the remaining violations change balances and are not corrected here, their constraint is left NOT VALID
+-- until they are fixed by hand and validated with ALTER TABLE ... VALIDATE CONSTRAINT
+DO $$
+DECLARE
+    violations BIGINT;
+BEGIN
+    SELECT COUNT(*) INTO violations FROM accounts WHERE balance < 0;
+    IF violations = 0 THEN
+        ALTER TABLE accounts VALIDATE CONSTRAINT accounts_balance_check;
+    ELSE
+        RAISE WARNING 'accounts_balance_check not validated, % accounts have a negative balance', violations;
+    END IF;
+
+    SELECT COUNT(*) INTO violations FROM transactions WHERE amount < 0;
+    IF violations = 0 THEN
+        ALTER TABLE transactions VALIDATE CONSTRAINT transactions_amount_check;
+    ELSE
+        RAISE WARNING 'transactions_amount_check not validated, % transactions have a negative amount', violations;
+    END IF;
+
+    SELECT COUNT(*) INTO violations FROM transactions WHERE type NOT IN ('deposit', 'withdrawal', 'interest', 'fee');
+    IF violations = 0 THEN
+        ALTER TABLE transactions VALIDATE CONSTRAINT transactions_type_check;
+    ELSE
+        RAISE WARNING 'transactions_type_check not validated, % transactions have an unknown type', violations;
+    END IF;
+END;
+$$;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- only the constraints are dropped, deleted zero amount transactions are not restored
+ALTER TABLE transactions
+    DROP CONSTRAINT IF EXISTS transactions_type_check,
+    DROP CONSTRAINT IF EXISTS transactions_amount_check;
+
+ALTER TABLE accounts DROP CONSTRAINT IF EXISTS accounts_balance_check;
+-- +goose StatementEnd
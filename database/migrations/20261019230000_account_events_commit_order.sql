@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+-- event ids are drawn when the event is inserted, not when it is committed, so a lower id may be committed later.
+-- Every event keeps the id of the transaction that recorded it. Readers return only events of transactions older than
+-- any transaction still in progress and order them by transaction first, an order no later commit can change
+ALTER TABLE account_events ADD COLUMN IF NOT EXISTS xid XID8 NOT NULL DEFAULT pg_current_xact_id();
+
+CREATE INDEX IF NOT EXISTS account_events_xid_idx ON account_events (xid, id);
+CREATE INDEX IF NOT EXISTS account_events_account_id_xid_idx ON account_events (account_id, xid, id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS account_events_account_id_xid_idx;
+DROP INDEX IF EXISTS account_events_xid_idx;
+ALTER TABLE account_events DROP COLUMN IF EXISTS xid;
+-- +goose StatementEnd
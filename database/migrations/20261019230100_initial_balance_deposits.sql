@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+-- accounts created before initial balances were booked as deposits hold more than their transactions add up to.
+-- The difference is booked as the initial balance deposit at the time the account was created, so the ledger verifies.
+-- Negative differences are not initial balances, they are left for the ledger verification to report
+INSERT INTO transactions (account_id, amount, type, timestamp, created_at, updated_at, description, metadata)
+SELECT a.id, d.difference, 'deposit', a.created_at, a.created_at, a.created_at, 'initial balance',
+       '{"initial_balance_backfill": true}'::jsonb
+FROM accounts AS a
+CROSS JOIN LATERAL (
+    SELECT a.balance
+           - COALESCE(SUM(CASE WHEN t.type IN ('withdrawal', 'fee') THEN -t.amount ELSE t.amount END), 0)
+           - COALESCE((SELECT b.amount FROM transaction_archive_balances AS b WHERE b.account_id = a.id), 0) AS difference
+    FROM transactions AS t
+    WHERE t.account_id = a.id
+) AS d
+WHERE d.difference > 0;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM transactions WHERE metadata @> '{"initial_balance_backfill": true}';
+-- +goose StatementEnd
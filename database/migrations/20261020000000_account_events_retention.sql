@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- events older than the retention window are pruned by the partition maintenance
+CREATE INDEX IF NOT EXISTS account_events_created_at_idx ON account_events (created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS account_events_created_at_idx;
+-- +goose StatementEnd
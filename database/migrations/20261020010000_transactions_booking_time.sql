@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- statements, historical balances and reconciliation read the movements of an account by the partition key
+CREATE INDEX IF NOT EXISTS idx_transactions_account_id_timestamp ON transactions(account_id, timestamp, id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_transactions_account_id_timestamp;
+-- +goose StatementEnd
@@ -0,0 +1,131 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/softika/slogging"
+)
+
+const (
+	// currentLSNSql returns the write-ahead log position of the primary after the last commit.
+	currentLSNSql = `SELECT pg_current_wal_lsn()::text`
+	// replicaCaughtUpSql reports whether the replica has replayed the log up to the position,
+	// a server which is not in recovery is always up to date.
+	replicaCaughtUpSql = `
+SELECT CASE WHEN pg_is_in_recovery() THEN pg_last_wal_replay_lsn() ELSE pg_current_wal_lsn() END >= $1::pg_lsn`
+	// replicaLagSql returns the seconds since the last replayed transaction was committed on the primary.
+	replicaLagSql = `
+SELECT CASE WHEN pg_is_in_recovery()
+    THEN COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0)::float8
+    ELSE 0 END`
+)
+
+type (
+	sessionKey struct{}
+	primaryKey struct{}
+)
+
+// session tracks the log position of the writes made in it,
+// so later reads of the session are not served by a replica which is behind.
+type session struct {
+	mu  sync.Mutex
+	lsn uint64
+}
+
+// WithSession returns a context with a read-your-writes session.
+// The token is the consistency token of an earlier session, so the session reads its writes as well, it may be empty.
+// Invalid tokens are ignored.
+func WithSession(ctx context.Context, token string) context.Context {
+	s := new(session)
+	s.lsn, _ = parseLSN(token)
+	return context.WithValue(ctx, sessionKey{}, s)
+}
+
+// SessionToken returns the consistency token of the writes made in the session of ctx,
+// it is empty when ctx has no session or nothing was written.
+func SessionToken(ctx context.Context) string {
+	s, ok := ctx.Value(sessionKey{}).(*session)
+	if !ok {
+		return ""
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.lsn == 0 {
+		return ""
+	}
+	return formatLSN(s.lsn)
+}
+
+// WithPrimary returns a context whose reads are served by the primary.
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryKey{}, true)
+}
+
+func (s *service) Replicated() bool {
+	return s.replica != nil
+}
+
+func (s *service) ReadPool(ctx context.Context) *pgxpool.Pool {
+	if s.replica == nil {
+		return s.pool
+	}
+	if primary, _ := ctx.Value(primaryKey{}).(bool); primary {
+		return s.pool
+	}
+
+	token := SessionToken(ctx)
+	if token == "" {
+		return s.replica
+	}
+
+	var caughtUp bool
+	if err := s.replica.QueryRow(ctx, replicaCaughtUpSql, token).Scan(&caughtUp); err != nil {
+		slogging.Slogger().WarnContext(ctx, "failed to check replica position, reading from primary", "error", err)
+		return s.pool
+	}
+	if !caughtUp {
+		return s.pool
+	}
+	return s.replica
+}
+
+// trackWrite records the log position of the primary in the session of ctx after a commit.
+func trackWrite(ctx context.Context, pool *pgxpool.Pool) {
+	s, ok := ctx.Value(sessionKey{}).(*session)
+	if !ok {
+		return
+	}
+
+	var token string
+	if err := pool.QueryRow(ctx, currentLSNSql).Scan(&token); err != nil {
+		slogging.Slogger().WarnContext(ctx, "failed to read the log position", "error", err)
+		return
+	}
+	lsn, ok := parseLSN(token)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lsn = max(s.lsn, lsn)
+}
+
+// parseLSN parses the text form of a postgres log sequence number, two hexadecimal numbers separated by a slash.
+func parseLSN(token string) (uint64, bool) {
+	var hi, lo uint32
+	var rest string
+	if n, _ := fmt.Sscanf(token, "%X/%X%s", &hi, &lo, &rest); n != 2 {
+		return 0, false
+	}
+	return uint64(hi)<<32 | uint64(lo), true
+}
+
+func formatLSN(lsn uint64) string {
+	return fmt.Sprintf("%X/%X", uint32(lsn>>32), uint32(lsn))
+}
@@ -3,6 +3,7 @@ package repositories
 import (
 	"context"
 	_ "embed"
+	"errors"
 	"fmt"
 	"strings"
 
@@ -11,6 +12,7 @@ import (
 	"github.com/fmiskovic/cash-me-if-you-can/database"
 	"github.com/fmiskovic/cash-me-if-you-can/internal"
 	"github.com/fmiskovic/cash-me-if-you-can/internal/account"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/transaction"
 	"github.com/fmiskovic/cash-me-if-you-can/pkg/errorx"
 )
 
@@ -46,15 +48,52 @@ func (r AccountRepository) Get(ctx context.Context, id string) (*account.Account
 }
 
 func (r AccountRepository) Create(ctx context.Context, acc *account.Account) (*account.Account, error) {
-	if err := r.Pool().QueryRow(ctx, insertAccountSql,
-		acc.Owner,   // $1
-		acc.Balance, // $2
-	).Scan(&acc.ID); err != nil {
-		if strings.Contains(err.Error(), "accounts_owner_check") {
+	if acc.Currency == "" {
+		acc.Currency = account.DefaultCurrency
+	}
+	if acc.Product == "" {
+		acc.Product = account.DefaultProduct
+	}
+
+	// execute inside transaction and rollback on error
+	err := r.Execute(ctx, func(tx pgx.Tx) error {
+		if err := tx.QueryRow(ctx, insertAccountSql,
+			acc.Owner,    // $1
+			acc.Balance,  // $2
+			acc.Currency, // $3
+			acc.Product,  // $4
+		).Scan(&acc.ID, &acc.Status, &acc.CreatedAt, &acc.UpdatedAt); err != nil {
+			return err
+		}
+		if acc.Balance == 0 {
+			return nil
+		}
+
+		// deposit of the initial balance
+		return r.insertTransaction(ctx, tx, transaction.New(
+			transaction.WithAccountID(acc.ID),
+			transaction.WithType(transaction.Deposit),
+			transaction.WithAmount(acc.Balance),
+			transaction.WithDescription("initial balance"),
+		))
+	})
+	if err != nil {
+		switch violatedConstraint(err) {
+		case "accounts_product_fkey":
+			return nil, errorx.NewError(
+				fmt.Errorf("product %s does not exist", acc.Product),
+				errorx.ErrInvalidInput,
+			)
+		case "accounts_owner_key":
 			return nil, errorx.NewError(
 				fmt.Errorf("account with owner %s already exists", acc.Owner),
 				errorx.ErrInvalidInput,
 			)
+		case "accounts_owner_check":
+			return nil, errorx.NewError(
+				errors.New("owner is blank"),
+				errorx.ErrInvalidInput,
+			)
 		}
 		return nil, err
 	}
@@ -62,10 +101,24 @@ func (r AccountRepository) Create(ctx context.Context, acc *account.Account) (*a
 	return acc, nil
 }
 
-func (r AccountRepository) List(ctx context.Context, pageReq internal.PageRequest) (internal.Page[account.Account], error) {
+func (r AccountRepository) List(ctx context.Context, req account.ListRequest) (internal.Page[account.Account], error) {
 	var accounts []account.Account
 
-	rows, err := r.Pool().Query(ctx, accountPageSql, pageReq.Limit, pageReq.Offset)
+	filters := []any{
+		ownerPattern(req.Owner),         // $1
+		nullIfEmpty(string(req.Status)), // $2
+		nullIfEmpty(req.Currency),       // $3
+		req.MinBalance,                  // $4
+		req.MaxBalance,                  // $5
+		req.CreatedFrom,                 // $6
+		req.CreatedTo,                   // $7
+		req.UpdatedSince,                // $8
+	}
+
+	pageSql := fmt.Sprintf(accountPageSql, orderBy(req.SortBy, req.SortOrder))
+	// pages are served by the replica, if configured
+	pool := r.ReadPool(ctx)
+	rows, err := pool.Query(ctx, pageSql, append(filters, req.Limit, req.Offset)...)
 	if err != nil {
 		return internal.EmptyPage[account.Account](), err
 	}
@@ -73,7 +126,7 @@ func (r AccountRepository) List(ctx context.Context, pageReq internal.PageReques
 
 	for rows.Next() {
 		var acc account.Account
-		if err = rows.Scan(&acc.ID, &acc.Owner, &acc.Balance); err != nil {
+		if err = rows.Scan(&acc.ID, &acc.Owner, &acc.Balance, &acc.Currency, &acc.Status, &acc.Product, &acc.CreatedAt, &acc.UpdatedAt); err != nil {
 			return internal.EmptyPage[account.Account](), err
 		}
 		accounts = append(accounts, acc)
@@ -84,11 +137,11 @@ func (r AccountRepository) List(ctx context.Context, pageReq internal.PageReques
 	}
 
 	var count int
-	_ = r.Pool().QueryRow(ctx, totalAccountCountSql).Scan(&count) // ignore error, it's not critical
+	_ = pool.QueryRow(ctx, totalAccountCountSql, filters...).Scan(&count) // ignore error, it's not critical
 
 	totalPages := 0
-	if count != 0 && pageReq.Limit != 0 {
-		totalPages = (count + pageReq.Limit - 1) / pageReq.Limit
+	if count != 0 && req.Limit != 0 {
+		totalPages = (count + req.Limit - 1) / req.Limit
 	}
 
 	return internal.Page[account.Account]{
@@ -102,3 +155,41 @@ func (r AccountRepository) Delete(ctx context.Context, id string) error {
 	_, err := r.Pool().Exec(ctx, deleteAccountSql, id)
 	return err
 }
+
+// sortColumns whitelists the columns accounts can be sorted by,
+// since ORDER BY cannot be passed as a query parameter.
+var sortColumns = map[account.SortField]string{
+	account.SortByCreatedAt: "a.created_at",
+	account.SortByOwner:     "a.owner",
+	account.SortByBalance:   "a.balance",
+	account.SortByUpdatedAt: "a.updated_at",
+}
+
+func orderBy(field account.SortField, order account.SortOrder) string {
+	column, ok := sortColumns[field]
+	if !ok {
+		column = sortColumns[account.SortByCreatedAt]
+	}
+	if order == account.Desc {
+		return column + " DESC"
+	}
+	return column + " ASC"
+}
+
+// ownerPattern builds a case-insensitive substring pattern for ILIKE,
+// escaping the wildcard characters of the search term.
+func ownerPattern(owner string) *string {
+	if owner == "" {
+		return nil
+	}
+	escaped := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(owner)
+	pattern := "%" + escaped + "%"
+	return &pattern
+}
+
+func nullIfEmpty(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
@@ -0,0 +1,84 @@
+package repositories
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/fmiskovic/cash-me-if-you-can/database"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/balance"
+	"github.com/fmiskovic/cash-me-if-you-can/pkg/errorx"
+)
+
+var (
+	//go:embed sql/balance_as_of.sql
+	selectBalanceAsOfSql string
+	//go:embed sql/balance_snapshot_last.sql
+	selectLastBalanceSnapshotSql string
+	//go:embed sql/balance_snapshot_insert.sql
+	insertBalanceSnapshotSql string
+)
+
+type BalanceRepository struct {
+	baseRepository
+}
+
+func NewBalanceRepository(db database.Service) BalanceRepository {
+	return BalanceRepository{
+		baseRepository: newBaseRepository(db),
+	}
+}
+
+func (r BalanceRepository) AsOf(ctx context.Context, accountID string, asOf time.Time) (*balance.Balance, error) {
+	if err := rejectArchived(ctx, r.Pool(), asOf); err != nil {
+		return nil, err
+	}
+
+	b := &balance.Balance{AsOf: asOf}
+	err := r.Pool().QueryRow(ctx, selectBalanceAsOfSql,
+		accountID, // $1
+		asOf,      // $2
+	).Scan(&b.AccountID, &b.Currency, &b.Amount)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, errorx.NewError(
+			fmt.Errorf("account with id %s not found", accountID),
+			errorx.ErrNotFound,
+		)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+func (r BalanceRepository) LastSnapshot(ctx context.Context) (*time.Time, error) {
+	var last *time.Time
+	if err := r.Pool().QueryRow(ctx, selectLastBalanceSnapshotSql).Scan(&last); err != nil {
+		return nil, err
+	}
+	return last, nil
+}
+
+func (r BalanceRepository) Snapshot(ctx context.Context, at time.Time) (int, error) {
+	var n int
+
+	// execute inside transaction and rollback on error
+	err := r.Execute(ctx, func(tx pgx.Tx) error {
+		// every account is snapshotted, so the insert is not limited by the statement timeout
+		if err := disableTimeout(ctx, tx); err != nil {
+			return err
+		}
+
+		tag, err := tx.Exec(ctx, insertBalanceSnapshotSql, at)
+		if err != nil {
+			return err
+		}
+		n = int(tag.RowsAffected())
+		return nil
+	})
+	return n, err
+}
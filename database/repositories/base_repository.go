@@ -3,12 +3,19 @@ package repositories
 import (
 	"context"
 	_ "embed"
+	"errors"
 	"fmt"
+	"slices"
+	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
 
 	"github.com/fmiskovic/cash-me-if-you-can/database"
 	"github.com/fmiskovic/cash-me-if-you-can/internal/account"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/fee"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/transaction"
 	"github.com/fmiskovic/cash-me-if-you-can/pkg/errorx"
 )
 
@@ -17,8 +24,24 @@ var (
 	lockAccountByIdSql string
 	//go:embed sql/account_exist.sql
 	accountExistSql string
+	//go:embed sql/account_increment_balance.sql
+	incrementAccountBalanceSql string
+	//go:embed sql/transaction_insert.sql
+	insertTransactionSql string
+	//go:embed sql/fee_rule_select_by_account_id.sql
+	selectFeeRuleSql string
+	//go:embed sql/statement_timeout_disable.sql
+	disableStatementTimeoutSql string
+	//go:embed sql/transaction_archived_until.sql
+	selectArchivedUntilSql string
 )
 
+// querier is implemented by both the connection pool and a transaction.
+type querier interface {
+	Query(context.Context, string, ...any) (pgx.Rows, error)
+	QueryRow(context.Context, string, ...any) pgx.Row
+}
+
 type baseRepository struct {
 	database.Service
 	database.TxManager
@@ -31,6 +54,72 @@ func newBaseRepository(db database.Service) baseRepository {
 	}
 }
 
+// Execute runs fn inside a transaction, rolled back on error, and translates violations
+// of the money integrity constraints into errorx errors.
+func (r baseRepository) Execute(ctx context.Context, fn func(pgx.Tx) error) error {
+	return constraintError(r.TxManager.Execute(ctx, fn))
+}
+
+// disableTimeout lifts the statement timeout for the rest of the transaction. Postgres counts the time a result
+// waits for the client against the timeout, so streams and queries over whole tables are not limited by it.
+func disableTimeout(ctx context.Context, tx pgx.Tx) error {
+	_, err := tx.Exec(ctx, disableStatementTimeoutSql)
+	return err
+}
+
+// beginUnbounded begins a read-only transaction on the pool without statement timeout.
+// It is only read from, so the caller always rolls it back.
+func beginUnbounded(ctx context.Context, pool *pgxpool.Pool) (pgx.Tx, error) {
+	tx, err := pool.BeginTx(ctx, pgx.TxOptions{AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return nil, err
+	}
+	if err = disableTimeout(ctx, tx); err != nil {
+		_ = tx.Rollback(ctx)
+		return nil, err
+	}
+	return tx, nil
+}
+
+// rejectArchived fails with invalid input if transactions booked at or after from may be archived,
+// their sums are only known per account, so periods reaching into archived months can not be computed.
+func rejectArchived(ctx context.Context, q querier, from time.Time) error {
+	var until *time.Time
+	if err := q.QueryRow(ctx, selectArchivedUntilSql).Scan(&until); err != nil {
+		return err
+	}
+	if until != nil && from.Before(*until) {
+		return errorx.NewError(
+			fmt.Errorf("transactions before %s are archived, the period must start at or after it", until.UTC().Format(time.RFC3339)),
+			errorx.ErrInvalidInput,
+		)
+	}
+	return nil
+}
+
+// violatedConstraint returns the name of the constraint the error violates, empty if it is not a constraint violation.
+func violatedConstraint(err error) string {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.ConstraintName
+	}
+	return ""
+}
+
+// constraintError translates the violation of a money integrity constraint into an errorx error,
+// the constraints only fail if a check of the repositories was missed. Other errors are returned as they are.
+func constraintError(err error) error {
+	switch violatedConstraint(err) {
+	case "accounts_balance_check":
+		return errorx.NewError(errors.New("insufficient funds"), errorx.ErrInvalidInput)
+	case "transactions_amount_check":
+		return errorx.NewError(errors.New("invalid amount"), errorx.ErrInvalidInput)
+	case "transactions_type_check":
+		return errorx.NewError(errors.New("invalid transaction type"), errorx.ErrInvalidInput)
+	}
+	return err
+}
+
 func (r baseRepository) lockAccountById(ctx context.Context, tx pgx.Tx, id string) (*account.Account, error) {
 	a := new(account.Account)
 
@@ -47,7 +136,7 @@ func (r baseRepository) lockAccountById(ctx context.Context, tx pgx.Tx, id strin
 		)
 	}
 
-	if err = row.Scan(&a.ID, &a.Owner, &a.Balance); err != nil {
+	if err = row.Scan(&a.ID, &a.Owner, &a.Balance, &a.Currency, &a.Status, &a.Product, &a.CreatedAt, &a.UpdatedAt); err != nil {
 		return nil, fmt.Errorf("failed to scan account: %w", err)
 	}
 
@@ -58,3 +147,204 @@ func (r baseRepository) lockAccountById(ctx context.Context, tx pgx.Tx, id strin
 
 	return a, nil
 }
+
+// lockAccounts locks the given accounts sorted by id,
+// so concurrent transactions touching the same accounts cannot deadlock.
+// Revenue accounts credited by fees must be locked with them, see withRevenueAccounts.
+func (r baseRepository) lockAccounts(ctx context.Context, tx pgx.Tx, ids ...string) (map[string]*account.Account, error) {
+	sorted := slices.Clone(ids)
+	slices.Sort(sorted)
+	sorted = slices.Compact(sorted)
+
+	accounts := make(map[string]*account.Account, len(sorted))
+	for _, id := range sorted {
+		acc, err := r.lockAccountById(ctx, tx, id)
+		if err != nil {
+			return nil, err
+		}
+		accounts[id] = acc
+	}
+
+	return accounts, nil
+}
+
+// withRevenueAccounts adds the revenue accounts of the charges to the accounts to lock,
+// fees are quoted before locking so every account a transaction updates is locked in the same order.
+func withRevenueAccounts(ids []string, charges ...fee.Charge) []string {
+	for _, c := range charges {
+		if c.Amount > 0 {
+			ids = append(ids, c.RevenueAccountID)
+		}
+	}
+	return ids
+}
+
+// transfer moves funds between two locked accounts, records both legs and charges the quoted transfer fee,
+// whose revenue account must be locked as well. Balances of the given accounts are updated only if every statement succeeds.
+func (r baseRepository) transfer(
+	ctx context.Context,
+	tx pgx.Tx,
+	accFrom, accTo *account.Account,
+	from, to *transaction.Transaction,
+	charge fee.Charge,
+) error {
+	// transfers between different currencies are not supported
+	if accFrom.Currency != accTo.Currency {
+		return errorx.NewError(
+			errors.New("transfer failed - currency mismatch"),
+			errorx.ErrInvalidInput,
+		)
+	}
+
+	// check if account has enough funds to make a transfer and pay the fee
+	if accFrom.Balance < from.Amount+charge.Amount {
+		return errorx.NewError(
+			errors.New("transfer failed - insufficient funds"),
+			errorx.ErrInvalidInput,
+		)
+	}
+
+	// balances are updated relatively, the revenue account credited by fees may be one of the accounts
+	fromBalance := accFrom.Balance - from.Amount
+	if _, err := tx.Exec(ctx, incrementAccountBalanceSql, from.AccountID, -from.Amount); err != nil {
+		return err
+	}
+
+	toBalance := accTo.Balance + to.Amount
+	if _, err := tx.Exec(ctx, incrementAccountBalanceSql, to.AccountID, to.Amount); err != nil {
+		return err
+	}
+
+	// create transactions
+	if err := r.insertTransaction(ctx, tx, from); err != nil {
+		return err
+	}
+	if err := r.insertTransaction(ctx, tx, to); err != nil {
+		return err
+	}
+
+	if err := r.postFee(ctx, tx, charge, fee.Transfer, from); err != nil {
+		return err
+	}
+
+	accFrom.Balance, accTo.Balance = fromBalance-charge.Amount, toBalance
+	return nil
+}
+
+// insertTransaction stores the transaction and fills in the generated id and timestamps.
+func (r baseRepository) insertTransaction(ctx context.Context, tx pgx.Tx, t *transaction.Transaction) error {
+	var metadata any
+	if len(t.Metadata) > 0 {
+		metadata = t.Metadata
+	}
+
+	err := tx.QueryRow(ctx, insertTransactionSql,
+		t.AccountID,                      // $1
+		t.Amount,                         // $2
+		t.Type,                           // $3
+		nullIfEmpty(t.Description),       // $4
+		nullIfEmpty(t.ExternalReference), // $5
+		nullIfEmpty(t.Counterparty),      // $6
+		metadata,                         // $7
+	).Scan(&t.ID, &t.Timestamp, &t.CreatedAt, &t.UpdatedAt)
+
+	if violatedConstraint(err) == "transaction_references_pkey" {
+		return errorx.NewError(
+			fmt.Errorf("transaction with external reference %s already exists", t.ExternalReference),
+			errorx.ErrConflict,
+		)
+	}
+	return err
+}
+
+// quoteFee calculates the fee of the operation for the account, the charge is zero if the operation is free.
+func (r baseRepository) quoteFee(
+	ctx context.Context,
+	q querier,
+	accountID string,
+	op fee.Operation,
+	amount float64,
+) (fee.Charge, error) {
+	rule, err := r.feeRule(ctx, q, accountID, op)
+	if err != nil || rule == nil {
+		return fee.Charge{}, err
+	}
+	return fee.Charge{
+		Amount:           rule.Calculate(amount),
+		RevenueAccountID: rule.RevenueAccountID,
+	}, nil
+}
+
+// feeRule returns the fee rule of the account's product for the operation, nil if the operation is free.
+func (r baseRepository) feeRule(ctx context.Context, q querier, accountID string, op fee.Operation) (*fee.Rule, error) {
+	rule := new(fee.Rule)
+	err := q.QueryRow(ctx, selectFeeRuleSql, accountID, op).Scan(
+		&rule.ID,
+		&rule.Product,
+		&rule.Operation,
+		&rule.Kind,
+		&rule.Amount,
+		&rule.Rate,
+		&rule.Tiers,
+		&rule.Min,
+		&rule.Max,
+		&rule.RevenueAccountID,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// postFee moves the charged fee from the payer of the origin transaction to the revenue account
+// and records both legs. The origin transaction must already be stored and both accounts locked.
+func (r baseRepository) postFee(
+	ctx context.Context,
+	tx pgx.Tx,
+	charge fee.Charge,
+	op fee.Operation,
+	origin *transaction.Transaction,
+) error {
+	if charge.Amount <= 0 {
+		return nil
+	}
+
+	description := fmt.Sprintf("%s fee", op)
+	metadata := map[string]any{"transaction_id": origin.ID}
+
+	payer := transaction.New(
+		transaction.WithAccountID(origin.AccountID),
+		transaction.WithType(transaction.Fee),
+		transaction.WithAmount(charge.Amount),
+		transaction.WithDescription(description),
+		transaction.WithCounterparty(charge.RevenueAccountID),
+		transaction.WithMetadata(metadata),
+	)
+	revenue := transaction.New(
+		transaction.WithAccountID(charge.RevenueAccountID),
+		transaction.WithType(transaction.Deposit),
+		transaction.WithAmount(charge.Amount),
+		transaction.WithDescription(description),
+		transaction.WithCounterparty(origin.AccountID),
+		transaction.WithMetadata(metadata),
+	)
+
+	if _, err := tx.Exec(ctx, incrementAccountBalanceSql, payer.AccountID, -charge.Amount); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, incrementAccountBalanceSql, revenue.AccountID, charge.Amount); err != nil {
+		return err
+	}
+	if err := r.insertTransaction(ctx, tx, payer); err != nil {
+		return err
+	}
+	if err := r.insertTransaction(ctx, tx, revenue); err != nil {
+		return err
+	}
+
+	origin.Fee = payer
+	return nil
+}
@@ -0,0 +1,256 @@
+package repositories
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/fmiskovic/cash-me-if-you-can/database"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/account"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/batch"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/fee"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/transaction"
+	"github.com/fmiskovic/cash-me-if-you-can/pkg/errorx"
+)
+
+var (
+	//go:embed sql/batch_insert.sql
+	insertBatchSql string
+	//go:embed sql/batch_item_insert.sql
+	insertBatchItemSql string
+	//go:embed sql/batch_select_by_id.sql
+	selectBatchByIdSql string
+	//go:embed sql/batch_item_select_by_batch_id.sql
+	selectBatchItemsSql string
+)
+
+type BatchRepository struct {
+	baseRepository
+}
+
+func NewBatchRepository(db database.Service) BatchRepository {
+	return BatchRepository{
+		baseRepository: newBaseRepository(db),
+	}
+}
+
+func (r BatchRepository) Transfer(ctx context.Context, b *batch.Batch) error {
+	if b == nil || len(b.Items) == 0 {
+		return errorx.NewError(
+			errors.New("batch has no transfers"),
+			errorx.ErrInvalidInput,
+		)
+	}
+	for _, item := range b.Items {
+		if item.From.Amount <= 0 || item.To.Amount <= 0 {
+			return errorx.NewError(
+				fmt.Errorf("transfer %d: invalid amount", item.Position),
+				errorx.ErrInvalidInput,
+			)
+		}
+	}
+
+	// execute inside transaction and rollback on error
+	return r.Execute(ctx, func(tx pgx.Tx) error {
+		var err error
+		if b.Mode == batch.BestEffort {
+			err = r.transferBestEffort(ctx, tx, b)
+		} else {
+			err = r.transferAtomic(ctx, tx, b)
+		}
+		if err != nil {
+			return err
+		}
+
+		b.Resolve()
+		return r.insertBatch(ctx, tx, b)
+	})
+}
+
+func (r BatchRepository) Get(ctx context.Context, id string) (*batch.Batch, error) {
+	b := new(batch.Batch)
+	err := r.Pool().
+		QueryRow(ctx, selectBatchByIdSql, id).
+		Scan(&b.ID, &b.Mode, &b.Status, &b.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, errorx.NewError(
+			fmt.Errorf("batch with id %s not found", id),
+			errorx.ErrNotFound,
+		)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.Pool().Query(ctx, selectBatchItemsSql, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item := batch.Item{
+			From: transaction.New(transaction.WithType(transaction.Withdrawal)),
+			To:   transaction.New(transaction.WithType(transaction.Deposit)),
+		}
+		if err = rows.Scan(
+			&item.Position,
+			&item.From.AccountID,
+			&item.To.AccountID,
+			&item.From.Amount,
+			&item.Status,
+			&item.Error,
+			&item.From.ID,
+			&item.To.ID,
+		); err != nil {
+			return nil, err
+		}
+		item.To.Amount = item.From.Amount
+		b.Items = append(b.Items, item)
+	}
+
+	return b, rows.Err()
+}
+
+// transferAtomic executes all items and fails on the first failing one.
+func (r BatchRepository) transferAtomic(ctx context.Context, tx pgx.Tx, b *batch.Batch) error {
+	charges, err := r.quoteFees(ctx, tx, b)
+	if err != nil {
+		return err
+	}
+
+	accounts, err := r.lockAccounts(ctx, tx, withRevenueAccounts(accountIds(b), charges...)...)
+	if err != nil {
+		return err
+	}
+
+	for i := range b.Items {
+		item := &b.Items[i]
+		accFrom, accTo := accounts[item.From.AccountID], accounts[item.To.AccountID]
+		if err = r.transfer(ctx, tx, accFrom, accTo, item.From, item.To, charges[i]); err != nil {
+			return itemError(item.Position, err)
+		}
+		item.Status = batch.ItemSucceeded
+	}
+
+	return nil
+}
+
+// transferBestEffort executes every item inside its own savepoint,
+// so a failing item is rolled back without affecting the others.
+func (r BatchRepository) transferBestEffort(ctx context.Context, tx pgx.Tx, b *batch.Batch) error {
+	charges, err := r.quoteFees(ctx, tx, b)
+	if err != nil {
+		return err
+	}
+
+	// lock accounts in a deterministic order and remember the missing ones
+	ids := withRevenueAccounts(accountIds(b), charges...)
+	slices.Sort(ids)
+	accounts := make(map[string]*account.Account, len(ids))
+	for _, id := range slices.Compact(ids) {
+		acc, err := r.lockAccountById(ctx, tx, id)
+		var errx *errorx.Error
+		if errors.As(err, &errx) && errx.Type == errorx.ErrNotFound {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		accounts[id] = acc
+	}
+
+	for i := range b.Items {
+		item := &b.Items[i]
+
+		accFrom, accTo := accounts[item.From.AccountID], accounts[item.To.AccountID]
+		if accFrom == nil || accTo == nil {
+			missing := item.From.AccountID
+			if accFrom != nil {
+				missing = item.To.AccountID
+			}
+			item.Status, item.Error = batch.ItemFailed, fmt.Sprintf("account with id %s not found", missing)
+			continue
+		}
+
+		sp, err := tx.Begin(ctx) // creates a savepoint
+		if err != nil {
+			return err
+		}
+		if err = r.transfer(ctx, sp, accFrom, accTo, item.From, item.To, charges[i]); err != nil {
+			if rbErr := sp.Rollback(ctx); rbErr != nil {
+				return rbErr
+			}
+			item.From.ID, item.To.ID = "", ""
+			item.Status, item.Error = batch.ItemFailed, err.Error()
+			continue
+		}
+		if err = sp.Commit(ctx); err != nil {
+			return err
+		}
+		item.Status = batch.ItemSucceeded
+	}
+
+	return nil
+}
+
+// quoteFees returns the transfer fee of every item, quoted before the accounts are locked
+// so the revenue accounts are locked in order with the others.
+func (r BatchRepository) quoteFees(ctx context.Context, tx pgx.Tx, b *batch.Batch) ([]fee.Charge, error) {
+	charges := make([]fee.Charge, len(b.Items))
+	for i, item := range b.Items {
+		charge, err := r.quoteFee(ctx, tx, item.From.AccountID, fee.Transfer, item.From.Amount)
+		if err != nil {
+			return nil, err
+		}
+		charges[i] = charge
+	}
+	return charges, nil
+}
+
+func (r BatchRepository) insertBatch(ctx context.Context, tx pgx.Tx, b *batch.Batch) error {
+	if err := tx.QueryRow(ctx, insertBatchSql,
+		b.Mode,   // $1
+		b.Status, // $2
+	).Scan(&b.ID, &b.CreatedAt); err != nil {
+		return err
+	}
+
+	items := &pgx.Batch{}
+	for _, item := range b.Items {
+		items.Queue(insertBatchItemSql,
+			b.ID,                      // $1
+			item.Position,             // $2
+			item.From.AccountID,       // $3
+			item.To.AccountID,         // $4
+			item.From.Amount,          // $5
+			item.Status,               // $6
+			nullIfEmpty(item.Error),   // $7
+			nullIfEmpty(item.From.ID), // $8
+			nullIfEmpty(item.To.ID),   // $9
+		)
+	}
+
+	return tx.SendBatch(ctx, items).Close()
+}
+
+func accountIds(b *batch.Batch) []string {
+	ids := make([]string, 0, len(b.Items)*2)
+	for _, item := range b.Items {
+		ids = append(ids, item.From.AccountID, item.To.AccountID)
+	}
+	return ids
+}
+
+// itemError prefixes the error with the position of the failed item, keeping its type.
+func itemError(position int, err error) error {
+	var errx *errorx.Error
+	if errors.As(err, &errx) {
+		return errorx.NewError(fmt.Errorf("transfer %d: %w", position, err), errx.Type)
+	}
+	return err
+}
@@ -0,0 +1,107 @@
+package repositories
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/fmiskovic/cash-me-if-you-can/database"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/event"
+	"github.com/fmiskovic/cash-me-if-you-can/pkg/errorx"
+)
+
+var (
+	//go:embed sql/event_last_id.sql
+	selectLastEventIdSql string
+	//go:embed sql/event_select.sql
+	selectEventsSql string
+	//go:embed sql/event_listen.sql
+	listenEventsSql string
+	//go:embed sql/event_prune.sql
+	pruneEventsSql string
+)
+
+// EventRepository reads the account events recorded by the database triggers on transactions and balances.
+type EventRepository struct {
+	baseRepository
+}
+
+func NewEventRepository(db database.Service) EventRepository {
+	return EventRepository{
+		baseRepository: newBaseRepository(db),
+	}
+}
+
+func (r EventRepository) LastID(ctx context.Context, accountID string) (int64, error) {
+	if accountID != "" {
+		var exist bool
+		if err := r.Pool().QueryRow(ctx, accountExistSql, accountID).Scan(&exist); err != nil {
+			return 0, err
+		}
+		if !exist {
+			return 0, errorx.NewError(
+				errors.New("account not found"),
+				errorx.ErrNotFound,
+			)
+		}
+	}
+
+	var id int64
+	err := r.Pool().QueryRow(ctx, selectLastEventIdSql, nullIfEmpty(accountID)).Scan(&id)
+	return id, err
+}
+
+func (r EventRepository) Events(ctx context.Context, accountID string, afterID int64, limit int) ([]event.Event, error) {
+	rows, err := r.Pool().Query(ctx, selectEventsSql,
+		nullIfEmpty(accountID), // $1
+		afterID,                // $2
+		limit,                  // $3
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (event.Event, error) {
+		var e event.Event
+		err := row.Scan(&e.ID, &e.AccountID, &e.Type, &e.Data, &e.CreatedAt)
+		return e, err
+	})
+}
+
+// Listen takes a connection out of the pool for as long as it listens, it is closed afterward
+// instead of being returned to the pool still listening.
+func (r EventRepository) Listen(ctx context.Context, listening func(), notify func(accountID string)) error {
+	pooled, err := r.Pool().Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	conn := pooled.Hijack()
+	defer func() { _ = conn.Close(context.Background()) }()
+
+	if _, err = conn.Exec(ctx, listenEventsSql); err != nil {
+		return err
+	}
+	listening()
+
+	for {
+		n, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		notify(n.Payload)
+	}
+}
+
+func (r EventRepository) Prune(ctx context.Context, before time.Time, limit int) (int64, error) {
+	tag, err := r.Pool().Exec(ctx, pruneEventsSql,
+		before, // $1
+		limit,  // $2
+	)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,84 @@
+package repositories
+
+import (
+	"context"
+	_ "embed"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/fmiskovic/cash-me-if-you-can/database"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/export"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/transaction"
+)
+
+var (
+	//go:embed sql/export_transactions_cursor.sql
+	declareExportCursorSql string
+	//go:embed sql/export_transactions_fetch.sql
+	fetchExportCursorSql string
+)
+
+type ExportRepository struct {
+	baseRepository
+}
+
+func NewExportRepository(db database.Service) ExportRepository {
+	return ExportRepository{
+		baseRepository: newBaseRepository(db),
+	}
+}
+
+// Transactions reads the transactions through a server-side cursor a batch at a time,
+// so memory usage does not grow with the size of the export.
+func (r ExportRepository) Transactions(
+	ctx context.Context,
+	filter export.Filter,
+	fn func(transaction.Transaction) error,
+) error {
+	// cursors live inside a transaction, it is read only, so it is always rolled back and served by the replica.
+	// Batches are fetched at the pace of the reader, so they are not limited by the statement timeout
+	tx, err := beginUnbounded(ctx, r.ReadPool(ctx))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if _, err = tx.Exec(ctx, declareExportCursorSql,
+		nullIfEmpty(filter.AccountID),    // $1
+		filter.From,                      // $2
+		filter.To,                        // $3
+		nullIfEmpty(string(filter.Type)), // $4
+	); err != nil {
+		return err
+	}
+
+	for {
+		n, err := r.fetch(ctx, tx, fn)
+		if err != nil || n == 0 {
+			return err
+		}
+	}
+}
+
+// fetch passes the next batch of the cursor to fn and returns the number of rows read.
+func (r ExportRepository) fetch(ctx context.Context, tx pgx.Tx, fn func(transaction.Transaction) error) (int, error) {
+	rows, err := tx.Query(ctx, fetchExportCursorSql)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	n := 0
+	for rows.Next() {
+		var t transaction.Transaction
+		if err = rows.Scan(transactionColumns(&t)...); err != nil {
+			return n, err
+		}
+		if err = fn(t); err != nil {
+			return n, err
+		}
+		n++
+	}
+
+	return n, rows.Err()
+}
@@ -0,0 +1,37 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"github.com/fmiskovic/cash-me-if-you-can/database"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/fee"
+	"github.com/fmiskovic/cash-me-if-you-can/pkg/errorx"
+)
+
+type FeeRepository struct {
+	baseRepository
+}
+
+func NewFeeRepository(db database.Service) FeeRepository {
+	return FeeRepository{
+		baseRepository: newBaseRepository(db),
+	}
+}
+
+func (r FeeRepository) Rule(ctx context.Context, accountID string, op fee.Operation) (*fee.Rule, error) {
+	// first check if account exists
+	var exist bool
+	err := r.Pool().QueryRow(ctx, accountExistSql, accountID).Scan(&exist)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return nil, errorx.NewError(
+			errors.New("account not found"),
+			errorx.ErrNotFound,
+		)
+	}
+
+	return r.feeRule(ctx, r.Pool(), accountID, op)
+}
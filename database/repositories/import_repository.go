@@ -0,0 +1,348 @@
+package repositories
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/fmiskovic/cash-me-if-you-can/database"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/account"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/imports"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/transaction"
+)
+
+var (
+	//go:embed sql/import_product_codes.sql
+	selectProductCodesSql string
+	//go:embed sql/import_existing_owners.sql
+	selectExistingOwnersSql string
+	//go:embed sql/import_lock_accounts.sql
+	lockImportAccountsSql string
+	//go:embed sql/import_existing_references.sql
+	selectExistingReferencesSql string
+)
+
+var (
+	accountCopyColumns     = []string{"id", "owner", "balance", "currency", "product"}
+	transactionCopyColumns = []string{"id", "account_id", "amount", "type", "description", "external_reference", "counterparty", "metadata"}
+)
+
+// errDryRun rolls back the transaction of a dry run.
+var errDryRun = errors.New("dry run")
+
+type ImportRepository struct {
+	baseRepository
+}
+
+func NewImportRepository(db database.Service) ImportRepository {
+	return ImportRepository{
+		baseRepository: newBaseRepository(db),
+	}
+}
+
+// ImportAccounts checks the rows against the stored accounts and products and copies the accepted ones
+// along with the deposits of their initial balances.
+func (r ImportRepository) ImportAccounts(
+	ctx context.Context,
+	dryRun bool,
+	chunks func(fn func([]imports.AccountRow) error) error,
+) ([]imports.Result, error) {
+	var results []imports.Result
+
+	// execute inside transaction and rollback on error or dry run
+	err := r.Execute(ctx, func(tx pgx.Tx) error {
+		products, err := r.productCodes(ctx, tx)
+		if err != nil {
+			return err
+		}
+		owners := make(map[string]bool) // owners imported so far
+
+		err = chunks(func(rows []imports.AccountRow) error {
+			existing, err := r.existingOwners(ctx, tx, rows)
+			if err != nil {
+				return err
+			}
+
+			var accounts, deposits [][]any
+			for _, row := range rows {
+				currency, product := row.Currency, row.Product
+				if currency == "" {
+					currency = account.DefaultCurrency
+				}
+				if product == "" {
+					product = account.DefaultProduct
+				}
+
+				switch {
+				case !products[product]:
+					results = append(results, importRejected(row.Line, fmt.Sprintf("product %s does not exist", product)))
+					continue
+				case existing[row.Owner] || owners[row.Owner]:
+					results = append(results, importRejected(row.Line, fmt.Sprintf("account with owner %s already exists", row.Owner)))
+					continue
+				}
+				owners[row.Owner] = true
+
+				id := uuid.NewString()
+				accounts = append(accounts, []any{id, row.Owner, row.Balance, currency, product})
+				// deposit of the initial balance
+				deposits = append(deposits, []any{
+					uuid.NewString(), id, row.Balance, transaction.Deposit, "initial balance", nil, nil, nil,
+				})
+				results = append(results, importAccepted(row.Line, id, dryRun))
+			}
+
+			if _, err = tx.CopyFrom(ctx, pgx.Identifier{"accounts"}, accountCopyColumns, pgx.CopyFromRows(accounts)); err != nil {
+				return err
+			}
+			_, err = tx.CopyFrom(ctx, pgx.Identifier{"transactions"}, transactionCopyColumns, pgx.CopyFromRows(deposits))
+			return err
+		})
+		if err != nil {
+			return err
+		}
+
+		if dryRun {
+			return errDryRun
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errDryRun) {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// ImportTransactions books the rows in file order: all accounts are locked before the first row is booked
+// and a withdrawal is rejected if the balance left by the previous rows does not cover it.
+// Imported transactions are historical movements, so no fees are charged.
+func (r ImportRepository) ImportTransactions(
+	ctx context.Context,
+	dryRun bool,
+	accountIDs []string,
+	chunks func(fn func([]imports.TransactionRow) error) error,
+) ([]imports.Result, error) {
+	var results []imports.Result
+
+	// execute inside transaction and rollback on error or dry run
+	err := r.Execute(ctx, func(tx pgx.Tx) error {
+		balances, err := r.lockImportAccounts(ctx, tx, accountIDs)
+		if err != nil {
+			return err
+		}
+		references := make(map[[2]string]bool)
+
+		err = chunks(func(rows []imports.TransactionRow) error {
+			if err := r.existingReferences(ctx, tx, rows, references); err != nil {
+				return err
+			}
+
+			var transactions [][]any
+			deltas := make(map[string]float64)
+			for _, row := range rows {
+				balance, ok := balances[row.AccountID]
+				ref := [2]string{row.AccountID, row.ExternalReference}
+
+				delta := row.Amount
+				if row.Type.IsDebit() {
+					delta = -row.Amount
+				}
+
+				switch {
+				case !ok:
+					results = append(results, importRejected(row.Line, fmt.Sprintf("account with id %s not found", row.AccountID)))
+					continue
+				case row.ExternalReference != "" && references[ref]:
+					results = append(results, importRejected(row.Line,
+						fmt.Sprintf("transaction with external reference %s already exists", row.ExternalReference)))
+					continue
+				case balance+delta < 0:
+					results = append(results, importRejected(row.Line, fmt.Sprintf("%s failed - insufficient funds", row.Type)))
+					continue
+				}
+				if row.ExternalReference != "" {
+					references[ref] = true
+				}
+				balances[row.AccountID] += delta
+				deltas[row.AccountID] += delta
+
+				var metadata any
+				if len(row.Metadata) > 0 {
+					metadata = row.Metadata
+				}
+
+				id := uuid.NewString()
+				transactions = append(transactions, []any{
+					id,
+					row.AccountID,
+					row.Amount,
+					row.Type,
+					nullIfEmpty(row.Description),
+					nullIfEmpty(row.ExternalReference),
+					nullIfEmpty(row.Counterparty),
+					metadata,
+				})
+				results = append(results, importAccepted(row.Line, id, dryRun))
+			}
+
+			if _, err := tx.CopyFrom(ctx, pgx.Identifier{"transactions"}, transactionCopyColumns, pgx.CopyFromRows(transactions)); err != nil {
+				return err
+			}
+
+			// update account balances relatively, so they stay the exact sum of their transactions
+			ids := make([]string, 0, len(deltas))
+			for id := range deltas {
+				ids = append(ids, id)
+			}
+			slices.Sort(ids)
+			for _, id := range ids {
+				if _, err := tx.Exec(ctx, incrementAccountBalanceSql, id, deltas[id]); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		if dryRun {
+			return errDryRun
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errDryRun) {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+func (r ImportRepository) productCodes(ctx context.Context, tx pgx.Tx) (map[string]bool, error) {
+	rows, err := tx.Query(ctx, selectProductCodesSql)
+	if err != nil {
+		return nil, err
+	}
+	codes, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, err
+	}
+
+	products := make(map[string]bool, len(codes))
+	for _, code := range codes {
+		products[code] = true
+	}
+	return products, nil
+}
+
+func (r ImportRepository) existingOwners(ctx context.Context, tx pgx.Tx, rows []imports.AccountRow) (map[string]bool, error) {
+	owners := make([]string, len(rows))
+	for i, row := range rows {
+		owners[i] = row.Owner
+	}
+
+	res, err := tx.Query(ctx, selectExistingOwnersSql, owners)
+	if err != nil {
+		return nil, err
+	}
+	found, err := pgx.CollectRows(res, pgx.RowTo[string])
+	if err != nil {
+		return nil, err
+	}
+
+	existing := make(map[string]bool, len(found))
+	for _, owner := range found {
+		existing[owner] = true
+	}
+	return existing, nil
+}
+
+// lockImportAccounts locks the accounts and returns their balances.
+// Ids that are not valid are left out, their rows are rejected as not found.
+func (r ImportRepository) lockImportAccounts(ctx context.Context, tx pgx.Tx, accountIDs []string) (map[string]float64, error) {
+	balances := make(map[string]float64, len(accountIDs))
+
+	ids := make([]string, 0, len(accountIDs))
+	for _, id := range accountIDs {
+		if err := uuid.Validate(id); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return balances, nil
+	}
+
+	res, err := tx.Query(ctx, lockImportAccountsSql, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Close()
+
+	for res.Next() {
+		var (
+			id      string
+			balance float64
+		)
+		if err = res.Scan(&id, &balance); err != nil {
+			return nil, err
+		}
+		balances[id] = balance
+	}
+	return balances, res.Err()
+}
+
+// existingReferences adds the external references of the rows that are already stored.
+func (r ImportRepository) existingReferences(
+	ctx context.Context,
+	tx pgx.Tx,
+	rows []imports.TransactionRow,
+	references map[[2]string]bool,
+) error {
+	var ids, refs []string
+	for _, row := range rows {
+		if row.ExternalReference == "" || uuid.Validate(row.AccountID) != nil {
+			continue
+		}
+		ids = append(ids, row.AccountID)
+		refs = append(refs, row.ExternalReference)
+	}
+	if len(refs) == 0 {
+		return nil
+	}
+
+	res, err := tx.Query(ctx, selectExistingReferencesSql,
+		ids,  // $1
+		refs, // $2
+	)
+	if err != nil {
+		return err
+	}
+	defer res.Close()
+
+	for res.Next() {
+		var ref [2]string
+		if err = res.Scan(&ref[0], &ref[1]); err != nil {
+			return err
+		}
+		references[ref] = true
+	}
+	return res.Err()
+}
+
+func importRejected(line int, reason string) imports.Result {
+	return imports.Result{Line: line, Status: imports.Rejected, Error: reason}
+}
+
+// importAccepted reports the id only if the row is stored.
+func importAccepted(line int, id string, dryRun bool) imports.Result {
+	if dryRun {
+		return imports.Result{Line: line, Status: imports.Valid}
+	}
+	return imports.Result{Line: line, Status: imports.Imported, ID: id}
+}
@@ -0,0 +1,156 @@
+package repositories
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/fmiskovic/cash-me-if-you-can/database"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/interest"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/transaction"
+	"github.com/fmiskovic/cash-me-if-you-can/pkg/errorx"
+)
+
+var (
+	//go:embed sql/interest_last_run.sql
+	selectInterestLastRunSql string
+	//go:embed sql/interest_positions.sql
+	selectInterestPositionsSql string
+	//go:embed sql/interest_accrual_insert.sql
+	insertInterestAccrualSql string
+	//go:embed sql/interest_accrual_uncapitalized_sum.sql
+	sumUncapitalizedInterestSql string
+	//go:embed sql/interest_accrual_capitalize.sql
+	capitalizeInterestSql string
+	//go:embed sql/interest_run_insert.sql
+	insertInterestRunSql string
+)
+
+type InterestRepository struct {
+	baseRepository
+}
+
+func NewInterestRepository(db database.Service) InterestRepository {
+	return InterestRepository{
+		baseRepository: newBaseRepository(db),
+	}
+}
+
+func (r InterestRepository) LastRun(ctx context.Context) (*time.Time, error) {
+	var last *time.Time
+	if err := r.Pool().QueryRow(ctx, selectInterestLastRunSql).Scan(&last); err != nil {
+		return nil, err
+	}
+	return last, nil
+}
+
+func (r InterestRepository) Positions(ctx context.Context, date time.Time) ([]interest.Position, error) {
+	endOfDay := interest.Date(date).AddDate(0, 0, 1)
+
+	rows, err := r.Pool().Query(ctx, selectInterestPositionsSql, endOfDay)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var positions []interest.Position
+	for rows.Next() {
+		var p interest.Position
+		if err = rows.Scan(
+			&p.AccountID,
+			&p.Balance,
+			&p.Product.Code,
+			&p.Product.AnnualRate,
+			&p.Product.DayCount,
+			&p.Product.Capitalization,
+		); err != nil {
+			return nil, err
+		}
+		positions = append(positions, p)
+	}
+
+	return positions, rows.Err()
+}
+
+func (r InterestRepository) SaveAccruals(ctx context.Context, accruals []interest.Accrual) error {
+	if len(accruals) == 0 {
+		return nil
+	}
+
+	// execute inside transaction and rollback on error
+	return r.Execute(ctx, func(tx pgx.Tx) error {
+		b := &pgx.Batch{}
+		for _, a := range accruals {
+			b.Queue(insertInterestAccrualSql,
+				a.AccountID,  // $1
+				a.Date,       // $2
+				a.Balance,    // $3
+				a.AnnualRate, // $4
+				a.DayCount,   // $5
+				a.Amount,     // $6
+			)
+		}
+		return tx.SendBatch(ctx, b).Close()
+	})
+}
+
+func (r InterestRepository) Capitalize(ctx context.Context, accountID string, date time.Time) (*transaction.Transaction, error) {
+	date = interest.Date(date)
+
+	var t *transaction.Transaction
+
+	// execute inside transaction and rollback on error
+	err := r.Execute(ctx, func(tx pgx.Tx) error {
+		// the account lock serializes concurrent capitalizations of the same account
+		_, err := r.lockAccountById(ctx, tx, accountID)
+		if err != nil {
+			return err
+		}
+
+		var amount float64
+		if err = tx.QueryRow(ctx, sumUncapitalizedInterestSql, accountID, date).Scan(&amount); err != nil {
+			return err
+		}
+		if amount <= 0 {
+			return nil
+		}
+
+		t = transaction.New(
+			transaction.WithAccountID(accountID),
+			transaction.WithType(transaction.Interest),
+			transaction.WithAmount(amount),
+			transaction.WithDescription(fmt.Sprintf("interest until %s", date.Format(time.DateOnly))),
+			transaction.WithExternalReference("interest:"+date.Format(time.DateOnly)),
+		)
+
+		if _, err = tx.Exec(ctx, incrementAccountBalanceSql, accountID, amount); err != nil {
+			return err
+		}
+		if err = r.insertTransaction(ctx, tx, t); err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(ctx, capitalizeInterestSql, accountID, date, t.ID)
+		return err
+	})
+
+	var errx *errorx.Error
+	if errors.As(err, &errx) && errx.Type == errorx.ErrConflict {
+		// interest until this date has already been capitalized
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+func (r InterestRepository) CompleteRun(ctx context.Context, run interest.Run) error {
+	_, err := r.Pool().Exec(ctx, insertInterestRunSql, interest.Date(run.Date), run.Accrued)
+	return err
+}
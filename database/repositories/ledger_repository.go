@@ -0,0 +1,96 @@
+package repositories
+
+import (
+	"context"
+	_ "embed"
+
+	"github.com/fmiskovic/cash-me-if-you-can/database"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/ledger"
+)
+
+var (
+	//go:embed sql/ledger_account_count.sql
+	countLedgerAccountsSql string
+	//go:embed sql/ledger_drifts.sql
+	selectLedgerDriftsSql string
+	//go:embed sql/ledger_unmatched_legs.sql
+	selectLedgerUnmatchedLegsSql string
+)
+
+type LedgerRepository struct {
+	baseRepository
+}
+
+func NewLedgerRepository(db database.Service) LedgerRepository {
+	return LedgerRepository{
+		baseRepository: newBaseRepository(db),
+	}
+}
+
+func (r LedgerRepository) CountAccounts(ctx context.Context) (int, error) {
+	var n int
+	if err := r.Pool().QueryRow(ctx, countLedgerAccountsSql).Scan(&n); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+func (r LedgerRepository) Drifts(ctx context.Context) ([]ledger.Drift, error) {
+	// every account and transaction is read, so the query is not limited by the statement timeout
+	tx, err := beginUnbounded(ctx, r.Pool())
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	rows, err := tx.Query(ctx, selectLedgerDriftsSql)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var drifts []ledger.Drift
+	for rows.Next() {
+		var d ledger.Drift
+		if err = rows.Scan(&d.AccountID, &d.Stored, &d.Expected); err != nil {
+			return nil, err
+		}
+		drifts = append(drifts, d)
+	}
+
+	return drifts, rows.Err()
+}
+
+func (r LedgerRepository) UnmatchedLegs(ctx context.Context) ([]ledger.UnmatchedLeg, error) {
+	// every account and transaction is read, so the query is not limited by the statement timeout
+	tx, err := beginUnbounded(ctx, r.Pool())
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	rows, err := tx.Query(ctx, selectLedgerUnmatchedLegsSql)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var legs []ledger.UnmatchedLeg
+	for rows.Next() {
+		var l ledger.UnmatchedLeg
+		if err = rows.Scan(
+			&l.AccountID,
+			&l.CounterpartyID,
+			&l.Debit,
+			&l.Amount,
+			&l.CreatedAt,
+			&l.Legs,
+			&l.Matched,
+		); err != nil {
+			return nil, err
+		}
+		legs = append(legs, l)
+	}
+
+	return legs, rows.Err()
+}
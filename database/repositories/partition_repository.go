@@ -0,0 +1,164 @@
+package repositories
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/fmiskovic/cash-me-if-you-can/database"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/partition"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/transaction"
+	"github.com/fmiskovic/cash-me-if-you-can/pkg/errorx"
+)
+
+var (
+	//go:embed sql/partition_create.sql
+	createPartitionSql string
+	//go:embed sql/partition_select.sql
+	selectPartitionsSql string
+	//go:embed sql/partition_transactions.sql
+	selectPartitionTransactionsSql string
+	//go:embed sql/partition_archive_insert.sql
+	insertPartitionArchiveSql string
+
+	// the statements below take the quoted partition name, identifiers can not be bound
+
+	//go:embed sql/partition_lock.sql
+	lockPartitionSql string
+	//go:embed sql/partition_detach.sql
+	detachPartitionSql string
+	//go:embed sql/partition_count.sql
+	countPartitionSql string
+	//go:embed sql/partition_archive_balances.sql
+	archivePartitionBalancesSql string
+	//go:embed sql/partition_drop.sql
+	dropPartitionSql string
+)
+
+type PartitionRepository struct {
+	baseRepository
+}
+
+func NewPartitionRepository(db database.Service) PartitionRepository {
+	return PartitionRepository{
+		baseRepository: newBaseRepository(db),
+	}
+}
+
+func (r PartitionRepository) Create(ctx context.Context, p partition.Partition) (bool, error) {
+	var created bool
+	if err := r.Pool().QueryRow(ctx, createPartitionSql, p.From).Scan(&created); err != nil {
+		return false, err
+	}
+	return created, nil
+}
+
+func (r PartitionRepository) Partitions(ctx context.Context) ([]partition.Partition, error) {
+	rows, err := r.Pool().Query(ctx, selectPartitionsSql)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var partitions []partition.Partition
+	for rows.Next() {
+		var name string
+		if err = rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		if p, ok := partition.Parse(name); ok {
+			partitions = append(partitions, p)
+		}
+	}
+
+	return partitions, rows.Err()
+}
+
+// Transactions reads from the primary, the archive must not miss transactions the replica has not received yet.
+// A partition is read as a whole, so it is not limited by the statement timeout.
+func (r PartitionRepository) Transactions(
+	ctx context.Context,
+	p partition.Partition,
+	fn func(transaction.Transaction) error,
+) error {
+	tx, err := beginUnbounded(ctx, r.Pool())
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	rows, err := tx.Query(ctx, selectPartitionTransactionsSql,
+		p.From, // $1
+		p.To,   // $2
+	)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var t transaction.Transaction
+		if err = rows.Scan(transactionColumns(&t)...); err != nil {
+			return err
+		}
+		if err = fn(t); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// Archive counts and sums the partition under a lock of the partition alone, so the month is scanned while the
+// transactions table stays available. Detaching locks the whole table until commit, it is done last, only the archive
+// record and the drop follow. The table has a default partition, so it can not be detached concurrently.
+func (r PartitionRepository) Archive(ctx context.Context, a partition.Archive) error {
+	name := pgx.Identifier{a.Partition.Name}.Sanitize()
+
+	// execute inside transaction and rollback on error
+	return r.Execute(ctx, func(tx pgx.Tx) error {
+		// the partition is counted and summed as a whole
+		if err := disableTimeout(ctx, tx); err != nil {
+			return err
+		}
+
+		// nothing is booked into the partition from here on, it is a past month
+		if _, err := tx.Exec(ctx, fmt.Sprintf(lockPartitionSql, name)); err != nil {
+			return err
+		}
+
+		var n int64
+		if err := tx.QueryRow(ctx, fmt.Sprintf(countPartitionSql, name)).Scan(&n); err != nil {
+			return err
+		}
+		if n != a.Transactions {
+			return errorx.NewError(
+				fmt.Errorf("partition %s holds %d transactions, %d were archived", a.Partition.Name, n, a.Transactions),
+				errorx.ErrConflict,
+			)
+		}
+
+		if _, err := tx.Exec(ctx, fmt.Sprintf(archivePartitionBalancesSql, name)); err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec(ctx, fmt.Sprintf(detachPartitionSql, name)); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, insertPartitionArchiveSql,
+			a.Partition.Name, // $1
+			a.Partition.From, // $2
+			a.Partition.To,   // $3
+			a.Transactions,   // $4
+			a.Location,       // $5
+			a.ArchivedAt,     // $6
+		); err != nil {
+			return err
+		}
+
+		_, err := tx.Exec(ctx, fmt.Sprintf(dropPartitionSql, name))
+		return err
+	})
+}
@@ -0,0 +1,362 @@
+package repositories
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/fmiskovic/cash-me-if-you-can/database"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/reconciliation"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/transaction"
+	"github.com/fmiskovic/cash-me-if-you-can/pkg/errorx"
+)
+
+var (
+	//go:embed sql/reconciliation_run_insert.sql
+	insertReconciliationRunSql string
+	//go:embed sql/reconciliation_line_insert.sql
+	insertReconciliationLineSql string
+	//go:embed sql/reconciliation_run_select_by_id.sql
+	selectReconciliationRunByIdSql string
+	//go:embed sql/reconciliation_line_select_by_run_id.sql
+	selectReconciliationLinesSql string
+	//go:embed sql/reconciliation_line_lock.sql
+	lockReconciliationLineSql string
+	//go:embed sql/reconciliation_line_match.sql
+	matchReconciliationLineSql string
+	//go:embed sql/reconciliation_line_unmatch.sql
+	unmatchReconciliationLineSql string
+	//go:embed sql/reconciliation_candidates.sql
+	selectReconciliationCandidatesSql string
+	//go:embed sql/reconciliation_run_lock.sql
+	lockReconciliationRunsSql string
+)
+
+type ReconciliationRepository struct {
+	baseRepository
+}
+
+func NewReconciliationRepository(db database.Service) ReconciliationRepository {
+	return ReconciliationRepository{
+		baseRepository: newBaseRepository(db),
+	}
+}
+
+// Import stages the run and matches its lines in one transaction, a failed match leaves no run behind.
+func (r ReconciliationRepository) Import(
+	ctx context.Context,
+	run *reconciliation.Run,
+	lines []reconciliation.Line,
+	match func([]transaction.Transaction) []reconciliation.Pair,
+	at time.Time,
+) error {
+	// execute inside transaction and rollback on error
+	return r.Execute(ctx, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, lockReconciliationRunsSql, run.AccountID); err != nil {
+			return err
+		}
+		if err := r.createRun(ctx, tx, run, lines); err != nil {
+			return err
+		}
+
+		candidates, err := r.candidates(ctx, tx, run.AccountID, run.PeriodStart, run.PeriodEnd)
+		if err != nil {
+			return err
+		}
+		for _, p := range match(candidates) {
+			if err = r.matchLine(ctx, tx, p.LineID, p.TransactionID, reconciliation.Auto, at); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r ReconciliationRepository) Get(ctx context.Context, id string) (*reconciliation.Run, error) {
+	run := new(reconciliation.Run)
+	var dateTolerance int64
+	err := r.Pool().QueryRow(ctx, selectReconciliationRunByIdSql, id).Scan(
+		&run.ID,
+		&run.AccountID,
+		&run.Format,
+		&run.Rules.AmountTolerance,
+		&dateTolerance,
+		&run.PeriodStart,
+		&run.PeriodEnd,
+		&run.CreatedAt,
+		&run.UpdatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, errorx.NewError(
+			fmt.Errorf("reconciliation run with id %s not found", id),
+			errorx.ErrNotFound,
+		)
+	}
+	if err != nil {
+		return nil, err
+	}
+	run.Rules.DateTolerance = time.Duration(dateTolerance) * time.Second
+	return run, nil
+}
+
+func (r ReconciliationRepository) Lines(ctx context.Context, runID string) ([]reconciliation.Line, error) {
+	rows, err := r.Pool().Query(ctx, selectReconciliationLinesSql, runID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var lines []reconciliation.Line
+	for rows.Next() {
+		var l reconciliation.Line
+		if err = rows.Scan(lineColumns(&l)...); err != nil {
+			return nil, err
+		}
+		lines = append(lines, l)
+	}
+
+	return lines, rows.Err()
+}
+
+func (r ReconciliationRepository) Candidates(
+	ctx context.Context,
+	accountID string,
+	start, end time.Time,
+) ([]transaction.Transaction, error) {
+	return r.candidates(ctx, r.Pool(), accountID, start, end)
+}
+
+func (r ReconciliationRepository) MatchLine(
+	ctx context.Context,
+	runID, lineID, transactionID string,
+	at time.Time,
+) (*reconciliation.Line, error) {
+	var l *reconciliation.Line
+
+	// execute inside transaction and rollback on error
+	err := r.Execute(ctx, func(tx pgx.Tx) error {
+		var (
+			accountID string
+			err       error
+		)
+		l, accountID, err = r.lockLine(ctx, tx, runID, lineID)
+		if err != nil {
+			return err
+		}
+		if l.Matched() {
+			return errorx.NewError(
+				fmt.Errorf("line with id %s is already matched to transaction %s", lineID, l.TransactionID),
+				errorx.ErrConflict,
+			)
+		}
+
+		var t transaction.Transaction
+		err = tx.QueryRow(ctx, selectTransactionByIdSql, transactionID).Scan(transactionColumns(&t)...)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return errorx.NewError(
+				fmt.Errorf("transaction with id %s not found", transactionID),
+				errorx.ErrNotFound,
+			)
+		}
+		if err != nil {
+			return err
+		}
+		if t.AccountID != accountID {
+			return errorx.NewError(
+				fmt.Errorf("transaction with id %s does not belong to account %s", transactionID, accountID),
+				errorx.ErrInvalidInput,
+			)
+		}
+
+		if err = r.matchLine(ctx, tx, lineID, transactionID, reconciliation.Manual, at); err != nil {
+			return err
+		}
+		l.TransactionID, l.MatchKind, l.MatchedAt = transactionID, reconciliation.Manual, &at
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return l, nil
+}
+
+func (r ReconciliationRepository) UnmatchLine(ctx context.Context, runID, lineID string) (*reconciliation.Line, error) {
+	var l *reconciliation.Line
+
+	// execute inside transaction and rollback on error
+	err := r.Execute(ctx, func(tx pgx.Tx) error {
+		var err error
+		l, _, err = r.lockLine(ctx, tx, runID, lineID)
+		if err != nil {
+			return err
+		}
+		if !l.Matched() {
+			return errorx.NewError(
+				fmt.Errorf("line with id %s is not matched", lineID),
+				errorx.ErrConflict,
+			)
+		}
+
+		if _, err = tx.Exec(ctx, unmatchReconciliationLineSql, lineID); err != nil {
+			return err
+		}
+		l.TransactionID, l.MatchKind, l.MatchedAt = "", reconciliation.Unmatched, nil
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return l, nil
+}
+
+// createRun inserts the run with its lines, setting their ids.
+func (r ReconciliationRepository) createRun(
+	ctx context.Context,
+	tx pgx.Tx,
+	run *reconciliation.Run,
+	lines []reconciliation.Line,
+) error {
+	err := tx.QueryRow(ctx, insertReconciliationRunSql,
+		run.AccountID,                     // $1
+		run.Format,                        // $2
+		run.Rules.AmountTolerance,         // $3
+		run.Rules.DateTolerance.Seconds(), // $4
+		run.PeriodStart,                   // $5
+		run.PeriodEnd,                     // $6
+	).Scan(&run.ID, &run.CreatedAt, &run.UpdatedAt)
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23503" { // foreign_key_violation
+		return errorx.NewError(
+			fmt.Errorf("account with id %s not found", run.AccountID),
+			errorx.ErrNotFound,
+		)
+	}
+	if err != nil {
+		return err
+	}
+
+	batch := &pgx.Batch{}
+	for i := range lines {
+		l := &lines[i]
+		l.RunID = run.ID
+		batch.Queue(insertReconciliationLineSql,
+			l.RunID,                    // $1
+			l.LineNo,                   // $2
+			l.BookedAt,                 // $3
+			l.Amount,                   // $4
+			nullIfEmpty(l.Reference),   // $5
+			nullIfEmpty(l.Description), // $6
+		).QueryRow(func(row pgx.Row) error {
+			return row.Scan(&l.ID)
+		})
+	}
+
+	err = tx.SendBatch(ctx, batch).Close()
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" { // unique_violation
+		return errorx.NewError(
+			errors.New("statement has duplicate line numbers"),
+			errorx.ErrInvalidInput,
+		)
+	}
+	return err
+}
+
+// lockLine selects the line of the run for update along with the account of the run.
+func (r ReconciliationRepository) lockLine(
+	ctx context.Context,
+	tx pgx.Tx,
+	runID, lineID string,
+) (*reconciliation.Line, string, error) {
+	l := new(reconciliation.Line)
+	var accountID string
+	err := tx.QueryRow(ctx, lockReconciliationLineSql,
+		lineID, // $1
+		runID,  // $2
+	).Scan(append(lineColumns(l), &accountID)...)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, "", errorx.NewError(
+			fmt.Errorf("line with id %s of reconciliation run %s not found", lineID, runID),
+			errorx.ErrNotFound,
+		)
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	return l, accountID, nil
+}
+
+func (r ReconciliationRepository) matchLine(
+	ctx context.Context,
+	tx pgx.Tx,
+	lineID, transactionID string,
+	kind reconciliation.MatchKind,
+	at time.Time,
+) error {
+	tag, err := tx.Exec(ctx, matchReconciliationLineSql,
+		lineID,        // $1
+		transactionID, // $2
+		kind,          // $3
+		at,            // $4
+	)
+
+	if violatedConstraint(err) == "reconciliation_lines_transaction_id_key" {
+		return errorx.NewError(
+			fmt.Errorf("transaction with id %s is already matched", transactionID),
+			errorx.ErrConflict,
+		)
+	}
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return errorx.NewError(
+			fmt.Errorf("line with id %s is already matched", lineID),
+			errorx.ErrConflict,
+		)
+	}
+	return nil
+}
+
+// candidates reads the unmatched transactions of the account booked in the period.
+func (r ReconciliationRepository) candidates(
+	ctx context.Context,
+	q querier,
+	accountID string,
+	start, end time.Time,
+) ([]transaction.Transaction, error) {
+	rows, err := q.Query(ctx, selectReconciliationCandidatesSql,
+		accountID, // $1
+		start,     // $2
+		end,       // $3
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var transactions []transaction.Transaction
+	for rows.Next() {
+		var t transaction.Transaction
+		if err = rows.Scan(transactionColumns(&t)...); err != nil {
+			return nil, err
+		}
+		transactions = append(transactions, t)
+	}
+
+	return transactions, rows.Err()
+}
+
+func lineColumns(l *reconciliation.Line) []any {
+	return []any{
+		&l.ID, &l.RunID, &l.LineNo, &l.BookedAt, &l.Amount, &l.Reference, &l.Description,
+		&l.TransactionID, &l.MatchKind, &l.MatchedAt,
+	}
+}
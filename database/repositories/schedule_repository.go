@@ -0,0 +1,227 @@
+package repositories
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/fmiskovic/cash-me-if-you-can/database"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/schedule"
+	"github.com/fmiskovic/cash-me-if-you-can/pkg/errorx"
+)
+
+var (
+	//go:embed sql/schedule_insert.sql
+	insertScheduleSql string
+	//go:embed sql/schedule_select_by_id.sql
+	selectScheduleByIdSql string
+	//go:embed sql/schedule_select_by_account_id.sql
+	selectSchedulesByAccountIdSql string
+	//go:embed sql/schedule_cancel.sql
+	cancelScheduleSql string
+	//go:embed sql/schedule_claim.sql
+	claimSchedulesSql string
+	//go:embed sql/schedule_update_run.sql
+	updateScheduleRunSql string
+	//go:embed sql/schedule_execution_insert.sql
+	insertScheduleExecutionSql string
+	//go:embed sql/schedule_execution_select_by_schedule_id.sql
+	selectScheduleExecutionsSql string
+)
+
+type ScheduleRepository struct {
+	baseRepository
+}
+
+func NewScheduleRepository(db database.Service) ScheduleRepository {
+	return ScheduleRepository{
+		baseRepository: newBaseRepository(db),
+	}
+}
+
+func (r ScheduleRepository) Create(ctx context.Context, s *schedule.Schedule) (*schedule.Schedule, error) {
+	if s == nil {
+		return nil, errorx.NewError(
+			errors.New("schedule input is nil"),
+			errorx.ErrInvalidInput,
+		)
+	}
+	if s.Amount <= 0 {
+		return nil, errorx.NewError(
+			errors.New("invalid amount"),
+			errorx.ErrInvalidInput,
+		)
+	}
+
+	err := r.Pool().QueryRow(ctx, insertScheduleSql,
+		s.FromAccountID,            // $1
+		s.ToAccountID,              // $2
+		s.Amount,                   // $3
+		nullIfEmpty(s.Description), // $4
+		s.StartAt,                  // $5
+		s.Frequency,                // $6
+		max(s.Interval, 1),         // $7
+		s.Until,                    // $8
+		s.MaxRuns,                  // $9
+		s.NextRunAt,                // $10
+		s.Status,                   // $11
+	).Scan(&s.ID, &s.CreatedAt, &s.UpdatedAt)
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23503" { // foreign_key_violation
+		return nil, errorx.NewError(
+			errors.New("account not found"),
+			errorx.ErrNotFound,
+		)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (r ScheduleRepository) Get(ctx context.Context, id string) (*schedule.Schedule, error) {
+	s := new(schedule.Schedule)
+	err := r.Pool().
+		QueryRow(ctx, selectScheduleByIdSql, id).
+		Scan(scheduleColumns(s)...)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, errorx.NewError(
+			fmt.Errorf("schedule with id %s not found", id),
+			errorx.ErrNotFound,
+		)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (r ScheduleRepository) List(ctx context.Context, req schedule.ListRequest) ([]schedule.Schedule, error) {
+	// first check if account exists
+	var exist bool
+	err := r.Pool().QueryRow(ctx, accountExistSql, req.AccountID).Scan(&exist)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return nil, errorx.NewError(
+			errors.New("account not found"),
+			errorx.ErrNotFound,
+		)
+	}
+
+	rows, err := r.Pool().Query(ctx, selectSchedulesByAccountIdSql, req.AccountID)
+	if err != nil {
+		return nil, err
+	}
+	return scanSchedules(rows)
+}
+
+func (r ScheduleRepository) Cancel(ctx context.Context, id string) (*schedule.Schedule, error) {
+	s := new(schedule.Schedule)
+	err := r.Pool().
+		QueryRow(ctx, cancelScheduleSql, id).
+		Scan(scheduleColumns(s)...)
+	if errors.Is(err, pgx.ErrNoRows) {
+		// distinguish between missing and no longer active schedules
+		if _, err = r.Get(ctx, id); err != nil {
+			return nil, err
+		}
+		return nil, errorx.NewError(
+			fmt.Errorf("schedule with id %s is not active", id),
+			errorx.ErrConflict,
+		)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (r ScheduleRepository) Executions(ctx context.Context, id string) ([]schedule.Execution, error) {
+	rows, err := r.Pool().Query(ctx, selectScheduleExecutionsSql, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var executions []schedule.Execution
+	for rows.Next() {
+		var e schedule.Execution
+		if err = rows.Scan(&e.ID, &e.ScheduleID, &e.ScheduledAt, &e.ExecutedAt, &e.Status, &e.Error); err != nil {
+			return nil, err
+		}
+		executions = append(executions, e)
+	}
+
+	return executions, rows.Err()
+}
+
+func (r ScheduleRepository) Claim(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]schedule.Schedule, error) {
+	rows, err := r.Pool().Query(ctx, claimSchedulesSql,
+		now,   // $1
+		limit, // $2
+		lease, // $3
+	)
+	if err != nil {
+		return nil, err
+	}
+	return scanSchedules(rows)
+}
+
+func (r ScheduleRepository) Complete(ctx context.Context, s *schedule.Schedule, e *schedule.Execution) error {
+	// execute inside transaction and rollback on error
+	return r.Execute(ctx, func(tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, insertScheduleExecutionSql,
+			e.ScheduleID,         // $1
+			e.ScheduledAt,        // $2
+			e.ExecutedAt,         // $3
+			e.Status,             // $4
+			nullIfEmpty(e.Error), // $5
+		).Scan(&e.ID)
+		// the occurrence has already been recorded
+		if errors.Is(err, pgx.ErrNoRows) {
+			err = nil
+		}
+		if err != nil {
+			return err
+		}
+
+		return tx.QueryRow(ctx, updateScheduleRunSql,
+			s.ID,        // $1
+			s.Runs,      // $2
+			s.NextRunAt, // $3
+			s.Status,    // $4
+		).Scan(&s.UpdatedAt)
+	})
+}
+
+func scanSchedules(rows pgx.Rows) ([]schedule.Schedule, error) {
+	defer rows.Close()
+
+	var schedules []schedule.Schedule
+	for rows.Next() {
+		var s schedule.Schedule
+		if err := rows.Scan(scheduleColumns(&s)...); err != nil {
+			return nil, err
+		}
+		schedules = append(schedules, s)
+	}
+
+	return schedules, rows.Err()
+}
+
+// scheduleColumns returns scan destinations matching the schedule select queries.
+func scheduleColumns(s *schedule.Schedule) []any {
+	return []any{
+		&s.ID, &s.FromAccountID, &s.ToAccountID, &s.Amount, &s.Description, &s.StartAt, &s.Frequency,
+		&s.Interval, &s.Until, &s.MaxRuns, &s.Runs, &s.NextRunAt, &s.Status, &s.CreatedAt, &s.UpdatedAt,
+	}
+}
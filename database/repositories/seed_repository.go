@@ -0,0 +1,26 @@
+package repositories
+
+import (
+	"context"
+	_ "embed"
+
+	"github.com/fmiskovic/cash-me-if-you-can/database"
+)
+
+//go:embed sql/seed_reset.sql
+var resetSql string
+
+type SeedRepository struct {
+	baseRepository
+}
+
+func NewSeedRepository(db database.Service) SeedRepository {
+	return SeedRepository{
+		baseRepository: newBaseRepository(db),
+	}
+}
+
+func (r SeedRepository) Reset(ctx context.Context) error {
+	_, err := r.Pool().Exec(ctx, resetSql)
+	return err
+}
@@ -1,3 +1,3 @@
-INSERT INTO accounts (owner, balance)
-VALUES ($1, $2)
-RETURNING id;
\ No newline at end of file
+INSERT INTO accounts (owner, balance, currency, product)
+VALUES ($1, $2, $3, $4)
+RETURNING id, status, created_at, updated_at;
@@ -1,2 +1,2 @@
-SELECT id, owner, balance FROM accounts WHERE id = $1 FOR UPDATE;
--- Locks the selected row for update.
\ No newline at end of file
+SELECT id, owner, balance, currency, status, product, created_at, updated_at FROM accounts WHERE id = $1 FOR UPDATE;
+-- Locks the selected row for update.
@@ -1,3 +1,12 @@
-SELECT a.id, a.owner, a.balance FROM accounts AS a ORDER BY a.created_at LIMIT $1 OFFSET $2;
-
-
+SELECT a.id, a.owner, a.balance, a.currency, a.status, a.product, a.created_at, a.updated_at
+FROM accounts AS a
+WHERE ($1::text IS NULL OR a.owner ILIKE $1)
+  AND ($2::text IS NULL OR a.status = $2)
+  AND ($3::text IS NULL OR a.currency = $3)
+  AND ($4::numeric IS NULL OR a.balance >= $4)
+  AND ($5::numeric IS NULL OR a.balance <= $5)
+  AND ($6::timestamptz IS NULL OR a.created_at >= $6)
+  AND ($7::timestamptz IS NULL OR a.created_at < $7)
+  AND ($8::timestamptz IS NULL OR a.updated_at > $8)
+ORDER BY %s, a.id
+LIMIT $9 OFFSET $10;
@@ -1 +1,10 @@
-SELECT COUNT(a.id) FROM accounts AS a;
\ No newline at end of file
+SELECT COUNT(a.id)
+FROM accounts AS a
+WHERE ($1::text IS NULL OR a.owner ILIKE $1)
+  AND ($2::text IS NULL OR a.status = $2)
+  AND ($3::text IS NULL OR a.currency = $3)
+  AND ($4::numeric IS NULL OR a.balance >= $4)
+  AND ($5::numeric IS NULL OR a.balance <= $5)
+  AND ($6::timestamptz IS NULL OR a.created_at >= $6)
+  AND ($7::timestamptz IS NULL OR a.created_at < $7)
+  AND ($8::timestamptz IS NULL OR a.updated_at > $8);
@@ -1 +0,0 @@
-UPDATE accounts SET balance = $2 WHERE id = $1;
\ No newline at end of file
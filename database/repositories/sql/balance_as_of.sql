@@ -0,0 +1,29 @@
+-- the balance is rolled forward from the last snapshot before $2 taken after the archived months,
+-- otherwise rolled back from the first snapshot after it or from the current balance
+SELECT a.id, a.currency,
+       CASE
+           WHEN $2 < a.created_at THEN 0
+           WHEN prev.taken_at IS NOT NULL THEN prev.balance + COALESCE((
+               SELECT SUM(CASE WHEN t.type IN ('withdrawal', 'fee') THEN -t.amount ELSE t.amount END)
+               FROM transactions AS t
+               WHERE t.account_id = a.id AND t.timestamp >= prev.taken_at AND t.timestamp < $2
+           ), 0)
+           ELSE COALESCE(next.balance, a.balance) - COALESCE((
+               SELECT SUM(CASE WHEN t.type IN ('withdrawal', 'fee') THEN -t.amount ELSE t.amount END)
+               FROM transactions AS t
+               WHERE t.account_id = a.id AND t.timestamp >= $2 AND (next.taken_at IS NULL OR t.timestamp < next.taken_at)
+           ), 0)
+       END
+FROM accounts AS a
+LEFT JOIN LATERAL (
+    SELECT s.taken_at, s.balance FROM balance_snapshots AS s
+    WHERE s.account_id = a.id AND s.taken_at <= $2
+      AND s.taken_at >= COALESCE((SELECT MAX(range_end) FROM transaction_archives), '-infinity')
+    ORDER BY s.taken_at DESC LIMIT 1
+) AS prev ON TRUE
+LEFT JOIN LATERAL (
+    SELECT s.taken_at, s.balance FROM balance_snapshots AS s
+    WHERE s.account_id = a.id AND s.taken_at > $2
+    ORDER BY s.taken_at LIMIT 1
+) AS next ON TRUE
+WHERE a.id = $1;
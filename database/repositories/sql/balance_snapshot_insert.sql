@@ -0,0 +1,10 @@
+-- balance at $1 is the current balance without the transactions made after it
+INSERT INTO balance_snapshots (account_id, taken_at, balance)
+SELECT a.id, $1, a.balance - COALESCE((
+    SELECT SUM(CASE WHEN t.type IN ('withdrawal', 'fee') THEN -t.amount ELSE t.amount END)
+    FROM transactions AS t
+    WHERE t.account_id = a.id AND t.timestamp >= $1
+), 0)
+FROM accounts AS a
+WHERE a.created_at < $1
+ON CONFLICT (account_id, taken_at) DO NOTHING;
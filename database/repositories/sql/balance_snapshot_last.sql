@@ -0,0 +1 @@
+SELECT MAX(taken_at) FROM balance_snapshots;
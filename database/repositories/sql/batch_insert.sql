@@ -0,0 +1,3 @@
+INSERT INTO transfer_batches (mode, status)
+VALUES ($1, $2)
+RETURNING id, created_at;
@@ -0,0 +1,2 @@
+INSERT INTO transfer_batch_items (batch_id, position, from_account_id, to_account_id, amount, status, error, from_transaction_id, to_transaction_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9);
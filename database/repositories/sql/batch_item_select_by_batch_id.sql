@@ -0,0 +1,5 @@
+SELECT i.position, i.from_account_id, i.to_account_id, i.amount, i.status, COALESCE(i.error, ''),
+       COALESCE(i.from_transaction_id::text, ''), COALESCE(i.to_transaction_id::text, '')
+FROM transfer_batch_items AS i
+WHERE i.batch_id = $1
+ORDER BY i.position;
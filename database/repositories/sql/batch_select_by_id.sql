@@ -0,0 +1,3 @@
+SELECT b.id, b.mode, b.status, b.created_at
+FROM transfer_batches AS b
+WHERE b.id = $1;
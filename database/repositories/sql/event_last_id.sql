@@ -0,0 +1,9 @@
+-- select the id of the latest committed event, of all accounts if the account is not set
+SELECT COALESCE((
+    SELECT id
+    FROM account_events
+    WHERE xid < pg_snapshot_xmin(pg_current_snapshot())
+      AND ($1::uuid IS NULL OR account_id = $1)
+    ORDER BY xid DESC, id DESC
+    LIMIT 1
+), 0);
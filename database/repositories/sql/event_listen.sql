@@ -0,0 +1,2 @@
+-- listen for the account ids of committed events
+LISTEN account_events;
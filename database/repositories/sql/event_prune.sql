@@ -0,0 +1,8 @@
+-- delete a batch of the events recorded before the given time
+DELETE FROM account_events
+WHERE id IN (
+    SELECT id FROM account_events
+    WHERE created_at < $1
+    ORDER BY created_at
+    LIMIT $2
+);
@@ -0,0 +1,13 @@
+-- select the events recorded after the given event in order, of all accounts if the account is not set.
+-- Events of transactions still in progress and everything after them are left for a later read: below the oldest
+-- running transaction every event is committed or rolled back, so no event is committed before the returned ones later
+SELECT e.id, e.account_id, e.type, e.data, e.created_at
+FROM account_events AS e
+WHERE (e.xid, e.id) > (
+        COALESCE((SELECT a.xid FROM account_events AS a WHERE a.id = $2), '0'::xid8),
+        $2
+    )
+  AND e.xid < pg_snapshot_xmin(pg_current_snapshot())
+  AND ($1::uuid IS NULL OR e.account_id = $1)
+ORDER BY e.xid, e.id
+LIMIT $3;
@@ -0,0 +1,9 @@
+DECLARE export_transactions NO SCROLL CURSOR FOR
+SELECT t.id, t.account_id, t.type, t.amount, t.timestamp, t.created_at, t.updated_at,
+       COALESCE(t.description, ''), COALESCE(t.external_reference, ''), COALESCE(t.counterparty, ''), t.metadata
+FROM transactions AS t
+WHERE ($1::uuid IS NULL OR t.account_id = $1)
+  AND ($2::timestamptz IS NULL OR t.created_at >= $2)
+  AND ($3::timestamptz IS NULL OR t.created_at < $3)
+  AND ($4::text IS NULL OR t.type = $4)
+ORDER BY t.created_at, t.id;
@@ -0,0 +1 @@
+FETCH FORWARD 1000 FROM export_transactions;
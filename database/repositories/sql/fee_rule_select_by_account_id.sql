@@ -0,0 +1,5 @@
+SELECT f.id, f.product, f.operation, f.kind, f.amount, f.rate, COALESCE(f.tiers, '[]'::jsonb),
+       f.min_fee, f.max_fee, f.revenue_account_id
+FROM fee_rules AS f
+JOIN accounts AS a ON a.product = f.product
+WHERE a.id = $1 AND f.operation = $2;
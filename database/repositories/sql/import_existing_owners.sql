@@ -0,0 +1 @@
+SELECT owner FROM accounts WHERE owner = ANY($1);
@@ -0,0 +1,5 @@
+-- references of archived transactions are kept, so an archived transaction is not imported again
+SELECT account_id, external_reference
+FROM transaction_references
+WHERE account_id = ANY($1::UUID[])
+  AND external_reference = ANY($2);
@@ -0,0 +1,3 @@
+-- locks all accounts of an import in id order before any of them is written, like every other writer locks
+-- its accounts, so an import can not deadlock with concurrent imports and transfers
+SELECT id, balance FROM accounts WHERE id = ANY($1::UUID[]) ORDER BY id FOR UPDATE;
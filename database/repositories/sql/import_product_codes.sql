@@ -0,0 +1 @@
+SELECT code FROM products;
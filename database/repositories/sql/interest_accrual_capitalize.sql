@@ -0,0 +1,3 @@
+UPDATE interest_accruals
+SET transaction_id = $3
+WHERE account_id = $1 AND accrual_date <= $2 AND transaction_id IS NULL;
@@ -0,0 +1,3 @@
+INSERT INTO interest_accruals (account_id, accrual_date, balance, annual_rate, day_count, amount)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (account_id, accrual_date) DO NOTHING;
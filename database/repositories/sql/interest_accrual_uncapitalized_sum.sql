@@ -0,0 +1,3 @@
+SELECT COALESCE(SUM(i.amount), 0)
+FROM interest_accruals AS i
+WHERE i.account_id = $1 AND i.accrual_date <= $2 AND i.transaction_id IS NULL;
@@ -0,0 +1,2 @@
+SELECT MAX(r.accrual_date)
+FROM interest_runs AS r;
@@ -0,0 +1,14 @@
+-- end-of-day balance is the current balance without the transactions made after the day ended
+SELECT a.id,
+       a.balance - COALESCE((
+           SELECT SUM(CASE WHEN t.type IN ('withdrawal', 'fee') THEN -t.amount ELSE t.amount END)
+           FROM transactions AS t
+           WHERE t.account_id = a.id AND t.timestamp >= $1
+       ), 0),
+       p.code, p.annual_rate, p.day_count, p.capitalization
+FROM accounts AS a
+JOIN products AS p ON p.code = a.product
+WHERE p.annual_rate > 0
+  AND a.status <> 'closed'
+  AND a.created_at < $1
+ORDER BY a.id;
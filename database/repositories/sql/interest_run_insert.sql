@@ -0,0 +1,3 @@
+INSERT INTO interest_runs (accrual_date, accounts)
+VALUES ($1, $2)
+ON CONFLICT (accrual_date) DO UPDATE SET accounts = EXCLUDED.accounts, completed_at = NOW();
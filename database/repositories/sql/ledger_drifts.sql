@@ -0,0 +1,11 @@
+-- expected balance is the sum of the signed amounts of all transactions of the account, archived ones included
+SELECT a.id, a.balance, e.balance
+FROM accounts AS a
+CROSS JOIN LATERAL (
+    SELECT COALESCE(SUM(CASE WHEN t.type IN ('withdrawal', 'fee') THEN -t.amount ELSE t.amount END), 0)
+           + COALESCE((SELECT b.amount FROM transaction_archive_balances AS b WHERE b.account_id = a.id), 0) AS balance
+    FROM transactions AS t
+    WHERE t.account_id = a.id
+) AS e
+WHERE a.balance <> e.balance
+ORDER BY a.id;
@@ -0,0 +1,27 @@
+-- transactions with an account as counterparty are transfer or fee legs, every debit leg must be matched
+-- by a credit leg of the counterparty booked together with the same amount and vice versa
+WITH legs AS (
+    SELECT l.account_id, l.counterparty_id, l.debit, l.amount, l.created_at, COUNT(*) AS legs
+    FROM (
+        SELECT t.account_id,
+               CASE WHEN t.counterparty ~* '^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$'
+                   THEN t.counterparty::uuid END AS counterparty_id,
+               t.type IN ('withdrawal', 'fee') AS debit,
+               t.amount,
+               t.created_at
+        FROM transactions AS t
+        WHERE t.counterparty IS NOT NULL
+    ) AS l
+    JOIN accounts AS c ON c.id = l.counterparty_id
+    GROUP BY l.account_id, l.counterparty_id, l.debit, l.amount, l.created_at
+)
+SELECT l.account_id, l.counterparty_id, l.debit, l.amount, l.created_at, l.legs, COALESCE(m.legs, 0)
+FROM legs AS l
+LEFT JOIN legs AS m
+       ON m.account_id = l.counterparty_id
+      AND m.counterparty_id = l.account_id
+      AND m.debit <> l.debit
+      AND m.amount = l.amount
+      AND m.created_at = l.created_at
+WHERE l.legs <> COALESCE(m.legs, 0)
+ORDER BY l.created_at, l.account_id;
@@ -0,0 +1,5 @@
+INSERT INTO transaction_archive_balances (account_id, amount)
+SELECT t.account_id, SUM(CASE WHEN t.type IN ('withdrawal', 'fee') THEN -t.amount ELSE t.amount END)
+FROM %s AS t
+GROUP BY t.account_id
+ON CONFLICT (account_id) DO UPDATE SET amount = transaction_archive_balances.amount + EXCLUDED.amount;
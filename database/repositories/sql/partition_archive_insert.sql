@@ -0,0 +1,2 @@
+INSERT INTO transaction_archives (partition, range_start, range_end, transactions, location, archived_at)
+VALUES ($1, $2, $3, $4, $5, $6);
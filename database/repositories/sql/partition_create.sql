@@ -0,0 +1 @@
+SELECT create_transactions_partition($1::date) IS NOT NULL;
@@ -0,0 +1 @@
+ALTER TABLE transactions DETACH PARTITION %s;
@@ -0,0 +1,2 @@
+-- blocks writes to the partition only, the transactions table stays readable and writable
+LOCK TABLE %s IN SHARE MODE;
@@ -0,0 +1,6 @@
+SELECT c.relname
+FROM pg_inherits AS i
+JOIN pg_class AS c ON c.oid = i.inhrelid
+WHERE i.inhparent = 'transactions'::regclass
+  AND c.relname <> 'transactions_default'
+ORDER BY c.relname;
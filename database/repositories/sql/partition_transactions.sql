@@ -0,0 +1,7 @@
+-- the range on the partition key prunes the scan to the partition
+SELECT t.id, t.account_id, t.type, t.amount, t.timestamp, t.created_at, t.updated_at,
+       COALESCE(t.description, ''), COALESCE(t.external_reference, ''), COALESCE(t.counterparty, ''), t.metadata
+FROM transactions AS t
+WHERE t.timestamp >= $1
+  AND t.timestamp < $2
+ORDER BY t.created_at, t.id;
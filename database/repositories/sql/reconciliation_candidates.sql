@@ -0,0 +1,8 @@
+-- transactions of the account booked in the period that no statement line is matched to
+SELECT t.id, t.account_id, t.type, t.amount, t.timestamp, t.created_at, t.updated_at,
+       COALESCE(t.description, ''), COALESCE(t.external_reference, ''), COALESCE(t.counterparty, ''), t.metadata
+FROM transactions AS t
+WHERE t.account_id = $1
+  AND t.timestamp BETWEEN $2 AND $3
+  AND NOT EXISTS (SELECT 1 FROM reconciliation_lines AS l WHERE l.transaction_id = t.id)
+ORDER BY t.timestamp, t.id;
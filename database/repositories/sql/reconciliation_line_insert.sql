@@ -0,0 +1,3 @@
+INSERT INTO reconciliation_lines (run_id, line_no, booked_at, amount, reference, description)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id;
@@ -0,0 +1,7 @@
+SELECT l.id, l.run_id, l.line_no, l.booked_at, l.amount, COALESCE(l.reference, ''), COALESCE(l.description, ''),
+       COALESCE(l.transaction_id::TEXT, ''), COALESCE(l.match_kind, ''), l.matched_at, r.account_id
+FROM reconciliation_lines AS l
+         JOIN reconciliation_runs AS r ON r.id = l.run_id
+WHERE l.id = $1
+  AND l.run_id = $2
+FOR UPDATE OF l;
@@ -0,0 +1,6 @@
+UPDATE reconciliation_lines
+SET transaction_id = $2,
+    match_kind     = $3,
+    matched_at     = $4
+WHERE id = $1
+  AND transaction_id IS NULL;
@@ -0,0 +1,5 @@
+SELECT l.id, l.run_id, l.line_no, l.booked_at, l.amount, COALESCE(l.reference, ''), COALESCE(l.description, ''),
+       COALESCE(l.transaction_id::TEXT, ''), COALESCE(l.match_kind, ''), l.matched_at
+FROM reconciliation_lines AS l
+WHERE l.run_id = $1
+ORDER BY l.line_no;
@@ -0,0 +1,5 @@
+UPDATE reconciliation_lines
+SET transaction_id = NULL,
+    match_kind     = NULL,
+    matched_at     = NULL
+WHERE id = $1;
@@ -0,0 +1,3 @@
+INSERT INTO reconciliation_runs (account_id, format, amount_tolerance, date_tolerance, period_start, period_end)
+VALUES ($1, $2, $3, make_interval(secs => $4), $5, $6)
+RETURNING id, created_at, updated_at;
@@ -0,0 +1,2 @@
+-- imports of an account are serialized until commit, so they do not match the same transactions
+SELECT pg_advisory_xact_lock(hashtext('reconciliation_runs'), hashtext($1::text));
@@ -0,0 +1,4 @@
+SELECT r.id, r.account_id, r.format, r.amount_tolerance, EXTRACT(EPOCH FROM r.date_tolerance)::BIGINT,
+       r.period_start, r.period_end, r.created_at, r.updated_at
+FROM reconciliation_runs AS r
+WHERE r.id = $1;
@@ -0,0 +1,5 @@
+UPDATE scheduled_transfers
+SET status = 'cancelled', next_run_at = NULL, claimed_until = NULL
+WHERE id = $1 AND status = 'active'
+RETURNING id, from_account_id, to_account_id, amount, COALESCE(description, ''), start_at, frequency,
+          interval_count, until, max_runs, runs, next_run_at, status, created_at, updated_at;
@@ -0,0 +1,14 @@
+UPDATE scheduled_transfers
+SET claimed_until = $1::timestamptz + $3::interval
+WHERE id IN (
+    SELECT id
+    FROM scheduled_transfers
+    WHERE status = 'active'
+      AND next_run_at <= $1
+      AND (claimed_until IS NULL OR claimed_until < $1)
+    ORDER BY next_run_at
+    LIMIT $2
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING id, from_account_id, to_account_id, amount, COALESCE(description, ''), start_at, frequency,
+          interval_count, until, max_runs, runs, next_run_at, status, created_at, updated_at;
@@ -0,0 +1,4 @@
+INSERT INTO scheduled_transfer_executions (schedule_id, scheduled_at, executed_at, status, error)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (schedule_id, scheduled_at) DO NOTHING
+RETURNING id;
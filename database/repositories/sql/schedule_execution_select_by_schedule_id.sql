@@ -0,0 +1,4 @@
+SELECT e.id, e.schedule_id, e.scheduled_at, e.executed_at, e.status, COALESCE(e.error, '')
+FROM scheduled_transfer_executions AS e
+WHERE e.schedule_id = $1
+ORDER BY e.scheduled_at;
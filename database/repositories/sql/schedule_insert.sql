@@ -0,0 +1,3 @@
+INSERT INTO scheduled_transfers (from_account_id, to_account_id, amount, description, start_at, frequency, interval_count, until, max_runs, next_run_at, status)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+RETURNING id, created_at, updated_at;
@@ -0,0 +1,5 @@
+SELECT s.id, s.from_account_id, s.to_account_id, s.amount, COALESCE(s.description, ''), s.start_at, s.frequency,
+       s.interval_count, s.until, s.max_runs, s.runs, s.next_run_at, s.status, s.created_at, s.updated_at
+FROM scheduled_transfers AS s
+WHERE s.from_account_id = $1 OR s.to_account_id = $1
+ORDER BY s.created_at DESC, s.id;
@@ -0,0 +1,4 @@
+UPDATE scheduled_transfers
+SET runs = $2, next_run_at = $3, status = $4, claimed_until = NULL
+WHERE id = $1
+RETURNING updated_at;
@@ -0,0 +1,18 @@
+-- fee rules are deleted with the accounts their revenue is booked to, products are kept
+TRUNCATE TABLE
+    account_events,
+    balance_snapshots,
+    reconciliation_lines,
+    reconciliation_runs,
+    interest_accruals,
+    interest_runs,
+    scheduled_transfer_executions,
+    scheduled_transfers,
+    transfer_batch_items,
+    transfer_batches,
+    transaction_archive_balances,
+    transaction_archives,
+    transaction_references,
+    transactions,
+    fee_rules,
+    accounts;
@@ -0,0 +1,9 @@
+-- balances at the start and the end of the period are the current balance without the transactions made after them
+SELECT a.id, a.currency,
+       a.balance - COALESCE(SUM(CASE WHEN t.type IN ('withdrawal', 'fee') THEN -t.amount ELSE t.amount END), 0),
+       a.balance - COALESCE(SUM(CASE WHEN t.type IN ('withdrawal', 'fee') THEN -t.amount ELSE t.amount END)
+                            FILTER (WHERE t.timestamp >= $3), 0)
+FROM accounts AS a
+LEFT JOIN transactions AS t ON t.account_id = a.id AND t.timestamp >= $2
+WHERE a.id = $1
+GROUP BY a.id;
@@ -0,0 +1,20 @@
+-- the running balance is the opening balance plus the movements booked so far
+WITH opening AS (
+    SELECT a.balance - COALESCE((
+        SELECT SUM(CASE WHEN t.type IN ('withdrawal', 'fee') THEN -t.amount ELSE t.amount END)
+        FROM transactions AS t
+        WHERE t.account_id = a.id AND t.timestamp >= $2
+    ), 0) AS balance
+    FROM accounts AS a
+    WHERE a.id = $1
+)
+SELECT t.id, t.account_id, t.type, t.amount, t.timestamp, t.created_at, t.updated_at,
+       COALESCE(t.description, ''), COALESCE(t.external_reference, ''), COALESCE(t.counterparty, ''), t.metadata,
+       o.balance + SUM(CASE WHEN t.type IN ('withdrawal', 'fee') THEN -t.amount ELSE t.amount END)
+                   OVER (ORDER BY t.timestamp, t.id)
+FROM transactions AS t
+CROSS JOIN opening AS o
+WHERE t.account_id = $1
+  AND t.timestamp >= $2
+  AND t.timestamp < $3
+ORDER BY t.timestamp, t.id;
@@ -0,0 +1,2 @@
+-- lifts the statement timeout until the end of the transaction
+SET LOCAL statement_timeout = 0;
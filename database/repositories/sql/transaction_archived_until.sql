@@ -0,0 +1,2 @@
+-- transactions booked before the end of the latest archived partition may be kept in archive files only
+SELECT MAX(range_end) FROM transaction_archives;
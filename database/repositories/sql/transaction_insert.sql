@@ -1,3 +1,3 @@
-INSERT INTO transactions (account_id, amount, type, timestamp)
-VALUES ($1, $2, $3, CURRENT_TIMESTAMP)
-RETURNING id, timestamp;
\ No newline at end of file
+INSERT INTO transactions (account_id, amount, type, timestamp, description, external_reference, counterparty, metadata)
+VALUES ($1, $2, $3, CURRENT_TIMESTAMP, $4, $5, $6, $7)
+RETURNING id, timestamp, created_at, updated_at;
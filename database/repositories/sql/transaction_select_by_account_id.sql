@@ -1,4 +1,9 @@
-SELECT t.id, t.account_id, t.type, t.amount, t.timestamp
+SELECT t.id, t.account_id, t.type, t.amount, t.timestamp, t.created_at, t.updated_at,
+       COALESCE(t.description, ''), COALESCE(t.external_reference, ''), COALESCE(t.counterparty, ''), t.metadata
 FROM transactions AS t
 WHERE t.account_id = $1
-ORDER BY t.timestamp DESC;
\ No newline at end of file
+  AND ($2::timestamptz IS NULL OR t.created_at >= $2)
+  AND ($3::timestamptz IS NULL OR t.created_at < $3)
+  AND ($4::timestamptz IS NULL OR t.updated_at > $4)
+  AND ($5::text IS NULL OR t.external_reference = $5)
+ORDER BY t.timestamp DESC;
@@ -1,3 +1,4 @@
-SELECT t.id, t.account_id, t.type, t.amount, t.timestamp
+SELECT t.id, t.account_id, t.type, t.amount, t.timestamp, t.created_at, t.updated_at,
+       COALESCE(t.description, ''), COALESCE(t.external_reference, ''), COALESCE(t.counterparty, ''), t.metadata
 FROM transactions AS t
-WHERE t.id = $1;
\ No newline at end of file
+WHERE t.id = $1;
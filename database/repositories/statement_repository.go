@@ -0,0 +1,113 @@
+package repositories
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/fmiskovic/cash-me-if-you-can/database"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/statement"
+	"github.com/fmiskovic/cash-me-if-you-can/pkg/errorx"
+)
+
+var (
+	//go:embed sql/statement_balances.sql
+	selectStatementBalancesSql string
+	//go:embed sql/statement_entries.sql
+	selectStatementEntriesSql string
+)
+
+type StatementRepository struct {
+	baseRepository
+}
+
+func NewStatementRepository(db database.Service) StatementRepository {
+	return StatementRepository{
+		baseRepository: newBaseRepository(db),
+	}
+}
+
+// Snapshot begins a repeatable read, read-only transaction, the header and the entries of a statement are read
+// from the same committed state. The entries are streamed at the pace of the reader, so the transaction
+// is not limited by the statement timeout.
+func (r StatementRepository) Snapshot(ctx context.Context) (statement.Snapshot, error) {
+	tx, err := r.Pool().BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return nil, err
+	}
+	if err = disableTimeout(ctx, tx); err != nil {
+		_ = tx.Rollback(ctx)
+		return nil, err
+	}
+	return statementSnapshot{tx: tx}, nil
+}
+
+// statementSnapshot reads statements inside a transaction that is only read from, so it is always rolled back.
+type statementSnapshot struct {
+	tx pgx.Tx
+}
+
+func (s statementSnapshot) Balances(ctx context.Context, accountID string, from, to time.Time) (*statement.Statement, error) {
+	if err := rejectArchived(ctx, s.tx, from); err != nil {
+		return nil, err
+	}
+
+	st := new(statement.Statement)
+	err := s.tx.QueryRow(ctx, selectStatementBalancesSql,
+		accountID, // $1
+		from,      // $2
+		to,        // $3
+	).Scan(&st.AccountID, &st.Currency, &st.OpeningBalance, &st.ClosingBalance)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, errorx.NewError(
+			fmt.Errorf("account with id %s not found", accountID),
+			errorx.ErrNotFound,
+		)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return st, nil
+}
+
+// Entries reads the movements row by row, so the period is never held in memory as a whole.
+func (s statementSnapshot) Entries(
+	ctx context.Context,
+	accountID string,
+	from, to time.Time,
+	fn func(statement.Entry) error,
+) error {
+	if err := rejectArchived(ctx, s.tx, from); err != nil {
+		return err
+	}
+
+	rows, err := s.tx.Query(ctx, selectStatementEntriesSql,
+		accountID, // $1
+		from,      // $2
+		to,        // $3
+	)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var e statement.Entry
+		if err = rows.Scan(append(transactionColumns(&e.Transaction), &e.Balance)...); err != nil {
+			return err
+		}
+		if err = fn(e); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+func (s statementSnapshot) Close(ctx context.Context) error {
+	return s.tx.Rollback(ctx)
+}
@@ -2,16 +2,17 @@ package tests
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
-	"github.com/fmiskovic/cash-me-if-you-can/database/repositories"
 	"github.com/fmiskovic/cash-me-if-you-can/internal"
 	"github.com/fmiskovic/cash-me-if-you-can/internal/account"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/transaction"
 )
 
-func (s *RepositoriesTestSuite) TestGetAccount() {
-	repo := repositories.NewAccountRepository(s.dbService)
+func (s *StorageTestSuite) TestGetAccount() {
+	repo := s.accounts
 
 	tests := []struct {
 		name    string
@@ -37,7 +38,7 @@ func (s *RepositoriesTestSuite) TestGetAccount() {
 
 	for _, tt := range tests {
 		s.T().Run(tt.name, func(t *testing.T) {
-			acc, err := repo.Get(s.dbContainer.Ctx, tt.id)
+			acc, err := repo.Get(s.ctx, tt.id)
 
 			tt.wantErr(t, err, "Get() error = %v, wantErr %v", err, tt.wantErr)
 			if err != nil {
@@ -49,48 +50,54 @@ func (s *RepositoriesTestSuite) TestGetAccount() {
 	}
 }
 
-func (s *RepositoriesTestSuite) TestListAccounts() {
-	repo := repositories.NewAccountRepository(s.dbService)
+func (s *StorageTestSuite) TestListAccounts() {
+	repo := s.accounts
 
 	tests := []struct {
 		name    string
-		input   internal.PageRequest
+		input   account.ListRequest
 		wantErr assert.ErrorAssertionFunc
 	}{
 		{
 			name: "two accounts per page",
-			input: internal.PageRequest{
-				Limit:  2,
-				Offset: 0,
+			input: account.ListRequest{
+				PageRequest: internal.PageRequest{
+					Limit:  2,
+					Offset: 0,
+				},
 			},
 			wantErr: assert.NoError,
 		},
 		{
 			name: "all accounts at the page",
-			input: internal.PageRequest{
-				Limit:  10,
-				Offset: 0,
+			input: account.ListRequest{
+				PageRequest: internal.PageRequest{
+					Limit:  10,
+					Offset: 0,
+				},
 			},
 			wantErr: assert.NoError,
 		},
 		{
 			name: "empty page",
-			input: internal.PageRequest{
-				Limit:  10,
-				Offset: 10,
+			input: account.ListRequest{
+				PageRequest: internal.PageRequest{
+					Limit:  10,
+					Offset: 10,
+				},
 			},
 			wantErr: assert.NoError,
 		},
 		{
 			name:    "empty page when page request is empty",
-			input:   internal.PageRequest{},
+			input:   account.ListRequest{},
 			wantErr: assert.NoError,
 		},
 	}
 
 	for _, tt := range tests {
 		s.T().Run(tt.name, func(t *testing.T) {
-			page, err := repo.List(s.dbContainer.Ctx, tt.input)
+			page, err := repo.List(s.ctx, tt.input)
 
 			tt.wantErr(t, err, "List() error = %v, wantErr %v", err, tt.wantErr)
 			if err != nil {
@@ -104,8 +111,99 @@ func (s *RepositoriesTestSuite) TestListAccounts() {
 	}
 }
 
-func (s *RepositoriesTestSuite) TestCreateAccount() {
-	repo := repositories.NewAccountRepository(s.dbService)
+func (s *StorageTestSuite) TestSearchAccounts() {
+	repo := s.accounts
+
+	minBalance := 1000.0
+	future := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name       string
+		input      account.ListRequest
+		wantCount  int
+		wantFirst  string
+		wantOwners []string
+	}{
+		{
+			name: "owner substring is case-insensitive",
+			input: account.ListRequest{
+				PageRequest: internal.DefaultPageRequest(),
+				Owner:       "LI",
+			},
+			wantCount:  2,
+			wantOwners: []string{"Alice", "Charlie"},
+		},
+		{
+			name: "owner wildcard characters are escaped",
+			input: account.ListRequest{
+				PageRequest: internal.DefaultPageRequest(),
+				Owner:       "_",
+			},
+			wantCount: 0,
+		},
+		{
+			name: "filter by currency",
+			input: account.ListRequest{
+				PageRequest: internal.DefaultPageRequest(),
+				Currency:    "EUR",
+			},
+			wantCount: 0,
+		},
+		{
+			name: "filter by status and min balance",
+			input: account.ListRequest{
+				PageRequest: internal.DefaultPageRequest(),
+				Status:      account.Active,
+				MinBalance:  &minBalance,
+			},
+			wantCount:  1,
+			wantOwners: []string{"Alice"},
+		},
+		{
+			name: "nothing updated since the future",
+			input: account.ListRequest{
+				PageRequest: internal.DefaultPageRequest(),
+				TimeFilter:  internal.TimeFilter{UpdatedSince: &future},
+			},
+			wantCount: 0,
+		},
+		{
+			name: "sort by owner descending",
+			input: account.ListRequest{
+				PageRequest: internal.PageRequest{Limit: 1},
+				SortBy:      account.SortByOwner,
+				SortOrder:   account.Desc,
+			},
+			wantCount: 4,
+			wantFirst: "David",
+		},
+	}
+
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			page, err := repo.List(s.ctx, tt.input)
+			s.Require().NoError(err)
+
+			s.Assert().Equal(tt.wantCount, page.TotalItems)
+
+			if tt.wantFirst != "" {
+				s.Require().NotEmpty(page.Items)
+				s.Assert().Equal(tt.wantFirst, page.Items[0].Owner)
+			}
+
+			if tt.wantOwners != nil {
+				owners := make([]string, len(page.Items))
+				for i, acc := range page.Items {
+					owners[i] = acc.Owner
+				}
+				s.Assert().ElementsMatch(tt.wantOwners, owners)
+			}
+		})
+	}
+}
+
+func (s *StorageTestSuite) TestCreateAccount() {
+	repo := s.accounts
 
 	tests := []struct {
 		name    string
@@ -145,7 +243,7 @@ func (s *RepositoriesTestSuite) TestCreateAccount() {
 
 	for _, tt := range tests {
 		s.T().Run(tt.name, func(t *testing.T) {
-			acc, err := repo.Create(s.dbContainer.Ctx, tt.input)
+			acc, err := repo.Create(s.ctx, tt.input)
 
 			tt.wantErr(t, err, "Create() error = %v, wantErr %v", err, tt.wantErr)
 			if err != nil {
@@ -155,14 +253,24 @@ func (s *RepositoriesTestSuite) TestCreateAccount() {
 			s.Assert().NotEmpty(acc.ID)
 			s.Assert().Equal(tt.input.Owner, acc.Owner)
 			s.Assert().Equal(tt.input.Balance, acc.Balance)
+			s.Assert().False(acc.CreatedAt.IsZero())
+			s.Assert().Equal(acc.CreatedAt, acc.UpdatedAt)
 
 			// assert if balance is correctly stored in the database
-			got, err := repo.Get(s.dbContainer.Ctx, acc.ID)
+			got, err := repo.Get(s.ctx, acc.ID)
 			s.Assert().NoError(err)
 			s.Assert().Equal(tt.input.Balance, got.Balance)
 
+			// the initial balance is booked as a deposit
+			trs, err := s.transactions.
+				GetByAccountId(s.ctx, transaction.ListRequest{AccountID: acc.ID})
+			s.Assert().NoError(err)
+			s.Require().Len(trs, 1)
+			s.Assert().Equal(transaction.Deposit, trs[0].Type)
+			s.Assert().Equal(tt.input.Balance, trs[0].Amount)
+
 			// cleanup
-			err = repo.Delete(s.dbContainer.Ctx, acc.ID)
+			err = s.deleteAccount(s.ctx, acc.ID)
 			s.Assert().NoError(err)
 		})
 	}
@@ -0,0 +1,85 @@
+package tests
+
+import (
+	"time"
+
+	"github.com/fmiskovic/cash-me-if-you-can/database/repositories"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/transaction"
+)
+
+func (s *RepositoriesTestSuite) TestBalanceAsOf() {
+	ctx := s.dbContainer.Ctx
+	repo := repositories.NewBalanceRepository(s.dbService)
+	transactionRepo := repositories.NewTransactionRepository(s.dbService)
+
+	dbNow := func() time.Time {
+		var now time.Time
+		s.Require().NoError(s.dbService.Pool().QueryRow(ctx, "SELECT NOW()").Scan(&now))
+		return now
+	}
+	move := func(tp transaction.Type, amount float64) {
+		_, err := transactionRepo.Create(ctx, transaction.New(
+			transaction.WithAccountID(charlieId),
+			transaction.WithType(tp),
+			transaction.WithAmount(amount),
+		))
+		s.Require().NoError(err)
+	}
+
+	// Charlie receives 40 and spends it again
+	before := dbNow()
+	move(transaction.Deposit, 40)
+	between := dbNow()
+	move(transaction.Withdrawal, 40)
+
+	initial, err := repo.AsOf(ctx, charlieId, before)
+	s.Require().NoError(err)
+	s.Assert().Equal(charlieId, initial.AccountID)
+	s.Assert().NotEmpty(initial.Currency)
+
+	assertBalances := func() {
+		b, err := repo.AsOf(ctx, charlieId, before)
+		s.Require().NoError(err)
+		s.Assert().InDelta(initial.Amount, b.Amount, 1e-9)
+
+		b, err = repo.AsOf(ctx, charlieId, between)
+		s.Require().NoError(err)
+		s.Assert().InDelta(initial.Amount+40, b.Amount, 1e-9)
+
+		b, err = repo.AsOf(ctx, charlieId, dbNow())
+		s.Require().NoError(err)
+		s.Assert().InDelta(initial.Amount, b.Amount, 1e-9)
+	}
+
+	// computed from the current balance
+	assertBalances()
+
+	// computed from a snapshot taken in between
+	n, err := repo.Snapshot(ctx, between)
+	s.Require().NoError(err)
+	s.Assert().Positive(n)
+	defer func() {
+		_, err := s.dbService.Pool().Exec(ctx, "DELETE FROM balance_snapshots WHERE taken_at = $1", between)
+		s.Assert().NoError(err)
+	}()
+
+	last, err := repo.LastSnapshot(ctx)
+	s.Require().NoError(err)
+	s.Require().NotNil(last)
+	s.Assert().True(between.Equal(*last))
+
+	// taking the same snapshot again is a no-op
+	n, err = repo.Snapshot(ctx, between)
+	s.Require().NoError(err)
+	s.Assert().Zero(n)
+
+	assertBalances()
+
+	// no balance before the account existed
+	b, err := repo.AsOf(ctx, charlieId, time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC))
+	s.Require().NoError(err)
+	s.Assert().Zero(b.Amount)
+
+	_, err = repo.AsOf(ctx, missingId, before)
+	s.Assert().Error(err)
+}
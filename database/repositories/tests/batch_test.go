@@ -0,0 +1,132 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/fmiskovic/cash-me-if-you-can/database/repositories"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/batch"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/transaction"
+)
+
+const (
+	aliceId   = "a1b2c3d4-1111-2222-3333-444455556666"
+	bobId     = "b1c2d3e4-2222-3333-4444-555566667777"
+	charlieId = "c1d2e3f4-3333-4444-5555-666677778888"
+	davidId   = "2f6f112a-a8e2-42c3-a6b0-c15e86d01704"
+	missingId = "a1b2c3d4-1111-2222-3333-000000000000"
+)
+
+func newBatch(mode batch.Mode, transfers ...transaction.TransferRequest) *batch.Batch {
+	b := &batch.Batch{Mode: mode}
+	for i, tr := range transfers {
+		from, to := transaction.NewTransferLegs(tr)
+		b.Items = append(b.Items, batch.Item{Position: i, From: from, To: to})
+	}
+	return b
+}
+
+func (s *RepositoriesTestSuite) TestBatchTransfer() {
+	batchRepo := repositories.NewBatchRepository(s.dbService)
+	accountRepo := repositories.NewAccountRepository(s.dbService)
+
+	tests := []struct {
+		name         string
+		input        *batch.Batch
+		wantErr      assert.ErrorAssertionFunc
+		wantStatus   batch.Status
+		wantStatuses []batch.ItemStatus
+	}{
+		{
+			name: "atomic batch",
+			input: newBatch(batch.Atomic,
+				transaction.TransferRequest{FromAccountID: aliceId, ToAccountID: charlieId, Amount: 10},
+				transaction.TransferRequest{FromAccountID: aliceId, ToAccountID: davidId, Amount: 20},
+			),
+			wantErr:      assert.NoError,
+			wantStatus:   batch.Completed,
+			wantStatuses: []batch.ItemStatus{batch.ItemSucceeded, batch.ItemSucceeded},
+		},
+		{
+			name: "atomic batch with insufficient funds",
+			input: newBatch(batch.Atomic,
+				transaction.TransferRequest{FromAccountID: aliceId, ToAccountID: charlieId, Amount: 10},
+				transaction.TransferRequest{FromAccountID: davidId, ToAccountID: charlieId, Amount: 1000000},
+			),
+			wantErr: assert.Error,
+		},
+		{
+			name: "atomic batch with non-existing account",
+			input: newBatch(batch.Atomic,
+				transaction.TransferRequest{FromAccountID: aliceId, ToAccountID: missingId, Amount: 10},
+			),
+			wantErr: assert.Error,
+		},
+		{
+			name: "best effort batch",
+			input: newBatch(batch.BestEffort,
+				transaction.TransferRequest{FromAccountID: aliceId, ToAccountID: charlieId, Amount: 10},
+				transaction.TransferRequest{FromAccountID: davidId, ToAccountID: charlieId, Amount: 1000000},
+				transaction.TransferRequest{FromAccountID: aliceId, ToAccountID: missingId, Amount: 10},
+			),
+			wantErr:      assert.NoError,
+			wantStatus:   batch.PartiallyCompleted,
+			wantStatuses: []batch.ItemStatus{batch.ItemSucceeded, batch.ItemFailed, batch.ItemFailed},
+		},
+	}
+
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			aliceBefore, err := accountRepo.Get(s.dbContainer.Ctx, aliceId)
+			s.Require().NoError(err)
+
+			err = batchRepo.Transfer(s.dbContainer.Ctx, tt.input)
+
+			tt.wantErr(t, err, "Transfer() error = %v, wantErr %v", err, tt.wantErr)
+			if err != nil {
+				// nothing is applied when an atomic batch fails
+				aliceAfter, err := accountRepo.Get(s.dbContainer.Ctx, aliceId)
+				s.Require().NoError(err)
+				s.Assert().Equal(aliceBefore.Balance, aliceAfter.Balance)
+				return
+			}
+
+			s.Assert().NotEmpty(tt.input.ID)
+			s.Assert().Equal(tt.wantStatus, tt.input.Status)
+
+			// assert the batch can be queried later
+			got, err := batchRepo.Get(s.dbContainer.Ctx, tt.input.ID)
+			s.Require().NoError(err)
+			s.Assert().Equal(tt.wantStatus, got.Status)
+			s.Require().Len(got.Items, len(tt.wantStatuses))
+
+			debited := 0.0
+			for i, item := range got.Items {
+				s.Assert().Equal(tt.wantStatuses[i], item.Status)
+				if item.Status == batch.ItemSucceeded {
+					s.Assert().NotEmpty(item.From.ID)
+					s.Assert().NotEmpty(item.To.ID)
+					if item.From.AccountID == aliceId {
+						debited += item.From.Amount
+					}
+				} else {
+					s.Assert().NotEmpty(item.Error)
+					s.Assert().Empty(item.From.ID)
+				}
+			}
+
+			// assert only succeeded items moved money
+			aliceAfter, err := accountRepo.Get(s.dbContainer.Ctx, aliceId)
+			s.Require().NoError(err)
+			s.Assert().InDelta(aliceBefore.Balance-debited, aliceAfter.Balance, 1e-9)
+		})
+	}
+}
+
+func (s *RepositoriesTestSuite) TestGetBatchNotFound() {
+	repo := repositories.NewBatchRepository(s.dbService)
+
+	_, err := repo.Get(s.dbContainer.Ctx, missingId)
+	s.Assert().Error(err)
+}
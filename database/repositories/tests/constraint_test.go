@@ -0,0 +1,57 @@
+package tests
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/fmiskovic/cash-me-if-you-can/database/repositories"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/account"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/transaction"
+	"github.com/fmiskovic/cash-me-if-you-can/pkg/errorx"
+)
+
+func (s *RepositoriesTestSuite) TestMoneyConstraints() {
+	ctx := s.dbContainer.Ctx
+	accountRepo := repositories.NewAccountRepository(s.dbService)
+	transactionRepo := repositories.NewTransactionRepository(s.dbService)
+
+	assertError := func(err error, want errorx.ErrorType, msg string) {
+		var errx *errorx.Error
+		s.Require().ErrorAs(err, &errx)
+		s.Assert().Equal(want, errx.Type)
+		s.Assert().EqualError(err, msg)
+	}
+	assertViolation := func(err error, constraint string) {
+		var pgErr *pgconn.PgError
+		s.Require().True(errors.As(err, &pgErr), "want violation of %s, got %v", constraint, err)
+		s.Assert().Equal(constraint, pgErr.ConstraintName)
+	}
+
+	// violations of the owner constraints are translated by name
+	_, err := accountRepo.Create(ctx, account.New(account.WithOwner("Alice")))
+	assertError(err, errorx.ErrInvalidInput, "account with owner Alice already exists")
+
+	_, err = accountRepo.Create(ctx, account.New(account.WithOwner("  ")))
+	assertError(err, errorx.ErrInvalidInput, "owner is blank")
+
+	// a transaction type the repository does not check is rejected by the schema
+	_, err = transactionRepo.Create(ctx, transaction.New(
+		transaction.WithAccountID(aliceId),
+		transaction.WithType("refund"),
+		transaction.WithAmount(1),
+	))
+	assertError(err, errorx.ErrInvalidInput, "invalid transaction type")
+
+	// writes bypassing the repositories are rejected as well
+	_, err = s.dbService.Pool().Exec(ctx, "UPDATE accounts SET balance = -1 WHERE id = $1", aliceId)
+	assertViolation(err, "accounts_balance_check")
+
+	_, err = s.dbService.Pool().Exec(ctx,
+		"INSERT INTO transactions (account_id, amount, type) VALUES ($1, 0, 'deposit')", aliceId)
+	assertViolation(err, "transactions_amount_check")
+
+	_, err = s.dbService.Pool().Exec(ctx,
+		"INSERT INTO transactions (account_id, amount, type) VALUES ($1, 1, 'refund')", aliceId)
+	assertViolation(err, "transactions_type_check")
+}
@@ -0,0 +1,192 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/fmiskovic/cash-me-if-you-can/database/repositories"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/event"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/transaction"
+	"github.com/fmiskovic/cash-me-if-you-can/pkg/errorx"
+)
+
+func (s *RepositoriesTestSuite) TestEvents() {
+	ctx := s.dbContainer.Ctx
+	repo := repositories.NewEventRepository(s.dbService)
+	transactionRepo := repositories.NewTransactionRepository(s.dbService)
+
+	_, err := repo.LastID(ctx, missingId)
+	var errx *errorx.Error
+	s.Require().ErrorAs(err, &errx)
+	s.Assert().Equal(errorx.ErrNotFound, errx.Type)
+
+	last, err := repo.LastID(ctx, charlieId)
+	s.Require().NoError(err)
+	lastAll, err := repo.LastID(ctx, "")
+	s.Require().NoError(err)
+	s.Assert().GreaterOrEqual(lastAll, last)
+
+	// listen before the deposit is committed
+	listenCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	listening := make(chan struct{})
+	notified := make(chan string, 10)
+	listenErr := make(chan error, 1)
+	go func() {
+		listenErr <- repo.Listen(listenCtx, func() { close(listening) }, func(id string) { notified <- id })
+	}()
+	select {
+	case <-listening:
+	case err = <-listenErr:
+		s.Require().NoError(err)
+	}
+
+	move := func(tp transaction.Type, amount float64) *transaction.Transaction {
+		t, err := transactionRepo.Create(ctx, transaction.New(
+			transaction.WithAccountID(charlieId),
+			transaction.WithType(tp),
+			transaction.WithAmount(amount),
+			transaction.WithDescription("event test"),
+		))
+		s.Require().NoError(err)
+		return t
+	}
+	deposit := move(transaction.Deposit, 15)
+	defer move(transaction.Withdrawal, 15)
+
+	select {
+	case id := <-notified:
+		s.Assert().Equal(charlieId, id)
+	case <-time.After(5 * time.Second):
+		s.Fail("no notification received")
+	}
+
+	events, err := repo.Events(ctx, charlieId, last, 100)
+	s.Require().NoError(err)
+	s.Require().Len(events, 2)
+
+	types := map[event.Type]event.Event{}
+	for i, e := range events {
+		s.Assert().Equal(charlieId, e.AccountID)
+		s.Assert().Greater(e.ID, last)
+		if i > 0 {
+			s.Assert().Greater(e.ID, events[i-1].ID)
+		}
+		types[e.Type] = e
+	}
+
+	var details transaction.Details
+	s.Require().NoError(json.Unmarshal(types[event.TransactionEvent].Data, &details))
+	s.Assert().Equal(deposit.ID, details.TransactionId)
+	s.Assert().Equal(string(transaction.Deposit), details.Type)
+	s.Assert().InDelta(15, details.Amount, 1e-9)
+	s.Assert().Equal("event test", details.Description)
+
+	var balance struct {
+		Balance         float64 `json:"balance"`
+		PreviousBalance float64 `json:"previous_balance"`
+	}
+	s.Require().NoError(json.Unmarshal(types[event.BalanceEvent].Data, &balance))
+	s.Assert().InDelta(15, balance.Balance-balance.PreviousBalance, 1e-9)
+
+	// the limit caps a batch, the stream of all accounts sees the same events
+	events, err = repo.Events(ctx, "", lastAll, 1)
+	s.Require().NoError(err)
+	s.Assert().Len(events, 1)
+
+	cancel()
+	s.Assert().Error(<-listenErr)
+}
+
+func (s *RepositoriesTestSuite) TestEventsCommitOrder() {
+	ctx := s.dbContainer.Ctx
+	repo := repositories.NewEventRepository(s.dbService)
+
+	last, err := repo.LastID(ctx, charlieId)
+	s.Require().NoError(err)
+
+	begin := func() pgx.Tx {
+		tx, err := s.dbService.Pool().Begin(ctx)
+		s.Require().NoError(err)
+		s.T().Cleanup(func() { _ = tx.Rollback(ctx) })
+		return tx
+	}
+	insert := func(tx pgx.Tx) int64 {
+		var id int64
+		s.Require().NoError(tx.QueryRow(ctx,
+			`INSERT INTO account_events (account_id, type, data) VALUES ($1, 'balance', '{}') RETURNING id`,
+			charlieId,
+		).Scan(&id))
+		return id
+	}
+
+	// the first writer starts before the second but records its event after it, with a higher id
+	first, second := begin(), begin()
+	_, err = first.Exec(ctx, "SELECT pg_current_xact_id()")
+	s.Require().NoError(err)
+	secondId := insert(second)
+	firstId := insert(first)
+	s.Require().Greater(firstId, secondId)
+
+	// writers do not wait for each other, the first one commits while the second is still open
+	s.Require().NoError(first.Commit(ctx))
+
+	events, err := repo.Events(ctx, charlieId, last, 100)
+	s.Require().NoError(err)
+	s.Require().Len(events, 1)
+	s.Assert().Equal(firstId, events[0].ID)
+
+	// a stream resuming after the first event still gets the second one with its lower id once it is committed
+	events, err = repo.Events(ctx, charlieId, firstId, 100)
+	s.Require().NoError(err)
+	s.Assert().Empty(events)
+
+	s.Require().NoError(second.Commit(ctx))
+
+	events, err = repo.Events(ctx, charlieId, firstId, 100)
+	s.Require().NoError(err)
+	s.Require().Len(events, 1)
+	s.Assert().Equal(secondId, events[0].ID)
+
+	lastId, err := repo.LastID(ctx, charlieId)
+	s.Require().NoError(err)
+	s.Assert().Equal(secondId, lastId)
+
+	_, err = s.dbService.Pool().Exec(ctx, "DELETE FROM account_events WHERE id IN ($1, $2)", firstId, secondId)
+	s.Assert().NoError(err)
+}
+
+func (s *RepositoriesTestSuite) TestEventsPrune() {
+	ctx := s.dbContainer.Ctx
+	repo := repositories.NewEventRepository(s.dbService)
+
+	before := time.Now().AddDate(0, 0, -90)
+
+	var old, recent int64
+	s.Require().NoError(s.dbService.Pool().QueryRow(ctx,
+		`INSERT INTO account_events (account_id, type, data, created_at) VALUES ($1, 'balance', '{}', $2) RETURNING id`,
+		charlieId, before.Add(-time.Hour),
+	).Scan(&old))
+	s.Require().NoError(s.dbService.Pool().QueryRow(ctx,
+		`INSERT INTO account_events (account_id, type, data) VALUES ($1, 'balance', '{}') RETURNING id`,
+		charlieId,
+	).Scan(&recent))
+	defer func() {
+		_, err := s.dbService.Pool().Exec(ctx, "DELETE FROM account_events WHERE id = $1", recent)
+		s.Assert().NoError(err)
+	}()
+
+	n, err := repo.Prune(ctx, before, 1000)
+	s.Require().NoError(err)
+	s.Assert().GreaterOrEqual(n, int64(1))
+
+	rows, err := s.dbService.Pool().Query(ctx, "SELECT id FROM account_events WHERE id IN ($1, $2)", old, recent)
+	s.Require().NoError(err)
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[int64])
+	s.Require().NoError(err)
+	s.Assert().Equal([]int64{recent}, ids)
+}
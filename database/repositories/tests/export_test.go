@@ -0,0 +1,62 @@
+package tests
+
+import (
+	"errors"
+	"time"
+
+	"github.com/fmiskovic/cash-me-if-you-can/database/repositories"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/export"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/transaction"
+)
+
+func (s *RepositoriesTestSuite) TestExportTransactions() {
+	ctx := s.dbContainer.Ctx
+	repo := repositories.NewExportRepository(s.dbService)
+
+	collect := func(filter export.Filter) []transaction.Transaction {
+		var trs []transaction.Transaction
+		err := repo.Transactions(ctx, filter, func(t transaction.Transaction) error {
+			trs = append(trs, t)
+			return nil
+		})
+		s.Require().NoError(err)
+		return trs
+	}
+
+	all := collect(export.Filter{})
+	s.Require().NotEmpty(all)
+	for i := 1; i < len(all); i++ {
+		s.Assert().False(all[i].CreatedAt.Before(all[i-1].CreatedAt), "transactions are exported in creation order")
+	}
+
+	// the count matches the table, the cursor is read past its first batch if needed
+	var n int
+	s.Require().NoError(s.dbService.Pool().QueryRow(ctx, "SELECT COUNT(*) FROM transactions").Scan(&n))
+	s.Assert().Len(all, n)
+
+	byAccount := collect(export.Filter{AccountID: aliceId})
+	s.Require().NotEmpty(byAccount)
+	for _, t := range byAccount {
+		s.Assert().Equal(aliceId, t.AccountID)
+	}
+
+	deposits := collect(export.Filter{AccountID: aliceId, Type: transaction.Deposit})
+	s.Require().NotEmpty(deposits)
+	for _, t := range deposits {
+		s.Assert().Equal(transaction.Deposit, t.Type)
+	}
+
+	future := time.Now().Add(time.Hour)
+	s.Assert().Empty(collect(export.Filter{From: &future}))
+	s.Assert().Len(collect(export.Filter{To: &future}), n)
+
+	// stop reading at the first callback error
+	stop := errors.New("stop")
+	calls := 0
+	err := repo.Transactions(ctx, export.Filter{}, func(transaction.Transaction) error {
+		calls++
+		return stop
+	})
+	s.Assert().ErrorIs(err, stop)
+	s.Assert().Equal(1, calls)
+}
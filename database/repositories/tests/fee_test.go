@@ -0,0 +1,121 @@
+package tests
+
+import (
+	"sync"
+
+	"github.com/fmiskovic/cash-me-if-you-can/database/repositories"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/account"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/fee"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/transaction"
+)
+
+func (s *RepositoriesTestSuite) TestFees() {
+	ctx := s.dbContainer.Ctx
+	feeRepo := repositories.NewFeeRepository(s.dbService)
+	accountRepo := repositories.NewAccountRepository(s.dbService)
+	transactionRepo := repositories.NewTransactionRepository(s.dbService)
+
+	// price transfers and withdrawals of current accounts for the duration of the test, David collects the fees
+	_, err := s.dbService.Pool().Exec(ctx, `
+		INSERT INTO fee_rules (product, operation, kind, amount, rate, min_fee, revenue_account_id) VALUES
+			($1, 'transfer', 'percent', 0, 0.01, 0.5, $2),
+			($1, 'withdrawal', 'flat', 1, 0, NULL, $2)`,
+		account.DefaultProduct, davidId,
+	)
+	s.Require().NoError(err)
+	defer func() {
+		_, err := s.dbService.Pool().Exec(ctx, "DELETE FROM fee_rules WHERE product = $1", account.DefaultProduct)
+		s.Assert().NoError(err)
+	}()
+
+	balance := func(id string) float64 {
+		acc, err := accountRepo.Get(ctx, id)
+		s.Require().NoError(err)
+		return acc.Balance
+	}
+
+	// quote
+	rule, err := feeRepo.Rule(ctx, aliceId, fee.Transfer)
+	s.Require().NoError(err)
+	s.Require().NotNil(rule)
+	s.Assert().Equal(fee.Percent, rule.Kind)
+	s.Assert().Equal(0.5, rule.Calculate(10))
+
+	rule, err = feeRepo.Rule(ctx, aliceId, fee.Deposit)
+	s.Require().NoError(err)
+	s.Assert().Nil(rule)
+
+	_, err = feeRepo.Rule(ctx, missingId, fee.Transfer)
+	s.Assert().Error(err)
+
+	// transfer is charged with the minimum fee
+	alice, charlie, david := balance(aliceId), balance(charlieId), balance(davidId)
+
+	from, to := transaction.NewTransferLegs(transaction.TransferRequest{
+		FromAccountID: aliceId, ToAccountID: charlieId, Amount: 10,
+	})
+	s.Require().NoError(transactionRepo.Transfer(ctx, from, to))
+	s.Require().NotNil(from.Fee)
+	s.Assert().Equal(transaction.Fee, from.Fee.Type)
+	s.Assert().Equal(0.5, from.Fee.Amount)
+
+	s.Assert().InDelta(alice-10.5, balance(aliceId), 1e-9)
+	s.Assert().InDelta(charlie+10, balance(charlieId), 1e-9)
+	s.Assert().InDelta(david+0.5, balance(davidId), 1e-9)
+
+	stored, err := transactionRepo.GetById(ctx, from.Fee.ID)
+	s.Require().NoError(err)
+	s.Assert().Equal(aliceId, stored.AccountID)
+	s.Assert().Equal(davidId, stored.Counterparty)
+
+	// withdrawal is charged with the flat fee
+	alice, david = balance(aliceId), balance(davidId)
+
+	t, err := transactionRepo.Create(ctx, transaction.New(
+		transaction.WithAccountID(aliceId),
+		transaction.WithType(transaction.Withdrawal),
+		transaction.WithAmount(5),
+	))
+	s.Require().NoError(err)
+	s.Require().NotNil(t.Fee)
+
+	s.Assert().InDelta(alice-6, balance(aliceId), 1e-9)
+	s.Assert().InDelta(david+1, balance(davidId), 1e-9)
+
+	// the fee counts towards the available funds
+	charlie = balance(charlieId)
+	from, to = transaction.NewTransferLegs(transaction.TransferRequest{
+		FromAccountID: charlieId, ToAccountID: aliceId, Amount: charlie,
+	})
+	s.Assert().Error(transactionRepo.Transfer(ctx, from, to))
+	s.Assert().InDelta(charlie, balance(charlieId), 1e-9)
+
+	// the revenue account is locked in order with the others: transfers from Alice credit David with their fee
+	// while David pays Alice, which locks David first, without deadlocking
+	_, err = transactionRepo.Create(ctx, transaction.New(
+		transaction.WithAccountID(davidId),
+		transaction.WithType(transaction.Deposit),
+		transaction.WithAmount(100),
+	))
+	s.Require().NoError(err)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req := transaction.TransferRequest{FromAccountID: aliceId, ToAccountID: charlieId, Amount: 1}
+			if i%2 == 1 {
+				req = transaction.TransferRequest{FromAccountID: davidId, ToAccountID: aliceId, Amount: 1}
+			}
+			from, to := transaction.NewTransferLegs(req)
+			errs <- transactionRepo.Transfer(ctx, from, to)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		s.Assert().NoError(err)
+	}
+}
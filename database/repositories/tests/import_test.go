@@ -0,0 +1,135 @@
+package tests
+
+import (
+	"github.com/fmiskovic/cash-me-if-you-can/database/repositories"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/account"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/imports"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/transaction"
+)
+
+func accountRows(rows ...imports.AccountRow) func(func([]imports.AccountRow) error) error {
+	return func(fn func([]imports.AccountRow) error) error {
+		return fn(rows)
+	}
+}
+
+func transactionRows(rows ...imports.TransactionRow) func(func([]imports.TransactionRow) error) error {
+	return func(fn func([]imports.TransactionRow) error) error {
+		return fn(rows)
+	}
+}
+
+func (s *RepositoriesTestSuite) TestImportAccounts() {
+	ctx := s.dbContainer.Ctx
+	repo := repositories.NewImportRepository(s.dbService)
+	accountRepo := repositories.NewAccountRepository(s.dbService)
+
+	rows := accountRows(
+		imports.AccountRow{Line: 2, CreateRequest: account.CreateRequest{Owner: "Imported Owner", Balance: 25}},
+		imports.AccountRow{Line: 3, CreateRequest: account.CreateRequest{Owner: "Imported Owner", Balance: 5}},
+		imports.AccountRow{Line: 4, CreateRequest: account.CreateRequest{Owner: "Alice", Balance: 5}},
+		imports.AccountRow{Line: 5, CreateRequest: account.CreateRequest{Owner: "Imported Saver", Balance: 5, Product: "gold"}},
+	)
+
+	// a dry run reports the same results without storing anything
+	results, err := repo.ImportAccounts(ctx, true, rows)
+	s.Require().NoError(err)
+	s.Require().Len(results, 4)
+	s.Assert().Equal(imports.Valid, results[0].Status)
+	s.Assert().Empty(results[0].ID)
+
+	var n int
+	s.Require().NoError(s.dbService.Pool().QueryRow(ctx, "SELECT COUNT(*) FROM accounts WHERE owner = 'Imported Owner'").Scan(&n))
+	s.Assert().Zero(n)
+
+	results, err = repo.ImportAccounts(ctx, false, rows)
+	s.Require().NoError(err)
+	s.Require().Len(results, 4)
+
+	s.Assert().Equal(imports.Imported, results[0].Status)
+	s.Require().NotEmpty(results[0].ID)
+	defer func() {
+		s.Assert().NoError(s.deleteAccount(ctx, results[0].ID))
+	}()
+
+	s.Assert().Equal(imports.Result{Line: 3, Status: imports.Rejected, Error: "account with owner Imported Owner already exists"}, results[1])
+	s.Assert().Equal(imports.Result{Line: 4, Status: imports.Rejected, Error: "account with owner Alice already exists"}, results[2])
+	s.Assert().Equal(imports.Result{Line: 5, Status: imports.Rejected, Error: "product gold does not exist"}, results[3])
+
+	acc, err := accountRepo.Get(ctx, results[0].ID)
+	s.Require().NoError(err)
+	s.Assert().Equal("Imported Owner", acc.Owner)
+	s.Assert().Equal(account.DefaultCurrency, acc.Currency)
+	s.Assert().Equal(account.DefaultProduct, acc.Product)
+	s.Assert().InDelta(25, acc.Balance, 1e-9)
+
+	// the initial balance is booked as a deposit
+	trs, err := repositories.NewTransactionRepository(s.dbService).
+		GetByAccountId(ctx, transaction.ListRequest{AccountID: acc.ID})
+	s.Require().NoError(err)
+	s.Require().Len(trs, 1)
+	s.Assert().Equal(transaction.Deposit, trs[0].Type)
+	s.Assert().InDelta(25, trs[0].Amount, 1e-9)
+}
+
+func (s *RepositoriesTestSuite) TestImportTransactions() {
+	ctx := s.dbContainer.Ctx
+	repo := repositories.NewImportRepository(s.dbService)
+	accountRepo := repositories.NewAccountRepository(s.dbService)
+
+	before, err := accountRepo.Get(ctx, charlieId)
+	s.Require().NoError(err)
+
+	row := func(line int, accountID string, tp transaction.Type, amount float64, ref string) imports.TransactionRow {
+		return imports.TransactionRow{Line: line, CreateRequest: transaction.CreateRequest{
+			AccountID:         accountID,
+			Type:              tp,
+			Amount:            amount,
+			ExternalReference: ref,
+			Metadata:          map[string]any{"source": "legacy"},
+		}}
+	}
+
+	// movements that leave Charlie's balance as it was
+	rows := transactionRows(
+		row(2, charlieId, transaction.Withdrawal, before.Balance+10, ""),
+		row(3, charlieId, transaction.Deposit, 10, "import-1"),
+		row(4, charlieId, transaction.Withdrawal, 10, ""),
+		row(5, charlieId, transaction.Deposit, 1, "import-1"),
+		row(6, missingId, transaction.Deposit, 1, ""),
+		row(7, "legacy-42", transaction.Deposit, 1, ""),
+	)
+
+	accounts := []string{charlieId, missingId, "legacy-42"}
+
+	results, err := repo.ImportTransactions(ctx, true, accounts, rows)
+	s.Require().NoError(err)
+	s.Require().Len(results, 6)
+	s.Assert().Equal(imports.Valid, results[1].Status)
+
+	trs, err := repositories.NewTransactionRepository(s.dbService).
+		GetByAccountId(ctx, transaction.ListRequest{AccountID: charlieId, ExternalReference: "import-1"})
+	s.Require().NoError(err)
+	s.Assert().Empty(trs)
+
+	results, err = repo.ImportTransactions(ctx, false, accounts, rows)
+	s.Require().NoError(err)
+	s.Require().Len(results, 6)
+
+	s.Assert().Equal(imports.Result{Line: 2, Status: imports.Rejected, Error: "withdrawal failed - insufficient funds"}, results[0])
+	s.Assert().Equal(imports.Imported, results[1].Status)
+	s.Assert().Equal(imports.Imported, results[2].Status)
+	s.Assert().Equal(imports.Result{Line: 5, Status: imports.Rejected, Error: "transaction with external reference import-1 already exists"}, results[3])
+	s.Assert().Equal(imports.Result{Line: 6, Status: imports.Rejected, Error: "account with id " + missingId + " not found"}, results[4])
+	s.Assert().Equal(imports.Result{Line: 7, Status: imports.Rejected, Error: "account with id legacy-42 not found"}, results[5])
+
+	imported, err := repositories.NewTransactionRepository(s.dbService).GetById(ctx, results[1].ID)
+	s.Require().NoError(err)
+	s.Assert().Equal(charlieId, imported.AccountID)
+	s.Assert().Equal("import-1", imported.ExternalReference)
+	s.Assert().Equal(map[string]any{"source": "legacy"}, imported.Metadata)
+
+	after, err := accountRepo.Get(ctx, charlieId)
+	s.Require().NoError(err)
+	s.Assert().InDelta(before.Balance, after.Balance, 1e-9)
+}
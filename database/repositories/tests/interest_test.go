@@ -0,0 +1,80 @@
+package tests
+
+import (
+	"time"
+
+	"github.com/fmiskovic/cash-me-if-you-can/database/repositories"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/account"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/interest"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/transaction"
+)
+
+func (s *RepositoriesTestSuite) TestInterestAccrualAndCapitalization() {
+	ctx := s.dbContainer.Ctx
+	repo := repositories.NewInterestRepository(s.dbService)
+	accountRepo := repositories.NewAccountRepository(s.dbService)
+	transactionRepo := repositories.NewTransactionRepository(s.dbService)
+
+	// turn Bob's account into a savings account for the duration of the test
+	_, err := s.dbService.Pool().Exec(ctx, "UPDATE accounts SET product = 'savings' WHERE id = $1", bobId)
+	s.Require().NoError(err)
+	defer func() {
+		_, err := s.dbService.Pool().Exec(ctx, "UPDATE accounts SET product = $2 WHERE id = $1", bobId, account.DefaultProduct)
+		s.Assert().NoError(err)
+	}()
+
+	acc, err := accountRepo.Get(ctx, bobId)
+	s.Require().NoError(err)
+
+	// end-of-day balance of today is the current balance
+	today := interest.Date(time.Now())
+	positions, err := repo.Positions(ctx, today)
+	s.Require().NoError(err)
+
+	var position *interest.Position
+	for i := range positions {
+		if positions[i].AccountID == acc.ID {
+			position = &positions[i]
+		}
+		// accounts without interest are not included
+		s.Assert().NotEqual(aliceId, positions[i].AccountID)
+	}
+	s.Require().NotNil(position)
+	s.Assert().InDelta(acc.Balance, position.Balance, 1e-9)
+	s.Assert().Equal("savings", position.Product.Code)
+	s.Assert().InDelta(0.025, position.Product.AnnualRate, 1e-9)
+
+	accrual := interest.Accrue(*position, today)
+	s.Require().NoError(repo.SaveAccruals(ctx, []interest.Accrual{accrual}))
+	// accruing the same day again is a no-op
+	s.Require().NoError(repo.SaveAccruals(ctx, []interest.Accrual{accrual}))
+
+	t, err := repo.Capitalize(ctx, acc.ID, today)
+	s.Require().NoError(err)
+	s.Require().NotNil(t)
+	s.Assert().Equal(transaction.Interest, t.Type)
+	s.Assert().InDelta(accrual.Amount, t.Amount, 1e-9)
+
+	// interest is capitalized only once
+	t, err = repo.Capitalize(ctx, acc.ID, today)
+	s.Require().NoError(err)
+	s.Assert().Nil(t)
+
+	got, err := accountRepo.Get(ctx, acc.ID)
+	s.Require().NoError(err)
+	s.Assert().InDelta(acc.Balance+accrual.Amount, got.Balance, 1e-9)
+
+	trs, err := transactionRepo.GetByAccountId(ctx, transaction.ListRequest{
+		AccountID:         acc.ID,
+		ExternalReference: "interest:" + today.Format(time.DateOnly),
+	})
+	s.Require().NoError(err)
+	s.Require().Len(trs, 1)
+	s.Assert().Equal(transaction.Interest, trs[0].Type)
+
+	s.Require().NoError(repo.CompleteRun(ctx, interest.Run{Date: today, Accrued: 1}))
+	last, err := repo.LastRun(ctx)
+	s.Require().NoError(err)
+	s.Require().NotNil(last)
+	s.Assert().True(today.Equal(*last))
+}
@@ -0,0 +1,127 @@
+package tests
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/fmiskovic/cash-me-if-you-can/database/repositories"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/account"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/partition"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/transaction"
+	"github.com/fmiskovic/cash-me-if-you-can/pkg/errorx"
+)
+
+func (s *RepositoriesTestSuite) TestPartitions() {
+	ctx := s.dbContainer.Ctx
+	repo := repositories.NewPartitionRepository(s.dbService)
+	accountRepo := repositories.NewAccountRepository(s.dbService)
+	transactionRepo := repositories.NewTransactionRepository(s.dbService)
+	ledgerRepo := repositories.NewLedgerRepository(s.dbService)
+
+	acc, err := accountRepo.Create(ctx, account.New(account.WithOwner("Partitioned")))
+	s.Require().NoError(err)
+
+	// a month long before any partition, its transactions are booked into the default partition
+	p := partition.Month(time.Date(2001, time.March, 1, 0, 0, 0, 0, time.UTC))
+	for _, amount := range []float64{50, 20} {
+		_, err = s.dbService.Pool().Exec(ctx, `
+			INSERT INTO transactions (account_id, amount, type, external_reference, timestamp, created_at)
+			VALUES ($1, $2, 'deposit', $3, $4, $4)`,
+			acc.ID, amount, fmt.Sprintf("archived-%.0f", amount), p.From.Add(time.Duration(amount)*time.Hour),
+		)
+		s.Require().NoError(err)
+		_, err = s.dbService.Pool().Exec(ctx, "UPDATE accounts SET balance = balance + $2 WHERE id = $1", acc.ID, amount)
+		s.Require().NoError(err)
+	}
+
+	created, err := repo.Create(ctx, p)
+	s.Require().NoError(err)
+	s.Assert().True(created)
+
+	created, err = repo.Create(ctx, p)
+	s.Require().NoError(err)
+	s.Assert().False(created)
+
+	partitions, err := repo.Partitions(ctx)
+	s.Require().NoError(err)
+	s.Require().NotEmpty(partitions)
+	s.Assert().Equal(p, partitions[0])
+
+	// the transactions were moved into the partition and are read across partitions as before
+	var moved []transaction.Transaction
+	err = repo.Transactions(ctx, p, func(t transaction.Transaction) error {
+		moved = append(moved, t)
+		return nil
+	})
+	s.Require().NoError(err)
+	s.Require().Len(moved, 2)
+	s.Assert().Equal(acc.ID, moved[0].AccountID)
+
+	got, err := transactionRepo.GetById(ctx, moved[0].ID)
+	s.Require().NoError(err)
+	s.Assert().Equal(moved[0].ID, got.ID)
+
+	assertErrorType := func(err error, want errorx.ErrorType) {
+		var errx *errorx.Error
+		s.Require().ErrorAs(err, &errx)
+		s.Assert().Equal(want, errx.Type)
+	}
+
+	// an archive missing transactions keeps the partition
+	a := partition.Archive{Partition: p, Location: "archive/transactions_2001_03.ndjson.gz", ArchivedAt: time.Now()}
+	a.Transactions = 1
+	assertErrorType(repo.Archive(ctx, a), errorx.ErrConflict)
+
+	partitions, err = repo.Partitions(ctx)
+	s.Require().NoError(err)
+	s.Assert().Equal(p, partitions[0])
+
+	a.Transactions = 2
+	s.Require().NoError(repo.Archive(ctx, a))
+
+	partitions, err = repo.Partitions(ctx)
+	s.Require().NoError(err)
+	s.Assert().NotContains(partitions, p)
+
+	_, err = transactionRepo.GetById(ctx, moved[0].ID)
+	assertErrorType(err, errorx.ErrNotFound)
+
+	var n int64
+	s.Require().NoError(s.dbService.Pool().QueryRow(ctx,
+		"SELECT transactions FROM transaction_archives WHERE partition = $1", p.Name,
+	).Scan(&n))
+	s.Assert().Equal(int64(2), n)
+
+	// the archived balance keeps the ledger balanced
+	drifts, err := ledgerRepo.Drifts(ctx)
+	s.Require().NoError(err)
+	s.Assert().Empty(drifts)
+
+	// external references of archived transactions are still taken
+	_, err = transactionRepo.Create(ctx, transaction.New(
+		transaction.WithAccountID(acc.ID),
+		transaction.WithType(transaction.Deposit),
+		transaction.WithAmount(1),
+		transaction.WithExternalReference(moved[0].ExternalReference),
+	))
+	assertErrorType(err, errorx.ErrConflict)
+
+	// statements and historical balances can not reach into the archived month
+	statementRepo := repositories.NewStatementRepository(s.dbService)
+	balanceRepo := repositories.NewBalanceRepository(s.dbService)
+
+	snap, err := statementRepo.Snapshot(ctx)
+	s.Require().NoError(err)
+	defer func() { _ = snap.Close(ctx) }()
+
+	_, err = snap.Balances(ctx, acc.ID, p.From.Add(24*time.Hour), time.Now())
+	assertErrorType(err, errorx.ErrInvalidInput)
+	_, err = balanceRepo.AsOf(ctx, acc.ID, p.From.Add(24*time.Hour))
+	assertErrorType(err, errorx.ErrInvalidInput)
+
+	st, err := snap.Balances(ctx, acc.ID, p.To, time.Now())
+	s.Require().NoError(err)
+	s.Assert().InDelta(70, st.OpeningBalance, 1e-9)
+	_, err = balanceRepo.AsOf(ctx, acc.ID, p.To)
+	s.Assert().NoError(err)
+}
@@ -0,0 +1,159 @@
+package tests
+
+import (
+	"time"
+
+	"github.com/fmiskovic/cash-me-if-you-can/database/repositories"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/reconciliation"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/transaction"
+	"github.com/fmiskovic/cash-me-if-you-can/pkg/errorx"
+)
+
+func (s *RepositoriesTestSuite) TestReconciliation() {
+	ctx := s.dbContainer.Ctx
+	repo := repositories.NewReconciliationRepository(s.dbService)
+	transactionRepo := repositories.NewTransactionRepository(s.dbService)
+
+	assertErrorType := func(err error, want errorx.ErrorType) {
+		var errx *errorx.Error
+		s.Require().ErrorAs(err, &errx)
+		s.Assert().Equal(want, errx.Type)
+	}
+
+	// movements that leave Charlie's balance as it was
+	deposit, err := transactionRepo.Create(ctx, transaction.New(
+		transaction.WithAccountID(charlieId),
+		transaction.WithType(transaction.Deposit),
+		transaction.WithAmount(50),
+		transaction.WithExternalReference("recon-1"),
+	))
+	s.Require().NoError(err)
+	withdrawal, err := transactionRepo.Create(ctx, transaction.New(
+		transaction.WithAccountID(charlieId),
+		transaction.WithType(transaction.Withdrawal),
+		transaction.WithAmount(50),
+	))
+	s.Require().NoError(err)
+
+	now := time.Now()
+	run := &reconciliation.Run{
+		AccountID:   charlieId,
+		Format:      reconciliation.CSV,
+		Rules:       reconciliation.DefaultRules,
+		PeriodStart: now.Add(-time.Hour),
+		PeriodEnd:   now.Add(time.Hour),
+	}
+	lines := []reconciliation.Line{
+		{LineNo: 2, BookedAt: now, Amount: 50, Reference: "recon-1", Description: "deposit"},
+		{LineNo: 3, BookedAt: now, Amount: -50},
+		{LineNo: 4, BookedAt: now, Amount: 7},
+	}
+	// the run is staged and its lines are matched automatically in one transaction
+	var candidates []transaction.Transaction
+	matchDeposit := func(c []transaction.Transaction) []reconciliation.Pair {
+		candidates = c
+		return []reconciliation.Pair{{LineID: lines[0].ID, TransactionID: deposit.ID}}
+	}
+	s.Require().NoError(repo.Import(ctx, run, lines, matchDeposit, now))
+	defer func() {
+		_, err := s.dbService.Pool().Exec(ctx, "DELETE FROM reconciliation_runs WHERE id = $1", run.ID)
+		s.Assert().NoError(err)
+	}()
+	s.Require().NotEmpty(run.ID)
+	for _, l := range lines {
+		s.Assert().NotEmpty(l.ID)
+		s.Assert().Equal(run.ID, l.RunID)
+	}
+	s.Assert().True(containsTransaction(candidates, deposit.ID))
+	s.Assert().True(containsTransaction(candidates, withdrawal.ID))
+
+	got, err := repo.Get(ctx, run.ID)
+	s.Require().NoError(err)
+	s.Assert().Equal(charlieId, got.AccountID)
+	s.Assert().Equal(reconciliation.CSV, got.Format)
+	s.Assert().Equal(reconciliation.DefaultRules, got.Rules)
+
+	// automatic and manual matches leave the transactions out of the candidates
+	matched, err := repo.MatchLine(ctx, run.ID, lines[1].ID, withdrawal.ID, now)
+	s.Require().NoError(err)
+	s.Assert().Equal(withdrawal.ID, matched.TransactionID)
+	s.Assert().Equal(reconciliation.Manual, matched.MatchKind)
+
+	candidates, err = repo.Candidates(ctx, charlieId, run.PeriodStart, run.PeriodEnd)
+	s.Require().NoError(err)
+	s.Assert().False(containsTransaction(candidates, deposit.ID))
+	s.Assert().False(containsTransaction(candidates, withdrawal.ID))
+
+	stored, err := repo.Lines(ctx, run.ID)
+	s.Require().NoError(err)
+	s.Require().Len(stored, 3)
+	s.Assert().Equal(deposit.ID, stored[0].TransactionID)
+	s.Assert().Equal(reconciliation.Auto, stored[0].MatchKind)
+	s.Assert().Equal("recon-1", stored[0].Reference)
+	s.Assert().InDelta(-50, stored[1].Amount, 1e-9)
+	s.Assert().Equal(reconciliation.Manual, stored[1].MatchKind)
+	s.Assert().False(stored[2].Matched())
+
+	// a line and a transaction are matched at most once
+	_, err = repo.MatchLine(ctx, run.ID, lines[1].ID, withdrawal.ID, now)
+	assertErrorType(err, errorx.ErrConflict)
+	_, err = repo.MatchLine(ctx, run.ID, lines[2].ID, deposit.ID, now)
+	assertErrorType(err, errorx.ErrConflict)
+
+	_, err = repo.MatchLine(ctx, run.ID, lines[2].ID, missingId, now)
+	assertErrorType(err, errorx.ErrNotFound)
+	_, err = repo.MatchLine(ctx, run.ID, missingId, deposit.ID, now)
+	assertErrorType(err, errorx.ErrNotFound)
+
+	unmatched, err := repo.UnmatchLine(ctx, run.ID, lines[0].ID)
+	s.Require().NoError(err)
+	s.Assert().False(unmatched.Matched())
+	_, err = repo.UnmatchLine(ctx, run.ID, lines[0].ID)
+	assertErrorType(err, errorx.ErrConflict)
+
+	_, err = repo.Get(ctx, missingId)
+	assertErrorType(err, errorx.ErrNotFound)
+
+	err = repo.Import(ctx, &reconciliation.Run{
+		AccountID:   missingId,
+		Format:      reconciliation.CSV,
+		PeriodStart: now,
+		PeriodEnd:   now,
+	}, []reconciliation.Line{{LineNo: 2, BookedAt: now, Amount: 50}}, noMatches, now)
+	assertErrorType(err, errorx.ErrNotFound)
+
+	// a failed match leaves no run behind, so the import can be retried
+	countRuns := func() int {
+		var n int
+		s.Require().NoError(s.dbService.Pool().QueryRow(ctx,
+			"SELECT COUNT(*) FROM reconciliation_runs WHERE account_id = $1", charlieId,
+		).Scan(&n))
+		return n
+	}
+	before := countRuns()
+
+	retry := []reconciliation.Line{{LineNo: 2, BookedAt: now, Amount: -50}}
+	err = repo.Import(ctx, &reconciliation.Run{
+		AccountID:   charlieId,
+		Format:      reconciliation.CSV,
+		PeriodStart: run.PeriodStart,
+		PeriodEnd:   run.PeriodEnd,
+	}, retry, func([]transaction.Transaction) []reconciliation.Pair {
+		return []reconciliation.Pair{{LineID: retry[0].ID, TransactionID: withdrawal.ID}}
+	}, now)
+	assertErrorType(err, errorx.ErrConflict)
+	s.Assert().Equal(before, countRuns())
+}
+
+func noMatches([]transaction.Transaction) []reconciliation.Pair {
+	return nil
+}
+
+func containsTransaction(transactions []transaction.Transaction, id string) bool {
+	for _, t := range transactions {
+		if t.ID == id {
+			return true
+		}
+	}
+	return false
+}
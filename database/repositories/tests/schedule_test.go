@@ -0,0 +1,105 @@
+package tests
+
+import (
+	"time"
+
+	"github.com/fmiskovic/cash-me-if-you-can/database/repositories"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/schedule"
+)
+
+func (s *RepositoriesTestSuite) TestScheduleLifecycle() {
+	repo := repositories.NewScheduleRepository(s.dbService)
+	ctx := s.dbContainer.Ctx
+
+	startAt := time.Now().UTC().Add(-time.Hour).Truncate(time.Second)
+	sc, err := repo.Create(ctx, &schedule.Schedule{
+		FromAccountID: aliceId,
+		ToAccountID:   charlieId,
+		Amount:        5,
+		Description:   "rent",
+		StartAt:       startAt,
+		Frequency:     schedule.Weekly,
+		Interval:      1,
+		NextRunAt:     &startAt,
+		Status:        schedule.Active,
+	})
+	s.Require().NoError(err)
+	s.Require().NotEmpty(sc.ID)
+
+	// due schedule is claimed once until the lease expires
+	now := time.Now().UTC()
+	claimed, err := repo.Claim(ctx, now, 10, time.Minute)
+	s.Require().NoError(err)
+	s.Require().Len(claimed, 1)
+	s.Assert().Equal(sc.ID, claimed[0].ID)
+	s.Assert().Equal("rent", claimed[0].Description)
+
+	claimed, err = repo.Claim(ctx, now, 10, time.Minute)
+	s.Require().NoError(err)
+	s.Assert().Empty(claimed)
+
+	// completing the execution moves the schedule to the next occurrence
+	execution := &schedule.Execution{
+		ScheduleID:  sc.ID,
+		ScheduledAt: startAt,
+		ExecutedAt:  now,
+		Status:      schedule.ExecutionSucceeded,
+	}
+	sc.Advance()
+	s.Require().NoError(repo.Complete(ctx, sc, execution))
+	s.Assert().NotEmpty(execution.ID)
+
+	// the same occurrence is recorded only once
+	s.Require().NoError(repo.Complete(ctx, sc, &schedule.Execution{
+		ScheduleID:  sc.ID,
+		ScheduledAt: startAt,
+		ExecutedAt:  now,
+		Status:      schedule.ExecutionSucceeded,
+	}))
+
+	got, err := repo.Get(ctx, sc.ID)
+	s.Require().NoError(err)
+	s.Assert().Equal(1, got.Runs)
+	s.Require().NotNil(got.NextRunAt)
+	s.Assert().True(startAt.AddDate(0, 0, 7).Equal(*got.NextRunAt))
+
+	executions, err := repo.Executions(ctx, sc.ID)
+	s.Require().NoError(err)
+	s.Require().Len(executions, 1)
+	s.Assert().Equal(schedule.ExecutionSucceeded, executions[0].Status)
+
+	list, err := repo.List(ctx, schedule.ListRequest{AccountID: charlieId})
+	s.Require().NoError(err)
+	s.Assert().NotEmpty(list)
+
+	// cancelled schedule cannot be cancelled again
+	cancelled, err := repo.Cancel(ctx, sc.ID)
+	s.Require().NoError(err)
+	s.Assert().Equal(schedule.Cancelled, cancelled.Status)
+	s.Assert().Nil(cancelled.NextRunAt)
+
+	_, err = repo.Cancel(ctx, sc.ID)
+	s.Assert().Error(err)
+}
+
+func (s *RepositoriesTestSuite) TestCreateScheduleForMissingAccount() {
+	repo := repositories.NewScheduleRepository(s.dbService)
+
+	startAt := time.Now().UTC()
+	_, err := repo.Create(s.dbContainer.Ctx, &schedule.Schedule{
+		FromAccountID: aliceId,
+		ToAccountID:   missingId,
+		Amount:        5,
+		StartAt:       startAt,
+		NextRunAt:     &startAt,
+		Status:        schedule.Active,
+	})
+	s.Assert().Error(err)
+}
+
+func (s *RepositoriesTestSuite) TestGetScheduleNotFound() {
+	repo := repositories.NewScheduleRepository(s.dbService)
+
+	_, err := repo.Get(s.dbContainer.Ctx, missingId)
+	s.Assert().Error(err)
+}
@@ -0,0 +1,96 @@
+package tests
+
+import (
+	"errors"
+	"time"
+
+	"github.com/fmiskovic/cash-me-if-you-can/database/repositories"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/statement"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/transaction"
+)
+
+func (s *RepositoriesTestSuite) TestStatement() {
+	ctx := s.dbContainer.Ctx
+	repo := repositories.NewStatementRepository(s.dbService)
+	transactionRepo := repositories.NewTransactionRepository(s.dbService)
+
+	var from time.Time
+	s.Require().NoError(s.dbService.Pool().QueryRow(ctx, "SELECT NOW()").Scan(&from))
+
+	snapshot := func() statement.Snapshot {
+		snap, err := repo.Snapshot(ctx)
+		s.Require().NoError(err)
+		s.T().Cleanup(func() { _ = snap.Close(ctx) })
+		return snap
+	}
+
+	before, err := snapshot().Balances(ctx, charlieId, from, from.Add(time.Hour))
+	s.Require().NoError(err)
+
+	// movements that leave Charlie's balance as it was
+	movements := []struct {
+		tp     transaction.Type
+		amount float64
+	}{
+		{tp: transaction.Deposit, amount: 50},
+		{tp: transaction.Withdrawal, amount: 20},
+		{tp: transaction.Withdrawal, amount: 30},
+	}
+	for _, m := range movements {
+		_, err = transactionRepo.Create(ctx, transaction.New(
+			transaction.WithAccountID(charlieId),
+			transaction.WithType(m.tp),
+			transaction.WithAmount(m.amount),
+		))
+		s.Require().NoError(err)
+	}
+
+	to := time.Now().Add(time.Hour)
+
+	snap := snapshot()
+	st, err := snap.Balances(ctx, charlieId, from, to)
+	s.Require().NoError(err)
+	s.Assert().Equal(charlieId, st.AccountID)
+	s.Assert().NotEmpty(st.Currency)
+	s.Assert().InDelta(before.OpeningBalance, st.OpeningBalance, 1e-9)
+	s.Assert().InDelta(st.OpeningBalance, st.ClosingBalance, 1e-9)
+
+	// a movement committed after the snapshot began is neither in the balances nor in the entries
+	_, err = transactionRepo.Create(ctx, transaction.New(
+		transaction.WithAccountID(charlieId),
+		transaction.WithType(transaction.Deposit),
+		transaction.WithAmount(10),
+	))
+	s.Require().NoError(err)
+
+	var entries []statement.Entry
+	err = snap.Entries(ctx, charlieId, from, to, func(e statement.Entry) error {
+		entries = append(entries, e)
+		return nil
+	})
+	s.Require().NoError(err)
+	s.Require().Len(entries, 3)
+
+	s.Assert().Equal(transaction.Deposit, entries[0].Type)
+	s.Assert().InDelta(st.OpeningBalance+50, entries[0].Balance, 1e-9)
+	s.Assert().InDelta(st.OpeningBalance+30, entries[1].Balance, 1e-9)
+	s.Assert().InDelta(st.ClosingBalance, entries[2].Balance, 1e-9)
+
+	// the period excludes movements made after it ended
+	st, err = snapshot().Balances(ctx, charlieId, from, entries[1].Timestamp)
+	s.Require().NoError(err)
+	s.Assert().InDelta(entries[0].Balance, st.ClosingBalance, 1e-9)
+
+	// stop reading at the first callback error
+	stop := errors.New("stop")
+	calls := 0
+	err = snapshot().Entries(ctx, charlieId, from, to, func(statement.Entry) error {
+		calls++
+		return stop
+	})
+	s.Assert().ErrorIs(err, stop)
+	s.Assert().Equal(1, calls)
+
+	_, err = snapshot().Balances(ctx, missingId, from, to)
+	s.Assert().Error(err)
+}
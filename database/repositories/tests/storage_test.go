@@ -0,0 +1,84 @@
+package tests
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pressly/goose/v3"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/fmiskovic/cash-me-if-you-can/database/repositories"
+	"github.com/fmiskovic/cash-me-if-you-can/database/repotest"
+	"github.com/fmiskovic/cash-me-if-you-can/database/sqlite"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/account"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/transaction"
+)
+
+// StorageTestSuite runs the account and transaction tests against every storage backend
+// seeded with the test data.
+type StorageTestSuite struct {
+	suite.Suite
+	ctx          context.Context
+	accounts     account.Repository
+	transactions transaction.Repository
+	// deleteAccount removes an account a test created together with its transactions.
+	deleteAccount func(ctx context.Context, id string) error
+}
+
+func (s *RepositoriesTestSuite) TestPostgresStorage() {
+	suite.Run(s.T(), &StorageTestSuite{
+		ctx:           s.dbContainer.Ctx,
+		accounts:      repositories.NewAccountRepository(s.dbService),
+		transactions:  repositories.NewTransactionRepository(s.dbService),
+		deleteAccount: s.deleteAccount,
+	})
+}
+
+// deleteAccount removes an account a test created together with its transactions,
+// the repositories never delete transaction history.
+func (s *RepositoriesTestSuite) deleteAccount(ctx context.Context, id string) error {
+	if _, err := s.dbService.Pool().Exec(ctx, "DELETE FROM transactions WHERE account_id = $1", id); err != nil {
+		return err
+	}
+	_, err := s.dbService.Pool().Exec(ctx, "DELETE FROM accounts WHERE id = $1", id)
+	return err
+}
+
+func (s *RepositoriesTestSuite) TestPostgresConformance() {
+	suite.Run(s.T(), repotest.New(
+		repositories.NewAccountRepository(s.dbService),
+		repositories.NewTransactionRepository(s.dbService),
+	))
+}
+
+// TestSQLiteStorage needs no container, so it runs in short mode as well.
+func TestSQLiteStorage(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := sqlite.Open(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	defer db.Close()
+
+	require.NoError(t, sqlite.Migrate(ctx, db))
+
+	seeds, err := goose.NewProvider(goose.DialectSQLite3, db, os.DirFS("testdata"), goose.WithDisableVersioning(true))
+	require.NoError(t, err)
+	_, err = seeds.Up(ctx)
+	require.NoError(t, err)
+
+	suite.Run(t, &StorageTestSuite{
+		ctx:          ctx,
+		accounts:     sqlite.NewAccountRepository(db),
+		transactions: sqlite.NewTransactionRepository(db),
+		deleteAccount: func(ctx context.Context, id string) error {
+			if _, err := db.ExecContext(ctx, "DELETE FROM transactions WHERE account_id = ?", id); err != nil {
+				return err
+			}
+			_, err := db.ExecContext(ctx, "DELETE FROM accounts WHERE id = ?", id)
+			return err
+		},
+	})
+}
@@ -26,13 +26,16 @@ func (s *RepositoriesTestSuite) SetupSuite() {
 		s.T().Fatal("failed to start postgres container", err)
 	}
 
-	s.dbService = database.New(s.dbContainer.Config)
+	s.dbService, err = database.New(s.dbContainer.Ctx, s.dbContainer.Config)
+	if err != nil {
+		s.T().Fatal("failed to connect to postgres", err)
+	}
 
 	if err = goose.Up(s.dbService.DB(), "../../migrations"); err != nil {
 		s.T().Fatal("failed to run migrations", err)
 	}
 
-	if err = goose.Up(s.dbService.DB(), "testdata"); err != nil {
+	if err = goose.Up(s.dbService.DB(), "testdata", goose.WithAllowMissing()); err != nil {
 		s.T().Fatal("failed to seed test data", err)
 	}
 }
@@ -5,13 +5,13 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
-	"github.com/fmiskovic/cash-me-if-you-can/database/repositories"
 	"github.com/fmiskovic/cash-me-if-you-can/internal/transaction"
+	"github.com/fmiskovic/cash-me-if-you-can/pkg/errorx"
 )
 
-func (s *RepositoriesTestSuite) TestCreateTransaction() {
-	trRepo := repositories.NewTransactionRepository(s.dbService)
-	accRepo := repositories.NewAccountRepository(s.dbService)
+func (s *StorageTestSuite) TestCreateTransaction() {
+	trRepo := s.transactions
+	accRepo := s.accounts
 
 	tests := []struct {
 		name    string
@@ -77,12 +77,12 @@ func (s *RepositoriesTestSuite) TestCreateTransaction() {
 	for _, tt := range tests {
 		s.T().Run(tt.name, func(t *testing.T) {
 			balanceBefore := 0.0
-			accBefore, err := accRepo.Get(s.dbContainer.Ctx, tt.input.AccountID)
+			accBefore, err := accRepo.Get(s.ctx, tt.input.AccountID)
 			if err == nil {
 				balanceBefore = accBefore.Balance
 			}
 
-			tr, err := trRepo.Create(s.dbContainer.Ctx, tt.input)
+			tr, err := trRepo.Create(s.ctx, tt.input)
 
 			tt.wantErr(t, err, "Create() error = %v, wantErr %v", err, tt.wantErr)
 			if err != nil {
@@ -90,12 +90,12 @@ func (s *RepositoriesTestSuite) TestCreateTransaction() {
 			}
 
 			// assert transaction was created
-			got, err := trRepo.GetById(s.dbContainer.Ctx, tr.ID)
+			got, err := trRepo.GetById(s.ctx, tr.ID)
 			s.Assert().NoError(err)
 			s.Assert().Equal(tr, got)
 
 			// assert account balance was updated
-			accAfter, err := accRepo.Get(s.dbContainer.Ctx, tt.input.AccountID)
+			accAfter, err := accRepo.Get(s.ctx, tt.input.AccountID)
 			s.Assert().NoError(err)
 			balanceAfter := accAfter.Balance
 
@@ -104,13 +104,16 @@ func (s *RepositoriesTestSuite) TestCreateTransaction() {
 			} else {
 				s.Assert().Equal(balanceBefore-tt.input.Amount, balanceAfter)
 			}
+
+			// assert account modification time was bumped
+			s.Assert().True(accAfter.UpdatedAt.After(accBefore.UpdatedAt))
 		})
 	}
 }
 
-func (s *RepositoriesTestSuite) TestTransferTransaction() {
-	trRepo := repositories.NewTransactionRepository(s.dbService)
-	accountRepo := repositories.NewAccountRepository(s.dbService)
+func (s *StorageTestSuite) TestTransferTransaction() {
+	trRepo := s.transactions
+	accountRepo := s.accounts
 
 	tests := []struct {
 		name    string
@@ -179,18 +182,18 @@ func (s *RepositoriesTestSuite) TestTransferTransaction() {
 	for _, tt := range tests {
 		s.T().Run(tt.name, func(t *testing.T) {
 			fromAccBalanceBefore := 0.0
-			fromAccBefore, err := accountRepo.Get(s.dbContainer.Ctx, tt.from.AccountID)
+			fromAccBefore, err := accountRepo.Get(s.ctx, tt.from.AccountID)
 			if err == nil {
 				fromAccBalanceBefore = fromAccBefore.Balance
 			}
 
 			toAccBalanceBefore := 0.0
-			toAccBefore, err := accountRepo.Get(s.dbContainer.Ctx, tt.to.AccountID)
+			toAccBefore, err := accountRepo.Get(s.ctx, tt.to.AccountID)
 			if err == nil {
 				toAccBalanceBefore = toAccBefore.Balance
 			}
 
-			err = trRepo.Transfer(s.dbContainer.Ctx, tt.from, tt.to)
+			err = trRepo.Transfer(s.ctx, tt.from, tt.to)
 
 			tt.wantErr(t, err, "Transfer() error = %v, wantErr %v", err, tt.wantErr)
 			if err != nil {
@@ -198,16 +201,95 @@ func (s *RepositoriesTestSuite) TestTransferTransaction() {
 			}
 
 			// assert from-account balance was updated
-			fromAccAfter, err := accountRepo.Get(s.dbContainer.Ctx, tt.from.AccountID)
+			fromAccAfter, err := accountRepo.Get(s.ctx, tt.from.AccountID)
 			s.Assert().NoError(err)
 			fromAccBalanceAfter := fromAccAfter.Balance
 			s.Assert().Equal(fromAccBalanceBefore-tt.from.Amount, fromAccBalanceAfter)
 
 			// assert to-account balance was updated
-			toAccAfter, err := accountRepo.Get(s.dbContainer.Ctx, tt.to.AccountID)
+			toAccAfter, err := accountRepo.Get(s.ctx, tt.to.AccountID)
 			s.Assert().NoError(err)
 			toAccBalanceAfter := toAccAfter.Balance
 			s.Assert().Equal(toAccBalanceBefore+tt.to.Amount, toAccBalanceAfter)
 		})
 	}
 }
+
+func (s *StorageTestSuite) TestGetTransactionById() {
+	repo := s.transactions
+
+	tests := []struct {
+		name    string
+		id      string
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name:    "existing transaction",
+			id:      "b1c2d3e4-2222-3333-4444-555566669999",
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "non-existing transaction",
+			id:      "b1c2d3e4-2222-3333-4444-000000000000",
+			wantErr: assert.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			tr, err := repo.GetById(s.ctx, tt.id)
+
+			tt.wantErr(t, err, "GetById() error = %v, wantErr %v", err, tt.wantErr)
+			if err != nil {
+				var errx *errorx.Error
+				s.Assert().ErrorAs(err, &errx)
+				s.Assert().Equal(errorx.ErrNotFound, errx.Type)
+				return
+			}
+
+			s.Assert().Equal(tt.id, tr.ID)
+			s.Assert().Equal("b1c2d3e4-2222-3333-4444-555566667777", tr.AccountID)
+		})
+	}
+}
+
+func (s *StorageTestSuite) TestCreateTransactionWithReference() {
+	repo := s.transactions
+
+	input := func() *transaction.Transaction {
+		return &transaction.Transaction{
+			AccountID:         "b1c2d3e4-2222-3333-4444-555566667777",
+			Amount:            1,
+			Type:              transaction.Deposit,
+			Description:       "invoice",
+			ExternalReference: "inv-2024-001",
+			Counterparty:      "ACME",
+			Metadata:          map[string]any{"invoice": "2024-001"},
+		}
+	}
+
+	tr, err := repo.Create(s.ctx, input())
+	s.Require().NoError(err)
+
+	got, err := repo.GetById(s.ctx, tr.ID)
+	s.Require().NoError(err)
+	s.Assert().Equal("invoice", got.Description)
+	s.Assert().Equal("inv-2024-001", got.ExternalReference)
+	s.Assert().Equal("ACME", got.Counterparty)
+	s.Assert().Equal(map[string]any{"invoice": "2024-001"}, got.Metadata)
+
+	// the same reference is rejected for the same account
+	_, err = repo.Create(s.ctx, input())
+	var errx *errorx.Error
+	s.Require().ErrorAs(err, &errx)
+	s.Assert().Equal(errorx.ErrConflict, errx.Type)
+
+	// and can be used to look the transaction up
+	trs, err := repo.GetByAccountId(s.ctx, transaction.ListRequest{
+		AccountID:         "b1c2d3e4-2222-3333-4444-555566667777",
+		ExternalReference: "inv-2024-001",
+	})
+	s.Require().NoError(err)
+	s.Require().Len(trs, 1)
+	s.Assert().Equal(tr.ID, trs[0].ID)
+}
@@ -0,0 +1,92 @@
+package tests
+
+import (
+	"os"
+	"strings"
+
+	"github.com/fmiskovic/cash-me-if-you-can/database/repositories"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/transaction"
+)
+
+func (s *RepositoriesTestSuite) TestVerifyLedger() {
+	ctx := s.dbContainer.Ctx
+	repo := repositories.NewLedgerRepository(s.dbService)
+
+	// every balance change made by the other tests is backed by transactions
+	n, err := repo.CountAccounts(ctx)
+	s.Require().NoError(err)
+	s.Assert().Positive(n)
+
+	drifts, err := repo.Drifts(ctx)
+	s.Require().NoError(err)
+	s.Assert().Empty(drifts)
+
+	legs, err := repo.UnmatchedLegs(ctx)
+	s.Require().NoError(err)
+	s.Assert().Empty(legs)
+
+	// a withdrawal leg from Charlie to David without the deposit leg, not reflected in Charlie's balance
+	var id string
+	err = s.dbService.Pool().QueryRow(ctx, `
+		INSERT INTO transactions (account_id, amount, type, counterparty)
+		VALUES ($1, 5, 'withdrawal', $2) RETURNING id`,
+		charlieId, davidId,
+	).Scan(&id)
+	s.Require().NoError(err)
+	defer func() {
+		_, err := s.dbService.Pool().Exec(ctx, "DELETE FROM transactions WHERE id = $1", id)
+		s.Assert().NoError(err)
+	}()
+
+	drifts, err = repo.Drifts(ctx)
+	s.Require().NoError(err)
+	s.Require().Len(drifts, 1)
+	s.Assert().Equal(charlieId, drifts[0].AccountID)
+	s.Assert().InDelta(5, drifts[0].Difference(), 1e-9)
+
+	legs, err = repo.UnmatchedLegs(ctx)
+	s.Require().NoError(err)
+	s.Require().Len(legs, 1)
+	s.Assert().Equal(charlieId, legs[0].AccountID)
+	s.Assert().Equal(davidId, legs[0].CounterpartyID)
+	s.Assert().True(legs[0].Debit)
+	s.Assert().Equal(1, legs[0].Legs)
+	s.Assert().Zero(legs[0].Matched)
+}
+
+func (s *RepositoriesTestSuite) TestInitialBalanceBackfill() {
+	ctx := s.dbContainer.Ctx
+	repo := repositories.NewLedgerRepository(s.dbService)
+
+	// an account created before initial balances were booked as deposits
+	var id string
+	err := s.dbService.Pool().QueryRow(ctx,
+		"INSERT INTO accounts (owner, balance) VALUES ('Legacy Owner', 42) RETURNING id",
+	).Scan(&id)
+	s.Require().NoError(err)
+	defer func() { s.Assert().NoError(s.deleteAccount(ctx, id)) }()
+
+	drifts, err := repo.Drifts(ctx)
+	s.Require().NoError(err)
+	s.Require().Len(drifts, 1)
+	s.Assert().Equal(id, drifts[0].AccountID)
+
+	// the migration books the difference, it runs again here as the account did not exist when it was applied
+	migration, err := os.ReadFile("../../migrations/20261019230100_initial_balance_deposits.sql")
+	s.Require().NoError(err)
+	up, _, _ := strings.Cut(string(migration), "-- +goose Down")
+	_, err = s.dbService.Pool().Exec(ctx, up)
+	s.Require().NoError(err)
+
+	drifts, err = repo.Drifts(ctx)
+	s.Require().NoError(err)
+	s.Assert().Empty(drifts)
+
+	trs, err := repositories.NewTransactionRepository(s.dbService).
+		GetByAccountId(ctx, transaction.ListRequest{AccountID: id})
+	s.Require().NoError(err)
+	s.Require().Len(trs, 1)
+	s.Assert().Equal(transaction.Deposit, trs[0].Type)
+	s.Assert().Equal(42.0, trs[0].Amount)
+	s.Assert().Equal("initial balance", trs[0].Description)
+}
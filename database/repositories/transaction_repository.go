@@ -4,19 +4,17 @@ import (
 	"context"
 	_ "embed"
 	"errors"
+	"fmt"
 
 	"github.com/jackc/pgx/v5"
 
 	"github.com/fmiskovic/cash-me-if-you-can/database"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/fee"
 	"github.com/fmiskovic/cash-me-if-you-can/internal/transaction"
 	"github.com/fmiskovic/cash-me-if-you-can/pkg/errorx"
 )
 
 var (
-	//go:embed sql/transaction_insert.sql
-	insertTransactionSql string
-	//go:embed sql/account_update.sql
-	updateAccountSql string
 	//go:embed sql/transaction_select_by_account_id.sql
 	selectTransactionsByAccountIdSql string
 	//go:embed sql/transaction_select_by_id.sql
@@ -49,38 +47,44 @@ func (r TransactionRepository) Create(ctx context.Context, t *transaction.Transa
 
 	// execute inside transaction and rollback on error
 	err := r.Execute(ctx, func(tx pgx.Tx) error {
-		acc, err := r.lockAccountById(ctx, tx, t.AccountID)
+		// the fee is quoted first, its revenue account is locked in order with the account
+		op := fee.Operation(t.Type)
+		charge, err := r.quoteFee(ctx, tx, t.AccountID, op, t.Amount)
 		if err != nil {
 			return err
 		}
 
-		// fail if account does not have enough funds
-		if t.Type == transaction.Withdrawal && acc.Balance < t.Amount {
+		accounts, err := r.lockAccounts(ctx, tx, withRevenueAccounts([]string{t.AccountID}, charge)...)
+		if err != nil {
+			return err
+		}
+		acc := accounts[t.AccountID]
+
+		delta := t.Amount
+		if t.Type.IsDebit() {
+			delta = -t.Amount
+		}
+		newBalance := acc.Balance + delta
+
+		// fail if account does not have enough funds, including the fee
+		if newBalance-charge.Amount < 0 {
 			return errorx.NewError(
-				errors.New("withdrawal failed - insufficient funds"),
+				fmt.Errorf("%s failed - insufficient funds", t.Type),
 				errorx.ErrInvalidInput,
 			)
 		}
 
-		// update account balance
-		newBalance := acc.Balance + t.Amount
-		if t.Type == transaction.Withdrawal {
-			newBalance = acc.Balance - t.Amount
-		}
-		if _, err = tx.Exec(ctx, updateAccountSql, t.AccountID, newBalance); err != nil {
+		// update account balance relatively, so it stays the exact sum of its transactions
+		if _, err = tx.Exec(ctx, incrementAccountBalanceSql, t.AccountID, delta); err != nil {
 			return err
 		}
 
 		// create transaction
-		if err = tx.QueryRow(ctx, insertTransactionSql,
-			t.AccountID, // $1
-			t.Amount,    // $2
-			t.Type,      // $3
-		).Scan(&t.ID, &t.Timestamp); err != nil {
+		if err = r.insertTransaction(ctx, tx, t); err != nil {
 			return err
 		}
 
-		return nil
+		return r.postFee(ctx, tx, charge, op, t)
 	})
 
 	return t, err
@@ -102,51 +106,28 @@ func (r TransactionRepository) Transfer(ctx context.Context, from *transaction.T
 
 	// execute inside transaction and rollback on error
 	err := r.Execute(ctx, func(tx pgx.Tx) error {
-		accFrom, err := r.lockAccountById(ctx, tx, from.AccountID)
+		charge, err := r.quoteFee(ctx, tx, from.AccountID, fee.Transfer, from.Amount)
 		if err != nil {
 			return err
 		}
-		accTo, err := r.lockAccountById(ctx, tx, to.AccountID)
-		if err != nil {
-			return err
-		}
-
-		// check if account has enough funds to make a transfer
-		if accFrom.Balance < from.Amount {
-			return errorx.NewError(
-				errors.New("transfer failed - insufficient funds"),
-				errorx.ErrInvalidInput,
-			)
-		}
 
-		// update account-from balance
-		accFrom.Balance -= from.Amount
-		if _, err = tx.Exec(ctx, updateAccountSql, from.AccountID, accFrom.Balance); err != nil {
-			return err
-		}
-
-		// update account-to balance
-		accTo.Balance += to.Amount
-		if _, err = tx.Exec(ctx, updateAccountSql, to.AccountID, accTo.Balance); err != nil {
-			return err
-		}
-
-		// create transactions
-		_, err = tx.Exec(ctx, insertTransactionSql, from.AccountID, from.Amount, from.Type)
+		ids := withRevenueAccounts([]string{from.AccountID, to.AccountID}, charge)
+		accounts, err := r.lockAccounts(ctx, tx, ids...)
 		if err != nil {
 			return err
 		}
-		_, err = tx.Exec(ctx, insertTransactionSql, to.AccountID, to.Amount, to.Type)
-
-		return err
+		return r.transfer(ctx, tx, accounts[from.AccountID], accounts[to.AccountID], from, to, charge)
 	})
 	return err
 }
 
-func (r TransactionRepository) GetByAccountId(ctx context.Context, accountId string) ([]transaction.Transaction, error) {
+func (r TransactionRepository) GetByAccountId(ctx context.Context, req transaction.ListRequest) ([]transaction.Transaction, error) {
+	// history is served by the replica, if configured
+	pool := r.ReadPool(ctx)
+
 	// first check if account exists
 	var exist bool
-	err := r.Pool().QueryRow(ctx, accountExistSql, accountId).Scan(&exist)
+	err := pool.QueryRow(ctx, accountExistSql, req.AccountID).Scan(&exist)
 	if err != nil {
 		return nil, err
 	}
@@ -157,7 +138,13 @@ func (r TransactionRepository) GetByAccountId(ctx context.Context, accountId str
 		)
 	}
 
-	rows, err := r.Pool().Query(ctx, selectTransactionsByAccountIdSql, accountId)
+	rows, err := pool.Query(ctx, selectTransactionsByAccountIdSql,
+		req.AccountID,                      // $1
+		req.CreatedFrom,                    // $2
+		req.CreatedTo,                      // $3
+		req.UpdatedSince,                   // $4
+		nullIfEmpty(req.ExternalReference), // $5
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -166,7 +153,7 @@ func (r TransactionRepository) GetByAccountId(ctx context.Context, accountId str
 	var trs []transaction.Transaction
 	for rows.Next() {
 		var tr transaction.Transaction
-		if err = rows.Scan(&tr.ID, &tr.AccountID, &tr.Type, &tr.Amount, &tr.Timestamp); err != nil {
+		if err = rows.Scan(transactionColumns(&tr)...); err != nil {
 			return nil, err
 		}
 		trs = append(trs, tr)
@@ -177,8 +164,25 @@ func (r TransactionRepository) GetByAccountId(ctx context.Context, accountId str
 
 func (r TransactionRepository) GetById(ctx context.Context, id string) (*transaction.Transaction, error) {
 	tr := new(transaction.Transaction)
-	err := r.Pool().
+	err := r.ReadPool(ctx).
 		QueryRow(ctx, selectTransactionByIdSql, id).
-		Scan(&tr.ID, &tr.AccountID, &tr.Type, &tr.Amount, &tr.Timestamp)
-	return tr, err
+		Scan(transactionColumns(tr)...)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, errorx.NewError(
+			fmt.Errorf("transaction with id %s not found", id),
+			errorx.ErrNotFound,
+		)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return tr, nil
+}
+
+// transactionColumns returns scan destinations matching the transaction select queries.
+func transactionColumns(t *transaction.Transaction) []any {
+	return []any{
+		&t.ID, &t.AccountID, &t.Type, &t.Amount, &t.Timestamp, &t.CreatedAt, &t.UpdatedAt,
+		&t.Description, &t.ExternalReference, &t.Counterparty, &t.Metadata,
+	}
 }
@@ -0,0 +1,356 @@
+// Package repotest is the conformance suite of account and transaction repositories.
+// Every storage backend and repository wrapper runs it, so they all behave the same.
+//
+// The suite creates its own accounts with unique owners and never deletes data,
+// so it can run against a shared or already seeded storage.
+package repotest
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/fmiskovic/cash-me-if-you-can/internal"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/account"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/transaction"
+	"github.com/fmiskovic/cash-me-if-you-can/pkg/errorx"
+)
+
+// missingID is a well-formed id no account or transaction has.
+const missingID = "00000000-0000-0000-0000-000000000000"
+
+// Suite checks the behavior every account.Repository and transaction.Repository must have.
+// The repositories must share the storage and no fees may be configured for the default product.
+type Suite struct {
+	suite.Suite
+	ctx          context.Context
+	accounts     account.Repository
+	transactions transaction.Repository
+}
+
+// Run runs the conformance suite against the repositories.
+func Run(t *testing.T, accounts account.Repository, transactions transaction.Repository) {
+	suite.Run(t, New(accounts, transactions))
+}
+
+// New returns the conformance suite of the repositories, to be run by suite.Run.
+func New(accounts account.Repository, transactions transaction.Repository) *Suite {
+	return &Suite{
+		ctx:          context.Background(),
+		accounts:     accounts,
+		transactions: transactions,
+	}
+}
+
+// newOwner returns an owner name no other test uses.
+func newOwner(prefix string) string {
+	return fmt.Sprintf("%s %s", prefix, uuid.NewString())
+}
+
+// newAccount creates an account with a unique owner and the balance.
+func (s *Suite) newAccount(balance float64, opts ...account.Option) *account.Account {
+	opts = append([]account.Option{account.WithOwner(newOwner("repotest")), account.WithBalance(balance)}, opts...)
+	acc, err := s.accounts.Create(s.ctx, account.New(opts...))
+	s.Require().NoError(err)
+	return acc
+}
+
+// balance returns the current balance of the account.
+func (s *Suite) balance(id string) float64 {
+	acc, err := s.accounts.Get(s.ctx, id)
+	s.Require().NoError(err)
+	return acc.Balance
+}
+
+// transfer moves the amount between the accounts.
+func (s *Suite) transfer(from, to string, amount float64) error {
+	legFrom, legTo := transaction.NewTransferLegs(transaction.TransferRequest{
+		FromAccountID: from,
+		ToAccountID:   to,
+		Amount:        amount,
+	})
+	return s.transactions.Transfer(s.ctx, legFrom, legTo)
+}
+
+// requireErrorType fails unless err is an errorx.Error of the type.
+func (s *Suite) requireErrorType(err error, want errorx.ErrorType) {
+	s.T().Helper()
+
+	var errx *errorx.Error
+	s.Require().ErrorAs(err, &errx)
+	s.Assert().Equal(want, errx.Type)
+}
+
+func (s *Suite) TestCreateAccount() {
+	owner := newOwner("create")
+
+	acc, err := s.accounts.Create(s.ctx, account.New(account.WithOwner(owner), account.WithBalance(25.5)))
+	s.Require().NoError(err)
+	s.Assert().NotEmpty(acc.ID)
+	s.Assert().Equal(account.DefaultCurrency, acc.Currency)
+	s.Assert().Equal(account.DefaultProduct, acc.Product)
+	s.Assert().Equal(account.Active, acc.Status)
+	s.Assert().False(acc.CreatedAt.IsZero())
+	s.Assert().Equal(acc.CreatedAt, acc.UpdatedAt)
+
+	got, err := s.accounts.Get(s.ctx, acc.ID)
+	s.Require().NoError(err)
+	s.Assert().Equal(owner, got.Owner)
+	s.Assert().Equal(25.5, got.Balance)
+
+	// the initial balance is booked as a deposit
+	trs, err := s.transactions.GetByAccountId(s.ctx, transaction.ListRequest{AccountID: acc.ID})
+	s.Require().NoError(err)
+	s.Require().Len(trs, 1)
+	s.Assert().Equal(transaction.Deposit, trs[0].Type)
+	s.Assert().Equal(25.5, trs[0].Amount)
+
+	// owners are unique and never blank
+	_, err = s.accounts.Create(s.ctx, account.New(account.WithOwner(owner)))
+	s.Assert().Error(err)
+	_, err = s.accounts.Create(s.ctx, account.New(account.WithOwner(" ")))
+	s.Assert().Error(err)
+}
+
+func (s *Suite) TestCreateAccountWithoutBalance() {
+	acc := s.newAccount(0)
+
+	trs, err := s.transactions.GetByAccountId(s.ctx, transaction.ListRequest{AccountID: acc.ID})
+	s.Require().NoError(err)
+	s.Assert().Empty(trs)
+}
+
+func (s *Suite) TestGetAccountNotFound() {
+	_, err := s.accounts.Get(s.ctx, missingID)
+	s.requireErrorType(err, errorx.ErrNotFound)
+}
+
+func (s *Suite) TestListAccountsPagination() {
+	prefix := newOwner("page")
+	for i := 0; i < 5; i++ {
+		_, err := s.accounts.Create(s.ctx, account.New(account.WithOwner(fmt.Sprintf("%s %d", prefix, i))))
+		s.Require().NoError(err)
+	}
+
+	tests := []struct {
+		name      string
+		page      internal.PageRequest
+		wantItems int
+		wantPages int
+	}{
+		{name: "first page", page: internal.PageRequest{Limit: 2, Offset: 0}, wantItems: 2, wantPages: 3},
+		{name: "last partial page", page: internal.PageRequest{Limit: 2, Offset: 4}, wantItems: 1, wantPages: 3},
+		{name: "past the last page", page: internal.PageRequest{Limit: 2, Offset: 6}, wantItems: 0, wantPages: 3},
+		{name: "single page", page: internal.PageRequest{Limit: 10, Offset: 0}, wantItems: 5, wantPages: 1},
+		{name: "exact pages", page: internal.PageRequest{Limit: 5, Offset: 0}, wantItems: 5, wantPages: 1},
+		{name: "no limit", page: internal.PageRequest{}, wantItems: 0, wantPages: 0},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			page, err := s.accounts.List(s.ctx, account.ListRequest{PageRequest: tt.page, Owner: prefix})
+			s.Require().NoError(err)
+			s.Assert().Equal(5, page.TotalItems)
+			s.Assert().Equal(tt.wantPages, page.TotalPages)
+			s.Assert().Len(page.Items, tt.wantItems)
+		})
+	}
+
+	// pages do not overlap
+	seen := make(map[string]bool)
+	for offset := 0; offset < 5; offset += 2 {
+		page, err := s.accounts.List(s.ctx, account.ListRequest{
+			PageRequest: internal.PageRequest{Limit: 2, Offset: offset},
+			Owner:       prefix,
+			SortBy:      account.SortByOwner,
+		})
+		s.Require().NoError(err)
+		for _, acc := range page.Items {
+			s.Assert().False(seen[acc.ID], "account %s listed twice", acc.ID)
+			seen[acc.ID] = true
+		}
+	}
+	s.Assert().Len(seen, 5)
+}
+
+func (s *Suite) TestCreateTransaction() {
+	acc := s.newAccount(100)
+
+	deposit, err := s.transactions.Create(s.ctx, transaction.New(
+		transaction.WithAccountID(acc.ID),
+		transaction.WithType(transaction.Deposit),
+		transaction.WithAmount(50),
+	))
+	s.Require().NoError(err)
+	s.Assert().NotEmpty(deposit.ID)
+	s.Assert().InDelta(150, s.balance(acc.ID), 1e-9)
+
+	_, err = s.transactions.Create(s.ctx, transaction.New(
+		transaction.WithAccountID(acc.ID),
+		transaction.WithType(transaction.Withdrawal),
+		transaction.WithAmount(30),
+	))
+	s.Require().NoError(err)
+	s.Assert().InDelta(120, s.balance(acc.ID), 1e-9)
+
+	got, err := s.transactions.GetById(s.ctx, deposit.ID)
+	s.Require().NoError(err)
+	s.Assert().Equal(acc.ID, got.AccountID)
+	s.Assert().Equal(transaction.Deposit, got.Type)
+	s.Assert().Equal(50.0, got.Amount)
+
+	trs, err := s.transactions.GetByAccountId(s.ctx, transaction.ListRequest{AccountID: acc.ID})
+	s.Require().NoError(err)
+	s.Assert().Len(trs, 3)
+}
+
+func (s *Suite) TestCreateTransactionInvalid() {
+	acc := s.newAccount(100)
+
+	tests := []struct {
+		name    string
+		input   *transaction.Transaction
+		wantErr errorx.ErrorType
+	}{
+		{
+			name: "insufficient funds",
+			input: transaction.New(
+				transaction.WithAccountID(acc.ID),
+				transaction.WithType(transaction.Withdrawal),
+				transaction.WithAmount(100.01),
+			),
+			wantErr: errorx.ErrInvalidInput,
+		},
+		{
+			name: "zero amount",
+			input: transaction.New(
+				transaction.WithAccountID(acc.ID),
+				transaction.WithType(transaction.Deposit),
+			),
+			wantErr: errorx.ErrInvalidInput,
+		},
+		{
+			name: "non-existing account",
+			input: transaction.New(
+				transaction.WithAccountID(missingID),
+				transaction.WithType(transaction.Deposit),
+				transaction.WithAmount(1),
+			),
+			wantErr: errorx.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			_, err := s.transactions.Create(s.ctx, tt.input)
+			s.requireErrorType(err, tt.wantErr)
+		})
+	}
+
+	// failed transactions change nothing
+	s.Assert().InDelta(100, s.balance(acc.ID), 1e-9)
+}
+
+func (s *Suite) TestGetTransactionsNotFound() {
+	_, err := s.transactions.GetById(s.ctx, missingID)
+	s.requireErrorType(err, errorx.ErrNotFound)
+
+	_, err = s.transactions.GetByAccountId(s.ctx, transaction.ListRequest{AccountID: missingID})
+	s.requireErrorType(err, errorx.ErrNotFound)
+}
+
+func (s *Suite) TestTransfer() {
+	from := s.newAccount(100)
+	to := s.newAccount(10)
+	euro := s.newAccount(100, account.WithCurrency("EUR"))
+
+	s.Require().NoError(s.transfer(from.ID, to.ID, 40))
+	s.Assert().InDelta(60, s.balance(from.ID), 1e-9)
+	s.Assert().InDelta(50, s.balance(to.ID), 1e-9)
+
+	tests := []struct {
+		name     string
+		from, to string
+		amount   float64
+		wantErr  errorx.ErrorType
+	}{
+		{name: "insufficient funds", from: from.ID, to: to.ID, amount: 60.01, wantErr: errorx.ErrInvalidInput},
+		{name: "currency mismatch", from: from.ID, to: euro.ID, amount: 1, wantErr: errorx.ErrInvalidInput},
+		{name: "non-existing from-account", from: missingID, to: to.ID, amount: 1, wantErr: errorx.ErrNotFound},
+		{name: "non-existing to-account", from: from.ID, to: missingID, amount: 1, wantErr: errorx.ErrNotFound},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			s.requireErrorType(s.transfer(tt.from, tt.to, tt.amount), tt.wantErr)
+		})
+	}
+
+	// failed transfers change nothing
+	s.Assert().InDelta(60, s.balance(from.ID), 1e-9)
+	s.Assert().InDelta(50, s.balance(to.ID), 1e-9)
+	s.Assert().InDelta(100, s.balance(euro.ID), 1e-9)
+}
+
+func (s *Suite) TestConcurrentTransfers() {
+	const rounds = 50
+
+	ids := make([]string, 3)
+	for i := range ids {
+		ids[i] = s.newAccount(100).ID
+	}
+
+	// concurrent transfers in every direction and withdrawals competing for the same funds
+	var (
+		wg          sync.WaitGroup
+		mu          sync.Mutex
+		withdrawals float64
+	)
+	for i := 0; i < rounds; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_ = s.transfer(ids[i%3], ids[(i+1)%3], 7)
+		}()
+		go func() {
+			defer wg.Done()
+			_, err := s.transactions.Create(s.ctx, transaction.New(
+				transaction.WithAccountID(ids[i%3]),
+				transaction.WithType(transaction.Withdrawal),
+				transaction.WithAmount(5),
+			))
+			if err == nil {
+				mu.Lock()
+				withdrawals += 5
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	// no update is lost, transfers create no money and every balance is the sum of its transactions
+	total := 0.0
+	for _, id := range ids {
+		balance := s.balance(id)
+		s.Assert().GreaterOrEqual(balance, 0.0)
+		total += balance
+
+		trs, err := s.transactions.GetByAccountId(s.ctx, transaction.ListRequest{AccountID: id})
+		s.Require().NoError(err)
+
+		sum := 0.0
+		for _, tr := range trs {
+			if tr.Type.IsDebit() {
+				sum -= tr.Amount
+			} else {
+				sum += tr.Amount
+			}
+		}
+		s.Assert().InDelta(sum, balance, 1e-9)
+	}
+	s.Assert().InDelta(300-withdrawals, total, 1e-9)
+}
@@ -0,0 +1,213 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	_ "embed"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/fmiskovic/cash-me-if-you-can/internal"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/account"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/transaction"
+	"github.com/fmiskovic/cash-me-if-you-can/pkg/errorx"
+)
+
+var (
+	//go:embed sql/account_insert.sql
+	insertAccountSql string
+	//go:embed sql/account_select_by_id.sql
+	selectAccountByIdSql string
+	//go:embed sql/account_select_page.sql
+	accountPageSql string
+	//go:embed sql/account_total_count.sql
+	totalAccountCountSql string
+	//go:embed sql/account_delete.sql
+	deleteAccountSql string
+)
+
+type AccountRepository struct {
+	db *sql.DB
+}
+
+func NewAccountRepository(db *sql.DB) AccountRepository {
+	return AccountRepository{db: db}
+}
+
+func (r AccountRepository) Get(ctx context.Context, id string) (*account.Account, error) {
+	return selectAccount(ctx, r.db, id)
+}
+
+func (r AccountRepository) Create(ctx context.Context, acc *account.Account) (*account.Account, error) {
+	if acc.Currency == "" {
+		acc.Currency = account.DefaultCurrency
+	}
+	if acc.Product == "" {
+		acc.Product = account.DefaultProduct
+	}
+
+	id := uuid.NewString()
+	now := time.Now().UTC()
+
+	// execute inside transaction and rollback on error
+	err := execute(ctx, r.db, func(tx *sql.Tx) error {
+		if err := tx.QueryRowContext(ctx, insertAccountSql,
+			id,           // ?1
+			acc.Owner,    // ?2
+			acc.Balance,  // ?3
+			acc.Currency, // ?4
+			acc.Product,  // ?5
+			now,          // ?6
+		).Scan(&acc.Status); err != nil {
+			return err
+		}
+		if acc.Balance == 0 {
+			return nil
+		}
+
+		// deposit of the initial balance
+		return insertTransaction(ctx, tx, transaction.New(
+			transaction.WithAccountID(id),
+			transaction.WithType(transaction.Deposit),
+			transaction.WithAmount(acc.Balance),
+			transaction.WithDescription("initial balance"),
+		))
+	})
+	switch {
+	case constraintViolation(err, foreignKeyViolation):
+		return nil, errorx.NewError(
+			fmt.Errorf("product %s does not exist", acc.Product),
+			errorx.ErrInvalidInput,
+		)
+	case constraintViolation(err, uniqueViolation):
+		return nil, errorx.NewError(
+			fmt.Errorf("account with owner %s already exists", acc.Owner),
+			errorx.ErrInvalidInput,
+		)
+	case constraintViolation(err, checkViolation):
+		return nil, errorx.NewError(
+			errors.New("owner is blank"),
+			errorx.ErrInvalidInput,
+		)
+	case err != nil:
+		return nil, err
+	}
+
+	acc.ID = id
+	acc.CreatedAt, acc.UpdatedAt = now, now
+	return acc, nil
+}
+
+func (r AccountRepository) List(ctx context.Context, req account.ListRequest) (internal.Page[account.Account], error) {
+	var accounts []account.Account
+
+	filters := []any{
+		ownerPattern(req.Owner),         // ?1
+		nullIfEmpty(string(req.Status)), // ?2
+		nullIfEmpty(req.Currency),       // ?3
+		req.MinBalance,                  // ?4
+		req.MaxBalance,                  // ?5
+		nullTime(req.CreatedFrom),       // ?6
+		nullTime(req.CreatedTo),         // ?7
+		nullTime(req.UpdatedSince),      // ?8
+	}
+
+	pageSql := fmt.Sprintf(accountPageSql, orderBy(req.SortBy, req.SortOrder))
+	rows, err := r.db.QueryContext(ctx, pageSql, append(filters, req.Limit, req.Offset)...)
+	if err != nil {
+		return internal.EmptyPage[account.Account](), err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var acc account.Account
+		if err = rows.Scan(accountColumns(&acc)...); err != nil {
+			return internal.EmptyPage[account.Account](), err
+		}
+		toUTC(&acc.CreatedAt, &acc.UpdatedAt)
+		accounts = append(accounts, acc)
+	}
+
+	if err = rows.Err(); err != nil {
+		return internal.EmptyPage[account.Account](), err
+	}
+
+	var count int
+	_ = r.db.QueryRowContext(ctx, totalAccountCountSql, filters...).Scan(&count) // ignore error, it's not critical
+
+	totalPages := 0
+	if count != 0 && req.Limit != 0 {
+		totalPages = (count + req.Limit - 1) / req.Limit
+	}
+
+	return internal.Page[account.Account]{
+		Items:      accounts,
+		TotalItems: count,
+		TotalPages: totalPages,
+	}, nil
+}
+
+func (r AccountRepository) Delete(ctx context.Context, id string) error {
+	_, err := r.db.ExecContext(ctx, deleteAccountSql, id)
+	return err
+}
+
+// querier is implemented by both the database and a transaction.
+type querier interface {
+	QueryRowContext(context.Context, string, ...any) *sql.Row
+}
+
+func selectAccount(ctx context.Context, q querier, id string) (*account.Account, error) {
+	a := new(account.Account)
+	err := q.QueryRowContext(ctx, selectAccountByIdSql, id).Scan(accountColumns(a)...)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errorx.NewError(
+			fmt.Errorf("account with id %s not found", id),
+			errorx.ErrNotFound,
+		)
+	}
+	if err != nil {
+		return nil, err
+	}
+	toUTC(&a.CreatedAt, &a.UpdatedAt)
+	return a, nil
+}
+
+func accountColumns(a *account.Account) []any {
+	return []any{&a.ID, &a.Owner, &a.Balance, &a.Currency, &a.Status, &a.Product, &a.CreatedAt, &a.UpdatedAt}
+}
+
+// sortColumns whitelists the columns accounts can be sorted by,
+// since ORDER BY cannot be passed as a query parameter.
+var sortColumns = map[account.SortField]string{
+	account.SortByCreatedAt: "a.created_at",
+	account.SortByOwner:     "a.owner",
+	account.SortByBalance:   "a.balance",
+	account.SortByUpdatedAt: "a.updated_at",
+}
+
+func orderBy(field account.SortField, order account.SortOrder) string {
+	column, ok := sortColumns[field]
+	if !ok {
+		column = sortColumns[account.SortByCreatedAt]
+	}
+	if order == account.Desc {
+		return column + " DESC"
+	}
+	return column + " ASC"
+}
+
+// ownerPattern builds a substring pattern for LIKE, which ignores the case of ASCII letters,
+// escaping the wildcard characters of the search term.
+func ownerPattern(owner string) *string {
+	if owner == "" {
+		return nil
+	}
+	escaped := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(owner)
+	pattern := "%" + escaped + "%"
+	return &pattern
+}
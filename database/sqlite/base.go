@@ -0,0 +1,86 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+
+	"github.com/fmiskovic/cash-me-if-you-can/internal/transaction"
+)
+
+// execute runs fn inside a transaction, it is committed if fn succeeds and rolled back otherwise.
+func execute(ctx context.Context, db *sql.DB, fn func(tx *sql.Tx) error) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err = fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// constraintViolation reports whether err violates a constraint of the given kind.
+func constraintViolation(err error, code int) bool {
+	var sqliteErr *sqlite.Error
+	return errors.As(err, &sqliteErr) && sqliteErr.Code() == code
+}
+
+const (
+	uniqueViolation     = sqlite3.SQLITE_CONSTRAINT_UNIQUE
+	foreignKeyViolation = sqlite3.SQLITE_CONSTRAINT_FOREIGNKEY
+	checkViolation      = sqlite3.SQLITE_CONSTRAINT_CHECK
+)
+
+func nullIfEmpty(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// nullTime binds an optional time in UTC, so stored times compare in order as text.
+func nullTime(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+	return t.UTC()
+}
+
+// toUTC sets the location of scanned times, they are stored in UTC
+// but parsed in the local location when its offset is the same.
+func toUTC(times ...*time.Time) {
+	for _, t := range times {
+		*t = t.UTC()
+	}
+}
+
+// transactionRow scans the transaction select queries, metadata is stored as a json object.
+type transactionRow struct {
+	transaction.Transaction
+	metadata sql.NullString
+}
+
+func (r *transactionRow) columns() []any {
+	t := &r.Transaction
+	return []any{
+		&t.ID, &t.AccountID, &t.Type, &t.Amount, &t.Timestamp, &t.CreatedAt, &t.UpdatedAt,
+		&t.Description, &t.ExternalReference, &t.Counterparty, &r.metadata,
+	}
+}
+
+func (r *transactionRow) transaction() (*transaction.Transaction, error) {
+	toUTC(&r.Timestamp, &r.CreatedAt, &r.UpdatedAt)
+	if r.metadata.Valid {
+		if err := json.Unmarshal([]byte(r.metadata.String), &r.Metadata); err != nil {
+			return nil, err
+		}
+	}
+	return &r.Transaction, nil
+}
@@ -0,0 +1,51 @@
+-- +goose Up
+-- +goose StatementBegin
+-- products define the pricing of an account, fees and interest are not supported by sqlite
+CREATE TABLE IF NOT EXISTS products (
+    code TEXT PRIMARY KEY,
+    name TEXT NOT NULL
+);
+
+INSERT INTO products (code, name) VALUES
+    ('current', 'Current account'),
+    ('savings', 'Savings account')
+ON CONFLICT (code) DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS accounts (
+    id TEXT PRIMARY KEY,
+    owner TEXT NOT NULL UNIQUE CHECK (TRIM(owner) <> ''),
+    balance REAL NOT NULL DEFAULT 0,
+    currency TEXT NOT NULL DEFAULT 'USD',
+    status TEXT NOT NULL DEFAULT 'active',
+    product TEXT NOT NULL DEFAULT 'current' REFERENCES products(code),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_accounts_created_at ON accounts (created_at);
+
+CREATE TABLE IF NOT EXISTS transactions (
+    id TEXT PRIMARY KEY,
+    account_id TEXT NOT NULL REFERENCES accounts(id),
+    amount REAL NOT NULL,
+    type TEXT NOT NULL,
+    timestamp TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    description TEXT,
+    external_reference TEXT,
+    counterparty TEXT,
+    -- json object
+    metadata TEXT CHECK (metadata IS NULL OR json_type(metadata) = 'object'),
+    UNIQUE (account_id, external_reference)
+);
+
+CREATE INDEX IF NOT EXISTS idx_transactions_account_id_timestamp ON transactions (account_id, timestamp);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS transactions;
+DROP TABLE IF EXISTS accounts;
+DROP TABLE IF EXISTS products;
+-- +goose StatementEnd
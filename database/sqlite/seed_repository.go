@@ -0,0 +1,33 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	_ "embed"
+)
+
+var (
+	//go:embed sql/transaction_delete.sql
+	deleteTransactionsSql string
+	//go:embed sql/account_delete_all.sql
+	deleteAccountsSql string
+)
+
+type SeedRepository struct {
+	db *sql.DB
+}
+
+func NewSeedRepository(db *sql.DB) SeedRepository {
+	return SeedRepository{db: db}
+}
+
+func (r SeedRepository) Reset(ctx context.Context) error {
+	// execute inside transaction and rollback on error
+	return execute(ctx, r.db, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, deleteTransactionsSql); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, deleteAccountsSql)
+		return err
+	})
+}
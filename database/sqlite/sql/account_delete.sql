@@ -0,0 +1 @@
+DELETE FROM accounts WHERE id = ?1;
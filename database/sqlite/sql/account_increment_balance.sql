@@ -0,0 +1,4 @@
+-- update the balance relatively, so it stays the exact sum of the account's transactions
+UPDATE accounts
+SET balance = balance + ?2, updated_at = ?3
+WHERE id = ?1;
@@ -0,0 +1,3 @@
+INSERT INTO accounts (id, owner, balance, currency, product, created_at, updated_at)
+VALUES (?1, ?2, ?3, ?4, ?5, ?6, ?6)
+RETURNING status;
@@ -0,0 +1,3 @@
+SELECT id, owner, balance, currency, status, product, created_at, updated_at
+FROM accounts
+WHERE id = ?1;
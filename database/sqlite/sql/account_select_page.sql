@@ -0,0 +1,12 @@
+SELECT a.id, a.owner, a.balance, a.currency, a.status, a.product, a.created_at, a.updated_at
+FROM accounts AS a
+WHERE (?1 IS NULL OR a.owner LIKE ?1 ESCAPE '\')
+  AND (?2 IS NULL OR a.status = ?2)
+  AND (?3 IS NULL OR a.currency = ?3)
+  AND (?4 IS NULL OR a.balance >= ?4)
+  AND (?5 IS NULL OR a.balance <= ?5)
+  AND (?6 IS NULL OR a.created_at >= ?6)
+  AND (?7 IS NULL OR a.created_at < ?7)
+  AND (?8 IS NULL OR a.updated_at > ?8)
+ORDER BY %s, a.id
+LIMIT ?9 OFFSET ?10;
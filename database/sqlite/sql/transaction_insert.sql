@@ -0,0 +1,3 @@
+INSERT INTO transactions (id, account_id, amount, type, timestamp, created_at, updated_at,
+                          description, external_reference, counterparty, metadata)
+VALUES (?1, ?2, ?3, ?4, ?5, ?5, ?5, ?6, ?7, ?8, ?9);
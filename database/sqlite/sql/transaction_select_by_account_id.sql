@@ -0,0 +1,9 @@
+SELECT t.id, t.account_id, t.type, t.amount, t.timestamp, t.created_at, t.updated_at,
+       COALESCE(t.description, ''), COALESCE(t.external_reference, ''), COALESCE(t.counterparty, ''), t.metadata
+FROM transactions AS t
+WHERE t.account_id = ?1
+  AND (?2 IS NULL OR t.created_at >= ?2)
+  AND (?3 IS NULL OR t.created_at < ?3)
+  AND (?4 IS NULL OR t.updated_at > ?4)
+  AND (?5 IS NULL OR t.external_reference = ?5)
+ORDER BY t.timestamp DESC, t.rowid DESC;
@@ -0,0 +1,69 @@
+// Package sqlite implements the account and transaction repositories on SQLite,
+// for single-node installs and local development without Postgres.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"net/url"
+
+	"github.com/pressly/goose/v3"
+	_ "modernc.org/sqlite" // pure-Go driver registered as "sqlite"
+)
+
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+func GetMigrationFS() embed.FS {
+	return migrations
+}
+
+func GetDialect() string {
+	return string(goose.DialectSQLite3)
+}
+
+// Open opens the database file, creating it if it does not exist.
+// Transactions begin with BEGIN IMMEDIATE, so a transaction takes the write lock before it reads the balances
+// it is going to update, and waits for the lock instead of failing while another transaction holds it.
+func Open(path string) (*sql.DB, error) {
+	params := url.Values{}
+	params.Add("_pragma", "foreign_keys(1)")
+	params.Add("_pragma", "journal_mode(WAL)")
+	params.Add("_pragma", "busy_timeout(5000)")
+	params.Set("_txlock", "immediate")
+	params.Set("_time_format", "sqlite")
+
+	db, err := sql.Open("sqlite", "file:"+path+"?"+params.Encode())
+	if err != nil {
+		return nil, err
+	}
+	if err = db.Ping(); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to open sqlite database %s: %w", path, err)
+	}
+	return db, nil
+}
+
+// Migrate runs the sqlite migrations up.
+func Migrate(ctx context.Context, db *sql.DB) error {
+	return run(db, func(p *goose.Provider) error {
+		_, err := p.Up(ctx)
+		return err
+	})
+}
+
+// run uses a provider instead of the global goose functions, so it does not change the dialect of the postgres migrations.
+func run(db *sql.DB, fn func(*goose.Provider) error) error {
+	fsys, err := fs.Sub(migrations, "migrations")
+	if err != nil {
+		return err
+	}
+	p, err := goose.NewProvider(goose.DialectSQLite3, db, fsys)
+	if err != nil {
+		return err
+	}
+	return fn(p)
+}
@@ -0,0 +1,243 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/fmiskovic/cash-me-if-you-can/internal/transaction"
+	"github.com/fmiskovic/cash-me-if-you-can/pkg/errorx"
+)
+
+var (
+	//go:embed sql/account_exist.sql
+	accountExistSql string
+	//go:embed sql/account_increment_balance.sql
+	incrementAccountBalanceSql string
+	//go:embed sql/transaction_insert.sql
+	insertTransactionSql string
+	//go:embed sql/transaction_select_by_account_id.sql
+	selectTransactionsByAccountIdSql string
+	//go:embed sql/transaction_select_by_id.sql
+	selectTransactionByIdSql string
+)
+
+// TransactionRepository books transactions in SQLite. Products have no fee rules in SQLite, so no fees are charged.
+type TransactionRepository struct {
+	db *sql.DB
+}
+
+func NewTransactionRepository(db *sql.DB) TransactionRepository {
+	return TransactionRepository{db: db}
+}
+
+func (r TransactionRepository) Create(ctx context.Context, t *transaction.Transaction) (*transaction.Transaction, error) {
+	if t == nil {
+		return nil, errorx.NewError(
+			errors.New("transaction input is nil"),
+			errorx.ErrInvalidInput,
+		)
+	}
+	if t.Amount <= 0 {
+		return nil, errorx.NewError(
+			errors.New("invalid amount"),
+			errorx.ErrInvalidInput,
+		)
+	}
+
+	// execute inside transaction and rollback on error,
+	// the transaction holds the write lock from the start, so the balance cannot change after it is read
+	err := execute(ctx, r.db, func(tx *sql.Tx) error {
+		acc, err := selectAccount(ctx, tx, t.AccountID)
+		if err != nil {
+			return err
+		}
+
+		delta := t.Amount
+		if t.Type.IsDebit() {
+			delta = -t.Amount
+		}
+
+		// fail if account does not have enough funds
+		if acc.Balance+delta < 0 {
+			return errorx.NewError(
+				fmt.Errorf("%s failed - insufficient funds", t.Type),
+				errorx.ErrInvalidInput,
+			)
+		}
+
+		if err = incrementBalance(ctx, tx, t.AccountID, delta); err != nil {
+			return err
+		}
+		return insertTransaction(ctx, tx, t)
+	})
+
+	return t, err
+}
+
+func (r TransactionRepository) Transfer(ctx context.Context, from *transaction.Transaction, to *transaction.Transaction) error {
+	if from == nil || to == nil {
+		return errorx.NewError(
+			errors.New("transaction input is nil"),
+			errorx.ErrInvalidInput,
+		)
+	}
+	if from.Amount <= 0 || to.Amount <= 0 {
+		return errorx.NewError(
+			errors.New("invalid amount"),
+			errorx.ErrInvalidInput,
+		)
+	}
+
+	// execute inside transaction and rollback on error
+	return execute(ctx, r.db, func(tx *sql.Tx) error {
+		accFrom, err := selectAccount(ctx, tx, from.AccountID)
+		if err != nil {
+			return err
+		}
+		accTo, err := selectAccount(ctx, tx, to.AccountID)
+		if err != nil {
+			return err
+		}
+
+		// transfers between different currencies are not supported
+		if accFrom.Currency != accTo.Currency {
+			return errorx.NewError(
+				errors.New("transfer failed - currency mismatch"),
+				errorx.ErrInvalidInput,
+			)
+		}
+
+		// check if account has enough funds to make a transfer
+		if accFrom.Balance < from.Amount {
+			return errorx.NewError(
+				errors.New("transfer failed - insufficient funds"),
+				errorx.ErrInvalidInput,
+			)
+		}
+
+		if err = incrementBalance(ctx, tx, from.AccountID, -from.Amount); err != nil {
+			return err
+		}
+		if err = incrementBalance(ctx, tx, to.AccountID, to.Amount); err != nil {
+			return err
+		}
+		if err = insertTransaction(ctx, tx, from); err != nil {
+			return err
+		}
+		return insertTransaction(ctx, tx, to)
+	})
+}
+
+func (r TransactionRepository) GetByAccountId(ctx context.Context, req transaction.ListRequest) ([]transaction.Transaction, error) {
+	// first check if account exists
+	var exist bool
+	err := r.db.QueryRowContext(ctx, accountExistSql, req.AccountID).Scan(&exist)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return nil, errorx.NewError(
+			errors.New("account not found"),
+			errorx.ErrNotFound,
+		)
+	}
+
+	rows, err := r.db.QueryContext(ctx, selectTransactionsByAccountIdSql,
+		req.AccountID,                      // ?1
+		nullTime(req.CreatedFrom),          // ?2
+		nullTime(req.CreatedTo),            // ?3
+		nullTime(req.UpdatedSince),         // ?4
+		nullIfEmpty(req.ExternalReference), // ?5
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var trs []transaction.Transaction
+	for rows.Next() {
+		var row transactionRow
+		if err = rows.Scan(row.columns()...); err != nil {
+			return nil, err
+		}
+		tr, err := row.transaction()
+		if err != nil {
+			return nil, err
+		}
+		trs = append(trs, *tr)
+	}
+
+	return trs, rows.Err()
+}
+
+func (r TransactionRepository) GetById(ctx context.Context, id string) (*transaction.Transaction, error) {
+	var row transactionRow
+	err := r.db.QueryRowContext(ctx, selectTransactionByIdSql, id).Scan(row.columns()...)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errorx.NewError(
+			fmt.Errorf("transaction with id %s not found", id),
+			errorx.ErrNotFound,
+		)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return row.transaction()
+}
+
+func incrementBalance(ctx context.Context, tx *sql.Tx, accountID string, delta float64) error {
+	_, err := tx.ExecContext(ctx, incrementAccountBalanceSql,
+		accountID,        // ?1
+		delta,            // ?2
+		time.Now().UTC(), // ?3
+	)
+	return err
+}
+
+// insertTransaction stores the transaction and fills in the generated id and timestamps.
+func insertTransaction(ctx context.Context, tx *sql.Tx, t *transaction.Transaction) error {
+	var metadata *string
+	if len(t.Metadata) > 0 {
+		b, err := json.Marshal(t.Metadata)
+		if err != nil {
+			return err
+		}
+		s := string(b)
+		metadata = &s
+	}
+
+	id := uuid.NewString()
+	now := time.Now().UTC()
+
+	_, err := tx.ExecContext(ctx, insertTransactionSql,
+		id,                               // ?1
+		t.AccountID,                      // ?2
+		t.Amount,                         // ?3
+		t.Type,                           // ?4
+		now,                              // ?5
+		nullIfEmpty(t.Description),       // ?6
+		nullIfEmpty(t.ExternalReference), // ?7
+		nullIfEmpty(t.Counterparty),      // ?8
+		metadata,                         // ?9
+	)
+	if constraintViolation(err, uniqueViolation) {
+		return errorx.NewError(
+			fmt.Errorf("transaction with external reference %s already exists", t.ExternalReference),
+			errorx.ErrConflict,
+		)
+	}
+	if err != nil {
+		return err
+	}
+
+	t.ID = id
+	t.Timestamp, t.CreatedAt, t.UpdatedAt = now, now, now
+	return nil
+}
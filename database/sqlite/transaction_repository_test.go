@@ -0,0 +1,94 @@
+package sqlite_test
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fmiskovic/cash-me-if-you-can/database/repotest"
+	"github.com/fmiskovic/cash-me-if-you-can/database/sqlite"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/account"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/transaction"
+)
+
+// newDB opens a migrated sqlite database in a temporary file.
+func newDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	db, err := sqlite.Open(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	require.NoError(t, sqlite.Migrate(context.Background(), db))
+	return db
+}
+
+func TestRepositoryConformance(t *testing.T) {
+	db := newDB(t)
+	repotest.Run(t, sqlite.NewAccountRepository(db), sqlite.NewTransactionRepository(db))
+}
+
+func TestTransactionRepositoryConcurrency(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	db := newDB(t)
+
+	accountRepo := sqlite.NewAccountRepository(db)
+	repo := sqlite.NewTransactionRepository(db)
+
+	ids := make([]string, 0, 3)
+	for _, owner := range []string{"Alice", "Bob", "Charlie"} {
+		acc, err := accountRepo.Create(ctx, account.New(account.WithOwner(owner), account.WithBalance(100)))
+		require.NoError(t, err)
+		ids = append(ids, acc.ID)
+	}
+
+	// concurrent transfers in every direction and withdrawals competing for the same funds
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			from, to := transaction.NewTransferLegs(transaction.TransferRequest{
+				FromAccountID: ids[i%3],
+				ToAccountID:   ids[(i+1)%3],
+				Amount:        1,
+			})
+			_ = repo.Transfer(ctx, from, to)
+		}()
+		go func() {
+			defer wg.Done()
+			_, _ = repo.Create(ctx, transaction.New(
+				transaction.WithAccountID(ids[i%3]),
+				transaction.WithType(transaction.Withdrawal),
+				transaction.WithAmount(2),
+			))
+		}()
+	}
+	wg.Wait()
+
+	// BEGIN IMMEDIATE serializes writers, so no update is lost and no balance goes negative
+	for _, id := range ids {
+		acc, err := accountRepo.Get(ctx, id)
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, acc.Balance, 0.0)
+
+		trs, err := repo.GetByAccountId(ctx, transaction.ListRequest{AccountID: id})
+		require.NoError(t, err)
+
+		sum := 0.0
+		for _, tr := range trs {
+			if tr.Type.IsDebit() {
+				sum -= tr.Amount
+			} else {
+				sum += tr.Amount
+			}
+		}
+		assert.InDelta(t, sum, acc.Balance, 1e-9)
+	}
+}
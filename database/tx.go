@@ -14,11 +14,13 @@ type TxManager interface {
 }
 
 func NewTxManager(db Service) TxManager {
-	return &txManager{db.Pool()}
+	return &txManager{Pool: db.Pool(), replicated: db.Replicated()}
 }
 
 type txManager struct {
 	*pgxpool.Pool
+	// replicated tracks committed writes in the session of the context, so replicas serve them consistently.
+	replicated bool
 }
 
 func (tm *txManager) Begin(ctx context.Context) (pgx.Tx, error) {
@@ -41,6 +43,9 @@ func (tm *txManager) Execute(ctx context.Context, fn func(tx pgx.Tx) error) erro
 		} else {
 			// if Commit returns error update err with commit err
 			err = tx.Commit(ctx)
+			if err == nil && tm.replicated {
+				trackWrite(ctx, tm.Pool)
+			}
 		}
 	}()
 	err = fn(tx)
@@ -72,7 +72,7 @@ func (mr *MockRepositoryMockRecorder) Get(arg0, arg1 any) *gomock.Call {
 }
 
 // List mocks base method.
-func (m *MockRepository) List(arg0 context.Context, arg1 internal.PageRequest) (internal.Page[account.Account], error) {
+func (m *MockRepository) List(arg0 context.Context, arg1 account.ListRequest) (internal.Page[account.Account], error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "List", arg0, arg1)
 	ret0, _ := ret[0].(internal.Page[account.Account])
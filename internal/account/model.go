@@ -1,11 +1,33 @@
 package account
 
+import "time"
+
 type Account struct {
-	ID      string
-	Owner   string
-	Balance float64
+	ID        string
+	Owner     string
+	Balance   float64
+	Currency  string
+	Status    Status
+	Product   string
+	CreatedAt time.Time
+	UpdatedAt time.Time
 }
 
+type Status string
+
+const (
+	Active Status = "active"
+	Frozen Status = "frozen"
+	Closed Status = "closed"
+)
+
+// DefaultCurrency is used when an account is created without a currency.
+const DefaultCurrency = "USD"
+
+// DefaultProduct is used when an account is created without a product.
+// Products define the pricing of an account, e.g. its interest rate.
+const DefaultProduct = "current"
+
 type Option func(*Account)
 
 func New(opts ...Option) *Account {
@@ -34,3 +56,21 @@ func WithBalance(balance float64) Option {
 		a.Balance = balance
 	}
 }
+
+func WithCurrency(currency string) Option {
+	return func(a *Account) {
+		a.Currency = currency
+	}
+}
+
+func WithStatus(status Status) Option {
+	return func(a *Account) {
+		a.Status = status
+	}
+}
+
+func WithProduct(product string) Option {
+	return func(a *Account) {
+		a.Product = product
+	}
+}
@@ -1,6 +1,49 @@
 package account
 
+import "github.com/fmiskovic/cash-me-if-you-can/internal"
+
 type CreateRequest struct {
-	Owner   string  `json:"owner" validate:"required,min=2,max=72"`
-	Balance float64 `json:"initial_balance" validate:"required,gt=0"`
+	Owner    string  `json:"owner" validate:"required,min=2,max=72"`
+	Balance  float64 `json:"initial_balance" validate:"required,gt=0"`
+	Currency string  `json:"currency" validate:"omitempty,iso4217"`
+	Product  string  `json:"product" validate:"omitempty,max=30"`
+}
+
+type SortField string
+
+const (
+	SortByCreatedAt SortField = "created_at"
+	SortByOwner     SortField = "owner"
+	SortByBalance   SortField = "balance"
+	SortByUpdatedAt SortField = "updated_at"
+)
+
+type SortOrder string
+
+const (
+	Asc  SortOrder = "asc"
+	Desc SortOrder = "desc"
+)
+
+// ListRequest is a page request narrowed down by optional filters.
+// Zero values mean the filter is not applied.
+type ListRequest struct {
+	internal.PageRequest
+	internal.TimeFilter
+
+	Owner      string    `validate:"omitempty,max=72"`
+	Status     Status    `validate:"omitempty,oneof=active frozen closed"`
+	Currency   string    `validate:"omitempty,iso4217"`
+	MinBalance *float64  `validate:"omitempty,gte=0"`
+	MaxBalance *float64  `validate:"omitempty,gte=0"`
+	SortBy     SortField `validate:"omitempty,oneof=created_at updated_at owner balance"`
+	SortOrder  SortOrder `validate:"omitempty,oneof=asc desc"`
+}
+
+func DefaultListRequest() ListRequest {
+	return ListRequest{
+		PageRequest: internal.DefaultPageRequest(),
+		SortBy:      SortByCreatedAt,
+		SortOrder:   Asc,
+	}
 }
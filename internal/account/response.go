@@ -1,7 +1,14 @@
 package account
 
+import "time"
+
 type Details struct {
-	AccountId string  `json:"account_id"`
-	Owner     string  `json:"owner"`
-	Balance   float64 `json:"balance"`
+	AccountId string    `json:"account_id"`
+	Owner     string    `json:"owner"`
+	Balance   float64   `json:"balance"`
+	Currency  string    `json:"currency"`
+	Status    string    `json:"status"`
+	Product   string    `json:"product"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
 }
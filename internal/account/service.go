@@ -7,12 +7,15 @@ import (
 	"github.com/softika/slogging"
 
 	"github.com/fmiskovic/cash-me-if-you-can/internal"
+	"github.com/fmiskovic/cash-me-if-you-can/pkg/errorx"
 )
 
 type Repository interface {
+	// Create stores the account and books its initial balance as a deposit,
+	// so the balance of every account always matches its transaction history.
 	Create(context.Context, *Account) (*Account, error)
 	Get(context.Context, string) (*Account, error)
-	List(context.Context, internal.PageRequest) (internal.Page[Account], error)
+	List(context.Context, ListRequest) (internal.Page[Account], error)
 }
 
 type Service struct {
@@ -24,9 +27,20 @@ func NewService(repo Repository) Service {
 }
 
 func (s Service) Create(ctx context.Context, req CreateRequest) (*Details, error) {
+	currency := req.Currency
+	if currency == "" {
+		currency = DefaultCurrency
+	}
+	product := req.Product
+	if product == "" {
+		product = DefaultProduct
+	}
+
 	input := New(
 		WithOwner(req.Owner),
 		WithBalance(req.Balance),
+		WithCurrency(currency),
+		WithProduct(product),
 	)
 
 	a, err := s.repo.Create(ctx, input)
@@ -36,11 +50,7 @@ func (s Service) Create(ctx context.Context, req CreateRequest) (*Details, error
 		return nil, err
 	}
 
-	return &Details{
-		AccountId: a.ID,
-		Owner:     a.Owner,
-		Balance:   a.Balance,
-	}, err
+	return newDetails(a), err
 }
 
 func (s Service) Get(ctx context.Context, id string) (*Details, error) {
@@ -50,14 +60,17 @@ func (s Service) Get(ctx context.Context, id string) (*Details, error) {
 		logger.ErrorContext(ctx, "failed to get account by id", "id", id, "err", err)
 		return nil, err
 	}
-	return &Details{
-		AccountId: a.ID,
-		Owner:     a.Owner,
-		Balance:   a.Balance,
-	}, nil
+	return newDetails(a), nil
 }
 
-func (s Service) List(ctx context.Context, req internal.PageRequest) (internal.Page[Details], error) {
+func (s Service) List(ctx context.Context, req ListRequest) (internal.Page[Details], error) {
+	if req.MinBalance != nil && req.MaxBalance != nil && *req.MinBalance > *req.MaxBalance {
+		return internal.EmptyPage[Details](), errorx.NewErrorMsg(
+			"min balance must not be greater than max balance",
+			errorx.ErrInvalidInput,
+		)
+	}
+
 	page, err := s.repo.List(ctx, req)
 	if err != nil {
 		logger := slogging.Slogger()
@@ -67,11 +80,7 @@ func (s Service) List(ctx context.Context, req internal.PageRequest) (internal.P
 
 	detailsList := make([]Details, len(page.Items))
 	for i := range page.Items {
-		detailsList[i] = Details{
-			AccountId: page.Items[i].ID,
-			Owner:     page.Items[i].Owner,
-			Balance:   page.Items[i].Balance,
-		}
+		detailsList[i] = *newDetails(&page.Items[i])
 	}
 
 	return internal.Page[Details]{
@@ -80,3 +89,16 @@ func (s Service) List(ctx context.Context, req internal.PageRequest) (internal.P
 		Items:      detailsList,
 	}, nil
 }
+
+func newDetails(a *Account) *Details {
+	return &Details{
+		AccountId: a.ID,
+		Owner:     a.Owner,
+		Balance:   a.Balance,
+		Currency:  a.Currency,
+		Status:    string(a.Status),
+		Product:   a.Product,
+		CreatedAt: a.CreatedAt,
+		UpdatedAt: a.UpdatedAt,
+	}
+}
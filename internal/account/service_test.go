@@ -22,6 +22,9 @@ func TestCreateAccount(t *testing.T) {
 		AccountId: "1",
 		Owner:     "Alice",
 		Balance:   100.37,
+		Currency:  account.DefaultCurrency,
+		Status:    string(account.Active),
+		Product:   account.DefaultProduct,
 	}
 
 	tests := []struct {
@@ -41,17 +44,57 @@ func TestCreateAccount(t *testing.T) {
 				input := account.New(
 					account.WithOwner("Alice"),
 					account.WithBalance(100.37),
+					account.WithCurrency(account.DefaultCurrency),
+					account.WithProduct(account.DefaultProduct),
 				)
 				got := account.New(
 					account.WithId("1"),
 					account.WithOwner("Alice"),
 					account.WithBalance(100.37),
+					account.WithCurrency(account.DefaultCurrency),
+					account.WithStatus(account.Active),
+					account.WithProduct(account.DefaultProduct),
 				)
 				m.EXPECT().Create(ctx, input).Return(got, nil)
 			},
 			want:    details,
 			wantErr: assert.NoError,
 		},
+		{
+			name: "create account with currency and product",
+			req: account.CreateRequest{
+				Owner:    "Alice",
+				Balance:  100.37,
+				Currency: "EUR",
+				Product:  "savings",
+			},
+			mockFn: func(m *mock.MockRepository) {
+				input := account.New(
+					account.WithOwner("Alice"),
+					account.WithBalance(100.37),
+					account.WithCurrency("EUR"),
+					account.WithProduct("savings"),
+				)
+				got := account.New(
+					account.WithId("1"),
+					account.WithOwner("Alice"),
+					account.WithBalance(100.37),
+					account.WithCurrency("EUR"),
+					account.WithStatus(account.Active),
+					account.WithProduct("savings"),
+				)
+				m.EXPECT().Create(ctx, input).Return(got, nil)
+			},
+			want: &account.Details{
+				AccountId: "1",
+				Owner:     "Alice",
+				Balance:   100.37,
+				Currency:  "EUR",
+				Status:    string(account.Active),
+				Product:   "savings",
+			},
+			wantErr: assert.NoError,
+		},
 		{
 			name: "create account error",
 			req: account.CreateRequest{
@@ -86,6 +129,8 @@ func TestCreateAccount(t *testing.T) {
 			assert.Equal(t, tt.want.AccountId, got.AccountId)
 			assert.Equal(t, tt.want.Owner, got.Owner)
 			assert.Equal(t, tt.want.Balance, got.Balance)
+			assert.Equal(t, tt.want.Currency, got.Currency)
+			assert.Equal(t, tt.want.Status, got.Status)
 		})
 	}
 }
@@ -182,14 +227,14 @@ func TestListAccounts(t *testing.T) {
 
 	tests := []struct {
 		name    string
-		req     internal.PageRequest
+		req     account.ListRequest
 		mockFn  func(m *mock.MockRepository)
 		want    internal.Page[account.Details]
 		wantErr assert.ErrorAssertionFunc
 	}{
 		{
 			name: "list accounts",
-			req:  internal.DefaultPageRequest(),
+			req:  account.DefaultListRequest(),
 			mockFn: func(m *mock.MockRepository) {
 				acc1 := account.Account{
 					ID:      "1",
@@ -207,22 +252,35 @@ func TestListAccounts(t *testing.T) {
 					TotalItems: 2,
 					TotalPages: 1,
 				}
-				m.EXPECT().List(ctx, internal.DefaultPageRequest()).Return(got, nil)
+				m.EXPECT().List(ctx, account.DefaultListRequest()).Return(got, nil)
 			},
 			want:    page,
 			wantErr: assert.NoError,
 		},
 		{
 			name: "list accounts error",
-			req:  internal.DefaultPageRequest(),
+			req:  account.DefaultListRequest(),
 			mockFn: func(m *mock.MockRepository) {
 				m.EXPECT().
-					List(ctx, internal.DefaultPageRequest()).
+					List(ctx, account.DefaultListRequest()).
 					Return(internal.Page[account.Account]{}, assert.AnError)
 			},
 			want:    internal.Page[account.Details]{},
 			wantErr: assert.Error,
 		},
+		{
+			name: "invalid balance range",
+			req: func() account.ListRequest {
+				minBalance, maxBalance := 200.0, 100.0
+				req := account.DefaultListRequest()
+				req.MinBalance = &minBalance
+				req.MaxBalance = &maxBalance
+				return req
+			}(),
+			mockFn:  func(m *mock.MockRepository) {},
+			want:    internal.Page[account.Details]{},
+			wantErr: assert.Error,
+		},
 	}
 
 	for _, tc := range tests {
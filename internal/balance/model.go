@@ -0,0 +1,23 @@
+package balance
+
+import "time"
+
+// Balance of an account at a point in time.
+type Balance struct {
+	AccountID string
+	Currency  string
+	Amount    float64
+	AsOf      time.Time
+}
+
+// SnapshotInterval is the time between two balance snapshots of an account.
+const SnapshotInterval = 24 * time.Hour
+
+// snapshotDelay keeps a snapshot from being taken before the transactions in flight at its time have committed.
+const snapshotDelay = time.Hour
+
+// SnapshotTime returns the time of the latest snapshot that can be taken at the given time.
+// Snapshots are taken at midnight UTC.
+func SnapshotTime(now time.Time) time.Time {
+	return now.UTC().Add(-snapshotDelay).Truncate(SnapshotInterval)
+}
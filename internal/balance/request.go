@@ -0,0 +1,9 @@
+package balance
+
+import "time"
+
+// AsOfRequest selects the balance of an account at a point in time, the current balance when AsOf is not set.
+type AsOfRequest struct {
+	AccountID string     `validate:"required"`
+	AsOf      *time.Time `validate:"omitempty"`
+}
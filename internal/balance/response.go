@@ -0,0 +1,19 @@
+package balance
+
+import "time"
+
+type Details struct {
+	AccountId string    `json:"account_id"`
+	Currency  string    `json:"currency"`
+	Balance   float64   `json:"balance"`
+	AsOf      time.Time `json:"as_of"`
+}
+
+func newDetails(b *Balance) *Details {
+	return &Details{
+		AccountId: b.AccountID,
+		Currency:  b.Currency,
+		Balance:   b.Amount,
+		AsOf:      b.AsOf,
+	}
+}
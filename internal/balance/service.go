@@ -0,0 +1,81 @@
+//go:generate mockgen -source=service.go -destination=./mock/service.go -package=mock
+package balance
+
+import (
+	"context"
+	"time"
+
+	"github.com/softika/slogging"
+
+	"github.com/fmiskovic/cash-me-if-you-can/pkg/clock"
+	"github.com/fmiskovic/cash-me-if-you-can/pkg/errorx"
+)
+
+type Repository interface {
+	// AsOf returns the balance of the account at the given time, computed from the nearest snapshot and the transactions in between.
+	// It fails with invalid input if the time lies before the end of the archived months.
+	AsOf(ctx context.Context, accountID string, asOf time.Time) (*Balance, error)
+	// LastSnapshot returns the time of the latest snapshot, nil if no snapshot has been taken.
+	LastSnapshot(context.Context) (*time.Time, error)
+	// Snapshot stores the balances of all accounts existing at the given time and returns the number of snapshots taken.
+	// Snapshots already taken at that time are kept.
+	Snapshot(ctx context.Context, at time.Time) (int, error)
+}
+
+type Service struct {
+	repo  Repository
+	clock clock.Clock
+}
+
+func NewService(repo Repository, clk clock.Clock) Service {
+	return Service{
+		repo:  repo,
+		clock: clk,
+	}
+}
+
+func (s Service) AsOf(ctx context.Context, req AsOfRequest) (*Details, error) {
+	now := s.clock.Now()
+
+	asOf := now
+	if req.AsOf != nil {
+		if req.AsOf.After(now) {
+			return nil, errorx.NewErrorMsg("balance cannot be computed for a future time", errorx.ErrInvalidInput)
+		}
+		asOf = *req.AsOf
+	}
+
+	b, err := s.repo.AsOf(ctx, req.AccountID, asOf)
+	if err != nil {
+		logger := slogging.Slogger()
+		logger.ErrorContext(ctx, "failed to get balance", "account_id", req.AccountID, "as_of", asOf, "error", err)
+		return nil, err
+	}
+
+	return newDetails(b), nil
+}
+
+// Snapshot takes the due balance snapshot and returns the number of accounts it covers.
+// It is a no-op until the next snapshot is due.
+func (s Service) Snapshot(ctx context.Context) (int, error) {
+	logger := slogging.Slogger()
+
+	at := SnapshotTime(s.clock.Now())
+
+	last, err := s.repo.LastSnapshot(ctx)
+	if err != nil {
+		logger.ErrorContext(ctx, "failed to get last balance snapshot", "error", err)
+		return 0, err
+	}
+	if last != nil && !last.Before(at) {
+		return 0, nil
+	}
+
+	n, err := s.repo.Snapshot(ctx, at)
+	if err != nil {
+		logger.ErrorContext(ctx, "failed to take balance snapshot", "at", at, "error", err)
+		return 0, err
+	}
+
+	return n, nil
+}
@@ -0,0 +1,161 @@
+package balance_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/fmiskovic/cash-me-if-you-can/internal/balance"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/balance/mock"
+	"github.com/fmiskovic/cash-me-if-you-can/pkg/clock"
+)
+
+var now = time.Date(2024, time.March, 15, 10, 30, 0, 0, time.UTC)
+
+func TestAsOf(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+
+	past := now.Add(-48 * time.Hour)
+	future := now.Add(time.Minute)
+
+	tests := []struct {
+		name    string
+		req     balance.AsOfRequest
+		mockFn  func(*mock.MockRepository)
+		want    *balance.Details
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name: "current balance",
+			req:  balance.AsOfRequest{AccountID: "1"},
+			mockFn: func(repo *mock.MockRepository) {
+				repo.EXPECT().AsOf(ctx, "1", now).
+					Return(&balance.Balance{AccountID: "1", Currency: "EUR", Amount: 10, AsOf: now}, nil)
+			},
+			want:    &balance.Details{AccountId: "1", Currency: "EUR", Balance: 10, AsOf: now},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "past balance",
+			req:  balance.AsOfRequest{AccountID: "1", AsOf: &past},
+			mockFn: func(repo *mock.MockRepository) {
+				repo.EXPECT().AsOf(ctx, "1", past).
+					Return(&balance.Balance{AccountID: "1", Currency: "EUR", Amount: 5, AsOf: past}, nil)
+			},
+			want:    &balance.Details{AccountId: "1", Currency: "EUR", Balance: 5, AsOf: past},
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "future balance",
+			req:     balance.AsOfRequest{AccountID: "1", AsOf: &future},
+			mockFn:  func(repo *mock.MockRepository) {},
+			wantErr: assert.Error,
+		},
+		{
+			name: "repository error",
+			req:  balance.AsOfRequest{AccountID: "1"},
+			mockFn: func(repo *mock.MockRepository) {
+				repo.EXPECT().AsOf(ctx, "1", now).Return(nil, assert.AnError)
+			},
+			wantErr: assert.Error,
+		},
+	}
+
+	for _, tc := range tests {
+		tt := tc
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := mock.NewMockRepository(ctrl)
+			tt.mockFn(repo)
+
+			s := balance.NewService(repo, clock.Fixed(now))
+			got, err := s.AsOf(ctx, tt.req)
+			tt.wantErr(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSnapshotTime(t *testing.T) {
+	t.Parallel()
+
+	midnight := time.Date(2024, time.March, 15, 0, 0, 0, 0, time.UTC)
+
+	// transactions in flight at midnight are given an hour to commit
+	assert.Equal(t, midnight.AddDate(0, 0, -1), balance.SnapshotTime(midnight.Add(30*time.Minute)))
+	assert.Equal(t, midnight, balance.SnapshotTime(midnight.Add(time.Hour)))
+	assert.Equal(t, midnight, balance.SnapshotTime(midnight.Add(23*time.Hour)))
+}
+
+func TestSnapshot(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+
+	at := time.Date(2024, time.March, 15, 0, 0, 0, 0, time.UTC)
+	yesterday := at.AddDate(0, 0, -1)
+
+	tests := []struct {
+		name    string
+		mockFn  func(*mock.MockRepository)
+		want    int
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name: "first snapshot",
+			mockFn: func(repo *mock.MockRepository) {
+				repo.EXPECT().LastSnapshot(ctx).Return(nil, nil)
+				repo.EXPECT().Snapshot(ctx, at).Return(4, nil)
+			},
+			want:    4,
+			wantErr: assert.NoError,
+		},
+		{
+			name: "snapshot due",
+			mockFn: func(repo *mock.MockRepository) {
+				repo.EXPECT().LastSnapshot(ctx).Return(&yesterday, nil)
+				repo.EXPECT().Snapshot(ctx, at).Return(4, nil)
+			},
+			want:    4,
+			wantErr: assert.NoError,
+		},
+		{
+			name: "snapshot already taken",
+			mockFn: func(repo *mock.MockRepository) {
+				repo.EXPECT().LastSnapshot(ctx).Return(&at, nil)
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "repository error",
+			mockFn: func(repo *mock.MockRepository) {
+				repo.EXPECT().LastSnapshot(ctx).Return(nil, nil)
+				repo.EXPECT().Snapshot(ctx, at).Return(0, assert.AnError)
+			},
+			wantErr: assert.Error,
+		},
+	}
+
+	for _, tc := range tests {
+		tt := tc
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := mock.NewMockRepository(ctrl)
+			tt.mockFn(repo)
+
+			s := balance.NewService(repo, clock.Fixed(now))
+			got, err := s.Snapshot(ctx)
+			tt.wantErr(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -1,5 +1,7 @@
 package internal
 
+import "time"
+
 type Page[T any] struct {
 	TotalPages int
 	TotalItems int
@@ -11,6 +13,14 @@ type PageRequest struct {
 	Offset int
 }
 
+// TimeFilter narrows down a list by creation and modification time.
+// Nil bounds are not applied.
+type TimeFilter struct {
+	CreatedFrom  *time.Time // inclusive
+	CreatedTo    *time.Time // exclusive
+	UpdatedSince *time.Time // exclusive, used to poll for changes
+}
+
 func DefaultPageRequest() PageRequest {
 	return PageRequest{
 		Limit:  10,
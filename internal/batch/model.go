@@ -0,0 +1,68 @@
+package batch
+
+import (
+	"time"
+
+	"github.com/fmiskovic/cash-me-if-you-can/internal/transaction"
+)
+
+// Mode defines how failures of individual transfers affect the batch.
+type Mode string
+
+const (
+	// Atomic executes either all transfers or none of them.
+	Atomic Mode = "atomic"
+	// BestEffort executes every transfer that can be executed and reports the rest.
+	BestEffort Mode = "best_effort"
+)
+
+type Status string
+
+const (
+	Completed          Status = "completed"
+	PartiallyCompleted Status = "partially_completed"
+	Failed             Status = "failed"
+)
+
+type ItemStatus string
+
+const (
+	ItemSucceeded ItemStatus = "succeeded"
+	ItemFailed    ItemStatus = "failed"
+)
+
+type Batch struct {
+	ID        string
+	Mode      Mode
+	Status    Status
+	Items     []Item
+	CreatedAt time.Time
+}
+
+// Item is a single transfer of a batch, made of a withdrawal and a deposit leg.
+type Item struct {
+	Position int
+	From     *transaction.Transaction
+	To       *transaction.Transaction
+	Status   ItemStatus
+	Error    string
+}
+
+// Resolve sets the batch status from the status of its items.
+func (b *Batch) Resolve() {
+	succeeded := 0
+	for _, item := range b.Items {
+		if item.Status == ItemSucceeded {
+			succeeded++
+		}
+	}
+
+	switch succeeded {
+	case len(b.Items):
+		b.Status = Completed
+	case 0:
+		b.Status = Failed
+	default:
+		b.Status = PartiallyCompleted
+	}
+}
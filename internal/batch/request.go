@@ -0,0 +1,8 @@
+package batch
+
+import "github.com/fmiskovic/cash-me-if-you-can/internal/transaction"
+
+type TransferRequest struct {
+	Mode      Mode                          `json:"mode" validate:"omitempty,oneof=atomic best_effort"`
+	Transfers []transaction.TransferRequest `json:"transfers" validate:"required,min=1,max=1000,dive"`
+}
@@ -0,0 +1,22 @@
+package batch
+
+import "time"
+
+type Details struct {
+	BatchId   string        `json:"batch_id"`
+	Mode      string        `json:"mode"`
+	Status    string        `json:"status"`
+	Items     []ItemDetails `json:"items"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+type ItemDetails struct {
+	Position          int     `json:"position"`
+	FromAccountId     string  `json:"from_account_id"`
+	ToAccountId       string  `json:"to_account_id"`
+	Amount            float64 `json:"amount"`
+	Status            string  `json:"status"`
+	Error             string  `json:"error,omitempty"`
+	FromTransactionId string  `json:"from_transaction_id,omitempty"`
+	ToTransactionId   string  `json:"to_transaction_id,omitempty"`
+}
@@ -0,0 +1,93 @@
+//go:generate mockgen -source=service.go -destination=./mock/service.go -package=mock
+package batch
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/softika/slogging"
+
+	"github.com/fmiskovic/cash-me-if-you-can/internal/transaction"
+	"github.com/fmiskovic/cash-me-if-you-can/pkg/errorx"
+)
+
+type Repository interface {
+	// Transfer executes the batch items and stores the batch with the outcome of each item.
+	Transfer(context.Context, *Batch) error
+	Get(context.Context, string) (*Batch, error)
+}
+
+type Service struct {
+	repo Repository
+}
+
+func NewService(repo Repository) Service {
+	return Service{repo: repo}
+}
+
+func (s Service) Transfer(ctx context.Context, req TransferRequest) (*Details, error) {
+	mode := req.Mode
+	if mode == "" {
+		mode = Atomic
+	}
+
+	b := &Batch{
+		Mode:  mode,
+		Items: make([]Item, len(req.Transfers)),
+	}
+
+	// validate all transfers up front, so nothing is executed for an invalid batch
+	for i, tr := range req.Transfers {
+		if err := transaction.ValidateTransfer(tr); err != nil {
+			return nil, errorx.NewError(fmt.Errorf("transfer %d: %w", i, err), errorx.ErrInvalidInput)
+		}
+		from, to := transaction.NewTransferLegs(tr)
+		b.Items[i] = Item{
+			Position: i,
+			From:     from,
+			To:       to,
+		}
+	}
+
+	if err := s.repo.Transfer(ctx, b); err != nil {
+		logger := slogging.Slogger()
+		logger.ErrorContext(ctx, "failed to execute batch transfer", "mode", mode, "error", err)
+		return nil, err
+	}
+
+	return newDetails(b), nil
+}
+
+func (s Service) Get(ctx context.Context, id string) (*Details, error) {
+	b, err := s.repo.Get(ctx, id)
+	if err != nil {
+		logger := slogging.Slogger()
+		logger.ErrorContext(ctx, "failed to get batch by id", "id", id, "error", err)
+		return nil, err
+	}
+	return newDetails(b), nil
+}
+
+func newDetails(b *Batch) *Details {
+	items := make([]ItemDetails, len(b.Items))
+	for i, item := range b.Items {
+		items[i] = ItemDetails{
+			Position:          item.Position,
+			FromAccountId:     item.From.AccountID,
+			ToAccountId:       item.To.AccountID,
+			Amount:            item.From.Amount,
+			Status:            string(item.Status),
+			Error:             item.Error,
+			FromTransactionId: item.From.ID,
+			ToTransactionId:   item.To.ID,
+		}
+	}
+
+	return &Details{
+		BatchId:   b.ID,
+		Mode:      string(b.Mode),
+		Status:    string(b.Status),
+		Items:     items,
+		CreatedAt: b.CreatedAt,
+	}
+}
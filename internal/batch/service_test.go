@@ -0,0 +1,209 @@
+package batch_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/fmiskovic/cash-me-if-you-can/internal/batch"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/batch/mock"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/transaction"
+)
+
+func TestBatchTransfer(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+
+	req := batch.TransferRequest{
+		Transfers: []transaction.TransferRequest{
+			{FromAccountID: "1", ToAccountID: "2", Amount: 10},
+			{FromAccountID: "1", ToAccountID: "3", Amount: 20},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		req     batch.TransferRequest
+		mockFn  func(*mock.MockRepository)
+		want    *batch.Details
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name: "atomic batch by default",
+			req:  req,
+			mockFn: func(repo *mock.MockRepository) {
+				repo.EXPECT().Transfer(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, b *batch.Batch) error {
+					assert.Equal(t, batch.Atomic, b.Mode)
+					assert.Len(t, b.Items, 2)
+					b.ID = "batch-1"
+					for i := range b.Items {
+						b.Items[i].Status = batch.ItemSucceeded
+					}
+					b.Resolve()
+					return nil
+				})
+			},
+			want: &batch.Details{
+				BatchId: "batch-1",
+				Mode:    string(batch.Atomic),
+				Status:  string(batch.Completed),
+				Items: []batch.ItemDetails{
+					{Position: 0, FromAccountId: "1", ToAccountId: "2", Amount: 10, Status: string(batch.ItemSucceeded)},
+					{Position: 1, FromAccountId: "1", ToAccountId: "3", Amount: 20, Status: string(batch.ItemSucceeded)},
+				},
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "best effort batch with failed item",
+			req: batch.TransferRequest{
+				Mode:      batch.BestEffort,
+				Transfers: req.Transfers,
+			},
+			mockFn: func(repo *mock.MockRepository) {
+				repo.EXPECT().Transfer(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, b *batch.Batch) error {
+					b.ID = "batch-2"
+					b.Items[0].Status = batch.ItemSucceeded
+					b.Items[1].Status, b.Items[1].Error = batch.ItemFailed, "insufficient funds"
+					b.Resolve()
+					return nil
+				})
+			},
+			want: &batch.Details{
+				BatchId: "batch-2",
+				Mode:    string(batch.BestEffort),
+				Status:  string(batch.PartiallyCompleted),
+				Items: []batch.ItemDetails{
+					{Position: 0, FromAccountId: "1", ToAccountId: "2", Amount: 10, Status: string(batch.ItemSucceeded)},
+					{Position: 1, FromAccountId: "1", ToAccountId: "3", Amount: 20, Status: string(batch.ItemFailed), Error: "insufficient funds"},
+				},
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "invalid transfer fails the whole batch",
+			req: batch.TransferRequest{
+				Transfers: []transaction.TransferRequest{
+					{FromAccountID: "1", ToAccountID: "2", Amount: 10},
+					{FromAccountID: "1", ToAccountID: "1", Amount: 10},
+				},
+			},
+			mockFn:  func(repo *mock.MockRepository) {},
+			wantErr: assert.Error,
+		},
+		{
+			name: "repository error",
+			req:  req,
+			mockFn: func(repo *mock.MockRepository) {
+				repo.EXPECT().Transfer(ctx, gomock.Any()).Return(assert.AnError)
+			},
+			wantErr: assert.Error,
+		},
+	}
+
+	for _, tc := range tests {
+		tt := tc
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := mock.NewMockRepository(ctrl)
+
+			tt.mockFn(repo)
+
+			s := batch.NewService(repo)
+
+			got, err := s.Transfer(ctx, tt.req)
+			if err != nil {
+				tt.wantErr(t, err, "unexpected error")
+				return
+			}
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestGetBatch(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+
+	tests := []struct {
+		name    string
+		id      string
+		mockFn  func(*mock.MockRepository)
+		want    *batch.Details
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name: "get batch",
+			id:   "batch-1",
+			mockFn: func(repo *mock.MockRepository) {
+				repo.EXPECT().Get(ctx, "batch-1").Return(&batch.Batch{
+					ID:     "batch-1",
+					Mode:   batch.Atomic,
+					Status: batch.Completed,
+					Items: []batch.Item{
+						{
+							Position: 0,
+							From:     &transaction.Transaction{ID: "t1", AccountID: "1", Amount: 10},
+							To:       &transaction.Transaction{ID: "t2", AccountID: "2", Amount: 10},
+							Status:   batch.ItemSucceeded,
+						},
+					},
+				}, nil)
+			},
+			want: &batch.Details{
+				BatchId: "batch-1",
+				Mode:    string(batch.Atomic),
+				Status:  string(batch.Completed),
+				Items: []batch.ItemDetails{
+					{
+						Position:          0,
+						FromAccountId:     "1",
+						ToAccountId:       "2",
+						Amount:            10,
+						Status:            string(batch.ItemSucceeded),
+						FromTransactionId: "t1",
+						ToTransactionId:   "t2",
+					},
+				},
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "get batch error",
+			id:   "batch-1",
+			mockFn: func(repo *mock.MockRepository) {
+				repo.EXPECT().Get(ctx, "batch-1").Return(nil, assert.AnError)
+			},
+			wantErr: assert.Error,
+		},
+	}
+
+	for _, tc := range tests {
+		tt := tc
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := mock.NewMockRepository(ctrl)
+
+			tt.mockFn(repo)
+
+			s := batch.NewService(repo)
+
+			got, err := s.Get(ctx, tt.id)
+			if err != nil {
+				tt.wantErr(t, err, "unexpected error")
+				return
+			}
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package event
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/softika/slogging"
+)
+
+// listenRetryDelay is the time to wait before listening again after the listener failed.
+const listenRetryDelay = 5 * time.Second
+
+// hub shares one listener between all subscriptions and wakes the subscribers of notified accounts.
+// It listens only while there are subscribers.
+type hub struct {
+	repo Repository
+
+	mu          sync.Mutex
+	subscribers map[*subscriber]struct{}
+	stop        context.CancelFunc
+}
+
+type subscriber struct {
+	accountID string
+	wake      chan struct{}
+}
+
+func newHub(repo Repository) *hub {
+	return &hub{
+		repo:        repo,
+		subscribers: make(map[*subscriber]struct{}),
+	}
+}
+
+func (h *hub) subscribe(accountID string) *subscriber {
+	sub := &subscriber{
+		accountID: accountID,
+		wake:      make(chan struct{}, 1),
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if len(h.subscribers) == 0 {
+		ctx, cancel := context.WithCancel(context.Background())
+		h.stop = cancel
+		go h.listen(ctx)
+	}
+	h.subscribers[sub] = struct{}{}
+
+	return sub
+}
+
+func (h *hub) unsubscribe(sub *subscriber) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	delete(h.subscribers, sub)
+	if len(h.subscribers) == 0 && h.stop != nil {
+		h.stop()
+		h.stop = nil
+	}
+}
+
+// listen keeps listening until the context is done. Notifications may be missed while the listener is down,
+// so every subscriber is woken up once it listens again.
+func (h *hub) listen(ctx context.Context) {
+	logger := slogging.Slogger()
+
+	for {
+		err := h.repo.Listen(ctx, func() { h.notify("") }, h.notify)
+		if ctx.Err() != nil {
+			return
+		}
+		logger.ErrorContext(ctx, "failed to listen for account events", "error", err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(listenRetryDelay):
+		}
+	}
+}
+
+// notify wakes the subscribers of the account and the subscribers of all accounts, everyone if the account is empty.
+func (h *hub) notify(accountID string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for sub := range h.subscribers {
+		if accountID != "" && sub.accountID != "" && sub.accountID != accountID {
+			continue
+		}
+		// a pending wake up is enough, the subscriber reads all new events
+		select {
+		case sub.wake <- struct{}{}:
+		default:
+		}
+	}
+}
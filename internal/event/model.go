@@ -0,0 +1,40 @@
+package event
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Type of account activity.
+type Type string
+
+const (
+	// TransactionEvent is recorded for every new transaction, its data are the transaction details.
+	TransactionEvent Type = "transaction"
+	// BalanceEvent is recorded for every balance change, its data hold the balance before and after the change.
+	BalanceEvent Type = "balance"
+)
+
+// Event is a recorded account activity. Ids increase in the order the events are recorded.
+type Event struct {
+	ID        int64
+	AccountID string
+	Type      Type
+	Data      json.RawMessage
+	CreatedAt time.Time
+}
+
+// Stream delivers the events of a subscription until the context of the subscription is done.
+type Stream struct {
+	// AccountID is the subscribed account, empty for the stream of all accounts.
+	AccountID string
+	Events    <-chan Event
+
+	err error
+}
+
+// Err returns the error that ended the stream, nil if the subscription was cancelled.
+// It is set once Events is closed.
+func (s *Stream) Err() error {
+	return s.err
+}
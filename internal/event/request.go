@@ -0,0 +1,8 @@
+package event
+
+// StreamRequest subscribes to the events of an account, of all accounts when AccountID is empty.
+// The stream resumes after LastEventID, or starts with the next recorded event when it is not set.
+type StreamRequest struct {
+	AccountID   string `validate:"omitempty,uuid"`
+	LastEventID *int64 `validate:"omitempty,gte=0"`
+}
@@ -0,0 +1,140 @@
+//go:generate mockgen -source=service.go -destination=./mock/service.go -package=mock
+package event
+
+import (
+	"context"
+	"time"
+
+	"github.com/softika/slogging"
+
+	"github.com/fmiskovic/cash-me-if-you-can/pkg/clock"
+	"github.com/fmiskovic/cash-me-if-you-can/pkg/errorx"
+)
+
+const (
+	// batchSize is the maximum number of events read at once.
+	batchSize = 100
+	// pollInterval is the time after which a stream reads new events even without a notification.
+	pollInterval = 30 * time.Second
+	// pruneBatchSize is the maximum number of events deleted at once.
+	pruneBatchSize = 10_000
+)
+
+type Repository interface {
+	// LastID returns the id of the latest event of the account, of all accounts if the account is empty,
+	// zero if there are no events. It fails with not found if the account does not exist.
+	LastID(ctx context.Context, accountID string) (int64, error)
+	// Events returns up to limit events recorded after the given event in order,
+	// of all accounts if the account is empty. Events are ordered so that no event
+	// is committed later before the returned ones, ids are not necessarily increasing.
+	Events(ctx context.Context, accountID string, afterID int64, limit int) ([]Event, error)
+	// Listen calls listening once it listens for new events and notify with the account id of every committed event.
+	// It blocks until the context is done or the listener fails.
+	Listen(ctx context.Context, listening func(), notify func(accountID string)) error
+	// Prune deletes up to limit events recorded before the given time and returns the number of deleted events.
+	Prune(ctx context.Context, before time.Time, limit int) (int64, error)
+}
+
+type Service struct {
+	repo  Repository
+	hub   *hub
+	clock clock.Clock
+}
+
+func NewService(repo Repository, clk clock.Clock) Service {
+	return Service{
+		repo:  repo,
+		hub:   newHub(repo),
+		clock: clk,
+	}
+}
+
+// Subscribe streams the events of the request until the context is done.
+func (s Service) Subscribe(ctx context.Context, req StreamRequest) (*Stream, error) {
+	last, err := s.repo.LastID(ctx, req.AccountID)
+	if err != nil {
+		logger := slogging.Slogger()
+		logger.ErrorContext(ctx, "failed to get last event id", "account_id", req.AccountID, "error", err)
+		return nil, err
+	}
+	if req.LastEventID != nil {
+		last = *req.LastEventID
+	}
+
+	events := make(chan Event)
+	stream := &Stream{
+		AccountID: req.AccountID,
+		Events:    events,
+	}
+
+	sub := s.hub.subscribe(req.AccountID)
+	go func() {
+		defer close(events)
+		defer s.hub.unsubscribe(sub)
+		stream.err = s.deliver(ctx, sub, last, events)
+	}()
+
+	return stream, nil
+}
+
+// deliver sends the events recorded after the last event id until the context is done.
+// New events are read when the subscriber is woken up, or after the poll interval in case a notification was missed.
+func (s Service) deliver(ctx context.Context, sub *subscriber, last int64, out chan<- Event) error {
+	poll := time.NewTicker(pollInterval)
+	defer poll.Stop()
+
+	for {
+		events, err := s.repo.Events(ctx, sub.accountID, last, batchSize)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			logger := slogging.Slogger()
+			logger.ErrorContext(ctx, "failed to read events", "account_id", sub.accountID, "after_id", last, "error", err)
+			return err
+		}
+
+		for _, e := range events {
+			select {
+			case out <- e:
+				last = e.ID
+			case <-ctx.Done():
+				return nil
+			}
+		}
+		if len(events) == batchSize {
+			continue
+		}
+
+		select {
+		case <-sub.wake:
+		case <-poll.C:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// Prune deletes the events recorded before the retained number of days and returns the number of deleted events.
+// Events are deleted in batches, so a large backlog does not hold one long-running delete.
+// A stream resuming after a pruned event continues with the oldest retained one.
+func (s Service) Prune(ctx context.Context, retainDays int) (int64, error) {
+	if retainDays < 1 {
+		return 0, errorx.NewErrorMsg("at least one day of events must be retained", errorx.ErrInvalidInput)
+	}
+
+	before := s.clock.Now().AddDate(0, 0, -retainDays)
+
+	var total int64
+	for {
+		n, err := s.repo.Prune(ctx, before, pruneBatchSize)
+		total += n
+		if err != nil {
+			slogging.Slogger().ErrorContext(ctx, "failed to prune events", "before", before, "error", err)
+			return total, err
+		}
+		if n < pruneBatchSize {
+			return total, nil
+		}
+	}
+}
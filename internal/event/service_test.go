@@ -0,0 +1,291 @@
+package event_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/fmiskovic/cash-me-if-you-can/internal/event"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/event/mock"
+	"github.com/fmiskovic/cash-me-if-you-can/pkg/clock"
+	"github.com/fmiskovic/cash-me-if-you-can/pkg/errorx"
+)
+
+const accountID = "a1b2c3d4-1111-2222-3333-444455556666"
+
+// listenUntilDone mocks a listener that never gets a notification.
+func listenUntilDone(repo *mock.MockRepository) {
+	repo.EXPECT().Listen(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, listening func(), notify func(string)) error {
+			listening()
+			<-ctx.Done()
+			return ctx.Err()
+		}).AnyTimes()
+}
+
+// receive reads n events of the stream, then cancels the subscription and waits for the stream to end.
+func receive(t *testing.T, cancel context.CancelFunc, stream *event.Stream, n int) []int64 {
+	t.Helper()
+
+	var ids []int64
+	for len(ids) < n {
+		select {
+		case e := <-stream.Events:
+			ids = append(ids, e.ID)
+		case <-time.After(time.Second):
+			t.Fatalf("received %d of %d events", len(ids), n)
+		}
+	}
+
+	cancel()
+	for range stream.Events {
+	}
+	return ids
+}
+
+func TestSubscribe(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+
+	lastEventID := int64(2)
+
+	tests := []struct {
+		name    string
+		req     event.StreamRequest
+		mockFn  func(*mock.MockRepository)
+		want    []int64
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name: "new events of the account",
+			req:  event.StreamRequest{AccountID: accountID},
+			mockFn: func(repo *mock.MockRepository) {
+				repo.EXPECT().LastID(gomock.Any(), accountID).Return(int64(5), nil)
+				repo.EXPECT().Events(gomock.Any(), accountID, int64(5), 100).
+					Return([]event.Event{{ID: 6, AccountID: accountID, Type: event.TransactionEvent}}, nil)
+				repo.EXPECT().Events(gomock.Any(), accountID, int64(6), 100).Return(nil, nil).AnyTimes()
+				listenUntilDone(repo)
+			},
+			want:    []int64{6},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "resume after the last event id",
+			req:  event.StreamRequest{AccountID: accountID, LastEventID: &lastEventID},
+			mockFn: func(repo *mock.MockRepository) {
+				repo.EXPECT().LastID(gomock.Any(), accountID).Return(int64(5), nil)
+				repo.EXPECT().Events(gomock.Any(), accountID, int64(2), 100).
+					Return([]event.Event{{ID: 3}, {ID: 5}}, nil)
+				repo.EXPECT().Events(gomock.Any(), accountID, int64(5), 100).Return(nil, nil).AnyTimes()
+				listenUntilDone(repo)
+			},
+			want:    []int64{3, 5},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "all accounts",
+			req:  event.StreamRequest{},
+			mockFn: func(repo *mock.MockRepository) {
+				repo.EXPECT().LastID(gomock.Any(), "").Return(int64(0), nil)
+				repo.EXPECT().Events(gomock.Any(), "", int64(0), 100).
+					Return([]event.Event{{ID: 1, AccountID: "1"}, {ID: 2, AccountID: "2"}}, nil)
+				repo.EXPECT().Events(gomock.Any(), "", int64(2), 100).Return(nil, nil).AnyTimes()
+				listenUntilDone(repo)
+			},
+			want:    []int64{1, 2},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "account not found",
+			req:  event.StreamRequest{AccountID: accountID},
+			mockFn: func(repo *mock.MockRepository) {
+				repo.EXPECT().LastID(gomock.Any(), accountID).
+					Return(int64(0), errorx.NewErrorMsg("account not found", errorx.ErrNotFound))
+			},
+			wantErr: assert.Error,
+		},
+	}
+
+	for _, tc := range tests {
+		tt := tc
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := mock.NewMockRepository(ctrl)
+			tt.mockFn(repo)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			s := event.NewService(repo, clock.System())
+			stream, err := s.Subscribe(ctx, tt.req)
+			tt.wantErr(t, err)
+			if err != nil {
+				return
+			}
+
+			assert.Equal(t, tt.want, receive(t, cancel, stream, len(tt.want)))
+			assert.NoError(t, stream.Err())
+		})
+	}
+}
+
+func TestSubscribeNotification(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	repo := mock.NewMockRepository(ctrl)
+
+	var (
+		mu       sync.Mutex
+		recorded []event.Event
+	)
+	notified := make(chan string)
+
+	repo.EXPECT().LastID(gomock.Any(), accountID).Return(int64(0), nil)
+	repo.EXPECT().Events(gomock.Any(), accountID, gomock.Any(), 100).
+		DoAndReturn(func(_ context.Context, _ string, afterID int64, _ int) ([]event.Event, error) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			var events []event.Event
+			for _, e := range recorded {
+				if e.ID > afterID {
+					events = append(events, e)
+				}
+			}
+			return events, nil
+		}).AnyTimes()
+	repo.EXPECT().Listen(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, listening func(), notify func(string)) error {
+			listening()
+			for {
+				select {
+				case id := <-notified:
+					notify(id)
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+		}).AnyTimes()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s := event.NewService(repo, clock.System())
+	stream, err := s.Subscribe(ctx, event.StreamRequest{AccountID: accountID})
+	require.NoError(t, err)
+
+	// the event is read on notification, long before the stream polls again
+	mu.Lock()
+	recorded = append(recorded, event.Event{ID: 1, AccountID: accountID, Type: event.BalanceEvent})
+	mu.Unlock()
+	notified <- accountID
+
+	assert.Equal(t, []int64{1}, receive(t, cancel, stream, 1))
+	assert.NoError(t, stream.Err())
+}
+
+func TestSubscribeEventsError(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	repo := mock.NewMockRepository(ctrl)
+
+	repo.EXPECT().LastID(gomock.Any(), accountID).Return(int64(0), nil)
+	repo.EXPECT().Events(gomock.Any(), accountID, int64(0), 100).Return(nil, assert.AnError)
+	listenUntilDone(repo)
+
+	s := event.NewService(repo, clock.System())
+	stream, err := s.Subscribe(context.Background(), event.StreamRequest{AccountID: accountID})
+	require.NoError(t, err)
+
+	select {
+	case _, ok := <-stream.Events:
+		assert.False(t, ok)
+	case <-time.After(time.Second):
+		t.Fatal("stream did not end")
+	}
+	assert.ErrorIs(t, stream.Err(), assert.AnError)
+}
+
+func TestPrune(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+
+	now := time.Date(2024, time.October, 1, 12, 0, 0, 0, time.UTC)
+	before := now.AddDate(0, 0, -30)
+
+	tests := []struct {
+		name       string
+		retainDays int
+		mockFn     func(*mock.MockRepository)
+		want       int64
+		wantErr    assert.ErrorAssertionFunc
+	}{
+		{
+			name:       "deletes in batches until a batch is not full",
+			retainDays: 30,
+			mockFn: func(repo *mock.MockRepository) {
+				gomock.InOrder(
+					repo.EXPECT().Prune(ctx, before, 10_000).Return(int64(10_000), nil),
+					repo.EXPECT().Prune(ctx, before, 10_000).Return(int64(5), nil),
+				)
+			},
+			want:    10_005,
+			wantErr: assert.NoError,
+		},
+		{
+			name:       "nothing to delete",
+			retainDays: 30,
+			mockFn: func(repo *mock.MockRepository) {
+				repo.EXPECT().Prune(ctx, before, 10_000).Return(int64(0), nil)
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name:       "at least a day is retained",
+			retainDays: 0,
+			mockFn:     func(*mock.MockRepository) {},
+			wantErr: func(t assert.TestingT, err error, _ ...any) bool {
+				var errx *errorx.Error
+				return assert.ErrorAs(t, err, &errx) && assert.Equal(t, errorx.ErrInvalidInput, errx.Type)
+			},
+		},
+		{
+			name:       "repository error",
+			retainDays: 30,
+			mockFn: func(repo *mock.MockRepository) {
+				gomock.InOrder(
+					repo.EXPECT().Prune(ctx, before, 10_000).Return(int64(10_000), nil),
+					repo.EXPECT().Prune(ctx, before, 10_000).Return(int64(0), assert.AnError),
+				)
+			},
+			want:    10_000,
+			wantErr: assert.Error,
+		},
+	}
+
+	for _, tc := range tests {
+		tt := tc
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := mock.NewMockRepository(ctrl)
+			tt.mockFn(repo)
+
+			s := event.NewService(repo, clock.Fixed(now))
+			got, err := s.Prune(ctx, tt.retainDays)
+			tt.wantErr(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
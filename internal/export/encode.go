@@ -0,0 +1,111 @@
+package export
+
+import (
+	"compress/gzip"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/fmiskovic/cash-me-if-you-can/internal/transaction"
+)
+
+// Write encodes the export in its format, transactions are written as they are read.
+func Write(w io.Writer, e *Export) error {
+	if !e.Gzip {
+		return write(w, e)
+	}
+
+	gw := gzip.NewWriter(w)
+	if err := write(gw, e); err != nil {
+		return err
+	}
+	return gw.Close()
+}
+
+func write(w io.Writer, e *Export) error {
+	if e.Format == CSV {
+		return writeCSV(w, e)
+	}
+	return writeNDJSON(w, e)
+}
+
+var csvHeader = []string{
+	"transaction_id", "account_id", "type", "amount", "description", "external_reference",
+	"counterparty", "metadata", "timestamp", "created_at", "updated_at",
+}
+
+// writeCSV writes one record per transaction, metadata is written as a JSON object.
+func writeCSV(w io.Writer, e *Export) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write(csvHeader)
+
+	err := e.Transactions(func(t transaction.Transaction) error {
+		var metadata string
+		if len(t.Metadata) > 0 {
+			b, err := json.Marshal(t.Metadata)
+			if err != nil {
+				return err
+			}
+			metadata = string(b)
+		}
+
+		return cw.Write([]string{
+			t.ID,
+			t.AccountID,
+			string(t.Type),
+			strconv.FormatFloat(t.Amount, 'f', -1, 64),
+			t.Description,
+			t.ExternalReference,
+			t.Counterparty,
+			metadata,
+			formatTime(t.Timestamp),
+			formatTime(t.CreatedAt),
+			formatTime(t.UpdatedAt),
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+type jsonTransaction struct {
+	TransactionId     string         `json:"transaction_id"`
+	AccountId         string         `json:"account_id"`
+	Type              string         `json:"type"`
+	Amount            float64        `json:"amount"`
+	Description       string         `json:"description,omitempty"`
+	ExternalReference string         `json:"external_reference,omitempty"`
+	Counterparty      string         `json:"counterparty,omitempty"`
+	Metadata          map[string]any `json:"metadata,omitempty"`
+	Timestamp         time.Time      `json:"timestamp"`
+	CreatedAt         time.Time      `json:"created_at"`
+	UpdatedAt         time.Time      `json:"updated_at"`
+}
+
+func writeNDJSON(w io.Writer, e *Export) error {
+	enc := json.NewEncoder(w)
+	return e.Transactions(func(t transaction.Transaction) error {
+		return enc.Encode(jsonTransaction{
+			TransactionId:     t.ID,
+			AccountId:         t.AccountID,
+			Type:              string(t.Type),
+			Amount:            t.Amount,
+			Description:       t.Description,
+			ExternalReference: t.ExternalReference,
+			Counterparty:      t.Counterparty,
+			Metadata:          t.Metadata,
+			Timestamp:         t.Timestamp,
+			CreatedAt:         t.CreatedAt,
+			UpdatedAt:         t.UpdatedAt,
+		})
+	})
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
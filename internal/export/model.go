@@ -0,0 +1,54 @@
+package export
+
+import (
+	"time"
+
+	"github.com/fmiskovic/cash-me-if-you-can/internal/transaction"
+)
+
+type Format string
+
+const (
+	CSV Format = "csv"
+	// NDJSON writes one JSON object per line.
+	NDJSON Format = "ndjson"
+)
+
+// ContentType returns the media type of the format.
+func (f Format) ContentType() string {
+	if f == CSV {
+		return "text/csv"
+	}
+	return "application/x-ndjson"
+}
+
+// Extension returns the file name extension of the format.
+func (f Format) Extension() string {
+	if f == CSV {
+		return "csv"
+	}
+	return "ndjson"
+}
+
+// Filter narrows down the exported transactions, zero values mean the filter is not applied.
+// The creation time range is [From, To).
+type Filter struct {
+	AccountID string
+	From      *time.Time
+	To        *time.Time
+	Type      transaction.Type
+}
+
+// Export of the transactions matching the filter.
+type Export struct {
+	Format Format
+	Filter Filter
+	// Gzip compresses the written export.
+	Gzip bool
+
+	// Transactions streams the matching transactions in creation order to fn,
+	// it stops at and returns the first error.
+	Transactions func(fn func(transaction.Transaction) error) error
+
+	CreatedAt time.Time
+}
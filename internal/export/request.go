@@ -0,0 +1,16 @@
+package export
+
+import (
+	"time"
+
+	"github.com/fmiskovic/cash-me-if-you-can/internal/transaction"
+)
+
+type Request struct {
+	AccountID string           `validate:"omitempty,uuid"`
+	From      *time.Time       `validate:"omitempty"`
+	To        *time.Time       `validate:"omitempty"`
+	Type      transaction.Type `validate:"omitempty,oneof=deposit withdrawal interest fee"`
+	Format    Format           `validate:"omitempty,oneof=csv ndjson"`
+	Gzip      bool
+}
@@ -0,0 +1,55 @@
+//go:generate mockgen -source=service.go -destination=./mock/service.go -package=mock
+package export
+
+import (
+	"context"
+
+	"github.com/fmiskovic/cash-me-if-you-can/internal/transaction"
+	"github.com/fmiskovic/cash-me-if-you-can/pkg/clock"
+	"github.com/fmiskovic/cash-me-if-you-can/pkg/errorx"
+)
+
+type Repository interface {
+	// Transactions streams the transactions matching the filter in creation order to fn.
+	Transactions(ctx context.Context, filter Filter, fn func(transaction.Transaction) error) error
+}
+
+type Service struct {
+	repo  Repository
+	clock clock.Clock
+}
+
+func NewService(repo Repository, clk clock.Clock) Service {
+	return Service{
+		repo:  repo,
+		clock: clk,
+	}
+}
+
+// Export returns the export of the requested transactions.
+// The transactions are not loaded upfront but read from the repository while the export is written.
+func (s Service) Export(ctx context.Context, req Request) (*Export, error) {
+	if req.From != nil && req.To != nil && !req.From.Before(*req.To) {
+		return nil, errorx.NewErrorMsg("export period must start before it ends", errorx.ErrInvalidInput)
+	}
+
+	e := &Export{
+		Format: req.Format,
+		Filter: Filter{
+			AccountID: req.AccountID,
+			From:      req.From,
+			To:        req.To,
+			Type:      req.Type,
+		},
+		Gzip:      req.Gzip,
+		CreatedAt: s.clock.Now(),
+	}
+	if e.Format == "" {
+		e.Format = NDJSON
+	}
+	e.Transactions = func(fn func(transaction.Transaction) error) error {
+		return s.repo.Transactions(ctx, e.Filter, fn)
+	}
+
+	return e, nil
+}
@@ -0,0 +1,180 @@
+package export_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/fmiskovic/cash-me-if-you-can/internal/export"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/export/mock"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/transaction"
+	"github.com/fmiskovic/cash-me-if-you-can/pkg/clock"
+	"github.com/fmiskovic/cash-me-if-you-can/pkg/errorx"
+)
+
+var (
+	from = time.Date(2024, time.August, 1, 0, 0, 0, 0, time.UTC)
+	to   = time.Date(2024, time.September, 1, 0, 0, 0, 0, time.UTC)
+	now  = time.Date(2024, time.October, 1, 12, 0, 0, 0, time.UTC)
+)
+
+var transactions = []transaction.Transaction{
+	{
+		ID: "t1", AccountID: "a1", Type: transaction.Deposit, Amount: 100,
+		Timestamp: from, CreatedAt: from, UpdatedAt: from,
+		Description: "salary", ExternalReference: "ref-1", Metadata: map[string]any{"source": "payroll"},
+	},
+	{
+		ID: "t2", AccountID: "a1", Type: transaction.Withdrawal, Amount: 30.5,
+		Timestamp: from.Add(time.Hour), CreatedAt: from.Add(time.Hour), UpdatedAt: from.Add(time.Hour),
+	},
+}
+
+func testExport(format export.Format, gz bool) *export.Export {
+	return &export.Export{
+		Format:    format,
+		Gzip:      gz,
+		CreatedAt: now,
+		Transactions: func(fn func(transaction.Transaction) error) error {
+			for _, t := range transactions {
+				if err := fn(t); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
+
+func TestExport(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+
+	tests := []struct {
+		name       string
+		req        export.Request
+		wantFilter export.Filter
+		wantFormat export.Format
+		wantErr    bool
+	}{
+		{
+			name:       "all transactions as ndjson by default",
+			req:        export.Request{},
+			wantFormat: export.NDJSON,
+		},
+		{
+			name: "filtered csv",
+			req: export.Request{
+				AccountID: "a1", From: &from, To: &to, Type: transaction.Fee, Format: export.CSV,
+			},
+			wantFilter: export.Filter{AccountID: "a1", From: &from, To: &to, Type: transaction.Fee},
+			wantFormat: export.CSV,
+		},
+		{
+			name:    "period ends before it starts",
+			req:     export.Request{From: &to, To: &from},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		tt := tc
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := mock.NewMockRepository(ctrl)
+			s := export.NewService(repo, clock.Fixed(now))
+
+			got, err := s.Export(ctx, tt.req)
+			if tt.wantErr {
+				var errx *errorx.Error
+				require.ErrorAs(t, err, &errx)
+				assert.Equal(t, errorx.ErrInvalidInput, errx.Type)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantFormat, got.Format)
+			assert.Equal(t, tt.wantFilter, got.Filter)
+			assert.Equal(t, now, got.CreatedAt)
+
+			// the transactions are read only when the export is written
+			repo.EXPECT().Transactions(ctx, tt.wantFilter, gomock.Any()).Return(nil)
+			assert.NoError(t, got.Transactions(func(transaction.Transaction) error { return nil }))
+		})
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	require.NoError(t, export.Write(&buf, testExport(export.CSV, false)))
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+
+	assert.Equal(t, "transaction_id", records[0][0])
+	assert.Equal(t, []string{
+		"t1", "a1", "deposit", "100", "salary", "ref-1", "", `{"source":"payroll"}`,
+		"2024-08-01T00:00:00Z", "2024-08-01T00:00:00Z", "2024-08-01T00:00:00Z",
+	}, records[1])
+	assert.Equal(t, "30.5", records[2][3])
+	assert.Empty(t, records[2][7])
+}
+
+func TestWriteNDJSON(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	require.NoError(t, export.Write(&buf, testExport(export.NDJSON, false)))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+
+	var got map[string]any
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &got))
+	assert.Equal(t, "t1", got["transaction_id"])
+	assert.Equal(t, 100.0, got["amount"])
+	assert.Equal(t, map[string]any{"source": "payroll"}, got["metadata"])
+
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &got))
+	assert.Equal(t, "withdrawal", got["type"])
+}
+
+func TestWriteGzip(t *testing.T) {
+	t.Parallel()
+
+	var plain, compressed bytes.Buffer
+	require.NoError(t, export.Write(&plain, testExport(export.NDJSON, false)))
+	require.NoError(t, export.Write(&compressed, testExport(export.NDJSON, true)))
+
+	r, err := gzip.NewReader(&compressed)
+	require.NoError(t, err)
+	got, err := io.ReadAll(r)
+	require.NoError(t, err)
+	assert.Equal(t, plain.String(), string(got))
+}
+
+func TestWriteStopsOnTransactionsError(t *testing.T) {
+	t.Parallel()
+
+	stop := errors.New("stop")
+	e := testExport(export.CSV, false)
+	e.Transactions = func(func(transaction.Transaction) error) error { return stop }
+
+	assert.ErrorIs(t, export.Write(io.Discard, e), stop)
+}
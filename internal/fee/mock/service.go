@@ -0,0 +1,56 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=service.go -destination=./mock/service.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	fee "github.com/fmiskovic/cash-me-if-you-can/internal/fee"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRepository is a mock of Repository interface.
+type MockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryMockRecorder
+}
+
+// MockRepositoryMockRecorder is the mock recorder for MockRepository.
+type MockRepositoryMockRecorder struct {
+	mock *MockRepository
+}
+
+// NewMockRepository creates a new mock instance.
+func NewMockRepository(ctrl *gomock.Controller) *MockRepository {
+	mock := &MockRepository{ctrl: ctrl}
+	mock.recorder = &MockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Rule mocks base method.
+func (m *MockRepository) Rule(ctx context.Context, accountID string, op fee.Operation) (*fee.Rule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rule", ctx, accountID, op)
+	ret0, _ := ret[0].(*fee.Rule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Rule indicates an expected call of Rule.
+func (mr *MockRepositoryMockRecorder) Rule(ctx, accountID, op any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rule", reflect.TypeOf((*MockRepository)(nil).Rule), ctx, accountID, op)
+}
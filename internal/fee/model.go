@@ -0,0 +1,81 @@
+package fee
+
+import "math"
+
+// Operation is the kind of money movement a fee is charged for.
+type Operation string
+
+const (
+	Deposit    Operation = "deposit"
+	Withdrawal Operation = "withdrawal"
+	Transfer   Operation = "transfer"
+)
+
+type Kind string
+
+const (
+	// Flat charges a fixed amount.
+	Flat Kind = "flat"
+	// Percent charges a share of the amount.
+	Percent Kind = "percent"
+	// Tiered charges the flat amount and the share of the tier the amount falls into.
+	Tiered Kind = "tiered"
+)
+
+// Tier applies to amounts up to and including UpTo, the last tier has no upper bound.
+type Tier struct {
+	UpTo   *float64 `json:"up_to,omitempty"`
+	Amount float64  `json:"amount,omitempty"`
+	Rate   float64  `json:"rate,omitempty"`
+}
+
+// Rule prices an operation for accounts of a product.
+type Rule struct {
+	ID        string
+	Product   string
+	Operation Operation
+	Kind      Kind
+	Amount    float64 // flat amount
+	Rate      float64 // share of the amount, e.g. 0.01 for 1%
+	Tiers     []Tier  // sorted by UpTo, used by tiered rules
+
+	// Min and Max optionally cap the calculated fee.
+	Min *float64
+	Max *float64
+
+	// RevenueAccountID is the house account the fees are posted to.
+	RevenueAccountID string
+}
+
+// Calculate returns the fee for the given amount, rounded to cents.
+func (r *Rule) Calculate(amount float64) float64 {
+	var fee float64
+	switch r.Kind {
+	case Flat:
+		fee = r.Amount
+	case Percent:
+		fee = amount * r.Rate
+	case Tiered:
+		for _, t := range r.Tiers {
+			if t.UpTo == nil || amount <= *t.UpTo {
+				fee = t.Amount + amount*t.Rate
+				break
+			}
+		}
+	}
+
+	if r.Min != nil {
+		fee = max(fee, *r.Min)
+	}
+	if r.Max != nil {
+		fee = min(fee, *r.Max)
+	}
+
+	return math.Round(max(fee, 0)*100) / 100
+}
+
+// Charge is a calculated fee, ready to be posted.
+type Charge struct {
+	Amount           float64
+	RevenueAccountID string
+}
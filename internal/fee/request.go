@@ -0,0 +1,7 @@
+package fee
+
+type QuoteRequest struct {
+	AccountID string    `validate:"required,uuid"`
+	Operation Operation `validate:"required,oneof=deposit withdrawal transfer"`
+	Amount    float64   `validate:"required,gt=0"`
+}
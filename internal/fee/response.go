@@ -0,0 +1,11 @@
+package fee
+
+type Quote struct {
+	AccountId string  `json:"account_id"`
+	Operation string  `json:"operation"`
+	Amount    float64 `json:"amount"`
+	Fee       float64 `json:"fee"`
+	// Total is the amount debited from the account including the fee,
+	// for deposits it is the amount credited after the fee.
+	Total float64 `json:"total"`
+}
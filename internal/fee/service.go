@@ -0,0 +1,48 @@
+//go:generate mockgen -source=service.go -destination=./mock/service.go -package=mock
+package fee
+
+import (
+	"context"
+
+	"github.com/softika/slogging"
+)
+
+type Repository interface {
+	// Rule returns the fee rule of the account's product for the operation, nil if the operation is free.
+	Rule(ctx context.Context, accountID string, op Operation) (*Rule, error)
+}
+
+type Service struct {
+	repo Repository
+}
+
+func NewService(repo Repository) Service {
+	return Service{repo: repo}
+}
+
+// Quote previews the fee of an operation without moving money.
+func (s Service) Quote(ctx context.Context, req QuoteRequest) (*Quote, error) {
+	rule, err := s.repo.Rule(ctx, req.AccountID, req.Operation)
+	if err != nil {
+		logger := slogging.Slogger()
+		logger.ErrorContext(ctx, "failed to get fee rule", "account_id", req.AccountID, "error", err)
+		return nil, err
+	}
+
+	q := &Quote{
+		AccountId: req.AccountID,
+		Operation: string(req.Operation),
+		Amount:    req.Amount,
+	}
+	if rule != nil {
+		q.Fee = rule.Calculate(req.Amount)
+	}
+
+	if req.Operation == Deposit {
+		q.Total = req.Amount - q.Fee
+	} else {
+		q.Total = req.Amount + q.Fee
+	}
+
+	return q, nil
+}
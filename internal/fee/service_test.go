@@ -0,0 +1,119 @@
+package fee_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/fmiskovic/cash-me-if-you-can/internal/fee"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/fee/mock"
+)
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestRuleCalculate(t *testing.T) {
+	t.Parallel()
+
+	tiers := []fee.Tier{
+		{UpTo: ptr(100.0), Amount: 1},
+		{UpTo: ptr(1000.0), Amount: 0.5, Rate: 0.01},
+		{Rate: 0.005},
+	}
+
+	tests := []struct {
+		name   string
+		rule   fee.Rule
+		amount float64
+		want   float64
+	}{
+		{name: "flat", rule: fee.Rule{Kind: fee.Flat, Amount: 2.5}, amount: 1000, want: 2.5},
+		{name: "percent", rule: fee.Rule{Kind: fee.Percent, Rate: 0.015}, amount: 200, want: 3},
+		{name: "percent rounded to cents", rule: fee.Rule{Kind: fee.Percent, Rate: 0.01}, amount: 12.345, want: 0.12},
+		{name: "percent with min", rule: fee.Rule{Kind: fee.Percent, Rate: 0.01, Min: ptr(1.0)}, amount: 10, want: 1},
+		{name: "percent with max", rule: fee.Rule{Kind: fee.Percent, Rate: 0.01, Max: ptr(5.0)}, amount: 10000, want: 5},
+		{name: "first tier", rule: fee.Rule{Kind: fee.Tiered, Tiers: tiers}, amount: 100, want: 1},
+		{name: "second tier", rule: fee.Rule{Kind: fee.Tiered, Tiers: tiers}, amount: 500, want: 5.5},
+		{name: "open ended tier", rule: fee.Rule{Kind: fee.Tiered, Tiers: tiers}, amount: 10000, want: 50},
+		{name: "capped tier", rule: fee.Rule{Kind: fee.Tiered, Tiers: tiers, Max: ptr(20.0)}, amount: 10000, want: 20},
+	}
+
+	for _, tc := range tests {
+		tt := tc
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.InDelta(t, tt.want, tt.rule.Calculate(tt.amount), 1e-9)
+		})
+	}
+}
+
+func TestQuote(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+
+	rule := &fee.Rule{Kind: fee.Flat, Amount: 1.5}
+
+	tests := []struct {
+		name    string
+		req     fee.QuoteRequest
+		mockFn  func(*mock.MockRepository)
+		want    *fee.Quote
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name: "transfer with fee",
+			req:  fee.QuoteRequest{AccountID: "1", Operation: fee.Transfer, Amount: 100},
+			mockFn: func(repo *mock.MockRepository) {
+				repo.EXPECT().Rule(ctx, "1", fee.Transfer).Return(rule, nil)
+			},
+			want:    &fee.Quote{AccountId: "1", Operation: "transfer", Amount: 100, Fee: 1.5, Total: 101.5},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "deposit with fee",
+			req:  fee.QuoteRequest{AccountID: "1", Operation: fee.Deposit, Amount: 100},
+			mockFn: func(repo *mock.MockRepository) {
+				repo.EXPECT().Rule(ctx, "1", fee.Deposit).Return(rule, nil)
+			},
+			want:    &fee.Quote{AccountId: "1", Operation: "deposit", Amount: 100, Fee: 1.5, Total: 98.5},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "free withdrawal",
+			req:  fee.QuoteRequest{AccountID: "1", Operation: fee.Withdrawal, Amount: 100},
+			mockFn: func(repo *mock.MockRepository) {
+				repo.EXPECT().Rule(ctx, "1", fee.Withdrawal).Return(nil, nil)
+			},
+			want:    &fee.Quote{AccountId: "1", Operation: "withdrawal", Amount: 100, Total: 100},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "repository error",
+			req:  fee.QuoteRequest{AccountID: "1", Operation: fee.Withdrawal, Amount: 100},
+			mockFn: func(repo *mock.MockRepository) {
+				repo.EXPECT().Rule(ctx, "1", fee.Withdrawal).Return(nil, assert.AnError)
+			},
+			wantErr: assert.Error,
+		},
+	}
+
+	for _, tc := range tests {
+		tt := tc
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := mock.NewMockRepository(ctrl)
+			tt.mockFn(repo)
+
+			s := fee.NewService(repo)
+			got, err := s.Quote(ctx, tt.req)
+			tt.wantErr(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
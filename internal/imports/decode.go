@@ -0,0 +1,160 @@
+package imports
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// maxLineSize limits the size of a single JSON line.
+const maxLineSize = 1 << 20
+
+// columns are the CSV columns of each kind, named like the JSON fields of the create requests.
+var columns = map[Kind][]string{
+	Accounts:     {"owner", "initial_balance", "currency", "product"},
+	Transactions: {"account_id", "type", "amount", "description", "external_reference", "counterparty", "metadata"},
+}
+
+// numericColumns hold numbers and jsonColumns JSON objects, all other columns are strings.
+var (
+	numericColumns = []string{"initial_balance", "amount"}
+	jsonColumns    = []string{"metadata"}
+)
+
+// rowError rejects a single row, the import continues with the next one.
+type rowError struct {
+	line int
+	err  error
+}
+
+func (e *rowError) Error() string {
+	return e.err.Error()
+}
+
+// decoder reads the rows of an imported file one at a time.
+type decoder interface {
+	// Next decodes the next row into v and returns its line number, io.EOF after the last row.
+	// A *rowError is returned for a row that cannot be decoded.
+	Next(v any) (int, error)
+}
+
+func newDecoder(kind Kind, format Format, r io.Reader) (decoder, error) {
+	switch format {
+	case CSV:
+		return newCSVDecoder(kind, r)
+	case JSONL:
+		s := bufio.NewScanner(r)
+		s.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+		return &jsonlDecoder{scanner: s}, nil
+	default:
+		return nil, fmt.Errorf("unsupported format %q", format)
+	}
+}
+
+type csvDecoder struct {
+	reader  *csv.Reader
+	columns []string
+}
+
+// newCSVDecoder reads the header, which must name known columns of the kind.
+func newCSVDecoder(kind Kind, r io.Reader) (*csvDecoder, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("file is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid header: %w", err)
+	}
+
+	d := &csvDecoder{reader: reader, columns: make([]string, len(header))}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !slices.Contains(columns[kind], name) {
+			return nil, fmt.Errorf("unknown column %q", name)
+		}
+		d.columns[i] = name
+	}
+	reader.FieldsPerRecord = len(header)
+
+	return d, nil
+}
+
+// Next converts the record to a JSON object, so it is decoded the same way as a JSON line.
+func (d *csvDecoder) Next(v any) (int, error) {
+	record, err := d.reader.Read()
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return parseErr.StartLine, &rowError{line: parseErr.StartLine, err: parseErr.Err}
+	}
+	if err != nil {
+		return 0, err
+	}
+	line, _ := d.reader.FieldPos(0)
+
+	object := make(map[string]json.RawMessage, len(record))
+	for i, value := range record {
+		name := d.columns[i]
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+
+		switch {
+		case slices.Contains(numericColumns, name):
+			if _, err = strconv.ParseFloat(value, 64); err != nil {
+				return line, &rowError{line: line, err: fmt.Errorf("invalid %s %q", name, value)}
+			}
+			object[name] = json.RawMessage(value)
+		case slices.Contains(jsonColumns, name):
+			if !json.Valid([]byte(value)) {
+				return line, &rowError{line: line, err: fmt.Errorf("invalid %s", name)}
+			}
+			object[name] = json.RawMessage(value)
+		default:
+			object[name], _ = json.Marshal(value)
+		}
+	}
+
+	b, err := json.Marshal(object)
+	if err != nil {
+		return line, &rowError{line: line, err: err}
+	}
+	if err = json.Unmarshal(b, v); err != nil {
+		return line, &rowError{line: line, err: err}
+	}
+	return line, nil
+}
+
+type jsonlDecoder struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+// Next skips blank lines.
+func (d *jsonlDecoder) Next(v any) (int, error) {
+	for d.scanner.Scan() {
+		d.line++
+		b := bytes.TrimSpace(d.scanner.Bytes())
+		if len(b) == 0 {
+			continue
+		}
+		if err := json.Unmarshal(b, v); err != nil {
+			return d.line, &rowError{line: d.line, err: fmt.Errorf("invalid json: %w", err)}
+		}
+		return d.line, nil
+	}
+	if err := d.scanner.Err(); err != nil {
+		return d.line + 1, fmt.Errorf("line %d: %w", d.line+1, err)
+	}
+	return d.line, io.EOF
+}
@@ -0,0 +1,71 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=service.go -destination=./mock/service.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	imports "github.com/fmiskovic/cash-me-if-you-can/internal/imports"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRepository is a mock of Repository interface.
+type MockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryMockRecorder
+}
+
+// MockRepositoryMockRecorder is the mock recorder for MockRepository.
+type MockRepositoryMockRecorder struct {
+	mock *MockRepository
+}
+
+// NewMockRepository creates a new mock instance.
+func NewMockRepository(ctrl *gomock.Controller) *MockRepository {
+	mock := &MockRepository{ctrl: ctrl}
+	mock.recorder = &MockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ImportAccounts mocks base method.
+func (m *MockRepository) ImportAccounts(ctx context.Context, dryRun bool, chunks func(func([]imports.AccountRow) error) error) ([]imports.Result, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportAccounts", ctx, dryRun, chunks)
+	ret0, _ := ret[0].([]imports.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportAccounts indicates an expected call of ImportAccounts.
+func (mr *MockRepositoryMockRecorder) ImportAccounts(ctx, dryRun, chunks any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportAccounts", reflect.TypeOf((*MockRepository)(nil).ImportAccounts), ctx, dryRun, chunks)
+}
+
+// ImportTransactions mocks base method.
+func (m *MockRepository) ImportTransactions(ctx context.Context, dryRun bool, accountIDs []string, chunks func(func([]imports.TransactionRow) error) error) ([]imports.Result, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportTransactions", ctx, dryRun, accountIDs, chunks)
+	ret0, _ := ret[0].([]imports.Result)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportTransactions indicates an expected call of ImportTransactions.
+func (mr *MockRepositoryMockRecorder) ImportTransactions(ctx, dryRun, accountIDs, chunks any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportTransactions", reflect.TypeOf((*MockRepository)(nil).ImportTransactions), ctx, dryRun, accountIDs, chunks)
+}
@@ -0,0 +1,94 @@
+package imports
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/fmiskovic/cash-me-if-you-can/internal/account"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/transaction"
+)
+
+// Kind of the imported records.
+type Kind string
+
+const (
+	Accounts     Kind = "accounts"
+	Transactions Kind = "transactions"
+)
+
+// Format of an imported file.
+type Format string
+
+const (
+	CSV Format = "csv"
+	// JSONL is a file of JSON objects, one per line.
+	JSONL Format = "jsonl"
+)
+
+type Status string
+
+const (
+	// Imported rows are stored.
+	Imported Status = "imported"
+	// Valid rows would be stored, they are reported by dry runs.
+	Valid Status = "valid"
+	// Rejected rows are skipped, the error tells why.
+	Rejected Status = "rejected"
+)
+
+// AccountRow is an account creation read from line Line of the imported file.
+type AccountRow struct {
+	Line int
+	account.CreateRequest
+}
+
+func (r *AccountRow) setLine(line int) {
+	r.Line = line
+}
+
+// TransactionRow is a deposit or withdrawal read from line Line of the imported file.
+type TransactionRow struct {
+	Line int
+	transaction.CreateRequest
+}
+
+func (r *TransactionRow) setLine(line int) {
+	r.Line = line
+}
+
+// Result is the outcome of a single row, ID is the id of the created account or transaction.
+type Result struct {
+	Line   int
+	Status Status
+	ID     string
+	Error  string
+}
+
+func rejectedRow(line int, err error) Result {
+	return Result{Line: line, Status: Rejected, Error: err.Error()}
+}
+
+// Report is the outcome of an import.
+type Report struct {
+	Kind    Kind
+	Format  Format
+	DryRun  bool
+	Results []Result
+}
+
+// Count returns the number of rows with the given status.
+func (r *Report) Count(status Status) int {
+	n := 0
+	for _, res := range r.Results {
+		if res.Status == status {
+			n++
+		}
+	}
+	return n
+}
+
+func sortResults(results []Result) {
+	slices.SortStableFunc(results, func(a, b Result) int {
+		return cmp.Compare(a.Line, b.Line)
+	})
+}
@@ -0,0 +1,11 @@
+package imports
+
+import "io"
+
+// ImportRequest streams the rows of File, nothing is stored by a dry run.
+type ImportRequest struct {
+	Kind   Kind      `validate:"required,oneof=accounts transactions"`
+	Format Format    `validate:"required,oneof=csv jsonl"`
+	File   io.Reader `validate:"required"`
+	DryRun bool
+}
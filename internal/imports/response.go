@@ -0,0 +1,43 @@
+package imports
+
+type ResultDetails struct {
+	Line   int    `json:"line"`
+	Status string `json:"status"`
+	Id     string `json:"id,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+type ReportDetails struct {
+	Kind     string          `json:"kind"`
+	Format   string          `json:"format"`
+	DryRun   bool            `json:"dry_run"`
+	Rows     int             `json:"rows"`
+	Imported int             `json:"imported"`
+	Valid    int             `json:"valid"`
+	Rejected int             `json:"rejected"`
+	Results  []ResultDetails `json:"results"`
+}
+
+func newReportDetails(r *Report) *ReportDetails {
+	details := &ReportDetails{
+		Kind:     string(r.Kind),
+		Format:   string(r.Format),
+		DryRun:   r.DryRun,
+		Rows:     len(r.Results),
+		Imported: r.Count(Imported),
+		Valid:    r.Count(Valid),
+		Rejected: r.Count(Rejected),
+		Results:  make([]ResultDetails, len(r.Results)),
+	}
+
+	for i, res := range r.Results {
+		details.Results[i] = ResultDetails{
+			Line:   res.Line,
+			Status: string(res.Status),
+			Id:     res.ID,
+			Error:  res.Error,
+		}
+	}
+
+	return details
+}
@@ -0,0 +1,220 @@
+//go:generate mockgen -source=service.go -destination=./mock/service.go -package=mock
+package imports
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/softika/slogging"
+
+	"github.com/fmiskovic/cash-me-if-you-can/internal/transaction"
+	"github.com/fmiskovic/cash-me-if-you-can/pkg/errorx"
+)
+
+// chunkSize is the number of valid rows handed to the repository at once.
+const chunkSize = 1000
+
+type Repository interface {
+	// ImportAccounts stores the accounts of all chunks inside a single database transaction, which is rolled back on a dry run.
+	// chunks calls fn with the valid rows of the file, chunk by chunk, until the file is read. A chunk is reused once fn returns.
+	// The results of the rows rejected by the repository, e.g. for an existing owner, are returned along with the stored ones.
+	ImportAccounts(ctx context.Context, dryRun bool, chunks func(fn func([]AccountRow) error) error) ([]Result, error)
+	// ImportTransactions stores the transactions of all chunks like ImportAccounts, booking them on the account balances.
+	// The accounts are the accounts of all valid rows, they are locked before the first chunk is read.
+	ImportTransactions(
+		ctx context.Context,
+		dryRun bool,
+		accountIDs []string,
+		chunks func(fn func([]TransactionRow) error) error,
+	) ([]Result, error)
+}
+
+type Service struct {
+	repo      Repository
+	validator *validator.Validate
+}
+
+func NewService(repo Repository) Service {
+	return Service{
+		repo:      repo,
+		validator: validator.New(),
+	}
+}
+
+// Import streams the rows of the file to the repository in chunks.
+// Every row is validated by the rules of the matching create request, invalid rows are rejected and reported
+// with the reason, while the valid ones are imported, or only checked on a dry run.
+func (s Service) Import(ctx context.Context, req ImportRequest) (*ReportDetails, error) {
+	logger := slogging.Slogger()
+
+	if _, ok := columns[req.Kind]; !ok {
+		return nil, errorx.NewError(fmt.Errorf("unsupported kind %q", req.Kind), errorx.ErrInvalidInput)
+	}
+	report := &Report{Kind: req.Kind, Format: req.Format, DryRun: req.DryRun}
+
+	var (
+		results []Result
+		err     error
+	)
+	switch req.Kind {
+	case Accounts:
+		var dec decoder
+		if dec, err = newDecoder(req.Kind, req.Format, req.File); err != nil {
+			return nil, errorx.NewError(err, errorx.ErrInvalidInput)
+		}
+		results, err = s.repo.ImportAccounts(ctx, req.DryRun, func(fn func([]AccountRow) error) error {
+			return readChunks(dec, &report.Results, s.validateAccount, fn)
+		})
+	case Transactions:
+		results, err = s.importTransactions(ctx, req, &report.Results)
+	}
+	if err != nil {
+		logger.ErrorContext(ctx, "failed to import", "kind", req.Kind, "error", err)
+		return nil, err
+	}
+
+	report.Results = append(report.Results, results...)
+	sortResults(report.Results)
+
+	return newReportDetails(report), nil
+}
+
+// importTransactions reads the file twice. The first pass collects the accounts of the valid rows, so the repository
+// locks all of them in a deterministic order before it books the rows of the second pass.
+func (s Service) importTransactions(ctx context.Context, req ImportRequest, rejected *[]Result) ([]Result, error) {
+	file, rewind, cleanup, err := rewindable(req.File)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	dec, err := newDecoder(req.Kind, req.Format, file)
+	if err != nil {
+		return nil, errorx.NewError(err, errorx.ErrInvalidInput)
+	}
+	var (
+		ids  []string
+		seen = make(map[string]bool)
+	)
+	// rows are rejected by the second pass
+	err = readChunks(dec, new([]Result), s.validateTransaction, func(rows []TransactionRow) error {
+		for _, row := range rows {
+			if !seen[row.AccountID] {
+				seen[row.AccountID] = true
+				ids = append(ids, row.AccountID)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if file, err = rewind(); err != nil {
+		return nil, err
+	}
+	if dec, err = newDecoder(req.Kind, req.Format, file); err != nil {
+		return nil, errorx.NewError(err, errorx.ErrInvalidInput)
+	}
+
+	return s.repo.ImportTransactions(ctx, req.DryRun, ids, func(fn func([]TransactionRow) error) error {
+		return readChunks(dec, rejected, s.validateTransaction, fn)
+	})
+}
+
+// rewindable returns a reader of the file and rewind, which returns a reader of the file from the start once
+// the first reader is read to the end. A file that can not seek is copied to a temporary file while it is read.
+func rewindable(file io.Reader) (io.Reader, func() (io.Reader, error), func(), error) {
+	if rs, ok := file.(io.ReadSeeker); ok {
+		start, err := rs.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		rewind := func() (io.Reader, error) {
+			_, err := rs.Seek(start, io.SeekStart)
+			return rs, err
+		}
+		return rs, rewind, func() {}, nil
+	}
+
+	tmp, err := os.CreateTemp("", "import-*")
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	rewind := func() (io.Reader, error) {
+		_, err := tmp.Seek(0, io.SeekStart)
+		return tmp, err
+	}
+	cleanup := func() {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+	}
+	return io.TeeReader(file, tmp), rewind, cleanup, nil
+}
+
+func (s Service) validateAccount(row *AccountRow) error {
+	if err := s.validator.Struct(row.CreateRequest); err != nil {
+		return err
+	}
+	if strings.TrimSpace(row.Owner) == "" {
+		return errors.New("owner must not be blank")
+	}
+	return nil
+}
+
+func (s Service) validateTransaction(row *TransactionRow) error {
+	if err := s.validator.Struct(row.CreateRequest); err != nil {
+		return err
+	}
+	if row.Amount <= 0 {
+		return errors.New("invalid amount")
+	}
+	return transaction.ValidateCreate(row.CreateRequest)
+}
+
+// readChunks decodes the rows of the file and passes the valid ones to fn in chunks of chunkSize,
+// the invalid rows are appended to rejected. A file that cannot be read any further fails the import.
+func readChunks[T any, R interface {
+	*T
+	setLine(int)
+}](dec decoder, rejected *[]Result, validate func(R) error, fn func([]T) error) error {
+	chunk := make([]T, 0, chunkSize)
+	for {
+		var row T
+		line, err := dec.Next(R(&row))
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		var rowErr *rowError
+		if errors.As(err, &rowErr) {
+			*rejected = append(*rejected, rejectedRow(line, err))
+			continue
+		}
+		if err != nil {
+			return errorx.NewError(err, errorx.ErrInvalidInput)
+		}
+
+		R(&row).setLine(line)
+		if err = validate(R(&row)); err != nil {
+			*rejected = append(*rejected, rejectedRow(line, err))
+			continue
+		}
+
+		if chunk = append(chunk, row); len(chunk) == chunkSize {
+			if err = fn(chunk); err != nil {
+				return err
+			}
+			chunk = chunk[:0]
+		}
+	}
+
+	if len(chunk) == 0 {
+		return nil
+	}
+	return fn(chunk)
+}
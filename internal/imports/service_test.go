@@ -0,0 +1,252 @@
+package imports_test
+
+import (
+	"context"
+	"io"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/fmiskovic/cash-me-if-you-can/internal/account"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/imports"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/imports/mock"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/transaction"
+)
+
+// importAll drains the chunks like the repository does and accepts every row.
+func importAll[T any](line func(T) int, got *[][]T) func(context.Context, bool, func(func([]T) error) error) ([]imports.Result, error) {
+	return func(_ context.Context, dryRun bool, chunks func(func([]T) error) error) ([]imports.Result, error) {
+		var results []imports.Result
+		err := chunks(func(rows []T) error {
+			*got = append(*got, append([]T(nil), rows...))
+			for _, row := range rows {
+				status := imports.Imported
+				if dryRun {
+					status = imports.Valid
+				}
+				results = append(results, imports.Result{Line: line(row), Status: status})
+			}
+			return nil
+		})
+		return results, err
+	}
+}
+
+func TestImportAccounts(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+
+	accountLine := func(row imports.AccountRow) int { return row.Line }
+
+	tests := []struct {
+		name     string
+		req      imports.ImportRequest
+		wantRows []imports.AccountRow
+		want     *imports.ReportDetails
+		wantErr  assert.ErrorAssertionFunc
+	}{
+		{
+			name: "csv",
+			req: imports.ImportRequest{
+				Kind:   imports.Accounts,
+				Format: imports.CSV,
+				File: strings.NewReader("owner,initial_balance,currency\n" +
+					"Alice Old,100.5,EUR\n" +
+					"B,10,EUR\n" +
+					"Carol Old,ten,EUR\n" +
+					"\"Dave\nOld\",20,\n"),
+			},
+			wantRows: []imports.AccountRow{
+				{Line: 2, CreateRequest: account.CreateRequest{Owner: "Alice Old", Balance: 100.5, Currency: "EUR"}},
+				{Line: 5, CreateRequest: account.CreateRequest{Owner: "Dave\nOld", Balance: 20}},
+			},
+			want: &imports.ReportDetails{
+				Kind:     "accounts",
+				Format:   "csv",
+				Rows:     4,
+				Imported: 2,
+				Rejected: 2,
+				Results: []imports.ResultDetails{
+					{Line: 2, Status: "imported"},
+					{Line: 3, Status: "rejected", Error: "Key: 'CreateRequest.Owner' Error:Field validation for 'Owner' failed on the 'min' tag"},
+					{Line: 4, Status: "rejected", Error: `invalid initial_balance "ten"`},
+					{Line: 5, Status: "imported"},
+				},
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "jsonl dry run",
+			req: imports.ImportRequest{
+				Kind:   imports.Accounts,
+				Format: imports.JSONL,
+				DryRun: true,
+				File: strings.NewReader(`{"owner": "Alice Old", "initial_balance": 100}` + "\n\n" +
+					`{"owner": "   ", "initial_balance": 100}` + "\n" +
+					`{"owner": "Bob Old", ` + "\n"),
+			},
+			wantRows: []imports.AccountRow{
+				{Line: 1, CreateRequest: account.CreateRequest{Owner: "Alice Old", Balance: 100}},
+			},
+			want: &imports.ReportDetails{
+				Kind:     "accounts",
+				Format:   "jsonl",
+				DryRun:   true,
+				Rows:     3,
+				Valid:    1,
+				Rejected: 2,
+				Results: []imports.ResultDetails{
+					{Line: 1, Status: "valid"},
+					{Line: 3, Status: "rejected", Error: "owner must not be blank"},
+					{Line: 4, Status: "rejected", Error: "invalid json: unexpected end of JSON input"},
+				},
+			},
+			wantErr: assert.NoError,
+		},
+	}
+
+	for _, tc := range tests {
+		tt := tc
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var got [][]imports.AccountRow
+			repo := mock.NewMockRepository(ctrl)
+			repo.EXPECT().ImportAccounts(ctx, tt.req.DryRun, gomock.Any()).DoAndReturn(importAll(accountLine, &got))
+
+			s := imports.NewService(repo)
+			res, err := s.Import(ctx, tt.req)
+			tt.wantErr(t, err)
+			assert.Equal(t, tt.want, res)
+			require.Len(t, got, 1)
+			assert.Equal(t, tt.wantRows, got[0])
+		})
+	}
+}
+
+func TestImportTransactions(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+
+	const accountId = "a1b2c3d4-1111-2222-3333-444455556666"
+	file := "account_id,type,amount,external_reference,metadata\n" +
+		accountId + ",deposit,100,ref-1,\"{\"\"source\"\": \"\"legacy\"\"}\"\n" +
+		accountId + ",withdrawal,-5,,\n" +
+		accountId + ",fee,5,,\n"
+
+	var got [][]imports.TransactionRow
+	repo := mock.NewMockRepository(ctrl)
+	lines := importAll(func(row imports.TransactionRow) int { return row.Line }, &got)
+	repo.EXPECT().ImportTransactions(ctx, false, []string{accountId}, gomock.Any()).
+		DoAndReturn(func(
+			ctx context.Context,
+			dryRun bool,
+			_ []string,
+			chunks func(func([]imports.TransactionRow) error) error,
+		) ([]imports.Result, error) {
+			return lines(ctx, dryRun, chunks)
+		}).Times(2)
+
+	// the accounts of the valid rows are collected before the rows are read again, files that can not seek are
+	// read again from a temporary copy
+	s := imports.NewService(repo)
+	res, err := s.Import(ctx, imports.ImportRequest{Kind: imports.Transactions, Format: imports.CSV, File: strings.NewReader(file)})
+	require.NoError(t, err)
+	again, err := s.Import(ctx, imports.ImportRequest{
+		Kind: imports.Transactions, Format: imports.CSV, File: io.MultiReader(strings.NewReader(file)),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, res, again)
+	got = got[:1]
+
+	require.Len(t, got, 1)
+	assert.Equal(t, []imports.TransactionRow{
+		{Line: 2, CreateRequest: transaction.CreateRequest{
+			AccountID:         accountId,
+			Type:              transaction.Deposit,
+			Amount:            100,
+			ExternalReference: "ref-1",
+			Metadata:          map[string]any{"source": "legacy"},
+		}},
+	}, got[0])
+
+	assert.Equal(t, 1, res.Imported)
+	assert.Equal(t, 2, res.Rejected)
+	assert.Equal(t, "invalid amount", res.Results[1].Error)
+	assert.Contains(t, res.Results[2].Error, "'oneof' tag")
+}
+
+func TestImportInChunks(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+
+	var b strings.Builder
+	for i := range 2500 {
+		fmt.Fprintf(&b, `{"owner": "owner %d", "initial_balance": 1}`+"\n", i)
+	}
+
+	var got [][]imports.AccountRow
+	repo := mock.NewMockRepository(ctrl)
+	repo.EXPECT().ImportAccounts(ctx, false, gomock.Any()).
+		DoAndReturn(importAll(func(row imports.AccountRow) int { return row.Line }, &got))
+
+	s := imports.NewService(repo)
+	res, err := s.Import(ctx, imports.ImportRequest{Kind: imports.Accounts, Format: imports.JSONL, File: strings.NewReader(b.String())})
+	require.NoError(t, err)
+
+	assert.Equal(t, 2500, res.Imported)
+	require.Len(t, got, 3)
+	assert.Len(t, got[0], 1000)
+	assert.Len(t, got[2], 500)
+	assert.Equal(t, 2500, got[2][499].Line)
+}
+
+func TestImportInvalidFile(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+
+	tests := []struct {
+		name string
+		req  imports.ImportRequest
+	}{
+		{
+			name: "unknown column",
+			req:  imports.ImportRequest{Kind: imports.Accounts, Format: imports.CSV, File: strings.NewReader("owner,iban\n")},
+		},
+		{
+			name: "empty csv",
+			req:  imports.ImportRequest{Kind: imports.Transactions, Format: imports.CSV, File: strings.NewReader("")},
+		},
+		{
+			name: "unsupported format",
+			req:  imports.ImportRequest{Kind: imports.Accounts, Format: "xlsx", File: strings.NewReader("")},
+		},
+		{
+			name: "unsupported kind",
+			req:  imports.ImportRequest{Kind: "products", Format: imports.CSV, File: strings.NewReader("code\n")},
+		},
+	}
+
+	for _, tc := range tests {
+		tt := tc
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := imports.NewService(mock.NewMockRepository(ctrl))
+			_, err := s.Import(ctx, tt.req)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,116 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=service.go -destination=./mock/service.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	interest "github.com/fmiskovic/cash-me-if-you-can/internal/interest"
+	transaction "github.com/fmiskovic/cash-me-if-you-can/internal/transaction"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRepository is a mock of Repository interface.
+type MockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryMockRecorder
+}
+
+// MockRepositoryMockRecorder is the mock recorder for MockRepository.
+type MockRepositoryMockRecorder struct {
+	mock *MockRepository
+}
+
+// NewMockRepository creates a new mock instance.
+func NewMockRepository(ctrl *gomock.Controller) *MockRepository {
+	mock := &MockRepository{ctrl: ctrl}
+	mock.recorder = &MockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Capitalize mocks base method.
+func (m *MockRepository) Capitalize(ctx context.Context, accountID string, date time.Time) (*transaction.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Capitalize", ctx, accountID, date)
+	ret0, _ := ret[0].(*transaction.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Capitalize indicates an expected call of Capitalize.
+func (mr *MockRepositoryMockRecorder) Capitalize(ctx, accountID, date any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Capitalize", reflect.TypeOf((*MockRepository)(nil).Capitalize), ctx, accountID, date)
+}
+
+// CompleteRun mocks base method.
+func (m *MockRepository) CompleteRun(arg0 context.Context, arg1 interest.Run) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteRun", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompleteRun indicates an expected call of CompleteRun.
+func (mr *MockRepositoryMockRecorder) CompleteRun(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteRun", reflect.TypeOf((*MockRepository)(nil).CompleteRun), arg0, arg1)
+}
+
+// LastRun mocks base method.
+func (m *MockRepository) LastRun(arg0 context.Context) (*time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LastRun", arg0)
+	ret0, _ := ret[0].(*time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LastRun indicates an expected call of LastRun.
+func (mr *MockRepositoryMockRecorder) LastRun(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LastRun", reflect.TypeOf((*MockRepository)(nil).LastRun), arg0)
+}
+
+// Positions mocks base method.
+func (m *MockRepository) Positions(arg0 context.Context, arg1 time.Time) ([]interest.Position, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Positions", arg0, arg1)
+	ret0, _ := ret[0].([]interest.Position)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Positions indicates an expected call of Positions.
+func (mr *MockRepositoryMockRecorder) Positions(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Positions", reflect.TypeOf((*MockRepository)(nil).Positions), arg0, arg1)
+}
+
+// SaveAccruals mocks base method.
+func (m *MockRepository) SaveAccruals(arg0 context.Context, arg1 []interest.Accrual) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveAccruals", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveAccruals indicates an expected call of SaveAccruals.
+func (mr *MockRepositoryMockRecorder) SaveAccruals(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveAccruals", reflect.TypeOf((*MockRepository)(nil).SaveAccruals), arg0, arg1)
+}
@@ -0,0 +1,117 @@
+package interest
+
+import "time"
+
+// DayCount is the convention used to convert an annual interest rate to a daily one.
+type DayCount string
+
+const (
+	Actual365    DayCount = "act/365"
+	Actual360    DayCount = "act/360"
+	ActualActual DayCount = "act/act"
+	Thirty360    DayCount = "30/360"
+)
+
+// DayFraction returns the fraction of a year the given day accrues interest for.
+func (d DayCount) DayFraction(date time.Time) float64 {
+	switch d {
+	case Actual360:
+		return 1.0 / 360
+	case ActualActual:
+		return 1.0 / float64(daysInYear(date.Year()))
+	case Thirty360:
+		return float64(days30360(date, date.AddDate(0, 0, 1))) / 360
+	default:
+		return 1.0 / 365
+	}
+}
+
+// days30360 counts the days between two dates as if every month had 30 days (30/360 European).
+func days30360(from, to time.Time) int {
+	y1, m1, d1 := from.Date()
+	y2, m2, d2 := to.Date()
+	d1, d2 = min(d1, 30), min(d2, 30)
+	return (y2-y1)*360 + (int(m2)-int(m1))*30 + (d2 - d1)
+}
+
+func daysInYear(year int) int {
+	return time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC).YearDay()
+}
+
+// Capitalization is the period after which accrued interest is posted to the account.
+type Capitalization string
+
+const (
+	Daily     Capitalization = "daily"
+	Monthly   Capitalization = "monthly"
+	Quarterly Capitalization = "quarterly"
+	Yearly    Capitalization = "yearly"
+)
+
+// IsDue reports whether the given accrual date closes a capitalization period.
+func (c Capitalization) IsDue(date time.Time) bool {
+	next := date.AddDate(0, 0, 1)
+	switch c {
+	case Daily:
+		return true
+	case Quarterly:
+		return next.Day() == 1 && (next.Month()-1)%3 == 0
+	case Yearly:
+		return next.YearDay() == 1
+	default:
+		return next.Day() == 1
+	}
+}
+
+// Product defines the interest of accounts it is assigned to.
+type Product struct {
+	Code           string
+	AnnualRate     float64 // e.g. 0.025 for 2.5%
+	DayCount       DayCount
+	Capitalization Capitalization
+}
+
+// Position is the end-of-day balance of an interest-bearing account.
+type Position struct {
+	AccountID string
+	Balance   float64
+	Product   Product
+}
+
+// Accrual is the interest earned by an account for a single day.
+type Accrual struct {
+	AccountID  string
+	Date       time.Time
+	Balance    float64
+	AnnualRate float64
+	DayCount   DayCount
+	Amount     float64
+}
+
+// Accrue computes the interest of the position for the given day, negative balances earn no interest.
+func Accrue(p Position, date time.Time) Accrual {
+	a := Accrual{
+		AccountID:  p.AccountID,
+		Date:       date,
+		Balance:    p.Balance,
+		AnnualRate: p.Product.AnnualRate,
+		DayCount:   p.Product.DayCount,
+	}
+	if p.Balance > 0 {
+		a.Amount = p.Balance * p.Product.AnnualRate * p.Product.DayCount.DayFraction(date)
+	}
+	return a
+}
+
+// Run is the outcome of accruing interest for a single date.
+type Run struct {
+	Date        time.Time
+	Accrued     int // number of accounts interest was accrued for
+	Capitalized int // number of interest transactions posted
+}
+
+// Date truncates the time to the start of its day in UTC.
+func Date(t time.Time) time.Time {
+	y, m, d := t.UTC().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
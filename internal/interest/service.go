@@ -0,0 +1,117 @@
+//go:generate mockgen -source=service.go -destination=./mock/service.go -package=mock
+package interest
+
+import (
+	"context"
+	"time"
+
+	"github.com/softika/slogging"
+
+	"github.com/fmiskovic/cash-me-if-you-can/internal/transaction"
+	"github.com/fmiskovic/cash-me-if-you-can/pkg/clock"
+	"github.com/fmiskovic/cash-me-if-you-can/pkg/errorx"
+)
+
+type Repository interface {
+	// LastRun returns the accrual date of the last completed run, nil if interest has never been accrued.
+	LastRun(context.Context) (*time.Time, error)
+	// Positions returns the end-of-day balances of interest-bearing accounts for the given date.
+	Positions(context.Context, time.Time) ([]Position, error)
+	// SaveAccruals stores the accruals, accruals already stored for the same account and date are kept.
+	SaveAccruals(context.Context, []Accrual) error
+	// Capitalize posts the uncapitalized interest accrued until the given date as an interest transaction.
+	// It returns nil when there is nothing to capitalize.
+	Capitalize(ctx context.Context, accountID string, date time.Time) (*transaction.Transaction, error)
+	// CompleteRun marks the accrual date as done.
+	CompleteRun(context.Context, Run) error
+}
+
+type Service struct {
+	repo  Repository
+	clock clock.Clock
+}
+
+func NewService(repo Repository, clk clock.Clock) Service {
+	return Service{
+		repo:  repo,
+		clock: clk,
+	}
+}
+
+// Accrue accrues the interest of the given day and capitalizes it at the end of a capitalization period.
+// Running it again for the same day does not accrue or capitalize the interest twice.
+func (s Service) Accrue(ctx context.Context, date time.Time) (*Run, error) {
+	logger := slogging.Slogger()
+
+	date = Date(date)
+	if !date.Before(Date(s.clock.Now())) {
+		return nil, errorx.NewErrorMsg("interest can only be accrued for past days", errorx.ErrInvalidInput)
+	}
+
+	positions, err := s.repo.Positions(ctx, date)
+	if err != nil {
+		logger.ErrorContext(ctx, "failed to get interest positions", "date", date, "error", err)
+		return nil, err
+	}
+
+	accruals := make([]Accrual, len(positions))
+	for i, p := range positions {
+		accruals[i] = Accrue(p, date)
+	}
+	if err = s.repo.SaveAccruals(ctx, accruals); err != nil {
+		logger.ErrorContext(ctx, "failed to save interest accruals", "date", date, "error", err)
+		return nil, err
+	}
+
+	run := Run{Date: date, Accrued: len(accruals)}
+	for _, p := range positions {
+		if !p.Product.Capitalization.IsDue(date) {
+			continue
+		}
+		t, err := s.repo.Capitalize(ctx, p.AccountID, date)
+		if err != nil {
+			// the run is not completed, so the capitalization is retried by the next run
+			logger.ErrorContext(ctx, "failed to capitalize interest", "account_id", p.AccountID, "date", date, "error", err)
+			return nil, err
+		}
+		if t != nil {
+			run.Capitalized++
+		}
+	}
+
+	if err = s.repo.CompleteRun(ctx, run); err != nil {
+		logger.ErrorContext(ctx, "failed to complete interest run", "date", date, "error", err)
+		return nil, err
+	}
+
+	return &run, nil
+}
+
+// AccrueDue accrues the interest of every day since the last run until yesterday.
+// The first run accrues yesterday only.
+func (s Service) AccrueDue(ctx context.Context) ([]Run, error) {
+	yesterday := Date(s.clock.Now()).AddDate(0, 0, -1)
+
+	last, err := s.repo.LastRun(ctx)
+	if err != nil {
+		logger := slogging.Slogger()
+		logger.ErrorContext(ctx, "failed to get last interest run", "error", err)
+		return nil, err
+	}
+
+	from := yesterday
+	if last != nil {
+		from = Date(*last).AddDate(0, 0, 1)
+	}
+
+	var runs []Run
+	for date := from; !date.After(yesterday); date = date.AddDate(0, 0, 1) {
+		run, err := s.Accrue(ctx, date)
+		if err != nil {
+			return runs, err
+		}
+		runs = append(runs, *run)
+	}
+
+	return runs, nil
+}
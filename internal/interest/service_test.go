@@ -0,0 +1,233 @@
+package interest_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/fmiskovic/cash-me-if-you-can/internal/interest"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/interest/mock"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/transaction"
+	"github.com/fmiskovic/cash-me-if-you-can/pkg/clock"
+)
+
+func date(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestDayFraction(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		dayCount interest.DayCount
+		date     time.Time
+		want     float64
+	}{
+		{name: "act/365", dayCount: interest.Actual365, date: date(2024, time.March, 1), want: 1.0 / 365},
+		{name: "act/360", dayCount: interest.Actual360, date: date(2024, time.March, 1), want: 1.0 / 360},
+		{name: "act/act in leap year", dayCount: interest.ActualActual, date: date(2024, time.March, 1), want: 1.0 / 366},
+		{name: "act/act in common year", dayCount: interest.ActualActual, date: date(2023, time.March, 1), want: 1.0 / 365},
+		{name: "30/360 regular day", dayCount: interest.Thirty360, date: date(2023, time.March, 1), want: 1.0 / 360},
+		{name: "30/360 on the 30th of a long month", dayCount: interest.Thirty360, date: date(2023, time.March, 30), want: 0},
+		{name: "30/360 on the end of february", dayCount: interest.Thirty360, date: date(2023, time.February, 28), want: 3.0 / 360},
+	}
+
+	for _, tc := range tests {
+		tt := tc
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.InDelta(t, tt.want, tt.dayCount.DayFraction(tt.date), 1e-15)
+		})
+	}
+}
+
+func TestThirty360AccruesThirtyDaysPerMonth(t *testing.T) {
+	t.Parallel()
+
+	for _, month := range []time.Month{time.January, time.February, time.April} {
+		total := 0.0
+		for d := date(2023, month, 1); d.Month() == month; d = d.AddDate(0, 0, 1) {
+			total += interest.Thirty360.DayFraction(d)
+		}
+		assert.InDelta(t, 30.0/360, total, 1e-12, month.String())
+	}
+}
+
+func TestCapitalizationIsDue(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		capitalization interest.Capitalization
+		date           time.Time
+		want           bool
+	}{
+		{name: "daily", capitalization: interest.Daily, date: date(2024, time.March, 14), want: true},
+		{name: "monthly on the last day", capitalization: interest.Monthly, date: date(2024, time.February, 29), want: true},
+		{name: "monthly in the middle", capitalization: interest.Monthly, date: date(2024, time.February, 28), want: false},
+		{name: "quarterly on the quarter end", capitalization: interest.Quarterly, date: date(2024, time.June, 30), want: true},
+		{name: "quarterly on a month end", capitalization: interest.Quarterly, date: date(2024, time.May, 31), want: false},
+		{name: "yearly on the year end", capitalization: interest.Yearly, date: date(2024, time.December, 31), want: true},
+		{name: "yearly on a month end", capitalization: interest.Yearly, date: date(2024, time.November, 30), want: false},
+	}
+
+	for _, tc := range tests {
+		tt := tc
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, tt.capitalization.IsDue(tt.date))
+		})
+	}
+}
+
+func TestAccrue(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+
+	savings := interest.Product{
+		Code:           "savings",
+		AnnualRate:     0.0365,
+		DayCount:       interest.Actual365,
+		Capitalization: interest.Monthly,
+	}
+	positions := []interest.Position{
+		{AccountID: "1", Balance: 1000, Product: savings},
+		{AccountID: "2", Balance: -10, Product: savings},
+	}
+
+	tests := []struct {
+		name    string
+		now     time.Time
+		date    time.Time
+		mockFn  func(*mock.MockRepository)
+		want    *interest.Run
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name: "accrue without capitalization",
+			now:  date(2024, time.March, 15).Add(time.Hour),
+			date: date(2024, time.March, 14).Add(15 * time.Hour),
+			mockFn: func(repo *mock.MockRepository) {
+				repo.EXPECT().Positions(ctx, date(2024, time.March, 14)).Return(positions, nil)
+				repo.EXPECT().SaveAccruals(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, accruals []interest.Accrual) error {
+					assert.Len(t, accruals, 2)
+					assert.InDelta(t, 0.1, accruals[0].Amount, 1e-12)
+					assert.Zero(t, accruals[1].Amount)
+					return nil
+				})
+				repo.EXPECT().CompleteRun(ctx, interest.Run{Date: date(2024, time.March, 14), Accrued: 2}).Return(nil)
+			},
+			want:    &interest.Run{Date: date(2024, time.March, 14), Accrued: 2},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "accrue and capitalize at the end of month",
+			now:  date(2024, time.April, 1),
+			date: date(2024, time.March, 31),
+			mockFn: func(repo *mock.MockRepository) {
+				repo.EXPECT().Positions(ctx, date(2024, time.March, 31)).Return(positions, nil)
+				repo.EXPECT().SaveAccruals(ctx, gomock.Any()).Return(nil)
+				repo.EXPECT().Capitalize(ctx, "1", date(2024, time.March, 31)).
+					Return(&transaction.Transaction{ID: "t1", Type: transaction.Interest}, nil)
+				// nothing to capitalize for the negative balance
+				repo.EXPECT().Capitalize(ctx, "2", date(2024, time.March, 31)).Return(nil, nil)
+				repo.EXPECT().CompleteRun(ctx, gomock.Any()).Return(nil)
+			},
+			want:    &interest.Run{Date: date(2024, time.March, 31), Accrued: 2, Capitalized: 1},
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "today cannot be accrued",
+			now:     date(2024, time.March, 15).Add(time.Hour),
+			date:    date(2024, time.March, 15),
+			mockFn:  func(repo *mock.MockRepository) {},
+			wantErr: assert.Error,
+		},
+		{
+			name: "failed capitalization does not complete the run",
+			now:  date(2024, time.April, 1),
+			date: date(2024, time.March, 31),
+			mockFn: func(repo *mock.MockRepository) {
+				repo.EXPECT().Positions(ctx, gomock.Any()).Return(positions[:1], nil)
+				repo.EXPECT().SaveAccruals(ctx, gomock.Any()).Return(nil)
+				repo.EXPECT().Capitalize(ctx, "1", gomock.Any()).Return(nil, assert.AnError)
+			},
+			wantErr: assert.Error,
+		},
+	}
+
+	for _, tc := range tests {
+		tt := tc
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := mock.NewMockRepository(ctrl)
+			tt.mockFn(repo)
+
+			s := interest.NewService(repo, clock.Fixed(tt.now))
+			got, err := s.Accrue(ctx, tt.date)
+			tt.wantErr(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestAccrueDue(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+
+	now := date(2024, time.March, 15).Add(10 * time.Hour)
+	last := date(2024, time.March, 12)
+
+	tests := []struct {
+		name      string
+		last      *time.Time
+		wantDates []time.Time
+	}{
+		{
+			name:      "first run accrues yesterday",
+			wantDates: []time.Time{date(2024, time.March, 14)},
+		},
+		{
+			name:      "catch up missed days",
+			last:      &last,
+			wantDates: []time.Time{date(2024, time.March, 13), date(2024, time.March, 14)},
+		},
+		{
+			name: "nothing due",
+			last: ptr(date(2024, time.March, 14)),
+		},
+	}
+
+	for _, tc := range tests {
+		tt := tc
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := mock.NewMockRepository(ctrl)
+			repo.EXPECT().LastRun(ctx).Return(tt.last, nil)
+			for _, d := range tt.wantDates {
+				repo.EXPECT().Positions(ctx, d).Return(nil, nil)
+				repo.EXPECT().SaveAccruals(ctx, gomock.Any()).Return(nil)
+				repo.EXPECT().CompleteRun(ctx, interest.Run{Date: d}).Return(nil)
+			}
+
+			s := interest.NewService(repo, clock.Fixed(now))
+			runs, err := s.AccrueDue(ctx)
+			assert.NoError(t, err)
+			assert.Len(t, runs, len(tt.wantDates))
+		})
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,86 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=service.go -destination=./mock/service.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+
+	ledger "github.com/fmiskovic/cash-me-if-you-can/internal/ledger"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRepository is a mock of Repository interface.
+type MockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryMockRecorder
+}
+
+// MockRepositoryMockRecorder is the mock recorder for MockRepository.
+type MockRepositoryMockRecorder struct {
+	mock *MockRepository
+}
+
+// NewMockRepository creates a new mock instance.
+func NewMockRepository(ctrl *gomock.Controller) *MockRepository {
+	mock := &MockRepository{ctrl: ctrl}
+	mock.recorder = &MockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CountAccounts mocks base method.
+func (m *MockRepository) CountAccounts(arg0 context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountAccounts", arg0)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountAccounts indicates an expected call of CountAccounts.
+func (mr *MockRepositoryMockRecorder) CountAccounts(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountAccounts", reflect.TypeOf((*MockRepository)(nil).CountAccounts), arg0)
+}
+
+// Drifts mocks base method.
+func (m *MockRepository) Drifts(arg0 context.Context) ([]ledger.Drift, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Drifts", arg0)
+	ret0, _ := ret[0].([]ledger.Drift)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Drifts indicates an expected call of Drifts.
+func (mr *MockRepositoryMockRecorder) Drifts(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Drifts", reflect.TypeOf((*MockRepository)(nil).Drifts), arg0)
+}
+
+// UnmatchedLegs mocks base method.
+func (m *MockRepository) UnmatchedLegs(arg0 context.Context) ([]ledger.UnmatchedLeg, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnmatchedLegs", arg0)
+	ret0, _ := ret[0].([]ledger.UnmatchedLeg)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnmatchedLegs indicates an expected call of UnmatchedLegs.
+func (mr *MockRepositoryMockRecorder) UnmatchedLegs(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnmatchedLegs", reflect.TypeOf((*MockRepository)(nil).UnmatchedLegs), arg0)
+}
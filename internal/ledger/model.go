@@ -0,0 +1,40 @@
+package ledger
+
+import "time"
+
+// Drift is an account whose stored balance differs from the balance recomputed from its transactions.
+type Drift struct {
+	AccountID string
+	Stored    float64
+	Expected  float64
+}
+
+// Difference is the amount the stored balance is off by.
+func (d Drift) Difference() float64 {
+	return d.Stored - d.Expected
+}
+
+// UnmatchedLeg is a group of transfer or fee legs between two accounts without the same number of opposite legs.
+// Both legs of a transfer are booked together, so they share the amount and the creation time.
+type UnmatchedLeg struct {
+	AccountID      string
+	CounterpartyID string
+	Debit          bool
+	Amount         float64
+	CreatedAt      time.Time
+	Legs           int // number of legs booked on the account
+	Matched        int // number of opposite legs booked on the counterparty
+}
+
+// Report is the result of a ledger verification.
+type Report struct {
+	Accounts      int
+	Drifts        []Drift
+	UnmatchedLegs []UnmatchedLeg
+	VerifiedAt    time.Time
+}
+
+// OK reports whether no discrepancies were found.
+func (r *Report) OK() bool {
+	return len(r.Drifts) == 0 && len(r.UnmatchedLegs) == 0
+}
@@ -0,0 +1,4 @@
+package ledger
+
+// VerifyRequest starts a verification of the whole ledger.
+type VerifyRequest struct{}
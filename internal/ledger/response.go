@@ -0,0 +1,65 @@
+package ledger
+
+import "time"
+
+type DriftDetails struct {
+	AccountId  string  `json:"account_id"`
+	Stored     float64 `json:"stored_balance"`
+	Expected   float64 `json:"expected_balance"`
+	Difference float64 `json:"difference"`
+}
+
+type UnmatchedLegDetails struct {
+	AccountId      string    `json:"account_id"`
+	CounterpartyId string    `json:"counterparty_id"`
+	Direction      string    `json:"direction"`
+	Amount         float64   `json:"amount"`
+	CreatedAt      time.Time `json:"created_at"`
+	Legs           int       `json:"legs"`
+	Matched        int       `json:"matched"`
+}
+
+type ReportDetails struct {
+	OK            bool                  `json:"ok"`
+	Accounts      int                   `json:"accounts"`
+	Drifts        []DriftDetails        `json:"drifts"`
+	UnmatchedLegs []UnmatchedLegDetails `json:"unmatched_legs"`
+	VerifiedAt    time.Time             `json:"verified_at"`
+}
+
+func newReportDetails(r *Report) *ReportDetails {
+	details := &ReportDetails{
+		OK:            r.OK(),
+		Accounts:      r.Accounts,
+		Drifts:        make([]DriftDetails, len(r.Drifts)),
+		UnmatchedLegs: make([]UnmatchedLegDetails, len(r.UnmatchedLegs)),
+		VerifiedAt:    r.VerifiedAt,
+	}
+
+	for i, d := range r.Drifts {
+		details.Drifts[i] = DriftDetails{
+			AccountId:  d.AccountID,
+			Stored:     d.Stored,
+			Expected:   d.Expected,
+			Difference: d.Difference(),
+		}
+	}
+
+	for i, l := range r.UnmatchedLegs {
+		direction := "credit"
+		if l.Debit {
+			direction = "debit"
+		}
+		details.UnmatchedLegs[i] = UnmatchedLegDetails{
+			AccountId:      l.AccountID,
+			CounterpartyId: l.CounterpartyID,
+			Direction:      direction,
+			Amount:         l.Amount,
+			CreatedAt:      l.CreatedAt,
+			Legs:           l.Legs,
+			Matched:        l.Matched,
+		}
+	}
+
+	return details
+}
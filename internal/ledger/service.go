@@ -0,0 +1,54 @@
+//go:generate mockgen -source=service.go -destination=./mock/service.go -package=mock
+package ledger
+
+import (
+	"context"
+
+	"github.com/softika/slogging"
+
+	"github.com/fmiskovic/cash-me-if-you-can/pkg/clock"
+)
+
+type Repository interface {
+	// CountAccounts returns the number of verified accounts.
+	CountAccounts(context.Context) (int, error)
+	// Drifts returns the accounts whose balance does not match the sum of their transactions.
+	Drifts(context.Context) ([]Drift, error)
+	// UnmatchedLegs returns the transfer and fee legs without an opposite leg on the counterparty account.
+	UnmatchedLegs(context.Context) ([]UnmatchedLeg, error)
+}
+
+type Service struct {
+	repo  Repository
+	clock clock.Clock
+}
+
+func NewService(repo Repository, clk clock.Clock) Service {
+	return Service{
+		repo:  repo,
+		clock: clk,
+	}
+}
+
+// Verify recomputes the account balances from the transactions and checks that the transfer legs balance.
+func (s Service) Verify(ctx context.Context, _ VerifyRequest) (*ReportDetails, error) {
+	logger := slogging.Slogger()
+
+	report := &Report{VerifiedAt: s.clock.Now()}
+
+	var err error
+	if report.Accounts, err = s.repo.CountAccounts(ctx); err != nil {
+		logger.ErrorContext(ctx, "failed to count accounts", "error", err)
+		return nil, err
+	}
+	if report.Drifts, err = s.repo.Drifts(ctx); err != nil {
+		logger.ErrorContext(ctx, "failed to verify account balances", "error", err)
+		return nil, err
+	}
+	if report.UnmatchedLegs, err = s.repo.UnmatchedLegs(ctx); err != nil {
+		logger.ErrorContext(ctx, "failed to verify transfer legs", "error", err)
+		return nil, err
+	}
+
+	return newReportDetails(report), nil
+}
@@ -0,0 +1,91 @@
+package ledger_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/fmiskovic/cash-me-if-you-can/internal/ledger"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/ledger/mock"
+	"github.com/fmiskovic/cash-me-if-you-can/pkg/clock"
+)
+
+func TestVerify(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+
+	now := time.Date(2024, time.March, 15, 3, 0, 0, 0, time.UTC)
+	drift := ledger.Drift{AccountID: "1", Stored: 110, Expected: 100}
+	leg := ledger.UnmatchedLeg{AccountID: "1", CounterpartyID: "2", Debit: true, Amount: 10, CreatedAt: now, Legs: 1}
+
+	tests := []struct {
+		name    string
+		mockFn  func(*mock.MockRepository)
+		want    *ledger.ReportDetails
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name: "balanced ledger",
+			mockFn: func(repo *mock.MockRepository) {
+				repo.EXPECT().CountAccounts(ctx).Return(2, nil)
+				repo.EXPECT().Drifts(ctx).Return(nil, nil)
+				repo.EXPECT().UnmatchedLegs(ctx).Return(nil, nil)
+			},
+			want: &ledger.ReportDetails{
+				OK:            true,
+				Accounts:      2,
+				Drifts:        []ledger.DriftDetails{},
+				UnmatchedLegs: []ledger.UnmatchedLegDetails{},
+				VerifiedAt:    now,
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "discrepancies",
+			mockFn: func(repo *mock.MockRepository) {
+				repo.EXPECT().CountAccounts(ctx).Return(2, nil)
+				repo.EXPECT().Drifts(ctx).Return([]ledger.Drift{drift}, nil)
+				repo.EXPECT().UnmatchedLegs(ctx).Return([]ledger.UnmatchedLeg{leg}, nil)
+			},
+			want: &ledger.ReportDetails{
+				Accounts: 2,
+				Drifts: []ledger.DriftDetails{
+					{AccountId: "1", Stored: 110, Expected: 100, Difference: 10},
+				},
+				UnmatchedLegs: []ledger.UnmatchedLegDetails{
+					{AccountId: "1", CounterpartyId: "2", Direction: "debit", Amount: 10, CreatedAt: now, Legs: 1},
+				},
+				VerifiedAt: now,
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "repository error",
+			mockFn: func(repo *mock.MockRepository) {
+				repo.EXPECT().CountAccounts(ctx).Return(2, nil)
+				repo.EXPECT().Drifts(ctx).Return(nil, assert.AnError)
+			},
+			wantErr: assert.Error,
+		},
+	}
+
+	for _, tc := range tests {
+		tt := tc
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := mock.NewMockRepository(ctrl)
+			tt.mockFn(repo)
+
+			s := ledger.NewService(repo, clock.Fixed(now))
+			got, err := s.Verify(ctx, ledger.VerifyRequest{})
+			tt.wantErr(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
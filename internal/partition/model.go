@@ -0,0 +1,47 @@
+package partition
+
+import (
+	"fmt"
+	"time"
+)
+
+// Partition of the transactions table holding the transactions booked in the month [From, To) in UTC.
+type Partition struct {
+	Name string
+	From time.Time
+	To   time.Time
+}
+
+// Month returns the partition of the month of t.
+func Month(t time.Time) Partition {
+	from := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return Partition{
+		Name: fmt.Sprintf("transactions_%04d_%02d", from.Year(), from.Month()),
+		From: from,
+		To:   from.AddDate(0, 1, 0),
+	}
+}
+
+// Parse returns the partition of the table name, ok is false if it is not a monthly partition.
+func Parse(name string) (Partition, bool) {
+	var year, month int
+	if n, err := fmt.Sscanf(name, "transactions_%04d_%02d", &year, &month); err != nil || n != 2 {
+		return Partition{}, false
+	}
+	if month < 1 || month > 12 {
+		return Partition{}, false
+	}
+	p := Month(time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC))
+	if p.Name != name {
+		return Partition{}, false
+	}
+	return p, true
+}
+
+// Archive of a partition whose transactions were written to a file before the partition was dropped.
+type Archive struct {
+	Partition    Partition
+	Location     string
+	Transactions int64
+	ArchivedAt   time.Time
+}
@@ -0,0 +1,12 @@
+package partition
+
+// MaintainRequest of the partitions of the transactions table.
+type MaintainRequest struct {
+	// Ahead is the number of months after the current one to create partitions for.
+	Ahead int `validate:"gte=0,lte=24"`
+	// Retain is the number of months, the current one included, whose partitions are kept.
+	// Older partitions are archived, zero disables archival.
+	Retain int `validate:"gte=0"`
+	// EventRetention is the number of days account events are kept, zero keeps all events.
+	EventRetention int `validate:"gte=0"`
+}
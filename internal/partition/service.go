@@ -0,0 +1,125 @@
+//go:generate mockgen -source=service.go -destination=./mock/service.go -package=mock
+package partition
+
+import (
+	"context"
+	"io"
+
+	"github.com/softika/slogging"
+
+	"github.com/fmiskovic/cash-me-if-you-can/internal/export"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/transaction"
+	"github.com/fmiskovic/cash-me-if-you-can/pkg/clock"
+	"github.com/fmiskovic/cash-me-if-you-can/pkg/errorx"
+)
+
+type Repository interface {
+	// Create creates the partition and moves the transactions of its month out of the default partition.
+	// It reports whether the partition was created, false if it already exists.
+	Create(context.Context, Partition) (bool, error)
+	// Partitions returns the attached monthly partitions in range order.
+	Partitions(context.Context) ([]Partition, error)
+	// Transactions streams the transactions of the partition in creation order to fn.
+	Transactions(ctx context.Context, p Partition, fn func(transaction.Transaction) error) error
+	// Archive records the archived balances of the accounts and the archive, then detaches and drops the partition.
+	// It fails and keeps the partition if it does not hold exactly the archived number of transactions.
+	Archive(context.Context, Archive) error
+}
+
+type Service struct {
+	repo  Repository
+	clock clock.Clock
+}
+
+func NewService(repo Repository, clk clock.Clock) Service {
+	return Service{
+		repo:  repo,
+		clock: clk,
+	}
+}
+
+// Prepare creates the missing partitions of the current month and the given number of months ahead,
+// so new transactions never land in the default partition. It returns the created partitions.
+func (s Service) Prepare(ctx context.Context, ahead int) ([]Partition, error) {
+	if ahead < 0 {
+		return nil, errorx.NewErrorMsg("months ahead must not be negative", errorx.ErrInvalidInput)
+	}
+
+	var created []Partition
+	current := Month(s.clock.Now())
+	for i := 0; i <= ahead; i++ {
+		p := Month(current.From.AddDate(0, i, 0))
+		ok, err := s.repo.Create(ctx, p)
+		if err != nil {
+			slogging.Slogger().ErrorContext(ctx, "failed to create partition", "partition", p.Name, "error", err)
+			return created, err
+		}
+		if ok {
+			created = append(created, p)
+		}
+	}
+
+	return created, nil
+}
+
+// Due returns the partitions entirely before the retained months, the current month included.
+func (s Service) Due(ctx context.Context, retain int) ([]Partition, error) {
+	if retain < 1 {
+		return nil, errorx.NewErrorMsg("at least the current month must be retained", errorx.ErrInvalidInput)
+	}
+
+	cutoff := Month(s.clock.Now()).From.AddDate(0, 1-retain, 0)
+
+	partitions, err := s.repo.Partitions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var due []Partition
+	for _, p := range partitions {
+		if !p.To.After(cutoff) {
+			due = append(due, p)
+		}
+	}
+
+	return due, nil
+}
+
+// Export writes the transactions of the partition to w as gzip-compressed JSON lines,
+// the format of the export command. It returns the number of written transactions.
+func (s Service) Export(ctx context.Context, p Partition, w io.Writer) (int64, error) {
+	var n int64
+	e := &export.Export{
+		Format: export.NDJSON,
+		Gzip:   true,
+		Transactions: func(fn func(transaction.Transaction) error) error {
+			return s.repo.Transactions(ctx, p, func(t transaction.Transaction) error {
+				n++
+				return fn(t)
+			})
+		},
+		CreatedAt: s.clock.Now(),
+	}
+
+	if err := export.Write(w, e); err != nil {
+		return n, err
+	}
+	return n, nil
+}
+
+// Archive drops the partition whose transactions were exported to the location.
+// The number of exported transactions guards against dropping transactions that are not in the archive.
+func (s Service) Archive(ctx context.Context, p Partition, location string, transactions int64) (*Archive, error) {
+	a := Archive{
+		Partition:    p,
+		Location:     location,
+		Transactions: transactions,
+		ArchivedAt:   s.clock.Now(),
+	}
+	if err := s.repo.Archive(ctx, a); err != nil {
+		slogging.Slogger().ErrorContext(ctx, "failed to archive partition", "partition", p.Name, "error", err)
+		return nil, err
+	}
+
+	return &a, nil
+}
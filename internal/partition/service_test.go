@@ -0,0 +1,240 @@
+package partition_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/fmiskovic/cash-me-if-you-can/internal/partition"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/partition/mock"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/transaction"
+	"github.com/fmiskovic/cash-me-if-you-can/pkg/clock"
+)
+
+var now = time.Date(2024, time.November, 15, 12, 0, 0, 0, time.UTC)
+
+func month(year int, m time.Month) partition.Partition {
+	return partition.Month(time.Date(year, m, 1, 0, 0, 0, 0, time.UTC))
+}
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	p, ok := partition.Parse("transactions_2024_02")
+	require.True(t, ok)
+	assert.Equal(t, month(2024, time.February), p)
+	assert.Equal(t, time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC), p.To)
+
+	for _, name := range []string{"transactions_default", "transactions_2024_13", "transactions_2024_2", "accounts"} {
+		_, ok = partition.Parse(name)
+		assert.False(t, ok, name)
+	}
+}
+
+func TestPrepare(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+
+	tests := []struct {
+		name    string
+		ahead   int
+		mockFn  func(*mock.MockRepository)
+		want    []partition.Partition
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name:  "creates missing partitions across the year end",
+			ahead: 2,
+			mockFn: func(repo *mock.MockRepository) {
+				repo.EXPECT().Create(ctx, month(2024, time.November)).Return(false, nil)
+				repo.EXPECT().Create(ctx, month(2024, time.December)).Return(true, nil)
+				repo.EXPECT().Create(ctx, month(2025, time.January)).Return(true, nil)
+			},
+			want:    []partition.Partition{month(2024, time.December), month(2025, time.January)},
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "negative months ahead",
+			ahead:   -1,
+			mockFn:  func(*mock.MockRepository) {},
+			wantErr: assert.Error,
+		},
+		{
+			name:  "repository error",
+			ahead: 1,
+			mockFn: func(repo *mock.MockRepository) {
+				repo.EXPECT().Create(ctx, month(2024, time.November)).Return(false, assert.AnError)
+			},
+			wantErr: assert.Error,
+		},
+	}
+
+	for _, tc := range tests {
+		tt := tc
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := mock.NewMockRepository(ctrl)
+			tt.mockFn(repo)
+
+			s := partition.NewService(repo, clock.Fixed(now))
+			got, err := s.Prepare(ctx, tt.ahead)
+			tt.wantErr(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestDue(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+
+	partitions := []partition.Partition{
+		month(2024, time.August),
+		month(2024, time.September),
+		month(2024, time.October),
+		month(2024, time.November),
+		month(2024, time.December),
+	}
+
+	tests := []struct {
+		name    string
+		retain  int
+		mockFn  func(*mock.MockRepository)
+		want    []partition.Partition
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name:   "partitions before the retained months",
+			retain: 2,
+			mockFn: func(repo *mock.MockRepository) {
+				repo.EXPECT().Partitions(ctx).Return(partitions, nil)
+			},
+			want:    partitions[:2],
+			wantErr: assert.NoError,
+		},
+		{
+			name:   "only the current month retained",
+			retain: 1,
+			mockFn: func(repo *mock.MockRepository) {
+				repo.EXPECT().Partitions(ctx).Return(partitions, nil)
+			},
+			want:    partitions[:3],
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "current month not retained",
+			retain:  0,
+			mockFn:  func(*mock.MockRepository) {},
+			wantErr: assert.Error,
+		},
+		{
+			name:   "repository error",
+			retain: 2,
+			mockFn: func(repo *mock.MockRepository) {
+				repo.EXPECT().Partitions(ctx).Return(nil, assert.AnError)
+			},
+			wantErr: assert.Error,
+		},
+	}
+
+	for _, tc := range tests {
+		tt := tc
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := mock.NewMockRepository(ctrl)
+			tt.mockFn(repo)
+
+			s := partition.NewService(repo, clock.Fixed(now))
+			got, err := s.Due(ctx, tt.retain)
+			tt.wantErr(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestExport(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	repo := mock.NewMockRepository(ctrl)
+
+	p := month(2024, time.August)
+	transactions := []transaction.Transaction{
+		{ID: "t1", AccountID: "a1", Type: transaction.Deposit, Amount: 100, CreatedAt: p.From},
+		{ID: "t2", AccountID: "a1", Type: transaction.Withdrawal, Amount: 30, CreatedAt: p.From.Add(time.Hour)},
+	}
+	repo.EXPECT().Transactions(ctx, p, gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ partition.Partition, fn func(transaction.Transaction) error) error {
+			for _, t := range transactions {
+				if err := fn(t); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	)
+
+	var buf bytes.Buffer
+	n, err := partition.NewService(repo, clock.Fixed(now)).Export(ctx, p, &buf)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), n)
+
+	gr, err := gzip.NewReader(&buf)
+	require.NoError(t, err)
+	b, err := io.ReadAll(gr)
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+	require.Len(t, lines, 2)
+	var line map[string]any
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &line))
+	assert.Equal(t, "t2", line["transaction_id"])
+}
+
+func TestArchive(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+
+	p := month(2024, time.August)
+	want := partition.Archive{Partition: p, Location: "archive/transactions_2024_08.ndjson.gz", Transactions: 2, ArchivedAt: now}
+
+	t.Run("archived", func(t *testing.T) {
+		t.Parallel()
+
+		repo := mock.NewMockRepository(ctrl)
+		repo.EXPECT().Archive(ctx, want).Return(nil)
+
+		got, err := partition.NewService(repo, clock.Fixed(now)).Archive(ctx, p, want.Location, 2)
+		require.NoError(t, err)
+		assert.Equal(t, &want, got)
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		t.Parallel()
+
+		repo := mock.NewMockRepository(ctrl)
+		repo.EXPECT().Archive(ctx, want).Return(assert.AnError)
+
+		got, err := partition.NewService(repo, clock.Fixed(now)).Archive(ctx, p, want.Location, 2)
+		assert.Error(t, err)
+		assert.Nil(t, got)
+	})
+}
@@ -0,0 +1,132 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=service.go -destination=./mock/service.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	reconciliation "github.com/fmiskovic/cash-me-if-you-can/internal/reconciliation"
+	transaction "github.com/fmiskovic/cash-me-if-you-can/internal/transaction"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRepository is a mock of Repository interface.
+type MockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryMockRecorder
+}
+
+// MockRepositoryMockRecorder is the mock recorder for MockRepository.
+type MockRepositoryMockRecorder struct {
+	mock *MockRepository
+}
+
+// NewMockRepository creates a new mock instance.
+func NewMockRepository(ctrl *gomock.Controller) *MockRepository {
+	mock := &MockRepository{ctrl: ctrl}
+	mock.recorder = &MockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Candidates mocks base method.
+func (m *MockRepository) Candidates(ctx context.Context, accountID string, start, end time.Time) ([]transaction.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Candidates", ctx, accountID, start, end)
+	ret0, _ := ret[0].([]transaction.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Candidates indicates an expected call of Candidates.
+func (mr *MockRepositoryMockRecorder) Candidates(ctx, accountID, start, end any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Candidates", reflect.TypeOf((*MockRepository)(nil).Candidates), ctx, accountID, start, end)
+}
+
+// Get mocks base method.
+func (m *MockRepository) Get(ctx context.Context, id string) (*reconciliation.Run, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, id)
+	ret0, _ := ret[0].(*reconciliation.Run)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockRepositoryMockRecorder) Get(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockRepository)(nil).Get), ctx, id)
+}
+
+// Import mocks base method.
+func (m *MockRepository) Import(ctx context.Context, run *reconciliation.Run, lines []reconciliation.Line, match func([]transaction.Transaction) []reconciliation.Pair, at time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Import", ctx, run, lines, match, at)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Import indicates an expected call of Import.
+func (mr *MockRepositoryMockRecorder) Import(ctx, run, lines, match, at any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Import", reflect.TypeOf((*MockRepository)(nil).Import), ctx, run, lines, match, at)
+}
+
+// Lines mocks base method.
+func (m *MockRepository) Lines(ctx context.Context, runID string) ([]reconciliation.Line, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Lines", ctx, runID)
+	ret0, _ := ret[0].([]reconciliation.Line)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Lines indicates an expected call of Lines.
+func (mr *MockRepositoryMockRecorder) Lines(ctx, runID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Lines", reflect.TypeOf((*MockRepository)(nil).Lines), ctx, runID)
+}
+
+// MatchLine mocks base method.
+func (m *MockRepository) MatchLine(ctx context.Context, runID, lineID, transactionID string, at time.Time) (*reconciliation.Line, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MatchLine", ctx, runID, lineID, transactionID, at)
+	ret0, _ := ret[0].(*reconciliation.Line)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MatchLine indicates an expected call of MatchLine.
+func (mr *MockRepositoryMockRecorder) MatchLine(ctx, runID, lineID, transactionID, at any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MatchLine", reflect.TypeOf((*MockRepository)(nil).MatchLine), ctx, runID, lineID, transactionID, at)
+}
+
+// UnmatchLine mocks base method.
+func (m *MockRepository) UnmatchLine(ctx context.Context, runID, lineID string) (*reconciliation.Line, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnmatchLine", ctx, runID, lineID)
+	ret0, _ := ret[0].(*reconciliation.Line)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnmatchLine indicates an expected call of UnmatchLine.
+func (mr *MockRepositoryMockRecorder) UnmatchLine(ctx, runID, lineID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnmatchLine", reflect.TypeOf((*MockRepository)(nil).UnmatchLine), ctx, runID, lineID)
+}
@@ -0,0 +1,193 @@
+package reconciliation
+
+import (
+	"math"
+	"strings"
+	"time"
+
+	"github.com/fmiskovic/cash-me-if-you-can/internal/transaction"
+)
+
+// Format of an imported bank statement.
+type Format string
+
+const (
+	CSV Format = "csv"
+	// CAMT053 is the ISO 20022 bank to customer statement.
+	CAMT053 Format = "camt.053"
+)
+
+type MatchKind string
+
+const (
+	Unmatched MatchKind = ""
+	// Auto matches are made by the matching rules on import.
+	Auto MatchKind = "auto"
+	// Manual matches are made through the api.
+	Manual MatchKind = "manual"
+)
+
+// Rules decide how far a statement line may be off from the transaction it is matched to.
+type Rules struct {
+	AmountTolerance float64
+	DateTolerance   time.Duration
+}
+
+// DefaultRules tolerate cent rounding and a couple of days between booking at the bank and here.
+var DefaultRules = Rules{
+	AmountTolerance: 0.01,
+	DateTolerance:   2 * 24 * time.Hour,
+}
+
+// Run is an imported bank statement of an account.
+type Run struct {
+	ID        string
+	AccountID string
+	Format    Format
+	Rules     Rules
+
+	// PeriodStart and PeriodEnd bound the booking time of the transactions reconciled by the run,
+	// they span the statement lines widened by the date tolerance.
+	PeriodStart time.Time
+	PeriodEnd   time.Time
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Line is a movement of an imported bank statement.
+type Line struct {
+	ID          string
+	RunID       string
+	LineNo      int
+	BookedAt    time.Time
+	Amount      float64 // positive for credits, negative for debits
+	Reference   string
+	Description string
+
+	TransactionID string
+	MatchKind     MatchKind
+	MatchedAt     *time.Time
+}
+
+// Matched reports whether the line is matched to a transaction.
+func (l *Line) Matched() bool {
+	return l.TransactionID != ""
+}
+
+// Pair matches a statement line with a transaction.
+type Pair struct {
+	LineID        string
+	TransactionID string
+}
+
+// Period returns the booking time bounds of the transactions the lines can be matched to.
+func Period(lines []Line, rules Rules) (start, end time.Time) {
+	for i, l := range lines {
+		if i == 0 || l.BookedAt.Before(start) {
+			start = l.BookedAt
+		}
+		if i == 0 || l.BookedAt.After(end) {
+			end = l.BookedAt
+		}
+	}
+	return start.Add(-rules.DateTolerance), end.Add(rules.DateTolerance)
+}
+
+func signedAmount(t transaction.Transaction) float64 {
+	if t.Type.IsDebit() {
+		return -t.Amount
+	}
+	return t.Amount
+}
+
+// referenceMatches reports whether the line refers to the transaction by its external reference or id,
+// ids may be compacted to fit the 35 characters of ISO 20022 references.
+func referenceMatches(l Line, t transaction.Transaction) bool {
+	return l.Reference == t.ExternalReference || l.Reference == t.ID || l.Reference == strings.ReplaceAll(t.ID, "-", "")
+}
+
+func (r Rules) amountMatches(l Line, t transaction.Transaction) bool {
+	// the epsilon absorbs the floating point error of amounts stored with more decimals
+	return math.Abs(l.Amount-signedAmount(t)) <= r.AmountTolerance+1e-9
+}
+
+func (r Rules) dateMatches(l Line, t transaction.Transaction) bool {
+	d := l.BookedAt.Sub(t.Timestamp)
+	return d <= r.DateTolerance && d >= -r.DateTolerance
+}
+
+// Match pairs the unmatched lines with the unmatched transactions.
+//
+// A line is matched by reference first: its reference equals the external reference or the id of exactly one
+// transaction with a matching amount. The remaining lines are matched by amount and booking date,
+// only if the line and the transaction are each other's single candidate, so ambiguous lines are left for manual matching.
+func Match(lines []Line, transactions []transaction.Transaction, rules Rules) []Pair {
+	var pairs []Pair
+	used := make(map[string]bool, len(transactions))
+	done := make(map[string]bool, len(lines))
+
+	pair := func(l Line, t transaction.Transaction) {
+		pairs = append(pairs, Pair{LineID: l.ID, TransactionID: t.ID})
+		used[t.ID] = true
+		done[l.ID] = true
+	}
+
+	// by reference
+	for _, l := range lines {
+		if l.Matched() || l.Reference == "" {
+			continue
+		}
+
+		var found []transaction.Transaction
+		for _, t := range transactions {
+			if used[t.ID] || !rules.amountMatches(l, t) {
+				continue
+			}
+			if referenceMatches(l, t) {
+				found = append(found, t)
+			}
+		}
+		if len(found) == 1 {
+			pair(l, found[0])
+		}
+	}
+
+	// by amount and date
+	candidates := func(l Line) []transaction.Transaction {
+		var found []transaction.Transaction
+		for _, t := range transactions {
+			if !used[t.ID] && rules.amountMatches(l, t) && rules.dateMatches(l, t) {
+				found = append(found, t)
+			}
+		}
+		return found
+	}
+	candidateLines := func(t transaction.Transaction) int {
+		n := 0
+		for _, l := range lines {
+			if !l.Matched() && !done[l.ID] && rules.amountMatches(l, t) && rules.dateMatches(l, t) {
+				n++
+			}
+		}
+		return n
+	}
+
+	for _, l := range lines {
+		if l.Matched() || done[l.ID] {
+			continue
+		}
+		if found := candidates(l); len(found) == 1 && candidateLines(found[0]) == 1 {
+			pair(l, found[0])
+		}
+	}
+
+	return pairs
+}
+
+// Report is the state of a run: its lines with their matches and the transactions of the period nothing is matched to.
+type Report struct {
+	Run                   Run
+	Lines                 []Line
+	UnmatchedTransactions []transaction.Transaction
+}
@@ -0,0 +1,187 @@
+package reconciliation
+
+import (
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Parse reads the lines of a bank statement in the given format.
+func Parse(format Format, r io.Reader) ([]Line, error) {
+	switch format {
+	case CSV:
+		return parseCSV(r)
+	case CAMT053:
+		return parseCAMT053(r)
+	default:
+		return nil, fmt.Errorf("unsupported format %q", format)
+	}
+}
+
+// parseTime parses an RFC 3339 timestamp or a date as midnight UTC.
+func parseTime(s string) (time.Time, error) {
+	if t, err := time.Parse(time.DateOnly, s); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
+
+// csvColumns maps the accepted header names to the line fields, the statement export of this service is accepted as well.
+var csvColumns = map[string]string{
+	"date":               "date",
+	"booked_at":          "date",
+	"amount":             "amount",
+	"reference":          "reference",
+	"external_reference": "reference",
+	"description":        "description",
+	"record":             "record",
+}
+
+// parseCSV reads a CSV statement with a header row naming at least the date and the signed amount columns.
+// Rows with a record column other than entry, e.g. opening and closing balances, are skipped.
+func parseCSV(r io.Reader) ([]Line, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("statement is empty")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make(map[string]int)
+	for i, name := range header {
+		if field, ok := csvColumns[strings.ToLower(strings.TrimSpace(name))]; ok {
+			columns[field] = i
+		}
+	}
+	for _, required := range []string{"date", "amount"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("statement header is missing the %s column", required)
+		}
+	}
+
+	value := func(record []string, field string) string {
+		i, ok := columns[field]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	var lines []Line
+	for lineNo := 2; ; lineNo++ {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if rec := value(record, "record"); rec != "" && rec != "entry" {
+			continue
+		}
+
+		bookedAt, err := parseTime(value(record, "date"))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid date: %w", lineNo, err)
+		}
+		amount, err := strconv.ParseFloat(value(record, "amount"), 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid amount: %w", lineNo, err)
+		}
+
+		lines = append(lines, Line{
+			LineNo:      lineNo,
+			BookedAt:    bookedAt,
+			Amount:      amount,
+			Reference:   value(record, "reference"),
+			Description: value(record, "description"),
+		})
+	}
+
+	return lines, nil
+}
+
+type camtEntry struct {
+	Amount      string `xml:"Amt"`
+	Indicator   string `xml:"CdtDbtInd"`
+	BookingDate struct {
+		Date     string `xml:"Dt"`
+		DateTime string `xml:"DtTm"`
+	} `xml:"BookgDt"`
+	Reference  string `xml:"AcctSvcrRef"`
+	EndToEndId string `xml:"NtryDtls>TxDtls>Refs>EndToEndId"`
+	Remittance string `xml:"NtryDtls>TxDtls>RmtInf>Ustrd"`
+	Info       string `xml:"AddtlNtryInf"`
+}
+
+type camtDocument struct {
+	Statements []struct {
+		Entries []camtEntry `xml:"Ntry"`
+	} `xml:"BkToCstmrStmt>Stmt"`
+}
+
+// parseCAMT053 reads the entries of all statements of a camt.053 document.
+// The end to end id is used as the reference, falling back to the bank's reference when it is not provided.
+func parseCAMT053(r io.Reader) ([]Line, error) {
+	var doc camtDocument
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("invalid camt.053 document: %w", err)
+	}
+
+	var lines []Line
+	for _, st := range doc.Statements {
+		for _, e := range st.Entries {
+			lineNo := len(lines) + 1
+
+			date := e.BookingDate.DateTime
+			if date == "" {
+				date = e.BookingDate.Date
+			}
+			bookedAt, err := parseTime(strings.TrimSpace(date))
+			if err != nil {
+				return nil, fmt.Errorf("entry %d: invalid booking date: %w", lineNo, err)
+			}
+
+			amount, err := strconv.ParseFloat(strings.TrimSpace(e.Amount), 64)
+			if err != nil {
+				return nil, fmt.Errorf("entry %d: invalid amount: %w", lineNo, err)
+			}
+			switch strings.TrimSpace(e.Indicator) {
+			case "CRDT":
+			case "DBIT":
+				amount = -amount
+			default:
+				return nil, fmt.Errorf("entry %d: invalid credit debit indicator %q", lineNo, e.Indicator)
+			}
+
+			reference := strings.TrimSpace(e.EndToEndId)
+			if reference == "" || reference == "NOTPROVIDED" {
+				reference = strings.TrimSpace(e.Reference)
+			}
+			description := strings.TrimSpace(e.Remittance)
+			if description == "" {
+				description = strings.TrimSpace(e.Info)
+			}
+
+			lines = append(lines, Line{
+				LineNo:      lineNo,
+				BookedAt:    bookedAt,
+				Amount:      amount,
+				Reference:   reference,
+				Description: description,
+			})
+		}
+	}
+
+	return lines, nil
+}
@@ -0,0 +1,23 @@
+package reconciliation
+
+// ImportRequest stages the lines of a bank statement of an account and matches them to its transactions.
+// Tolerances that are not set default to DefaultRules.
+type ImportRequest struct {
+	AccountID           string   `validate:"required,uuid"`
+	Format              Format   `validate:"required,oneof=csv camt.053"`
+	AmountTolerance     *float64 `validate:"omitempty,gte=0"`
+	DateToleranceInDays *int     `validate:"omitempty,gte=0,lte=31"`
+	Lines               []Line   `validate:"required,min=1"`
+}
+
+// LineRequest selects a line of a reconciliation run.
+type LineRequest struct {
+	RunID  string `validate:"required,uuid"`
+	LineID string `validate:"required,uuid"`
+}
+
+// MatchRequest manually matches a line of a reconciliation run with a transaction.
+type MatchRequest struct {
+	LineRequest
+	TransactionID string `json:"transaction_id" validate:"required,uuid"`
+}
@@ -0,0 +1,96 @@
+package reconciliation
+
+import "time"
+
+type LineDetails struct {
+	LineId        string     `json:"line_id"`
+	LineNo        int        `json:"line_no"`
+	BookedAt      time.Time  `json:"booked_at"`
+	Amount        float64    `json:"amount"`
+	Reference     string     `json:"reference,omitempty"`
+	Description   string     `json:"description,omitempty"`
+	TransactionId string     `json:"transaction_id,omitempty"`
+	Match         string     `json:"match,omitempty"`
+	MatchedAt     *time.Time `json:"matched_at,omitempty"`
+}
+
+type TransactionDetails struct {
+	TransactionId     string    `json:"transaction_id"`
+	Type              string    `json:"type"`
+	Amount            float64   `json:"amount"`
+	Description       string    `json:"description,omitempty"`
+	ExternalReference string    `json:"external_reference,omitempty"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+type ReportDetails struct {
+	RunId             string    `json:"run_id"`
+	AccountId         string    `json:"account_id"`
+	Format            string    `json:"format"`
+	AmountTolerance   float64   `json:"amount_tolerance"`
+	DateToleranceDays float64   `json:"date_tolerance_days"`
+	PeriodStart       time.Time `json:"period_start"`
+	PeriodEnd         time.Time `json:"period_end"`
+	CreatedAt         time.Time `json:"created_at"`
+
+	Lines   int `json:"lines"`
+	Matched int `json:"matched"`
+
+	MatchedLines          []LineDetails        `json:"matched_lines"`
+	UnmatchedLines        []LineDetails        `json:"unmatched_lines"`
+	UnmatchedTransactions []TransactionDetails `json:"unmatched_transactions"`
+}
+
+func newLineDetails(l *Line) *LineDetails {
+	return &LineDetails{
+		LineId:        l.ID,
+		LineNo:        l.LineNo,
+		BookedAt:      l.BookedAt,
+		Amount:        l.Amount,
+		Reference:     l.Reference,
+		Description:   l.Description,
+		TransactionId: l.TransactionID,
+		Match:         string(l.MatchKind),
+		MatchedAt:     l.MatchedAt,
+	}
+}
+
+func newReportDetails(r *Report) *ReportDetails {
+	details := &ReportDetails{
+		RunId:                 r.Run.ID,
+		AccountId:             r.Run.AccountID,
+		Format:                string(r.Run.Format),
+		AmountTolerance:       r.Run.Rules.AmountTolerance,
+		DateToleranceDays:     r.Run.Rules.DateTolerance.Hours() / 24,
+		PeriodStart:           r.Run.PeriodStart,
+		PeriodEnd:             r.Run.PeriodEnd,
+		CreatedAt:             r.Run.CreatedAt,
+		Lines:                 len(r.Lines),
+		MatchedLines:          make([]LineDetails, 0),
+		UnmatchedLines:        make([]LineDetails, 0),
+		UnmatchedTransactions: make([]TransactionDetails, len(r.UnmatchedTransactions)),
+	}
+
+	for i := range r.Lines {
+		l := &r.Lines[i]
+		if l.Matched() {
+			details.Matched++
+			details.MatchedLines = append(details.MatchedLines, *newLineDetails(l))
+		} else {
+			details.UnmatchedLines = append(details.UnmatchedLines, *newLineDetails(l))
+		}
+	}
+
+	for i, t := range r.UnmatchedTransactions {
+		details.UnmatchedTransactions[i] = TransactionDetails{
+			TransactionId:     t.ID,
+			Type:              string(t.Type),
+			Amount:            t.Amount,
+			Description:       t.Description,
+			ExternalReference: t.ExternalReference,
+			CreatedAt:         t.CreatedAt,
+		}
+	}
+
+	return details
+}
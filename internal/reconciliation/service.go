@@ -0,0 +1,125 @@
+//go:generate mockgen -source=service.go -destination=./mock/service.go -package=mock
+package reconciliation
+
+import (
+	"context"
+	"time"
+
+	"github.com/softika/slogging"
+
+	"github.com/fmiskovic/cash-me-if-you-can/internal/transaction"
+	"github.com/fmiskovic/cash-me-if-you-can/pkg/clock"
+)
+
+type Repository interface {
+	// Import stores the run with its lines, setting their ids, and stores the pairs match returns for the candidates
+	// of the run as automatic matches. It is atomic, if matching fails no run is stored.
+	Import(ctx context.Context, run *Run, lines []Line, match func([]transaction.Transaction) []Pair, at time.Time) error
+	// Get returns the run with the given id.
+	Get(ctx context.Context, id string) (*Run, error)
+	// Lines returns the lines of the run ordered by their number.
+	Lines(ctx context.Context, runID string) ([]Line, error)
+	// Candidates returns the transactions of the account booked in the period that are not matched to any line.
+	Candidates(ctx context.Context, accountID string, start, end time.Time) ([]transaction.Transaction, error)
+	// MatchLine manually matches the line of the run with a transaction of the run's account.
+	MatchLine(ctx context.Context, runID, lineID, transactionID string, at time.Time) (*Line, error)
+	// UnmatchLine removes the match of the line of the run.
+	UnmatchLine(ctx context.Context, runID, lineID string) (*Line, error)
+}
+
+type Service struct {
+	repo  Repository
+	clock clock.Clock
+}
+
+func NewService(repo Repository, clk clock.Clock) Service {
+	return Service{
+		repo:  repo,
+		clock: clk,
+	}
+}
+
+// Import stages the statement lines as a new run, matches them automatically and returns the run report.
+func (s Service) Import(ctx context.Context, req ImportRequest) (*ReportDetails, error) {
+	logger := slogging.Slogger()
+
+	rules := DefaultRules
+	if req.AmountTolerance != nil {
+		rules.AmountTolerance = *req.AmountTolerance
+	}
+	if req.DateToleranceInDays != nil {
+		rules.DateTolerance = time.Duration(*req.DateToleranceInDays) * 24 * time.Hour
+	}
+
+	run := &Run{
+		AccountID: req.AccountID,
+		Format:    req.Format,
+		Rules:     rules,
+	}
+	run.PeriodStart, run.PeriodEnd = Period(req.Lines, rules)
+
+	// the lines get their ids when they are stored, before they are matched
+	match := func(candidates []transaction.Transaction) []Pair {
+		return Match(req.Lines, candidates, rules)
+	}
+	if err := s.repo.Import(ctx, run, req.Lines, match, s.clock.Now()); err != nil {
+		logger.ErrorContext(ctx, "failed to import reconciliation run", "account_id", req.AccountID, "error", err)
+		return nil, err
+	}
+
+	return s.report(ctx, run)
+}
+
+// Get returns the report of the run with the given id.
+func (s Service) Get(ctx context.Context, id string) (*ReportDetails, error) {
+	run, err := s.repo.Get(ctx, id)
+	if err != nil {
+		logger := slogging.Slogger()
+		logger.ErrorContext(ctx, "failed to get reconciliation run", "run_id", id, "error", err)
+		return nil, err
+	}
+	return s.report(ctx, run)
+}
+
+// Match manually matches a line with a transaction the automatic matching missed.
+func (s Service) Match(ctx context.Context, req MatchRequest) (*LineDetails, error) {
+	l, err := s.repo.MatchLine(ctx, req.RunID, req.LineID, req.TransactionID, s.clock.Now())
+	if err != nil {
+		logger := slogging.Slogger()
+		logger.ErrorContext(ctx, "failed to match reconciliation line", "line_id", req.LineID, "error", err)
+		return nil, err
+	}
+	return newLineDetails(l), nil
+}
+
+// Unmatch removes a manual or automatic match of a line.
+func (s Service) Unmatch(ctx context.Context, req LineRequest) (*LineDetails, error) {
+	l, err := s.repo.UnmatchLine(ctx, req.RunID, req.LineID)
+	if err != nil {
+		logger := slogging.Slogger()
+		logger.ErrorContext(ctx, "failed to unmatch reconciliation line", "line_id", req.LineID, "error", err)
+		return nil, err
+	}
+	return newLineDetails(l), nil
+}
+
+func (s Service) report(ctx context.Context, run *Run) (*ReportDetails, error) {
+	logger := slogging.Slogger()
+
+	lines, err := s.repo.Lines(ctx, run.ID)
+	if err != nil {
+		logger.ErrorContext(ctx, "failed to get reconciliation lines", "run_id", run.ID, "error", err)
+		return nil, err
+	}
+	unmatched, err := s.repo.Candidates(ctx, run.AccountID, run.PeriodStart, run.PeriodEnd)
+	if err != nil {
+		logger.ErrorContext(ctx, "failed to get unmatched transactions", "run_id", run.ID, "error", err)
+		return nil, err
+	}
+
+	return newReportDetails(&Report{
+		Run:                   *run,
+		Lines:                 lines,
+		UnmatchedTransactions: unmatched,
+	}), nil
+}
@@ -0,0 +1,428 @@
+package reconciliation_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/fmiskovic/cash-me-if-you-can/internal/reconciliation"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/reconciliation/mock"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/statement"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/transaction"
+	"github.com/fmiskovic/cash-me-if-you-can/pkg/clock"
+)
+
+var (
+	day = time.Date(2024, time.August, 1, 10, 0, 0, 0, time.UTC)
+	now = time.Date(2024, time.September, 1, 12, 0, 0, 0, time.UTC)
+)
+
+func TestMatch(t *testing.T) {
+	t.Parallel()
+
+	deposit := transaction.Transaction{ID: "t1", Type: transaction.Deposit, Amount: 100, Timestamp: day, ExternalReference: "ref-1"}
+	withdrawal := transaction.Transaction{ID: "t2", Type: transaction.Withdrawal, Amount: 30.5, Timestamp: day.Add(time.Hour)}
+	fee := transaction.Transaction{ID: "t3", Type: transaction.Fee, Amount: 2, Timestamp: day}
+	otherFee := transaction.Transaction{ID: "t4", Type: transaction.Fee, Amount: 2, Timestamp: day.Add(time.Hour)}
+
+	tests := []struct {
+		name         string
+		lines        []reconciliation.Line
+		transactions []transaction.Transaction
+		want         []reconciliation.Pair
+	}{
+		{
+			name: "by reference",
+			lines: []reconciliation.Line{
+				{ID: "l1", BookedAt: day.AddDate(0, 0, 10), Amount: 100, Reference: "ref-1"},
+			},
+			transactions: []transaction.Transaction{deposit},
+			want:         []reconciliation.Pair{{LineID: "l1", TransactionID: "t1"}},
+		},
+		{
+			name: "by compacted transaction id",
+			lines: []reconciliation.Line{
+				{ID: "l1", BookedAt: day, Amount: -30.5, Reference: "a1b2c3d4111122223333444455556666"},
+			},
+			transactions: []transaction.Transaction{
+				{ID: "a1b2c3d4-1111-2222-3333-444455556666", Type: transaction.Withdrawal, Amount: 30.5, Timestamp: day},
+			},
+			want: []reconciliation.Pair{{LineID: "l1", TransactionID: "a1b2c3d4-1111-2222-3333-444455556666"}},
+		},
+		{
+			name: "reference with a different amount",
+			lines: []reconciliation.Line{
+				{ID: "l1", BookedAt: day.AddDate(0, 0, 10), Amount: 90, Reference: "ref-1"},
+			},
+			transactions: []transaction.Transaction{deposit},
+		},
+		{
+			name: "by amount and date within tolerance",
+			lines: []reconciliation.Line{
+				{ID: "l1", BookedAt: day.AddDate(0, 0, 1), Amount: 100.01},
+				{ID: "l2", BookedAt: day.AddDate(0, 0, 2), Amount: -30.5},
+			},
+			transactions: []transaction.Transaction{deposit, withdrawal},
+			want: []reconciliation.Pair{
+				{LineID: "l1", TransactionID: "t1"},
+				{LineID: "l2", TransactionID: "t2"},
+			},
+		},
+		{
+			name: "outside of date tolerance",
+			lines: []reconciliation.Line{
+				{ID: "l1", BookedAt: day.AddDate(0, 0, 3), Amount: 100},
+			},
+			transactions: []transaction.Transaction{deposit},
+		},
+		{
+			name: "debit sign",
+			lines: []reconciliation.Line{
+				{ID: "l1", BookedAt: day, Amount: 30.5},
+			},
+			transactions: []transaction.Transaction{withdrawal},
+		},
+		{
+			name: "ambiguous transactions",
+			lines: []reconciliation.Line{
+				{ID: "l1", BookedAt: day, Amount: -2},
+			},
+			transactions: []transaction.Transaction{fee, otherFee},
+		},
+		{
+			name: "ambiguous lines",
+			lines: []reconciliation.Line{
+				{ID: "l1", BookedAt: day, Amount: -2},
+				{ID: "l2", BookedAt: day.Add(time.Hour), Amount: -2},
+			},
+			transactions: []transaction.Transaction{fee},
+		},
+		{
+			name: "reference resolves ambiguity",
+			lines: []reconciliation.Line{
+				{ID: "l1", BookedAt: day, Amount: -2, Reference: "t4"},
+				{ID: "l2", BookedAt: day, Amount: -2},
+			},
+			transactions: []transaction.Transaction{fee, otherFee},
+			want: []reconciliation.Pair{
+				{LineID: "l1", TransactionID: "t4"},
+				{LineID: "l2", TransactionID: "t3"},
+			},
+		},
+		{
+			name: "already matched line",
+			lines: []reconciliation.Line{
+				{ID: "l1", BookedAt: day, Amount: 100, Reference: "ref-1", TransactionID: "t0"},
+			},
+			transactions: []transaction.Transaction{deposit},
+		},
+	}
+
+	for _, tc := range tests {
+		tt := tc
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := reconciliation.Match(tt.lines, tt.transactions, reconciliation.DefaultRules)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		format  reconciliation.Format
+		input   string
+		want    []reconciliation.Line
+		wantErr string
+	}{
+		{
+			name:   "csv",
+			format: reconciliation.CSV,
+			input: "Date,Amount,Reference,Description\n" +
+				"2024-08-01,100.00,ref-1,salary\n" +
+				"2024-08-02T10:00:00Z,-30.5,,\n",
+			want: []reconciliation.Line{
+				{LineNo: 2, BookedAt: time.Date(2024, time.August, 1, 0, 0, 0, 0, time.UTC), Amount: 100, Reference: "ref-1", Description: "salary"},
+				{LineNo: 3, BookedAt: time.Date(2024, time.August, 2, 10, 0, 0, 0, time.UTC), Amount: -30.5},
+			},
+		},
+		{
+			name:    "csv without amount column",
+			format:  reconciliation.CSV,
+			input:   "date,reference\n2024-08-01,ref-1\n",
+			wantErr: "statement header is missing the amount column",
+		},
+		{
+			name:    "csv with invalid amount",
+			format:  reconciliation.CSV,
+			input:   "date,amount\n2024-08-01,ten\n",
+			wantErr: "line 2: invalid amount",
+		},
+		{
+			name:    "empty csv",
+			format:  reconciliation.CSV,
+			wantErr: "statement is empty",
+		},
+		{
+			name:   "camt.053",
+			format: reconciliation.CAMT053,
+			input: `<?xml version="1.0" encoding="UTF-8"?>
+<Document xmlns="urn:iso:std:iso:20022:tech:xsd:camt.053.001.02">
+  <BkToCstmrStmt>
+    <Stmt>
+      <Ntry>
+        <Amt Ccy="EUR">100.00</Amt>
+        <CdtDbtInd>CRDT</CdtDbtInd>
+        <BookgDt><Dt>2024-08-01</Dt></BookgDt>
+        <AcctSvcrRef>BANK-1</AcctSvcrRef>
+        <NtryDtls><TxDtls><Refs><EndToEndId>ref-1</EndToEndId></Refs><RmtInf><Ustrd>salary</Ustrd></RmtInf></TxDtls></NtryDtls>
+      </Ntry>
+      <Ntry>
+        <Amt Ccy="EUR">30.50</Amt>
+        <CdtDbtInd>DBIT</CdtDbtInd>
+        <BookgDt><DtTm>2024-08-02T10:00:00Z</DtTm></BookgDt>
+        <AcctSvcrRef>BANK-2</AcctSvcrRef>
+        <NtryDtls><TxDtls><Refs><EndToEndId>NOTPROVIDED</EndToEndId></Refs></TxDtls></NtryDtls>
+        <AddtlNtryInf>card payment</AddtlNtryInf>
+      </Ntry>
+    </Stmt>
+  </BkToCstmrStmt>
+</Document>`,
+			want: []reconciliation.Line{
+				{LineNo: 1, BookedAt: time.Date(2024, time.August, 1, 0, 0, 0, 0, time.UTC), Amount: 100, Reference: "ref-1", Description: "salary"},
+				{LineNo: 2, BookedAt: time.Date(2024, time.August, 2, 10, 0, 0, 0, time.UTC), Amount: -30.5, Reference: "BANK-2", Description: "card payment"},
+			},
+		},
+		{
+			name:    "camt.053 with invalid indicator",
+			format:  reconciliation.CAMT053,
+			input:   `<Document><BkToCstmrStmt><Stmt><Ntry><Amt>1</Amt><CdtDbtInd>X</CdtDbtInd><BookgDt><Dt>2024-08-01</Dt></BookgDt></Ntry></Stmt></BkToCstmrStmt></Document>`,
+			wantErr: `entry 1: invalid credit debit indicator "X"`,
+		},
+		{
+			name:    "unsupported format",
+			format:  "mt940",
+			wantErr: `unsupported format "mt940"`,
+		},
+	}
+
+	for _, tc := range tests {
+		tt := tc
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := reconciliation.Parse(tt.format, strings.NewReader(tt.input))
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+// TestParseStatementExport checks that the statements exported by this service can be reconciled against themselves.
+func TestParseStatementExport(t *testing.T) {
+	t.Parallel()
+
+	transactions := []transaction.Transaction{
+		{ID: "c1d2e3f4-3333-4444-5555-666677778888", Type: transaction.Deposit, Amount: 100, Timestamp: day, ExternalReference: "ref-1"},
+		{ID: "b1c2d3e4-2222-3333-4444-555566667777", Type: transaction.Withdrawal, Amount: 30.5, Timestamp: day.Add(time.Hour)},
+	}
+
+	for _, format := range []reconciliation.Format{reconciliation.CSV, reconciliation.CAMT053} {
+		t.Run(string(format), func(t *testing.T) {
+			t.Parallel()
+
+			st := &statement.Statement{
+				AccountID: "a1b2c3d4-1111-2222-3333-444455556666",
+				Currency:  "EUR",
+				Format:    statement.Format(format),
+				From:      day.AddDate(0, 0, -1),
+				To:        day.AddDate(0, 0, 1),
+				CreatedAt: now,
+				Entries: func(fn func(statement.Entry) error) error {
+					for _, tr := range transactions {
+						if err := fn(statement.Entry{Transaction: tr}); err != nil {
+							return err
+						}
+					}
+					return nil
+				},
+			}
+
+			var buf bytes.Buffer
+			require.NoError(t, statement.Write(&buf, st))
+
+			lines, err := reconciliation.Parse(format, &buf)
+			require.NoError(t, err)
+			require.Len(t, lines, 2)
+			assert.Equal(t, 100.0, lines[0].Amount)
+			assert.Equal(t, -30.5, lines[1].Amount)
+
+			for i := range lines {
+				lines[i].ID = string(rune('a' + i))
+			}
+			pairs := reconciliation.Match(lines, transactions, reconciliation.Rules{})
+			assert.Equal(t, []reconciliation.Pair{
+				{LineID: "a", TransactionID: transactions[0].ID},
+				{LineID: "b", TransactionID: transactions[1].ID},
+			}, pairs)
+		})
+	}
+}
+
+func TestImport(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+
+	accountID := "a1b2c3d4-1111-2222-3333-444455556666"
+	deposit := transaction.Transaction{ID: "t1", Type: transaction.Deposit, Amount: 100, Timestamp: day}
+	fee := transaction.Transaction{ID: "t2", Type: transaction.Fee, Amount: 2, Timestamp: day, CreatedAt: day}
+
+	amountTolerance, dateTolerance := 0.0, 1
+
+	tests := []struct {
+		name    string
+		req     reconciliation.ImportRequest
+		mockFn  func(*mock.MockRepository)
+		want    *reconciliation.ReportDetails
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name: "matches and reports",
+			req: reconciliation.ImportRequest{
+				AccountID:           accountID,
+				Format:              reconciliation.CSV,
+				AmountTolerance:     &amountTolerance,
+				DateToleranceInDays: &dateTolerance,
+				Lines: []reconciliation.Line{
+					{LineNo: 2, BookedAt: day, Amount: 100},
+					{LineNo: 3, BookedAt: day, Amount: 7},
+				},
+			},
+			mockFn: func(repo *mock.MockRepository) {
+				rules := reconciliation.Rules{DateTolerance: 24 * time.Hour}
+				run := &reconciliation.Run{
+					AccountID:   accountID,
+					Format:      reconciliation.CSV,
+					Rules:       rules,
+					PeriodStart: day.AddDate(0, 0, -1),
+					PeriodEnd:   day.AddDate(0, 0, 1),
+				}
+				repo.EXPECT().Import(ctx, run, gomock.Len(2), gomock.Any(), now).
+					DoAndReturn(func(
+						_ context.Context,
+						run *reconciliation.Run,
+						lines []reconciliation.Line,
+						match func([]transaction.Transaction) []reconciliation.Pair,
+						_ time.Time,
+					) error {
+						run.ID, run.CreatedAt = "r1", now
+						lines[0].ID, lines[1].ID = "l1", "l2"
+						// lines are matched with the ids they were stored with
+						assert.Equal(t, []reconciliation.Pair{{LineID: "l1", TransactionID: "t1"}},
+							match([]transaction.Transaction{deposit, fee}))
+						return nil
+					})
+				repo.EXPECT().Lines(ctx, "r1").Return([]reconciliation.Line{
+					{ID: "l1", LineNo: 2, BookedAt: day, Amount: 100, TransactionID: "t1", MatchKind: reconciliation.Auto, MatchedAt: &now},
+					{ID: "l2", LineNo: 3, BookedAt: day, Amount: 7},
+				}, nil)
+				repo.EXPECT().Candidates(ctx, accountID, run.PeriodStart, run.PeriodEnd).
+					Return([]transaction.Transaction{fee}, nil)
+			},
+			want: &reconciliation.ReportDetails{
+				RunId:             "r1",
+				AccountId:         accountID,
+				Format:            "csv",
+				DateToleranceDays: 1,
+				PeriodStart:       day.AddDate(0, 0, -1),
+				PeriodEnd:         day.AddDate(0, 0, 1),
+				CreatedAt:         now,
+				Lines:             2,
+				Matched:           1,
+				MatchedLines: []reconciliation.LineDetails{
+					{LineId: "l1", LineNo: 2, BookedAt: day, Amount: 100, TransactionId: "t1", Match: "auto", MatchedAt: &now},
+				},
+				UnmatchedLines: []reconciliation.LineDetails{
+					{LineId: "l2", LineNo: 3, BookedAt: day, Amount: 7},
+				},
+				UnmatchedTransactions: []reconciliation.TransactionDetails{
+					{TransactionId: "t2", Type: "fee", Amount: 2, CreatedAt: day},
+				},
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "repository error",
+			req: reconciliation.ImportRequest{
+				AccountID: accountID,
+				Format:    reconciliation.CSV,
+				Lines:     []reconciliation.Line{{LineNo: 2, BookedAt: day, Amount: 100}},
+			},
+			mockFn: func(repo *mock.MockRepository) {
+				repo.EXPECT().Import(ctx, gomock.Any(), gomock.Any(), gomock.Any(), now).Return(assert.AnError)
+			},
+			wantErr: assert.Error,
+		},
+	}
+
+	for _, tc := range tests {
+		tt := tc
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := mock.NewMockRepository(ctrl)
+			tt.mockFn(repo)
+
+			s := reconciliation.NewService(repo, clock.Fixed(now))
+			got, err := s.Import(ctx, tt.req)
+			tt.wantErr(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMatchLine(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+
+	repo := mock.NewMockRepository(ctrl)
+	repo.EXPECT().MatchLine(ctx, "r1", "l1", "t1", now).Return(&reconciliation.Line{
+		ID: "l1", RunID: "r1", LineNo: 2, BookedAt: day, Amount: 7,
+		TransactionID: "t1", MatchKind: reconciliation.Manual, MatchedAt: &now,
+	}, nil)
+	repo.EXPECT().UnmatchLine(ctx, "r1", "l1").Return(&reconciliation.Line{
+		ID: "l1", RunID: "r1", LineNo: 2, BookedAt: day, Amount: 7,
+	}, nil)
+
+	s := reconciliation.NewService(repo, clock.Fixed(now))
+	line := reconciliation.LineRequest{RunID: "r1", LineID: "l1"}
+
+	got, err := s.Match(ctx, reconciliation.MatchRequest{LineRequest: line, TransactionID: "t1"})
+	require.NoError(t, err)
+	assert.Equal(t, &reconciliation.LineDetails{
+		LineId: "l1", LineNo: 2, BookedAt: day, Amount: 7, TransactionId: "t1", Match: "manual", MatchedAt: &now,
+	}, got)
+
+	got, err = s.Unmatch(ctx, line)
+	require.NoError(t, err)
+	assert.Equal(t, &reconciliation.LineDetails{LineId: "l1", LineNo: 2, BookedAt: day, Amount: 7}, got)
+}
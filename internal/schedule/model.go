@@ -0,0 +1,110 @@
+package schedule
+
+import (
+	"fmt"
+	"time"
+)
+
+// Frequency of a recurring schedule, empty for a one-off transfer.
+type Frequency string
+
+const (
+	Once    Frequency = ""
+	Daily   Frequency = "daily"
+	Weekly  Frequency = "weekly"
+	Monthly Frequency = "monthly"
+)
+
+type Status string
+
+const (
+	Active    Status = "active"
+	Cancelled Status = "cancelled"
+	Completed Status = "completed"
+)
+
+// Schedule is a standing order moving a fixed amount between two accounts.
+type Schedule struct {
+	ID            string
+	FromAccountID string
+	ToAccountID   string
+	Amount        float64
+	Description   string
+
+	// StartAt is the time of the first execution and the anchor of all following ones.
+	StartAt   time.Time
+	Frequency Frequency
+	// Interval is the number of frequency units between executions, e.g. every 2 weeks.
+	Interval int
+	// Until optionally ends the schedule, no execution happens after it.
+	Until *time.Time
+	// MaxRuns optionally limits the number of executions, 0 means unlimited.
+	MaxRuns int
+
+	Runs      int
+	NextRunAt *time.Time
+	Status    Status
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Occurrence returns the time of the n-th execution, starting from 0.
+// Monthly schedules keep the day of month of StartAt, clamped to the last day of shorter months,
+// e.g. a schedule starting on January 31st runs on February 28th and March 31st.
+func (s *Schedule) Occurrence(n int) time.Time {
+	interval := max(s.Interval, 1)
+	switch s.Frequency {
+	case Daily:
+		return s.StartAt.AddDate(0, 0, n*interval)
+	case Weekly:
+		return s.StartAt.AddDate(0, 0, 7*n*interval)
+	case Monthly:
+		y, m, d := s.StartAt.Date()
+		first := time.Date(y, m+time.Month(n*interval), 1,
+			s.StartAt.Hour(), s.StartAt.Minute(), s.StartAt.Second(), s.StartAt.Nanosecond(), s.StartAt.Location())
+		lastDay := first.AddDate(0, 1, -1).Day()
+		return first.AddDate(0, 0, min(d, lastDay)-1)
+	default:
+		return s.StartAt
+	}
+}
+
+// Advance records an execution and moves NextRunAt to the following occurrence,
+// completing the schedule when there is none.
+func (s *Schedule) Advance() {
+	s.Runs++
+
+	next := s.Occurrence(s.Runs)
+	if s.Frequency == Once ||
+		(s.MaxRuns > 0 && s.Runs >= s.MaxRuns) ||
+		(s.Until != nil && next.After(*s.Until)) {
+		s.NextRunAt = nil
+		s.Status = Completed
+		return
+	}
+
+	s.NextRunAt = &next
+}
+
+// Reference is the external reference of the transfer executed at the given time.
+// It makes executions idempotent, the same occurrence cannot be transferred twice.
+func (s *Schedule) Reference(runAt time.Time) string {
+	return fmt.Sprintf("schedule:%s:%d", s.ID, runAt.Unix())
+}
+
+type ExecutionStatus string
+
+const (
+	ExecutionSucceeded ExecutionStatus = "succeeded"
+	ExecutionFailed    ExecutionStatus = "failed"
+)
+
+// Execution is the outcome of a single scheduled transfer.
+type Execution struct {
+	ID          string
+	ScheduleID  string
+	ScheduledAt time.Time
+	ExecutedAt  time.Time
+	Status      ExecutionStatus
+	Error       string
+}
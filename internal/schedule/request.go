@@ -0,0 +1,19 @@
+package schedule
+
+import "time"
+
+type CreateRequest struct {
+	FromAccountID string     `json:"from_account_id" validate:"required,uuid"`
+	ToAccountID   string     `json:"to_account_id" validate:"required,uuid,nefield=FromAccountID"`
+	Amount        float64    `json:"amount" validate:"required,gt=0"`
+	Description   string     `json:"description" validate:"omitempty,max=255"`
+	StartAt       time.Time  `json:"start_at" validate:"required"`
+	Frequency     Frequency  `json:"frequency" validate:"omitempty,oneof=daily weekly monthly"`
+	Interval      int        `json:"interval" validate:"omitempty,min=1,max=365"`
+	Until         *time.Time `json:"until"`
+	Count         int        `json:"count" validate:"omitempty,min=1"`
+}
+
+type ListRequest struct {
+	AccountID string `validate:"required"`
+}
@@ -0,0 +1,31 @@
+package schedule
+
+import "time"
+
+type Details struct {
+	ScheduleId    string     `json:"schedule_id"`
+	FromAccountId string     `json:"from_account_id"`
+	ToAccountId   string     `json:"to_account_id"`
+	Amount        float64    `json:"amount"`
+	Description   string     `json:"description,omitempty"`
+	StartAt       time.Time  `json:"start_at"`
+	Frequency     string     `json:"frequency,omitempty"`
+	Interval      int        `json:"interval,omitempty"`
+	Until         *time.Time `json:"until,omitempty"`
+	Count         int        `json:"count,omitempty"`
+	Runs          int        `json:"runs"`
+	NextRunAt     *time.Time `json:"next_run_at,omitempty"`
+	Status        string     `json:"status"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+
+	Executions []ExecutionDetails `json:"executions,omitempty"`
+}
+
+type ExecutionDetails struct {
+	ExecutionId string    `json:"execution_id"`
+	ScheduledAt time.Time `json:"scheduled_at"`
+	ExecutedAt  time.Time `json:"executed_at"`
+	Status      string    `json:"status"`
+	Error       string    `json:"error,omitempty"`
+}
@@ -0,0 +1,214 @@
+//go:generate mockgen -source=service.go -destination=./mock/service.go -package=mock
+package schedule
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/softika/slogging"
+
+	"github.com/fmiskovic/cash-me-if-you-can/internal/transaction"
+	"github.com/fmiskovic/cash-me-if-you-can/pkg/errorx"
+)
+
+type Repository interface {
+	Create(context.Context, *Schedule) (*Schedule, error)
+	Get(context.Context, string) (*Schedule, error)
+	List(context.Context, ListRequest) ([]Schedule, error)
+	Cancel(context.Context, string) (*Schedule, error)
+	Executions(context.Context, string) ([]Execution, error)
+
+	// Claim reserves up to limit active schedules due at the given time until the lease expires,
+	// so other workers skip them. Schedules of a crashed worker are claimed again once the lease expires.
+	Claim(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]Schedule, error)
+	// Complete stores the execution, the advanced schedule and releases the claim.
+	Complete(context.Context, *Schedule, *Execution) error
+}
+
+// Transferer executes transfers, it is implemented by transaction.Service.
+type Transferer interface {
+	Transfer(context.Context, transaction.TransferRequest) (*transaction.TransferResponse, error)
+}
+
+type Service struct {
+	repo      Repository
+	transfers Transferer
+}
+
+func NewService(repo Repository, transfers Transferer) Service {
+	return Service{
+		repo:      repo,
+		transfers: transfers,
+	}
+}
+
+func (s Service) Create(ctx context.Context, req CreateRequest) (*Details, error) {
+	if req.Until != nil && req.Until.Before(req.StartAt) {
+		return nil, errorx.NewErrorMsg("until must not be before start_at", errorx.ErrInvalidInput)
+	}
+
+	startAt := req.StartAt.UTC()
+	input := &Schedule{
+		FromAccountID: req.FromAccountID,
+		ToAccountID:   req.ToAccountID,
+		Amount:        req.Amount,
+		Description:   req.Description,
+		StartAt:       startAt,
+		Frequency:     req.Frequency,
+		Interval:      max(req.Interval, 1),
+		Until:         req.Until,
+		MaxRuns:       req.Count,
+		NextRunAt:     &startAt,
+		Status:        Active,
+	}
+
+	sc, err := s.repo.Create(ctx, input)
+	if err != nil {
+		logger := slogging.Slogger()
+		logger.ErrorContext(ctx, "failed to create schedule", "error", err)
+		return nil, err
+	}
+
+	return newDetails(sc, nil), nil
+}
+
+func (s Service) Get(ctx context.Context, id string) (*Details, error) {
+	sc, err := s.repo.Get(ctx, id)
+	if err != nil {
+		logger := slogging.Slogger()
+		logger.ErrorContext(ctx, "failed to get schedule by id", "id", id, "error", err)
+		return nil, err
+	}
+
+	executions, err := s.repo.Executions(ctx, id)
+	if err != nil {
+		logger := slogging.Slogger()
+		logger.ErrorContext(ctx, "failed to get schedule executions", "id", id, "error", err)
+		return nil, err
+	}
+
+	return newDetails(sc, executions), nil
+}
+
+func (s Service) List(ctx context.Context, req ListRequest) ([]Details, error) {
+	schedules, err := s.repo.List(ctx, req)
+	if err != nil {
+		logger := slogging.Slogger()
+		logger.ErrorContext(ctx, "failed to get list of schedules", "error", err)
+		return nil, err
+	}
+
+	details := make([]Details, len(schedules))
+	for i := range schedules {
+		details[i] = *newDetails(&schedules[i], nil)
+	}
+
+	return details, nil
+}
+
+func (s Service) Cancel(ctx context.Context, id string) (*Details, error) {
+	sc, err := s.repo.Cancel(ctx, id)
+	if err != nil {
+		logger := slogging.Slogger()
+		logger.ErrorContext(ctx, "failed to cancel schedule", "id", id, "error", err)
+		return nil, err
+	}
+	return newDetails(sc, nil), nil
+}
+
+// ExecuteDue claims the schedules due at the given time and executes one occurrence of each.
+// It returns the number of executed schedules, including the ones which failed for business reasons.
+func (s Service) ExecuteDue(ctx context.Context, now time.Time, limit int, lease time.Duration) (int, error) {
+	logger := slogging.Slogger()
+
+	schedules, err := s.repo.Claim(ctx, now, limit, lease)
+	if err != nil {
+		logger.ErrorContext(ctx, "failed to claim due schedules", "error", err)
+		return 0, err
+	}
+
+	executed := 0
+	for i := range schedules {
+		sc := &schedules[i]
+		if err = s.execute(ctx, sc, now); err != nil {
+			// the claim expires and the schedule is retried by the next run
+			logger.ErrorContext(ctx, "failed to execute schedule", "id", sc.ID, "error", err)
+			continue
+		}
+		executed++
+	}
+
+	return executed, nil
+}
+
+func (s Service) execute(ctx context.Context, sc *Schedule, now time.Time) error {
+	runAt := *sc.NextRunAt
+
+	execution := &Execution{
+		ScheduleID:  sc.ID,
+		ScheduledAt: runAt,
+		ExecutedAt:  now,
+		Status:      ExecutionSucceeded,
+	}
+
+	_, err := s.transfers.Transfer(ctx, transaction.TransferRequest{
+		FromAccountID:     sc.FromAccountID,
+		ToAccountID:       sc.ToAccountID,
+		Amount:            sc.Amount,
+		Description:       sc.Description,
+		ExternalReference: sc.Reference(runAt),
+		Metadata:          map[string]any{"schedule_id": sc.ID},
+	})
+
+	var errx *errorx.Error
+	switch {
+	case err == nil:
+	case errors.As(err, &errx) && errx.Type == errorx.ErrConflict:
+		// already transferred by a previous attempt which did not complete
+	case errors.As(err, &errx) && (errx.Type == errorx.ErrInvalidInput || errx.Type == errorx.ErrNotFound):
+		// e.g. insufficient funds, the occurrence is skipped and recorded as failed
+		execution.Status = ExecutionFailed
+		execution.Error = err.Error()
+	default:
+		return err
+	}
+
+	sc.Advance()
+
+	return s.repo.Complete(ctx, sc, execution)
+}
+
+func newDetails(sc *Schedule, executions []Execution) *Details {
+	d := &Details{
+		ScheduleId:    sc.ID,
+		FromAccountId: sc.FromAccountID,
+		ToAccountId:   sc.ToAccountID,
+		Amount:        sc.Amount,
+		Description:   sc.Description,
+		StartAt:       sc.StartAt,
+		Frequency:     string(sc.Frequency),
+		Until:         sc.Until,
+		Count:         sc.MaxRuns,
+		Runs:          sc.Runs,
+		NextRunAt:     sc.NextRunAt,
+		Status:        string(sc.Status),
+		CreatedAt:     sc.CreatedAt,
+		UpdatedAt:     sc.UpdatedAt,
+	}
+	if sc.Frequency != Once {
+		d.Interval = sc.Interval
+	}
+
+	for _, e := range executions {
+		d.Executions = append(d.Executions, ExecutionDetails{
+			ExecutionId: e.ID,
+			ScheduledAt: e.ScheduledAt,
+			ExecutedAt:  e.ExecutedAt,
+			Status:      string(e.Status),
+			Error:       e.Error,
+		})
+	}
+
+	return d
+}
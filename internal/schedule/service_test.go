@@ -0,0 +1,330 @@
+package schedule_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/fmiskovic/cash-me-if-you-can/internal/schedule"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/schedule/mock"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/transaction"
+	"github.com/fmiskovic/cash-me-if-you-can/pkg/errorx"
+)
+
+func TestScheduleOccurrence(t *testing.T) {
+	t.Parallel()
+
+	startAt := time.Date(2024, time.January, 31, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		frequency schedule.Frequency
+		interval  int
+		n         int
+		want      time.Time
+	}{
+		{
+			name: "one-off transfer",
+			n:    3,
+			want: startAt,
+		},
+		{
+			name:      "every 2 days",
+			frequency: schedule.Daily,
+			interval:  2,
+			n:         1,
+			want:      time.Date(2024, time.February, 2, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "weekly",
+			frequency: schedule.Weekly,
+			interval:  1,
+			n:         2,
+			want:      time.Date(2024, time.February, 14, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "monthly clamped to the end of february",
+			frequency: schedule.Monthly,
+			interval:  1,
+			n:         1,
+			want:      time.Date(2024, time.February, 29, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "monthly keeps the day of start",
+			frequency: schedule.Monthly,
+			interval:  1,
+			n:         2,
+			want:      time.Date(2024, time.March, 31, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "quarterly over the year end",
+			frequency: schedule.Monthly,
+			interval:  3,
+			n:         4,
+			want:      time.Date(2025, time.January, 31, 9, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tc := range tests {
+		tt := tc
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := schedule.Schedule{StartAt: startAt, Frequency: tt.frequency, Interval: tt.interval}
+			assert.Equal(t, tt.want, s.Occurrence(tt.n))
+		})
+	}
+}
+
+func TestScheduleAdvance(t *testing.T) {
+	t.Parallel()
+
+	startAt := time.Date(2024, time.January, 1, 9, 0, 0, 0, time.UTC)
+	until := startAt.AddDate(0, 0, 2)
+
+	tests := []struct {
+		name       string
+		schedule   schedule.Schedule
+		wantNext   *time.Time
+		wantStatus schedule.Status
+	}{
+		{
+			name:       "one-off transfer completes",
+			schedule:   schedule.Schedule{StartAt: startAt, Status: schedule.Active},
+			wantStatus: schedule.Completed,
+		},
+		{
+			name:       "daily moves to the next day",
+			schedule:   schedule.Schedule{StartAt: startAt, Frequency: schedule.Daily, Interval: 1, Status: schedule.Active},
+			wantNext:   ptr(startAt.AddDate(0, 0, 1)),
+			wantStatus: schedule.Active,
+		},
+		{
+			name:       "completes after max runs",
+			schedule:   schedule.Schedule{StartAt: startAt, Frequency: schedule.Daily, Interval: 1, MaxRuns: 1, Status: schedule.Active},
+			wantStatus: schedule.Completed,
+		},
+		{
+			name: "completes when next run is after until",
+			schedule: schedule.Schedule{
+				StartAt: startAt, Frequency: schedule.Daily, Interval: 2, Until: &until, Runs: 1, Status: schedule.Active,
+			},
+			wantStatus: schedule.Completed,
+		},
+	}
+
+	for _, tc := range tests {
+		tt := tc
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := tt.schedule
+			runs := s.Runs
+			s.Advance()
+
+			assert.Equal(t, runs+1, s.Runs)
+			assert.Equal(t, tt.wantNext, s.NextRunAt)
+			assert.Equal(t, tt.wantStatus, s.Status)
+		})
+	}
+}
+
+func TestScheduleCreate(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+
+	startAt := time.Date(2024, time.January, 1, 9, 0, 0, 0, time.UTC)
+	before := startAt.Add(-time.Hour)
+
+	tests := []struct {
+		name    string
+		req     schedule.CreateRequest
+		mockFn  func(*mock.MockRepository)
+		want    *schedule.Details
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name: "create monthly schedule",
+			req: schedule.CreateRequest{
+				FromAccountID: "1",
+				ToAccountID:   "2",
+				Amount:        10,
+				StartAt:       startAt,
+				Frequency:     schedule.Monthly,
+			},
+			mockFn: func(repo *mock.MockRepository) {
+				repo.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, s *schedule.Schedule) (*schedule.Schedule, error) {
+					assert.Equal(t, 1, s.Interval)
+					assert.Equal(t, schedule.Active, s.Status)
+					s.ID = "schedule-1"
+					return s, nil
+				})
+			},
+			want: &schedule.Details{
+				ScheduleId:    "schedule-1",
+				FromAccountId: "1",
+				ToAccountId:   "2",
+				Amount:        10,
+				StartAt:       startAt,
+				Frequency:     string(schedule.Monthly),
+				Interval:      1,
+				NextRunAt:     &startAt,
+				Status:        string(schedule.Active),
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "until before start",
+			req: schedule.CreateRequest{
+				FromAccountID: "1",
+				ToAccountID:   "2",
+				Amount:        10,
+				StartAt:       startAt,
+				Until:         &before,
+			},
+			mockFn:  func(repo *mock.MockRepository) {},
+			wantErr: assert.Error,
+		},
+	}
+
+	for _, tc := range tests {
+		tt := tc
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := mock.NewMockRepository(ctrl)
+			tt.mockFn(repo)
+
+			s := schedule.NewService(repo, mock.NewMockTransferer(ctrl))
+			got, err := s.Create(ctx, tt.req)
+			tt.wantErr(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestScheduleExecuteDue(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+
+	now := time.Date(2024, time.January, 1, 10, 0, 0, 0, time.UTC)
+	runAt := now.Add(-time.Hour)
+	lease := time.Minute
+
+	due := func() schedule.Schedule {
+		return schedule.Schedule{
+			ID:            "schedule-1",
+			FromAccountID: "1",
+			ToAccountID:   "2",
+			Amount:        10,
+			StartAt:       runAt,
+			Frequency:     schedule.Daily,
+			Interval:      1,
+			NextRunAt:     &runAt,
+			Status:        schedule.Active,
+		}
+	}
+
+	tests := []struct {
+		name       string
+		transferFn func(*mock.MockTransferer)
+		completeFn func(*mock.MockRepository)
+		want       int
+	}{
+		{
+			name: "successful execution",
+			transferFn: func(tr *mock.MockTransferer) {
+				tr.EXPECT().Transfer(ctx, gomock.Any()).DoAndReturn(
+					func(_ context.Context, req transaction.TransferRequest) (*transaction.TransferResponse, error) {
+						assert.Equal(t, "1", req.FromAccountID)
+						assert.Equal(t, "2", req.ToAccountID)
+						assert.Equal(t, 10.0, req.Amount)
+						assert.Equal(t, fmt.Sprintf("schedule:schedule-1:%d", runAt.Unix()), req.ExternalReference)
+						return &transaction.TransferResponse{}, nil
+					})
+			},
+			completeFn: func(repo *mock.MockRepository) {
+				repo.EXPECT().Complete(ctx, gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ context.Context, s *schedule.Schedule, e *schedule.Execution) error {
+						assert.Equal(t, 1, s.Runs)
+						assert.Equal(t, runAt.AddDate(0, 0, 1), *s.NextRunAt)
+						assert.Equal(t, schedule.ExecutionSucceeded, e.Status)
+						assert.Equal(t, runAt, e.ScheduledAt)
+						return nil
+					})
+			},
+			want: 1,
+		},
+		{
+			name: "already transferred occurrence is completed",
+			transferFn: func(tr *mock.MockTransferer) {
+				tr.EXPECT().Transfer(ctx, gomock.Any()).Return(nil, errorx.NewErrorMsg("duplicate", errorx.ErrConflict))
+			},
+			completeFn: func(repo *mock.MockRepository) {
+				repo.EXPECT().Complete(ctx, gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ context.Context, _ *schedule.Schedule, e *schedule.Execution) error {
+						assert.Equal(t, schedule.ExecutionSucceeded, e.Status)
+						return nil
+					})
+			},
+			want: 1,
+		},
+		{
+			name: "insufficient funds records failed execution",
+			transferFn: func(tr *mock.MockTransferer) {
+				tr.EXPECT().Transfer(ctx, gomock.Any()).
+					Return(nil, errorx.NewErrorMsg("transfer failed - insufficient funds", errorx.ErrInvalidInput))
+			},
+			completeFn: func(repo *mock.MockRepository) {
+				repo.EXPECT().Complete(ctx, gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ context.Context, s *schedule.Schedule, e *schedule.Execution) error {
+						assert.Equal(t, 1, s.Runs)
+						assert.Equal(t, schedule.ExecutionFailed, e.Status)
+						assert.Equal(t, "transfer failed - insufficient funds", e.Error)
+						return nil
+					})
+			},
+			want: 1,
+		},
+		{
+			name: "unexpected error leaves the schedule claimed for a retry",
+			transferFn: func(tr *mock.MockTransferer) {
+				tr.EXPECT().Transfer(ctx, gomock.Any()).Return(nil, errors.New("connection refused"))
+			},
+			completeFn: func(repo *mock.MockRepository) {},
+			want:       0,
+		},
+	}
+
+	for _, tc := range tests {
+		tt := tc
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := mock.NewMockRepository(ctrl)
+			repo.EXPECT().Claim(ctx, now, 10, lease).Return([]schedule.Schedule{due()}, nil)
+			tt.completeFn(repo)
+
+			tr := mock.NewMockTransferer(ctrl)
+			tt.transferFn(tr)
+
+			s := schedule.NewService(repo, tr)
+			got, err := s.ExecuteDue(ctx, now, 10, lease)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,35 @@
+package seed
+
+// ResettableEnvironments are the environments whose data can be reset, compared case-insensitively.
+// Every other environment is refused, so a misspelled production environment is never wiped.
+var ResettableEnvironments = []string{"local", "dev", "test"}
+
+// Report of the generated demo data.
+type Report struct {
+	Accounts    int
+	Deposits    int
+	Withdrawals int
+	Transfers   int
+	// Rejected operations, for example because a fee left too little funds.
+	Rejected int
+}
+
+var firstNames = []string{
+	"Olivia", "Liam", "Emma", "Noah", "Amelia", "Oliver", "Sophia", "Elijah", "Isabella", "Lucas",
+	"Mia", "Mateo", "Charlotte", "Levi", "Ava", "Ethan", "Harper", "James", "Luna", "Henry",
+	"Aiko", "Kenji", "Priya", "Arjun", "Fatima", "Omar", "Ingrid", "Lars", "Chiara", "Marco",
+	"Zofia", "Mateusz", "Amara", "Kwame", "Lucía", "Diego", "Ana", "Ivan", "Mei", "Wei",
+}
+
+var lastNames = []string{
+	"Smith", "Johnson", "Williams", "Brown", "Jones", "Garcia", "Miller", "Davis", "Rodriguez", "Martinez",
+	"Hernandez", "Lopez", "Wilson", "Anderson", "Thomas", "Taylor", "Moore", "Jackson", "Martin", "Lee",
+	"Tanaka", "Sato", "Sharma", "Patel", "Haddad", "Nasser", "Larsen", "Johansson", "Rossi", "Bianchi",
+	"Nowak", "Kowalski", "Okafor", "Mensah", "Fernández", "Torres", "Horvat", "Petrov", "Chen", "Wang",
+}
+
+var depositDescriptions = []string{"Salary", "Freelance invoice", "Tax refund", "Cash deposit", "Gift", "Dividends"}
+
+var withdrawalDescriptions = []string{"Groceries", "Rent", "Utilities", "Restaurant", "Fuel", "Cash withdrawal", "Insurance"}
+
+var transferDescriptions = []string{"Shared dinner", "Loan repayment", "Birthday present", "Concert tickets", "Split rent"}
@@ -0,0 +1,14 @@
+package seed
+
+// Request of demo data. Requests with the same seed against the same data generate the same operations.
+type Request struct {
+	Customers int `validate:"gte=1,lte=10000"`
+	// Transactions is the number of deposits, withdrawals and transfers made between the customers.
+	Transactions int `validate:"gte=0,lte=1000000"`
+	Seed         int64
+}
+
+// ResetRequest deletes the data of the environment.
+type ResetRequest struct {
+	Environment string
+}
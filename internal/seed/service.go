@@ -0,0 +1,209 @@
+//go:generate mockgen -source=service.go -destination=./mock/service.go -package=mock
+package seed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"strings"
+
+	"github.com/fmiskovic/cash-me-if-you-can/internal/account"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/transaction"
+	"github.com/fmiskovic/cash-me-if-you-can/pkg/errorx"
+)
+
+type Repository interface {
+	// Reset deletes all accounts, their transactions and the data derived from them.
+	// Reference data such as products is kept.
+	Reset(context.Context) error
+}
+
+// AccountService creates the accounts of the customers, so the accounts are created like by the API.
+type AccountService interface {
+	Create(context.Context, account.CreateRequest) (*account.Details, error)
+}
+
+// TransactionService books the operations, so balances, fees and the ledger stay consistent.
+type TransactionService interface {
+	Create(context.Context, transaction.CreateRequest) (*transaction.Details, error)
+	Transfer(context.Context, transaction.TransferRequest) (*transaction.TransferResponse, error)
+}
+
+type Service struct {
+	repo         Repository
+	accounts     AccountService
+	transactions TransactionService
+}
+
+func NewService(repo Repository, accounts AccountService, transactions TransactionService) Service {
+	return Service{
+		repo:         repo,
+		accounts:     accounts,
+		transactions: transactions,
+	}
+}
+
+// maxOwnerAttempts is the number of random names tried for a customer before seeding fails.
+const maxOwnerAttempts = 10
+
+// Seed creates the customers' accounts and makes random deposits, withdrawals and transfers between them.
+// Withdrawals and transfers never exceed the funds of an account. Operations rejected as invalid,
+// for example because a fee left too little funds, are counted and skipped, other errors stop seeding.
+func (s Service) Seed(ctx context.Context, req Request) (*Report, error) {
+	rng := rand.New(rand.NewPCG(uint64(req.Seed), 0))
+	report := &Report{}
+
+	ids := make([]string, 0, req.Customers)
+	balances := make([]float64, 0, req.Customers)
+	for range req.Customers {
+		acc, err := s.createAccount(ctx, rng)
+		if err != nil {
+			return report, err
+		}
+		ids = append(ids, acc.AccountId)
+		balances = append(balances, acc.Balance)
+		report.Accounts++
+	}
+
+	for range req.Transactions {
+		i := rng.IntN(len(ids))
+
+		var err error
+		switch p := rng.Float64(); {
+		case p < 0.4 || balances[i] < 1:
+			err = s.deposit(ctx, rng, ids[i], &balances[i])
+			if err == nil {
+				report.Deposits++
+			}
+		case p < 0.7 || len(ids) == 1:
+			err = s.withdraw(ctx, rng, ids[i], &balances[i])
+			if err == nil {
+				report.Withdrawals++
+			}
+		default:
+			// any other account, the offset skips the account itself
+			j := (i + 1 + rng.IntN(len(ids)-1)) % len(ids)
+			err = s.transfer(ctx, rng, ids[i], ids[j], &balances[i], &balances[j])
+			if err == nil {
+				report.Transfers++
+			}
+		}
+
+		if rejected(err) {
+			report.Rejected++
+			continue
+		}
+		if err != nil {
+			return report, err
+		}
+	}
+
+	return report, nil
+}
+
+// Reset deletes the data of the environment, it is refused unless the environment is one of ResettableEnvironments.
+func (s Service) Reset(ctx context.Context, req ResetRequest) error {
+	resettable := slices.ContainsFunc(ResettableEnvironments, func(env string) bool {
+		return strings.EqualFold(env, strings.TrimSpace(req.Environment))
+	})
+	if !resettable {
+		return errorx.NewError(
+			fmt.Errorf("data of the %q environment can not be reset, only %s", req.Environment, strings.Join(ResettableEnvironments, ", ")),
+			errorx.ErrForbidden,
+		)
+	}
+
+	return s.repo.Reset(ctx)
+}
+
+// createAccount creates an account with an initial deposit, owners are unique so taken names are retried.
+func (s Service) createAccount(ctx context.Context, rng *rand.Rand) (*account.Details, error) {
+	req := account.CreateRequest{
+		Balance: amount(rng, 100, 5000),
+		Product: account.DefaultProduct,
+	}
+	if rng.IntN(5) == 0 {
+		req.Product = "savings"
+	}
+
+	for range maxOwnerAttempts {
+		req.Owner = pick(rng, firstNames) + " " + pick(rng, lastNames)
+
+		acc, err := s.accounts.Create(ctx, req)
+		if !rejected(err) {
+			return acc, err
+		}
+	}
+	return nil, fmt.Errorf("no free owner name found in %d attempts", maxOwnerAttempts)
+}
+
+func (s Service) deposit(ctx context.Context, rng *rand.Rand, id string, balance *float64) error {
+	t, err := s.transactions.Create(ctx, transaction.CreateRequest{
+		AccountID:   id,
+		Type:        transaction.Deposit,
+		Amount:      amount(rng, 10, 2000),
+		Description: pick(rng, depositDescriptions),
+	})
+	if err != nil {
+		return err
+	}
+	*balance += t.Amount - fee(t)
+	return nil
+}
+
+func (s Service) withdraw(ctx context.Context, rng *rand.Rand, id string, balance *float64) error {
+	t, err := s.transactions.Create(ctx, transaction.CreateRequest{
+		AccountID:   id,
+		Type:        transaction.Withdrawal,
+		Amount:      amount(rng, 1, *balance*0.3),
+		Description: pick(rng, withdrawalDescriptions),
+	})
+	if err != nil {
+		return err
+	}
+	*balance -= t.Amount + fee(t)
+	return nil
+}
+
+func (s Service) transfer(ctx context.Context, rng *rand.Rand, from, to string, fromBalance, toBalance *float64) error {
+	res, err := s.transactions.Transfer(ctx, transaction.TransferRequest{
+		FromAccountID: from,
+		ToAccountID:   to,
+		Amount:        amount(rng, 1, *fromBalance*0.2),
+		Description:   pick(rng, transferDescriptions),
+	})
+	if err != nil {
+		return err
+	}
+	*fromBalance -= res.Amount + res.Fee
+	*toBalance += res.Amount
+	return nil
+}
+
+// rejected reports whether the operation was refused as invalid input.
+func rejected(err error) bool {
+	var errx *errorx.Error
+	return errors.As(err, &errx) && errx.Type == errorx.ErrInvalidInput
+}
+
+func fee(t *transaction.Details) float64 {
+	if t.Fee == nil {
+		return 0
+	}
+	return t.Fee.Amount
+}
+
+// amount returns a random amount in cents between min and max, at least one cent.
+func amount(rng *rand.Rand, min, max float64) float64 {
+	if max < min {
+		max = min
+	}
+	return math.Max(0.01, math.Round((min+rng.Float64()*(max-min))*100)/100)
+}
+
+func pick(rng *rand.Rand, values []string) string {
+	return values[rng.IntN(len(values))]
+}
@@ -0,0 +1,270 @@
+package seed_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/fmiskovic/cash-me-if-you-can/internal/account"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/seed"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/seed/mock"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/transaction"
+	"github.com/fmiskovic/cash-me-if-you-can/pkg/errorx"
+)
+
+// recorder books the requests of a seed run against in-memory balances, failing like the services would.
+type recorder struct {
+	calls    []any
+	balances map[string]float64
+	owners   map[string]bool
+}
+
+func newRecorder() *recorder {
+	return &recorder{balances: map[string]float64{}, owners: map[string]bool{}}
+}
+
+func (r *recorder) expect(accounts *mock.MockAccountService, transactions *mock.MockTransactionService) {
+	accounts.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, req account.CreateRequest) (*account.Details, error) {
+			r.calls = append(r.calls, req)
+			if r.owners[req.Owner] {
+				return nil, errorx.NewError(fmt.Errorf("account with owner %s already exists", req.Owner), errorx.ErrInvalidInput)
+			}
+			r.owners[req.Owner] = true
+
+			id := fmt.Sprintf("account-%d", len(r.balances))
+			r.balances[id] = req.Balance
+			return &account.Details{AccountId: id, Owner: req.Owner, Balance: req.Balance}, nil
+		}).AnyTimes()
+
+	transactions.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, req transaction.CreateRequest) (*transaction.Details, error) {
+			r.calls = append(r.calls, req)
+			amount := req.Amount
+			if req.Type == transaction.Withdrawal {
+				amount = -amount
+			}
+			if err := r.book(req.AccountID, amount); err != nil {
+				return nil, err
+			}
+			return &transaction.Details{AccountId: req.AccountID, Type: string(req.Type), Amount: req.Amount}, nil
+		}).AnyTimes()
+
+	transactions.EXPECT().Transfer(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, req transaction.TransferRequest) (*transaction.TransferResponse, error) {
+			r.calls = append(r.calls, req)
+			if req.FromAccountID == req.ToAccountID {
+				return nil, errors.New("transfer to the same account")
+			}
+			if err := r.book(req.FromAccountID, -req.Amount); err != nil {
+				return nil, err
+			}
+			_ = r.book(req.ToAccountID, req.Amount)
+			return &transaction.TransferResponse{FromAccountId: req.FromAccountID, ToAccountId: req.ToAccountID, Amount: req.Amount}, nil
+		}).AnyTimes()
+}
+
+func (r *recorder) book(id string, amount float64) error {
+	if amount <= 0 && r.balances[id]+amount < 0 {
+		return errorx.NewError(errors.New("insufficient funds"), errorx.ErrInvalidInput)
+	}
+	r.balances[id] += amount
+	return nil
+}
+
+func run(t *testing.T, req seed.Request) (*seed.Report, *recorder) {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	accounts := mock.NewMockAccountService(ctrl)
+	transactions := mock.NewMockTransactionService(ctrl)
+
+	rec := newRecorder()
+	rec.expect(accounts, transactions)
+
+	svc := seed.NewService(mock.NewMockRepository(ctrl), accounts, transactions)
+	report, err := svc.Seed(context.Background(), req)
+	require.NoError(t, err)
+	return report, rec
+}
+
+func TestSeed(t *testing.T) {
+	t.Parallel()
+
+	req := seed.Request{Customers: 30, Transactions: 500, Seed: 42}
+	report, rec := run(t, req)
+
+	assert.Equal(t, 30, report.Accounts)
+	assert.Equal(t, 500, report.Deposits+report.Withdrawals+report.Transfers+report.Rejected)
+	assert.Positive(t, report.Deposits)
+	assert.Positive(t, report.Withdrawals)
+	assert.Positive(t, report.Transfers)
+	// withdrawals and transfers are limited to the funds of the account
+	assert.Zero(t, report.Rejected)
+
+	for id, balance := range rec.balances {
+		assert.GreaterOrEqual(t, balance, 0.0, id)
+	}
+
+	// the same seed generates the same requests, another one different requests
+	_, again := run(t, req)
+	assert.Equal(t, rec.calls, again.calls)
+
+	req.Seed = 7
+	_, other := run(t, req)
+	assert.NotEqual(t, rec.calls, other.calls)
+}
+
+func TestSeedErrors(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+
+	tests := []struct {
+		name         string
+		transactions int
+		mockFn       func(*mock.MockAccountService, *mock.MockTransactionService)
+		want         *seed.Report
+		wantErr      assert.ErrorAssertionFunc
+	}{
+		{
+			name: "taken owner names are retried",
+			mockFn: func(accounts *mock.MockAccountService, _ *mock.MockTransactionService) {
+				gomock.InOrder(
+					accounts.EXPECT().Create(ctx, gomock.Any()).
+						Return(nil, errorx.NewError(errors.New("owner taken"), errorx.ErrInvalidInput)).Times(2),
+					accounts.EXPECT().Create(ctx, gomock.Any()).Return(&account.Details{AccountId: "a"}, nil),
+				)
+			},
+			want:    &seed.Report{Accounts: 1},
+			wantErr: assert.NoError,
+		},
+		{
+			name:         "rejected operations are counted",
+			transactions: 3,
+			mockFn: func(accounts *mock.MockAccountService, transactions *mock.MockTransactionService) {
+				accounts.EXPECT().Create(ctx, gomock.Any()).Return(&account.Details{AccountId: "a", Balance: 100}, nil)
+				transactions.EXPECT().Create(ctx, gomock.Any()).
+					Return(nil, errorx.NewError(errors.New("insufficient funds"), errorx.ErrInvalidInput)).Times(3)
+			},
+			want:    &seed.Report{Accounts: 1, Rejected: 3},
+			wantErr: assert.NoError,
+		},
+		{
+			name:         "other errors stop seeding",
+			transactions: 3,
+			mockFn: func(accounts *mock.MockAccountService, transactions *mock.MockTransactionService) {
+				accounts.EXPECT().Create(ctx, gomock.Any()).Return(&account.Details{AccountId: "a", Balance: 100}, nil)
+				transactions.EXPECT().Create(ctx, gomock.Any()).Return(nil, assert.AnError)
+			},
+			want:    &seed.Report{Accounts: 1},
+			wantErr: assert.Error,
+		},
+		{
+			name: "no free owner name",
+			mockFn: func(accounts *mock.MockAccountService, _ *mock.MockTransactionService) {
+				accounts.EXPECT().Create(ctx, gomock.Any()).
+					Return(nil, errorx.NewError(errors.New("owner taken"), errorx.ErrInvalidInput)).Times(10)
+			},
+			want:    &seed.Report{},
+			wantErr: assert.Error,
+		},
+	}
+
+	for _, tc := range tests {
+		tt := tc
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			accounts := mock.NewMockAccountService(ctrl)
+			transactions := mock.NewMockTransactionService(ctrl)
+			tt.mockFn(accounts, transactions)
+
+			// a single customer can not transfer, every operation is a deposit or a withdrawal
+			svc := seed.NewService(mock.NewMockRepository(ctrl), accounts, transactions)
+			got, err := svc.Seed(ctx, seed.Request{Customers: 1, Transactions: tt.transactions, Seed: 1})
+			tt.wantErr(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestReset(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+
+	forbidden := func(t assert.TestingT, err error, _ ...any) bool {
+		var errx *errorx.Error
+		return assert.ErrorAs(t, err, &errx) && assert.Equal(t, errorx.ErrForbidden, errx.Type)
+	}
+	reset := func(repo *mock.MockRepository) {
+		repo.EXPECT().Reset(ctx).Return(nil)
+	}
+
+	tests := []struct {
+		name        string
+		environment string
+		mockFn      func(*mock.MockRepository)
+		wantErr     assert.ErrorAssertionFunc
+	}{
+		{
+			name:        "resets local data",
+			environment: "local",
+			mockFn:      reset,
+			wantErr:     assert.NoError,
+		},
+		{
+			name:        "environments are compared case-insensitively",
+			environment: "Dev",
+			mockFn:      reset,
+			wantErr:     assert.NoError,
+		},
+		{
+			name:        "refused in production",
+			environment: "production",
+			mockFn:      func(*mock.MockRepository) {},
+			wantErr:     forbidden,
+		},
+		{
+			name:        "refused in any spelling of production",
+			environment: "PROD",
+			mockFn:      func(*mock.MockRepository) {},
+			wantErr:     forbidden,
+		},
+		{
+			name:        "refused in unknown environments",
+			environment: "staging",
+			mockFn:      func(*mock.MockRepository) {},
+			wantErr:     forbidden,
+		},
+		{
+			name:        "repository error",
+			environment: "test",
+			mockFn: func(repo *mock.MockRepository) {
+				repo.EXPECT().Reset(ctx).Return(assert.AnError)
+			},
+			wantErr: assert.Error,
+		},
+	}
+
+	for _, tc := range tests {
+		tt := tc
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := mock.NewMockRepository(ctrl)
+			tt.mockFn(repo)
+
+			svc := seed.NewService(repo, mock.NewMockAccountService(ctrl), mock.NewMockTransactionService(ctrl))
+			tt.wantErr(t, svc.Reset(ctx, seed.ResetRequest{Environment: tt.environment}))
+		})
+	}
+}
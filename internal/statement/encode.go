@@ -0,0 +1,322 @@
+package statement
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Write encodes the statement in its format, entries are written as they are read.
+func Write(w io.Writer, st *Statement) error {
+	switch st.Format {
+	case CSV:
+		return writeCSV(w, st)
+	case CAMT053:
+		return writeCAMT053(w, st)
+	default:
+		return writeJSON(w, st)
+	}
+}
+
+func formatAmount(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', -1, 64)
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+func signedAmount(e Entry) float64 {
+	if e.Credit() {
+		return e.Amount
+	}
+	return -e.Amount
+}
+
+var csvHeader = []string{
+	"record", "booked_at", "transaction_id", "type", "description",
+	"counterparty", "external_reference", "amount", "balance",
+}
+
+// writeCSV writes the opening balance, one record per entry with signed amounts and the closing balance.
+func writeCSV(w io.Writer, st *Statement) error {
+	cw := csv.NewWriter(w)
+
+	_ = cw.Write(csvHeader)
+	_ = cw.Write([]string{"opening", formatTime(st.From), "", "", "", "", "", "", formatAmount(st.OpeningBalance)})
+
+	err := st.Entries(func(e Entry) error {
+		return cw.Write([]string{
+			"entry",
+			formatTime(e.Timestamp),
+			e.ID,
+			string(e.Type),
+			e.Description,
+			e.Counterparty,
+			e.ExternalReference,
+			formatAmount(signedAmount(e)),
+			formatAmount(e.Balance),
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	_ = cw.Write([]string{"closing", formatTime(st.To), "", "", "", "", "", "", formatAmount(st.ClosingBalance)})
+	cw.Flush()
+	return cw.Error()
+}
+
+type jsonStatement struct {
+	AccountId      string    `json:"account_id"`
+	Currency       string    `json:"currency"`
+	From           time.Time `json:"from"`
+	To             time.Time `json:"to"`
+	OpeningBalance float64   `json:"opening_balance"`
+	ClosingBalance float64   `json:"closing_balance"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+type jsonEntry struct {
+	TransactionId     string    `json:"transaction_id"`
+	Type              string    `json:"type"`
+	Amount            float64   `json:"amount"`
+	Balance           float64   `json:"balance"`
+	Description       string    `json:"description,omitempty"`
+	Counterparty      string    `json:"counterparty,omitempty"`
+	ExternalReference string    `json:"external_reference,omitempty"`
+	BookedAt          time.Time `json:"booked_at"`
+}
+
+// writeJSON writes the statement as a single object with the entries array written last.
+func writeJSON(w io.Writer, st *Statement) error {
+	header, err := json.Marshal(jsonStatement{
+		AccountId:      st.AccountID,
+		Currency:       st.Currency,
+		From:           st.From,
+		To:             st.To,
+		OpeningBalance: st.OpeningBalance,
+		ClosingBalance: st.ClosingBalance,
+		CreatedAt:      st.CreatedAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	// reopen the header object to append the entries
+	if _, err = w.Write(header[:len(header)-1]); err != nil {
+		return err
+	}
+	if _, err = io.WriteString(w, `,"entries":[`); err != nil {
+		return err
+	}
+
+	first := true
+	err = st.Entries(func(e Entry) error {
+		if !first {
+			if _, err := io.WriteString(w, ","); err != nil {
+				return err
+			}
+		}
+		first = false
+
+		b, err := json.Marshal(jsonEntry{
+			TransactionId:     e.ID,
+			Type:              string(e.Type),
+			Amount:            signedAmount(e),
+			Balance:           e.Balance,
+			Description:       e.Description,
+			Counterparty:      e.Counterparty,
+			ExternalReference: e.ExternalReference,
+			BookedAt:          e.Timestamp,
+		})
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(b)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w, "]}\n")
+	return err
+}
+
+const camt053Namespace = "urn:iso:std:iso:20022:tech:xsd:camt.053.001.02"
+
+type camtAmount struct {
+	Currency string `xml:"Ccy,attr"`
+	Value    string `xml:",chardata"`
+}
+
+type camtDateTime struct {
+	DateTime time.Time `xml:"DtTm"`
+}
+
+type camtAccount struct {
+	Id       string `xml:"Id>Othr>Id"`
+	Currency string `xml:"Ccy"`
+}
+
+type camtBalance struct {
+	Code      string       `xml:"Tp>CdOrPrtry>Cd"`
+	Amount    camtAmount   `xml:"Amt"`
+	Indicator string       `xml:"CdtDbtInd"`
+	Date      camtDateTime `xml:"Dt"`
+}
+
+type camtParty struct {
+	Name string `xml:"Nm"`
+}
+
+type camtEntry struct {
+	Amount      camtAmount   `xml:"Amt"`
+	Indicator   string       `xml:"CdtDbtInd"`
+	Status      string       `xml:"Sts"`
+	BookingDate camtDateTime `xml:"BookgDt"`
+	ValueDate   camtDateTime `xml:"ValDt"`
+	Reference   string       `xml:"AcctSvcrRef"`
+	Code        string       `xml:"BkTxCd>Prtry>Cd"`
+	Details     struct {
+		EndToEndId string     `xml:"Refs>EndToEndId"`
+		Debtor     *camtParty `xml:"RltdPties>Dbtr,omitempty"`
+		Creditor   *camtParty `xml:"RltdPties>Cdtr,omitempty"`
+		Remittance string     `xml:"RmtInf>Ustrd,omitempty"`
+	} `xml:"NtryDtls>TxDtls"`
+}
+
+// camtText truncates s to the maximum length of a camt.053 text field.
+func camtText(s string, size int) string {
+	r := []rune(s)
+	if len(r) > size {
+		return string(r[:size])
+	}
+	return s
+}
+
+// camtId compacts a UUID to fit the 35 characters of camt.053 identifiers.
+func camtId(id string) string {
+	return camtText(strings.ReplaceAll(id, "-", ""), 35)
+}
+
+// camtAmountOf returns the absolute amount with at most five decimals and its credit or debit indicator.
+func camtAmountOf(amount float64, currency string) (camtAmount, string) {
+	indicator := "CRDT"
+	if amount < 0 {
+		indicator = "DBIT"
+	}
+	value := math.Round(math.Abs(amount)*1e5) / 1e5
+	return camtAmount{Currency: currency, Value: formatAmount(value)}, indicator
+}
+
+func camtBalanceOf(code string, amount float64, currency string, date time.Time) camtBalance {
+	amt, indicator := camtAmountOf(amount, currency)
+	return camtBalance{Code: code, Amount: amt, Indicator: indicator, Date: camtDateTime{DateTime: date.UTC()}}
+}
+
+type camtGroupHeader struct {
+	MessageId string    `xml:"MsgId"`
+	CreatedAt time.Time `xml:"CreDtTm"`
+}
+
+type camtPeriod struct {
+	From time.Time `xml:"FrDtTm"`
+	To   time.Time `xml:"ToDtTm"`
+}
+
+// writeCAMT053 writes the statement as an ISO 20022 camt.053.001.02 document with a single account statement.
+func writeCAMT053(w io.Writer, st *Statement) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+
+	element := func(name string) xml.StartElement {
+		return xml.StartElement{Name: xml.Name{Local: name}}
+	}
+	encode := func(name string, v any) error {
+		return enc.EncodeElement(v, element(name))
+	}
+
+	document := element("Document")
+	document.Attr = []xml.Attr{{Name: xml.Name{Local: "xmlns"}, Value: camt053Namespace}}
+	report := element("BkToCstmrStmt")
+	statement := element("Stmt")
+
+	createdAt := st.CreatedAt.UTC()
+
+	// the statement header is written before the entries are read
+	header := []func() error{
+		func() error { return enc.EncodeToken(document) },
+		func() error { return enc.EncodeToken(report) },
+		func() error {
+			return encode("GrpHdr", camtGroupHeader{
+				MessageId: camtText(createdAt.Format("20060102150405")+camtId(st.AccountID), 35),
+				CreatedAt: createdAt,
+			})
+		},
+		func() error { return enc.EncodeToken(statement) },
+		func() error {
+			return encode("Id", fmt.Sprintf("%s-%s", st.From.UTC().Format("20060102"), st.To.UTC().Format("20060102")))
+		},
+		func() error { return encode("CreDtTm", createdAt) },
+		func() error { return encode("FrToDt", camtPeriod{From: st.From.UTC(), To: st.To.UTC()}) },
+		func() error { return encode("Acct", camtAccount{Id: st.AccountID, Currency: st.Currency}) },
+		func() error { return encode("Bal", camtBalanceOf("OPBD", st.OpeningBalance, st.Currency, st.From)) },
+		func() error { return encode("Bal", camtBalanceOf("CLBD", st.ClosingBalance, st.Currency, st.To)) },
+	}
+	for _, write := range header {
+		if err := write(); err != nil {
+			return err
+		}
+	}
+
+	err := st.Entries(func(e Entry) error {
+		amount, indicator := camtAmountOf(signedAmount(e), st.Currency)
+
+		entry := camtEntry{
+			Amount:      amount,
+			Indicator:   indicator,
+			Status:      "BOOK",
+			BookingDate: camtDateTime{DateTime: e.Timestamp.UTC()},
+			ValueDate:   camtDateTime{DateTime: e.Timestamp.UTC()},
+			Reference:   camtId(e.ID),
+			Code:        string(e.Type),
+		}
+		entry.Details.EndToEndId = "NOTPROVIDED"
+		if e.ExternalReference != "" {
+			entry.Details.EndToEndId = camtText(e.ExternalReference, 35)
+		}
+		if e.Counterparty != "" {
+			party := &camtParty{Name: camtText(e.Counterparty, 140)}
+			if e.Credit() {
+				entry.Details.Debtor = party
+			} else {
+				entry.Details.Creditor = party
+			}
+		}
+		entry.Details.Remittance = camtText(e.Description, 140)
+
+		return encode("Ntry", entry)
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, t := range []xml.StartElement{statement, report, document} {
+		if err = enc.EncodeToken(t.End()); err != nil {
+			return err
+		}
+	}
+	return enc.Flush()
+}
@@ -0,0 +1,123 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=service.go -destination=./mock/service.go -package=mock
+//
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	statement "github.com/fmiskovic/cash-me-if-you-can/internal/statement"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockRepository is a mock of Repository interface.
+type MockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryMockRecorder
+}
+
+// MockRepositoryMockRecorder is the mock recorder for MockRepository.
+type MockRepositoryMockRecorder struct {
+	mock *MockRepository
+}
+
+// NewMockRepository creates a new mock instance.
+func NewMockRepository(ctrl *gomock.Controller) *MockRepository {
+	mock := &MockRepository{ctrl: ctrl}
+	mock.recorder = &MockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Snapshot mocks base method.
+func (m *MockRepository) Snapshot(ctx context.Context) (statement.Snapshot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Snapshot", ctx)
+	ret0, _ := ret[0].(statement.Snapshot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Snapshot indicates an expected call of Snapshot.
+func (mr *MockRepositoryMockRecorder) Snapshot(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Snapshot", reflect.TypeOf((*MockRepository)(nil).Snapshot), ctx)
+}
+
+// MockSnapshot is a mock of Snapshot interface.
+type MockSnapshot struct {
+	ctrl     *gomock.Controller
+	recorder *MockSnapshotMockRecorder
+}
+
+// MockSnapshotMockRecorder is the mock recorder for MockSnapshot.
+type MockSnapshotMockRecorder struct {
+	mock *MockSnapshot
+}
+
+// NewMockSnapshot creates a new mock instance.
+func NewMockSnapshot(ctrl *gomock.Controller) *MockSnapshot {
+	mock := &MockSnapshot{ctrl: ctrl}
+	mock.recorder = &MockSnapshotMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSnapshot) EXPECT() *MockSnapshotMockRecorder {
+	return m.recorder
+}
+
+// Balances mocks base method.
+func (m *MockSnapshot) Balances(ctx context.Context, accountID string, from, to time.Time) (*statement.Statement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Balances", ctx, accountID, from, to)
+	ret0, _ := ret[0].(*statement.Statement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Balances indicates an expected call of Balances.
+func (mr *MockSnapshotMockRecorder) Balances(ctx, accountID, from, to any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Balances", reflect.TypeOf((*MockSnapshot)(nil).Balances), ctx, accountID, from, to)
+}
+
+// Close mocks base method.
+func (m *MockSnapshot) Close(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Close", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Close indicates an expected call of Close.
+func (mr *MockSnapshotMockRecorder) Close(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockSnapshot)(nil).Close), ctx)
+}
+
+// Entries mocks base method.
+func (m *MockSnapshot) Entries(ctx context.Context, accountID string, from, to time.Time, fn func(statement.Entry) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Entries", ctx, accountID, from, to, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Entries indicates an expected call of Entries.
+func (mr *MockSnapshotMockRecorder) Entries(ctx, accountID, from, to, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Entries", reflect.TypeOf((*MockSnapshot)(nil).Entries), ctx, accountID, from, to, fn)
+}
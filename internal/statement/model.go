@@ -0,0 +1,69 @@
+package statement
+
+import (
+	"time"
+
+	"github.com/fmiskovic/cash-me-if-you-can/internal/transaction"
+)
+
+type Format string
+
+const (
+	CSV  Format = "csv"
+	JSON Format = "json"
+	// CAMT053 is the ISO 20022 bank to customer statement, camt.053.001.02.
+	CAMT053 Format = "camt.053"
+)
+
+// ContentType returns the media type of the format.
+func (f Format) ContentType() string {
+	switch f {
+	case CSV:
+		return "text/csv"
+	case CAMT053:
+		return "application/xml"
+	default:
+		return "application/json"
+	}
+}
+
+// Extension returns the file name extension of the format.
+func (f Format) Extension() string {
+	switch f {
+	case CSV:
+		return "csv"
+	case CAMT053:
+		return "xml"
+	default:
+		return "json"
+	}
+}
+
+// Statement of an account for the period [From, To).
+type Statement struct {
+	AccountID string
+	Currency  string
+	Format    Format
+	From      time.Time
+	To        time.Time
+
+	OpeningBalance float64
+	ClosingBalance float64
+
+	// Entries streams the movements of the period in booking order to fn,
+	// it stops at and returns the first error.
+	Entries func(fn func(Entry) error) error
+
+	CreatedAt time.Time
+}
+
+// Entry is a movement of the account together with the balance after it.
+type Entry struct {
+	transaction.Transaction
+	Balance float64
+}
+
+// Credit reports whether the entry increased the balance.
+func (e Entry) Credit() bool {
+	return !e.Type.IsDebit()
+}
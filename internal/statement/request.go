@@ -0,0 +1,12 @@
+package statement
+
+import "time"
+
+// Request selects the period of an account statement.
+// The period ends now when To is not set and the statement is JSON encoded when Format is not set.
+type Request struct {
+	AccountID string     `validate:"required"`
+	From      time.Time  `validate:"required"`
+	To        *time.Time `validate:"omitempty"`
+	Format    Format     `validate:"omitempty,oneof=csv json camt.053"`
+}
@@ -0,0 +1,126 @@
+//go:generate mockgen -source=service.go -destination=./mock/service.go -package=mock
+package statement
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/softika/slogging"
+
+	"github.com/fmiskovic/cash-me-if-you-can/pkg/clock"
+	"github.com/fmiskovic/cash-me-if-you-can/pkg/errorx"
+)
+
+type Repository interface {
+	// Snapshot begins a consistent read of the statement data, every read of the snapshot sees the same committed state.
+	// The snapshot holds a connection until it is closed.
+	Snapshot(ctx context.Context) (Snapshot, error)
+}
+
+type Snapshot interface {
+	// Balances returns the statement of the account with the opening and closing balances of the period set.
+	// It fails with invalid input if the period starts before the end of the archived months.
+	Balances(ctx context.Context, accountID string, from, to time.Time) (*Statement, error)
+	// Entries streams the movements of the account made in the period in booking order to fn.
+	Entries(ctx context.Context, accountID string, from, to time.Time, fn func(Entry) error) error
+	// Close releases the snapshot.
+	Close(ctx context.Context) error
+}
+
+type Service struct {
+	repo  Repository
+	clock clock.Clock
+}
+
+func NewService(repo Repository, clk clock.Clock) Service {
+	return Service{
+		repo:  repo,
+		clock: clk,
+	}
+}
+
+// Generate returns the statement of the requested period.
+// The movements are not loaded upfront but read from the repository while the statement entries are consumed.
+// Balances and movements are read from the same snapshot, so the running balances of the entries end at the closing balance.
+// The snapshot is released once the entries are streamed, or when the context is done if they never are.
+func (s Service) Generate(ctx context.Context, req Request) (*Statement, error) {
+	now := s.clock.Now()
+
+	to := now
+	if req.To != nil && req.To.Before(now) {
+		to = *req.To
+	}
+	if !req.From.Before(to) {
+		return nil, errorx.NewErrorMsg("statement period must start before it ends", errorx.ErrInvalidInput)
+	}
+
+	snap, err := s.repo.Snapshot(ctx)
+	if err != nil {
+		logger := slogging.Slogger()
+		logger.ErrorContext(ctx, "failed to begin statement snapshot", "account_id", req.AccountID, "error", err)
+		return nil, err
+	}
+
+	st, err := snap.Balances(ctx, req.AccountID, req.From, to)
+	if err != nil {
+		_ = snap.Close(context.WithoutCancel(ctx))
+		logger := slogging.Slogger()
+		logger.ErrorContext(ctx, "failed to get statement balances", "account_id", req.AccountID, "error", err)
+		return nil, err
+	}
+
+	st.From, st.To = req.From, to
+	st.Format = req.Format
+	if st.Format == "" {
+		st.Format = JSON
+	}
+	st.CreatedAt = now
+
+	r := &snapshotReader{snap: snap}
+	stop := context.AfterFunc(ctx, func() { r.close(ctx) })
+	st.Entries = func(fn func(Entry) error) error {
+		defer stop()
+		return r.entries(ctx, req.AccountID, req.From, to, fn)
+	}
+
+	return st, nil
+}
+
+// snapshotReader streams the entries of a snapshot once and closes it,
+// a close while the entries are streamed waits until the stream ends.
+type snapshotReader struct {
+	mu     sync.Mutex
+	snap   Snapshot
+	closed bool
+}
+
+func (r *snapshotReader) entries(ctx context.Context, accountID string, from, to time.Time, fn func(Entry) error) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return errorx.NewErrorMsg("statement entries can be read only once", errorx.ErrConflict)
+	}
+	defer r.closeLocked(ctx)
+
+	return r.snap.Entries(ctx, accountID, from, to, fn)
+}
+
+func (r *snapshotReader) close(ctx context.Context) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.closeLocked(ctx)
+}
+
+// closeLocked closes the snapshot even if the context is done, the caller holds the lock.
+func (r *snapshotReader) closeLocked(ctx context.Context) {
+	if r.closed {
+		return
+	}
+	r.closed = true
+	if err := r.snap.Close(context.WithoutCancel(ctx)); err != nil {
+		logger := slogging.Slogger()
+		logger.ErrorContext(ctx, "failed to close statement snapshot", "error", err)
+	}
+}
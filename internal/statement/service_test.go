@@ -0,0 +1,310 @@
+package statement_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/fmiskovic/cash-me-if-you-can/internal/statement"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/statement/mock"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/transaction"
+	"github.com/fmiskovic/cash-me-if-you-can/pkg/clock"
+	"github.com/fmiskovic/cash-me-if-you-can/pkg/errorx"
+)
+
+var (
+	from = time.Date(2024, time.August, 1, 0, 0, 0, 0, time.UTC)
+	to   = time.Date(2024, time.September, 1, 0, 0, 0, 0, time.UTC)
+	now  = time.Date(2024, time.October, 1, 12, 0, 0, 0, time.UTC)
+)
+
+func TestGenerate(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+
+	future := now.Add(time.Hour)
+
+	tests := []struct {
+		name       string
+		req        statement.Request
+		mockFn     func(*mock.MockSnapshot)
+		wantTo     time.Time
+		wantFormat statement.Format
+		wantErr    assert.ErrorAssertionFunc
+	}{
+		{
+			name: "closed period",
+			req:  statement.Request{AccountID: "1", From: from, To: &to, Format: statement.CSV},
+			mockFn: func(snap *mock.MockSnapshot) {
+				snap.EXPECT().Balances(ctx, "1", from, to).Return(&statement.Statement{AccountID: "1"}, nil)
+			},
+			wantTo:     to,
+			wantFormat: statement.CSV,
+			wantErr:    assert.NoError,
+		},
+		{
+			name: "open period ends now and defaults to json",
+			req:  statement.Request{AccountID: "1", From: from},
+			mockFn: func(snap *mock.MockSnapshot) {
+				snap.EXPECT().Balances(ctx, "1", from, now).Return(&statement.Statement{AccountID: "1"}, nil)
+			},
+			wantTo:     now,
+			wantFormat: statement.JSON,
+			wantErr:    assert.NoError,
+		},
+		{
+			name: "period in the future ends now",
+			req:  statement.Request{AccountID: "1", From: from, To: &future},
+			mockFn: func(snap *mock.MockSnapshot) {
+				snap.EXPECT().Balances(ctx, "1", from, now).Return(&statement.Statement{AccountID: "1"}, nil)
+			},
+			wantTo:     now,
+			wantFormat: statement.JSON,
+			wantErr:    assert.NoError,
+		},
+		{
+			name:   "period ends before it starts",
+			req:    statement.Request{AccountID: "1", From: to, To: &from},
+			mockFn: func(*mock.MockSnapshot) {},
+			wantErr: func(t assert.TestingT, err error, _ ...interface{}) bool {
+				var errx *errorx.Error
+				return assert.ErrorAs(t, err, &errx) && assert.Equal(t, errorx.ErrInvalidInput, errx.Type)
+			},
+		},
+		{
+			name: "account not found",
+			req:  statement.Request{AccountID: "1", From: from, To: &to},
+			mockFn: func(snap *mock.MockSnapshot) {
+				snap.EXPECT().Balances(ctx, "1", from, to).
+					Return(nil, errorx.NewErrorMsg("account not found", errorx.ErrNotFound))
+				snap.EXPECT().Close(gomock.Any()).Return(nil)
+			},
+			wantErr: assert.Error,
+		},
+	}
+
+	for _, tc := range tests {
+		tt := tc
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := mock.NewMockRepository(ctrl)
+			snap := mock.NewMockSnapshot(ctrl)
+			repo.EXPECT().Snapshot(ctx).Return(snap, nil).MaxTimes(1)
+			tt.mockFn(snap)
+
+			s := statement.NewService(repo, clock.Fixed(now))
+			got, err := s.Generate(ctx, tt.req)
+			if !tt.wantErr(t, err) || err != nil {
+				return
+			}
+
+			assert.Equal(t, tt.wantTo, got.To)
+			assert.Equal(t, tt.wantFormat, got.Format)
+			assert.Equal(t, now, got.CreatedAt)
+
+			// entries are read from the snapshot only when consumed, which releases it
+			gomock.InOrder(
+				snap.EXPECT().Entries(ctx, "1", from, tt.wantTo, gomock.Any()).Return(nil),
+				snap.EXPECT().Close(gomock.Any()).Return(nil),
+			)
+			assert.NoError(t, got.Entries(func(statement.Entry) error { return nil }))
+			assert.Error(t, got.Entries(func(statement.Entry) error { return nil }))
+		})
+	}
+}
+
+func TestGenerateReleasesSnapshot(t *testing.T) {
+	t.Parallel()
+
+	ctrl := gomock.NewController(t)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	repo := mock.NewMockRepository(ctrl)
+	snap := mock.NewMockSnapshot(ctrl)
+	closed := make(chan struct{})
+	repo.EXPECT().Snapshot(ctx).Return(snap, nil)
+	snap.EXPECT().Balances(ctx, "1", from, to).Return(&statement.Statement{AccountID: "1"}, nil)
+	snap.EXPECT().Close(gomock.Any()).DoAndReturn(func(context.Context) error {
+		close(closed)
+		return nil
+	})
+
+	s := statement.NewService(repo, clock.Fixed(now))
+	_, err := s.Generate(ctx, statement.Request{AccountID: "1", From: from, To: &to})
+	require.NoError(t, err)
+
+	// the statement is never streamed, the snapshot is released when the request ends
+	cancel()
+	select {
+	case <-closed:
+	case <-time.After(time.Second):
+		t.Fatal("snapshot was not released")
+	}
+}
+
+func testStatement(format statement.Format) *statement.Statement {
+	entries := []statement.Entry{
+		{
+			Transaction: transaction.Transaction{
+				ID: "t1", Type: transaction.Deposit, Amount: 100, CreatedAt: from.Add(time.Hour), Timestamp: from.Add(time.Hour),
+				Description: "salary", Counterparty: "ACME", ExternalReference: "ref-1",
+			},
+			Balance: 110,
+		},
+		{
+			Transaction: transaction.Transaction{
+				ID: "t2", Type: transaction.Withdrawal, Amount: 30.5, CreatedAt: from.Add(2 * time.Hour), Timestamp: from.Add(2 * time.Hour),
+			},
+			Balance: 79.5,
+		},
+	}
+
+	return &statement.Statement{
+		AccountID:      "a1b2c3d4-1111-2222-3333-444455556666",
+		Currency:       "EUR",
+		Format:         format,
+		From:           from,
+		To:             to,
+		OpeningBalance: 10,
+		ClosingBalance: 79.5,
+		CreatedAt:      now,
+		Entries: func(fn func(statement.Entry) error) error {
+			for _, e := range entries {
+				if err := fn(e); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	require.NoError(t, statement.Write(&buf, testStatement(statement.CSV)))
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 5)
+
+	assert.Equal(t, "record", records[0][0])
+	assert.Equal(t, []string{"opening", "2024-08-01T00:00:00Z", "", "", "", "", "", "", "10"}, records[1])
+	assert.Equal(t, []string{"entry", "2024-08-01T01:00:00Z", "t1", "deposit", "salary", "ACME", "ref-1", "100", "110"}, records[2])
+	assert.Equal(t, []string{"entry", "2024-08-01T02:00:00Z", "t2", "withdrawal", "", "", "", "-30.5", "79.5"}, records[3])
+	assert.Equal(t, []string{"closing", "2024-09-01T00:00:00Z", "", "", "", "", "", "", "79.5"}, records[4])
+}
+
+func TestWriteJSON(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	require.NoError(t, statement.Write(&buf, testStatement(statement.JSON)))
+
+	var got struct {
+		AccountId      string  `json:"account_id"`
+		Currency       string  `json:"currency"`
+		OpeningBalance float64 `json:"opening_balance"`
+		ClosingBalance float64 `json:"closing_balance"`
+		Entries        []struct {
+			TransactionId string  `json:"transaction_id"`
+			Amount        float64 `json:"amount"`
+			Balance       float64 `json:"balance"`
+		} `json:"entries"`
+	}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &got))
+
+	assert.Equal(t, "EUR", got.Currency)
+	assert.Equal(t, 10.0, got.OpeningBalance)
+	assert.Equal(t, 79.5, got.ClosingBalance)
+	require.Len(t, got.Entries, 2)
+	assert.Equal(t, "t1", got.Entries[0].TransactionId)
+	assert.Equal(t, -30.5, got.Entries[1].Amount)
+	assert.Equal(t, 79.5, got.Entries[1].Balance)
+}
+
+func TestWriteJSONWithoutEntries(t *testing.T) {
+	t.Parallel()
+
+	st := testStatement(statement.JSON)
+	st.Entries = func(func(statement.Entry) error) error { return nil }
+
+	var buf bytes.Buffer
+	require.NoError(t, statement.Write(&buf, st))
+
+	var got map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &got))
+	assert.Equal(t, []any{}, got["entries"])
+}
+
+func TestWriteCAMT053(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	require.NoError(t, statement.Write(&buf, testStatement(statement.CAMT053)))
+
+	type amount struct {
+		Currency string `xml:"Ccy,attr"`
+		Value    string `xml:",chardata"`
+	}
+	var got struct {
+		XMLName xml.Name
+		Stmt    struct {
+			Account string `xml:"Acct>Id>Othr>Id"`
+			Bal     []struct {
+				Code      string `xml:"Tp>CdOrPrtry>Cd"`
+				Amount    amount `xml:"Amt"`
+				Indicator string `xml:"CdtDbtInd"`
+			} `xml:"Bal"`
+			Ntry []struct {
+				Amount     amount `xml:"Amt"`
+				Indicator  string `xml:"CdtDbtInd"`
+				Reference  string `xml:"AcctSvcrRef"`
+				EndToEndId string `xml:"NtryDtls>TxDtls>Refs>EndToEndId"`
+				Debtor     string `xml:"NtryDtls>TxDtls>RltdPties>Dbtr>Nm"`
+			} `xml:"Ntry"`
+		} `xml:"BkToCstmrStmt>Stmt"`
+	}
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &got))
+
+	assert.Equal(t, "urn:iso:std:iso:20022:tech:xsd:camt.053.001.02", got.XMLName.Space)
+	assert.Equal(t, "a1b2c3d4-1111-2222-3333-444455556666", got.Stmt.Account)
+
+	require.Len(t, got.Stmt.Bal, 2)
+	assert.Equal(t, "OPBD", got.Stmt.Bal[0].Code)
+	assert.Equal(t, amount{Currency: "EUR", Value: "10"}, got.Stmt.Bal[0].Amount)
+	assert.Equal(t, "CLBD", got.Stmt.Bal[1].Code)
+	assert.Equal(t, "79.5", got.Stmt.Bal[1].Amount.Value)
+
+	require.Len(t, got.Stmt.Ntry, 2)
+	assert.Equal(t, "CRDT", got.Stmt.Ntry[0].Indicator)
+	assert.Equal(t, "ref-1", got.Stmt.Ntry[0].EndToEndId)
+	assert.Equal(t, "ACME", got.Stmt.Ntry[0].Debtor)
+	assert.Equal(t, "DBIT", got.Stmt.Ntry[1].Indicator)
+	assert.Equal(t, "30.5", got.Stmt.Ntry[1].Amount.Value)
+	assert.Equal(t, "NOTPROVIDED", got.Stmt.Ntry[1].EndToEndId)
+}
+
+func TestWriteStopsOnEntriesError(t *testing.T) {
+	t.Parallel()
+
+	for _, format := range []statement.Format{statement.CSV, statement.JSON, statement.CAMT053} {
+		st := testStatement(format)
+		st.Entries = func(func(statement.Entry) error) error { return assert.AnError }
+
+		var buf bytes.Buffer
+		assert.ErrorIs(t, statement.Write(&buf, st), assert.AnError, string(format))
+	}
+}
@@ -56,18 +56,33 @@ func (mr *MockRepositoryMockRecorder) Create(ctx, t any) *gomock.Call {
 }
 
 // GetByAccountId mocks base method.
-func (m *MockRepository) GetByAccountId(ctx context.Context, accountId string) ([]transaction.Transaction, error) {
+func (m *MockRepository) GetByAccountId(ctx context.Context, req transaction.ListRequest) ([]transaction.Transaction, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByAccountId", ctx, accountId)
+	ret := m.ctrl.Call(m, "GetByAccountId", ctx, req)
 	ret0, _ := ret[0].([]transaction.Transaction)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByAccountId indicates an expected call of GetByAccountId.
-func (mr *MockRepositoryMockRecorder) GetByAccountId(ctx, accountId any) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) GetByAccountId(ctx, req any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByAccountId", reflect.TypeOf((*MockRepository)(nil).GetByAccountId), ctx, accountId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByAccountId", reflect.TypeOf((*MockRepository)(nil).GetByAccountId), ctx, req)
+}
+
+// GetById mocks base method.
+func (m *MockRepository) GetById(ctx context.Context, id string) (*transaction.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetById", ctx, id)
+	ret0, _ := ret[0].(*transaction.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetById indicates an expected call of GetById.
+func (mr *MockRepositoryMockRecorder) GetById(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetById", reflect.TypeOf((*MockRepository)(nil).GetById), ctx, id)
 }
 
 // Transfer mocks base method.
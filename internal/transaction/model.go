@@ -8,6 +8,16 @@ type Transaction struct {
 	Type      Type
 	Amount    float64
 	Timestamp time.Time
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	Description       string
+	ExternalReference string
+	Counterparty      string
+	Metadata          map[string]any
+
+	// Fee is the fee charged for the transaction, nil if it was free.
+	Fee *Transaction
 }
 
 type Type string
@@ -15,8 +25,17 @@ type Type string
 const (
 	Deposit    Type = "deposit"
 	Withdrawal Type = "withdrawal"
+	// Interest is a deposit of capitalized interest, posted by the interest engine.
+	Interest Type = "interest"
+	// Fee is a charge debited from the account, posted by the fee engine.
+	Fee Type = "fee"
 )
 
+// IsDebit reports whether transactions of the type decrease the account balance.
+func (t Type) IsDebit() bool {
+	return t == Withdrawal || t == Fee
+}
+
 type Option func(*Transaction)
 
 func New(opts ...Option) *Transaction {
@@ -45,3 +64,27 @@ func WithAmount(amount float64) Option {
 		t.Amount = amount
 	}
 }
+
+func WithDescription(description string) Option {
+	return func(t *Transaction) {
+		t.Description = description
+	}
+}
+
+func WithExternalReference(reference string) Option {
+	return func(t *Transaction) {
+		t.ExternalReference = reference
+	}
+}
+
+func WithCounterparty(counterparty string) Option {
+	return func(t *Transaction) {
+		t.Counterparty = counterparty
+	}
+}
+
+func WithMetadata(metadata map[string]any) Option {
+	return func(t *Transaction) {
+		t.Metadata = metadata
+	}
+}
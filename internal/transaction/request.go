@@ -1,13 +1,34 @@
 package transaction
 
+import "github.com/fmiskovic/cash-me-if-you-can/internal"
+
+// MaxMetadataSize is the maximum size of JSON encoded transaction metadata in bytes.
+const MaxMetadataSize = 4096
+
 type CreateRequest struct {
-	AccountID string  `json:"account_id" validate:"required"`
-	Type      Type    `json:"type" validate:"required,oneof=deposit withdrawal"`
-	Amount    float64 `json:"amount" validate:"required"`
+	AccountID         string         `json:"account_id" validate:"required"`
+	Type              Type           `json:"type" validate:"required,oneof=deposit withdrawal"`
+	Amount            float64        `json:"amount" validate:"required"`
+	Description       string         `json:"description" validate:"omitempty,max=255"`
+	ExternalReference string         `json:"external_reference" validate:"omitempty,max=64"`
+	Counterparty      string         `json:"counterparty" validate:"omitempty,max=255"`
+	Metadata          map[string]any `json:"metadata" validate:"omitempty,max=50"`
 }
 
+// TransferRequest moves funds between two accounts.
+// The counterparty of each leg is set to the opposite account.
 type TransferRequest struct {
-	FromAccountID string  `json:"from_account_id" validate:"required"`
-	ToAccountID   string  `json:"to_account_id" validate:"required"`
-	Amount        float64 `json:"amount" validate:"required,gt=0"`
+	FromAccountID     string         `json:"from_account_id" validate:"required,uuid"`
+	ToAccountID       string         `json:"to_account_id" validate:"required,uuid"`
+	Amount            float64        `json:"amount" validate:"required,gt=0"`
+	Description       string         `json:"description" validate:"omitempty,max=255"`
+	ExternalReference string         `json:"external_reference" validate:"omitempty,max=64"`
+	Metadata          map[string]any `json:"metadata" validate:"omitempty,max=50"`
+}
+
+// ListRequest selects the transactions of an account, optionally narrowed down by time.
+type ListRequest struct {
+	AccountID string `validate:"required"`
+	internal.TimeFilter
+	ExternalReference string `validate:"omitempty,max=64"`
 }
@@ -6,6 +6,9 @@ type TransferResponse struct {
 	FromAccountId string `json:"from_account_id"`
 	ToAccountId   string `json:"to_account_id"`
 	Amount        float64
+
+	Fee              float64 `json:"fee"`
+	FeeTransactionId string  `json:"fee_transaction_id,omitempty"`
 }
 
 type Details struct {
@@ -14,4 +17,13 @@ type Details struct {
 	Type          string    `json:"type"`
 	Amount        float64   `json:"amount"`
 	Timestamp     time.Time `json:"timestamp"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+
+	Description       string         `json:"description,omitempty"`
+	ExternalReference string         `json:"external_reference,omitempty"`
+	Counterparty      string         `json:"counterparty,omitempty"`
+	Metadata          map[string]any `json:"metadata,omitempty"`
+
+	Fee *Details `json:"fee,omitempty"`
 }
@@ -3,6 +3,8 @@ package transaction
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 
 	"github.com/softika/slogging"
 
@@ -12,7 +14,8 @@ import (
 type Repository interface {
 	Create(ctx context.Context, t *Transaction) (*Transaction, error)
 	Transfer(ctx context.Context, from *Transaction, to *Transaction) error
-	GetByAccountId(ctx context.Context, accountId string) ([]Transaction, error)
+	GetByAccountId(ctx context.Context, req ListRequest) ([]Transaction, error)
+	GetById(ctx context.Context, id string) (*Transaction, error)
 }
 
 type Service struct {
@@ -24,10 +27,18 @@ func NewService(repo Repository) Service {
 }
 
 func (s Service) Create(ctx context.Context, req CreateRequest) (*Details, error) {
+	if err := ValidateCreate(req); err != nil {
+		return nil, err
+	}
+
 	input := New(
 		WithAccountID(req.AccountID),
 		WithType(req.Type),
 		WithAmount(req.Amount),
+		WithDescription(req.Description),
+		WithExternalReference(req.ExternalReference),
+		WithCounterparty(req.Counterparty),
+		WithMetadata(req.Metadata),
 	)
 
 	t, err := s.repo.Create(ctx, input)
@@ -37,34 +48,15 @@ func (s Service) Create(ctx context.Context, req CreateRequest) (*Details, error
 		return nil, err
 	}
 
-	return &Details{
-		TransactionId: t.ID,
-		AccountId:     t.AccountID,
-		Amount:        t.Amount,
-		Timestamp:     t.Timestamp,
-		Type:          string(t.Type),
-	}, nil
+	return newDetails(t), nil
 }
 
 func (s Service) Transfer(ctx context.Context, req TransferRequest) (*TransferResponse, error) {
-	if req.FromAccountID == req.ToAccountID {
-		return nil, errorx.NewErrorMsg(
-			"from and to account ids are the same",
-			errorx.ErrInvalidInput,
-		)
+	if err := ValidateTransfer(req); err != nil {
+		return nil, err
 	}
 
-	from := New(
-		WithAccountID(req.FromAccountID),
-		WithType(Withdrawal),
-		WithAmount(req.Amount),
-	)
-
-	to := New(
-		WithAccountID(req.ToAccountID),
-		WithType(Deposit),
-		WithAmount(req.Amount),
-	)
+	from, to := NewTransferLegs(req)
 
 	if err := s.repo.Transfer(ctx, from, to); err != nil {
 		logger := slogging.Slogger()
@@ -72,15 +64,21 @@ func (s Service) Transfer(ctx context.Context, req TransferRequest) (*TransferRe
 		return nil, err
 	}
 
-	return &TransferResponse{
+	res := &TransferResponse{
 		FromAccountId: from.AccountID,
 		ToAccountId:   to.AccountID,
 		Amount:        from.Amount,
-	}, nil
+	}
+	if from.Fee != nil {
+		res.Fee = from.Fee.Amount
+		res.FeeTransactionId = from.Fee.ID
+	}
+
+	return res, nil
 }
 
-func (s Service) GetByAccountId(ctx context.Context, accountId string) ([]Details, error) {
-	trs, err := s.repo.GetByAccountId(ctx, accountId)
+func (s Service) GetByAccountId(ctx context.Context, req ListRequest) ([]Details, error) {
+	trs, err := s.repo.GetByAccountId(ctx, req)
 	if err != nil {
 		logger := slogging.Slogger()
 		logger.ErrorContext(ctx, "failed to get list of transactions by account id", "error", err)
@@ -92,15 +90,99 @@ func (s Service) GetByAccountId(ctx context.Context, accountId string) ([]Detail
 	}
 
 	details := make([]Details, len(trs))
-	for i, tr := range trs {
-		details[i] = Details{
-			TransactionId: tr.ID,
-			AccountId:     tr.AccountID,
-			Amount:        tr.Amount,
-			Timestamp:     tr.Timestamp,
-			Type:          string(tr.Type),
-		}
+	for i := range trs {
+		details[i] = *newDetails(&trs[i])
 	}
 
 	return details, nil
 }
+
+func (s Service) GetById(ctx context.Context, id string) (*Details, error) {
+	t, err := s.repo.GetById(ctx, id)
+	if err != nil {
+		logger := slogging.Slogger()
+		logger.ErrorContext(ctx, "failed to get transaction by id", "id", id, "error", err)
+		return nil, err
+	}
+
+	return newDetails(t), nil
+}
+
+func newDetails(t *Transaction) *Details {
+	d := &Details{
+		TransactionId: t.ID,
+		AccountId:     t.AccountID,
+		Amount:        t.Amount,
+		Timestamp:     t.Timestamp,
+		CreatedAt:     t.CreatedAt,
+		UpdatedAt:     t.UpdatedAt,
+		Type:          string(t.Type),
+
+		Description:       t.Description,
+		ExternalReference: t.ExternalReference,
+		Counterparty:      t.Counterparty,
+		Metadata:          t.Metadata,
+	}
+	if t.Fee != nil {
+		d.Fee = newDetails(t.Fee)
+	}
+	return d
+}
+
+// ValidateCreate checks the rules of a create request not covered by struct validation.
+func ValidateCreate(req CreateRequest) error {
+	return validateMetadata(req.Metadata)
+}
+
+// ValidateTransfer checks the rules of a transfer request not covered by struct validation.
+func ValidateTransfer(req TransferRequest) error {
+	if req.FromAccountID == req.ToAccountID {
+		return errorx.NewErrorMsg(
+			"from and to account ids are the same",
+			errorx.ErrInvalidInput,
+		)
+	}
+	return validateMetadata(req.Metadata)
+}
+
+// NewTransferLegs creates the withdrawal and deposit transactions of a transfer.
+func NewTransferLegs(req TransferRequest) (from *Transaction, to *Transaction) {
+	from = New(
+		WithAccountID(req.FromAccountID),
+		WithType(Withdrawal),
+		WithAmount(req.Amount),
+		WithDescription(req.Description),
+		WithExternalReference(req.ExternalReference),
+		WithCounterparty(req.ToAccountID),
+		WithMetadata(req.Metadata),
+	)
+
+	to = New(
+		WithAccountID(req.ToAccountID),
+		WithType(Deposit),
+		WithAmount(req.Amount),
+		WithDescription(req.Description),
+		WithExternalReference(req.ExternalReference),
+		WithCounterparty(req.FromAccountID),
+		WithMetadata(req.Metadata),
+	)
+
+	return from, to
+}
+
+func validateMetadata(metadata map[string]any) error {
+	if len(metadata) == 0 {
+		return nil
+	}
+	b, err := json.Marshal(metadata)
+	if err != nil {
+		return errorx.NewError(fmt.Errorf("invalid metadata: %w", err), errorx.ErrInvalidInput)
+	}
+	if len(b) > MaxMetadataSize {
+		return errorx.NewError(
+			fmt.Errorf("metadata exceeds %d bytes", MaxMetadataSize),
+			errorx.ErrInvalidInput,
+		)
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package transaction_test
 
 import (
 	"context"
+	"strings"
 	"testing"
 	"time"
 
@@ -55,6 +56,42 @@ func TestCreateTransaction(t *testing.T) {
 				Type:          string(transaction.Deposit),
 			},
 		},
+		{
+			name: "create transaction with details",
+			req: transaction.CreateRequest{
+				AccountID:         "1",
+				Type:              transaction.Deposit,
+				Amount:            23.5,
+				Description:       "salary",
+				ExternalReference: "ref-1",
+				Counterparty:      "ACME",
+				Metadata:          map[string]any{"month": "august"},
+			},
+			mockFn: func(repo *mock.MockRepository) {
+				input := transaction.New(
+					transaction.WithAccountID("1"),
+					transaction.WithType(transaction.Deposit),
+					transaction.WithAmount(23.5),
+					transaction.WithDescription("salary"),
+					transaction.WithExternalReference("ref-1"),
+					transaction.WithCounterparty("ACME"),
+					transaction.WithMetadata(map[string]any{"month": "august"}),
+				)
+				got := *input
+				got.ID = "1"
+				repo.EXPECT().Create(ctx, input).Return(&got, nil)
+			},
+			want: &transaction.Details{
+				TransactionId:     "1",
+				AccountId:         "1",
+				Amount:            23.5,
+				Type:              string(transaction.Deposit),
+				Description:       "salary",
+				ExternalReference: "ref-1",
+				Counterparty:      "ACME",
+				Metadata:          map[string]any{"month": "august"},
+			},
+		},
 		{
 			name: "create transaction error",
 			req:  req,
@@ -90,6 +127,10 @@ func TestCreateTransaction(t *testing.T) {
 			assert.Equal(t, tt.want.Amount, got.Amount)
 			assert.Equal(t, tt.want.TransactionId, got.TransactionId)
 			assert.Equal(t, tt.want.Type, got.Type)
+			assert.Equal(t, tt.want.Description, got.Description)
+			assert.Equal(t, tt.want.ExternalReference, got.ExternalReference)
+			assert.Equal(t, tt.want.Counterparty, got.Counterparty)
+			assert.Equal(t, tt.want.Metadata, got.Metadata)
 		})
 	}
 }
@@ -121,11 +162,13 @@ func TestTransfer(t *testing.T) {
 					transaction.WithAccountID(req.FromAccountID),
 					transaction.WithType(transaction.Withdrawal),
 					transaction.WithAmount(req.Amount),
+					transaction.WithCounterparty(req.ToAccountID),
 				)
 				to := transaction.New(
 					transaction.WithAccountID(req.ToAccountID),
 					transaction.WithType(transaction.Deposit),
 					transaction.WithAmount(req.Amount),
+					transaction.WithCounterparty(req.FromAccountID),
 				)
 				repo.EXPECT().Transfer(ctx, from, to).Return(nil)
 			},
@@ -136,6 +179,30 @@ func TestTransfer(t *testing.T) {
 			},
 			wantErr: assert.NoError,
 		},
+		{
+			name: "transfer with fee",
+			req:  req,
+			mockFn: func(repo *mock.MockRepository) {
+				repo.EXPECT().Transfer(ctx, gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ context.Context, from, _ *transaction.Transaction) error {
+						from.Fee = transaction.New(
+							transaction.WithAccountID(req.FromAccountID),
+							transaction.WithType(transaction.Fee),
+							transaction.WithAmount(0.5),
+						)
+						from.Fee.ID = "fee-1"
+						return nil
+					})
+			},
+			want: &transaction.TransferResponse{
+				FromAccountId:    req.FromAccountID,
+				ToAccountId:      req.ToAccountID,
+				Amount:           req.Amount,
+				Fee:              0.5,
+				FeeTransactionId: "fee-1",
+			},
+			wantErr: assert.NoError,
+		},
 		{
 			name: "transfer error",
 			req:  req,
@@ -144,11 +211,13 @@ func TestTransfer(t *testing.T) {
 					transaction.WithAccountID(req.FromAccountID),
 					transaction.WithType(transaction.Withdrawal),
 					transaction.WithAmount(req.Amount),
+					transaction.WithCounterparty(req.ToAccountID),
 				)
 				to := transaction.New(
 					transaction.WithAccountID(req.ToAccountID),
 					transaction.WithType(transaction.Deposit),
 					transaction.WithAmount(req.Amount),
+					transaction.WithCounterparty(req.FromAccountID),
 				)
 				repo.EXPECT().Transfer(ctx, from, to).Return(assert.AnError)
 			},
@@ -164,6 +233,17 @@ func TestTransfer(t *testing.T) {
 			mockFn:  func(repo *mock.MockRepository) {},
 			wantErr: assert.Error,
 		},
+		{
+			name: "metadata too large",
+			req: transaction.TransferRequest{
+				FromAccountID: "1",
+				ToAccountID:   "2",
+				Amount:        23.5,
+				Metadata:      map[string]any{"note": strings.Repeat("x", transaction.MaxMetadataSize)},
+			},
+			mockFn:  func(repo *mock.MockRepository) {},
+			wantErr: assert.Error,
+		},
 	}
 
 	for _, tc := range tests {
@@ -186,6 +266,8 @@ func TestTransfer(t *testing.T) {
 			assert.Equal(t, tt.want.FromAccountId, got.FromAccountId)
 			assert.Equal(t, tt.want.ToAccountId, got.ToAccountId)
 			assert.Equal(t, tt.want.Amount, got.Amount)
+			assert.Equal(t, tt.want.Fee, got.Fee)
+			assert.Equal(t, tt.want.FeeTransactionId, got.FeeTransactionId)
 		})
 	}
 }
@@ -197,17 +279,17 @@ func TestGetTransactionsByAccountId(t *testing.T) {
 	ctrl := gomock.NewController(t)
 
 	tests := []struct {
-		name      string
-		accountID string
-		mockFn    func(*mock.MockRepository)
-		want      []transaction.Details
-		wantErr   assert.ErrorAssertionFunc
+		name    string
+		req     transaction.ListRequest
+		mockFn  func(*mock.MockRepository)
+		want    []transaction.Details
+		wantErr assert.ErrorAssertionFunc
 	}{
 		{
-			name:      "get transactions by account id",
-			accountID: "1",
+			name: "get transactions by account id",
+			req:  transaction.ListRequest{AccountID: "1"},
 			mockFn: func(repo *mock.MockRepository) {
-				repo.EXPECT().GetByAccountId(ctx, "1").Return([]transaction.Transaction{
+				repo.EXPECT().GetByAccountId(ctx, transaction.ListRequest{AccountID: "1"}).Return([]transaction.Transaction{
 					{
 						ID:        "1",
 						AccountID: "1",
@@ -229,10 +311,80 @@ func TestGetTransactionsByAccountId(t *testing.T) {
 			wantErr: assert.NoError,
 		},
 		{
-			name:      "get transactions by account id error",
-			accountID: "1",
+			name: "get transactions by account id error",
+			req:  transaction.ListRequest{AccountID: "1"},
+			mockFn: func(repo *mock.MockRepository) {
+				repo.EXPECT().GetByAccountId(ctx, transaction.ListRequest{AccountID: "1"}).Return(nil, assert.AnError)
+			},
+			wantErr: assert.Error,
+		},
+	}
+
+	for _, tc := range tests {
+		tt := tc
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := mock.NewMockRepository(ctrl)
+
+			tt.mockFn(repo)
+
+			s := transaction.NewService(repo)
+
+			got, err := s.GetByAccountId(ctx, tt.req)
+			if err != nil {
+				tt.wantErr(t, err, "unexpected error")
+				return
+			}
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestGetTransactionById(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+
+	tests := []struct {
+		name    string
+		id      string
+		mockFn  func(*mock.MockRepository)
+		want    *transaction.Details
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name: "get transaction by id",
+			id:   "1",
+			mockFn: func(repo *mock.MockRepository) {
+				repo.EXPECT().GetById(ctx, "1").Return(&transaction.Transaction{
+					ID:        "1",
+					AccountID: "2",
+					Type:      transaction.Deposit,
+					Amount:    23.5,
+					Timestamp: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+					CreatedAt: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+					UpdatedAt: time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC),
+				}, nil)
+			},
+			want: &transaction.Details{
+				TransactionId: "1",
+				AccountId:     "2",
+				Amount:        23.5,
+				Type:          string(transaction.Deposit),
+				Timestamp:     time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+				CreatedAt:     time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+				UpdatedAt:     time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC),
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "get transaction by id error",
+			id:   "1",
 			mockFn: func(repo *mock.MockRepository) {
-				repo.EXPECT().GetByAccountId(ctx, "1").Return(nil, assert.AnError)
+				repo.EXPECT().GetById(ctx, "1").Return(nil, assert.AnError)
 			},
 			wantErr: assert.Error,
 		},
@@ -249,7 +401,7 @@ func TestGetTransactionsByAccountId(t *testing.T) {
 
 			s := transaction.NewService(repo)
 
-			got, err := s.GetByAccountId(ctx, tt.accountID)
+			got, err := s.GetById(ctx, tt.id)
 			if err != nil {
 				tt.wantErr(t, err, "unexpected error")
 				return
@@ -0,0 +1,29 @@
+// Package clock abstracts the current time, so time dependent logic can be tested deterministically.
+package clock
+
+import "time"
+
+type Clock interface {
+	Now() time.Time
+}
+
+// Func adapts a function to the Clock interface.
+type Func func() time.Time
+
+func (f Func) Now() time.Time {
+	return f()
+}
+
+// System returns the wall clock in UTC.
+func System() Clock {
+	return Func(func() time.Time {
+		return time.Now().UTC()
+	})
+}
+
+// Fixed returns a clock which always returns the given time.
+func Fixed(t time.Time) Clock {
+	return Func(func() time.Time {
+		return t
+	})
+}
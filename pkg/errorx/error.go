@@ -10,6 +10,7 @@ const (
 	ErrForbidden
 	ErrNotFound
 	ErrUnauthorized
+	ErrConflict
 )
 
 type Error struct {
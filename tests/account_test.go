@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/fmiskovic/cash-me-if-you-can/internal"
 	"github.com/fmiskovic/cash-me-if-you-can/internal/account"
@@ -41,6 +42,24 @@ func (s *E2ETestSuite) TestCreateAccount() {
 			},
 			wantCode: http.StatusBadRequest,
 		},
+		{
+			name: "savings product",
+			input: account.CreateRequest{
+				Balance: 100,
+				Owner:   "Jane Saver",
+				Product: "savings",
+			},
+			wantCode: http.StatusCreated,
+		},
+		{
+			name: "unknown product",
+			input: account.CreateRequest{
+				Balance: 100,
+				Owner:   "John Unknown",
+				Product: "platinum",
+			},
+			wantCode: http.StatusBadRequest,
+		},
 	}
 
 	for _, tc := range tests {
@@ -68,6 +87,12 @@ func (s *E2ETestSuite) TestCreateAccount() {
 
 			s.NotEmpty(resp.AccountId)
 			s.Equal(tt.input.Balance, resp.Balance)
+
+			wantProduct := tt.input.Product
+			if wantProduct == "" {
+				wantProduct = account.DefaultProduct
+			}
+			s.Equal(wantProduct, resp.Product)
 		})
 	}
 }
@@ -87,6 +112,8 @@ func (s *E2ETestSuite) TestGetAccountDetails() {
 				AccountId: "2f6f112a-a8e2-42c3-a6b0-c15e86d01704",
 				Owner:     "David",
 				Balance:   0.0000,
+				Currency:  "USD",
+				Status:    "active",
 			},
 		},
 		{
@@ -113,6 +140,11 @@ func (s *E2ETestSuite) TestGetAccountDetails() {
 			err := json.NewDecoder(w.Body).Decode(&resp)
 			s.NoError(err)
 
+			s.False(resp.CreatedAt.IsZero())
+			s.False(resp.UpdatedAt.IsZero())
+
+			// timestamps are generated by the database
+			resp.CreatedAt, resp.UpdatedAt = time.Time{}, time.Time{}
 			s.Equal(tt.wantResp, resp)
 		})
 	}
@@ -164,3 +196,62 @@ func (s *E2ETestSuite) TestListAccounts() {
 		})
 	}
 }
+
+func (s *E2ETestSuite) TestSearchAccounts() {
+	tests := []struct {
+		name      string
+		query     string
+		wantCode  int
+		wantFirst string
+	}{
+		{
+			name:      "search by owner",
+			query:     "?owner=dav",
+			wantCode:  http.StatusOK,
+			wantFirst: "David",
+		},
+		{
+			name:      "sort by balance descending",
+			query:     "?sort_by=balance&order=desc&limit=1",
+			wantCode:  http.StatusOK,
+			wantFirst: "Alice",
+		},
+		{
+			name:     "invalid sort field",
+			query:    "?sort_by=id",
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "invalid balance range",
+			query:    "?min_balance=100&max_balance=10",
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "malformed balance",
+			query:    "?min_balance=abc",
+			wantCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/accounts"+tt.query, nil)
+			w := httptest.NewRecorder()
+
+			s.router.ServeHTTP(w, req)
+
+			s.Equal(tt.wantCode, w.Code)
+
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+
+			var page internal.Page[account.Details]
+			err := json.NewDecoder(w.Body).Decode(&page)
+			s.NoError(err)
+
+			s.NotEmpty(page.Items)
+			s.Equal(tt.wantFirst, page.Items[0].Owner)
+		})
+	}
+}
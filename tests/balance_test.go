@@ -0,0 +1,67 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/fmiskovic/cash-me-if-you-can/internal/balance"
+)
+
+func (s *E2ETestSuite) TestAccountBalance() {
+	tests := []struct {
+		name        string
+		path        string
+		wantCode    int
+		wantBalance float64
+	}{
+		{
+			name:        "current balance",
+			path:        "/accounts/b1c2d3e4-2222-3333-4444-555566667777/balance",
+			wantCode:    http.StatusOK,
+			wantBalance: 100,
+		},
+		{
+			name:     "balance before the account existed",
+			path:     "/accounts/b1c2d3e4-2222-3333-4444-555566667777/balance?as_of=2000-01-01",
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "future balance",
+			path:     "/accounts/b1c2d3e4-2222-3333-4444-555566667777/balance?as_of=2999-01-01T00:00:00Z",
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "malformed as_of",
+			path:     "/accounts/b1c2d3e4-2222-3333-4444-555566667777/balance?as_of=yesterday",
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "non-existing account",
+			path:     "/accounts/a1b2c3d4-1111-2222-3333-000000000000/balance",
+			wantCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			w := httptest.NewRecorder()
+
+			s.router.ServeHTTP(w, req)
+
+			s.Equal(tt.wantCode, w.Code)
+
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+
+			var res balance.Details
+			err := json.NewDecoder(w.Body).Decode(&res)
+			s.NoError(err)
+			s.Equal("b1c2d3e4-2222-3333-4444-555566667777", res.AccountId)
+			s.InDelta(tt.wantBalance, res.Balance, 1e-9)
+		})
+	}
+}
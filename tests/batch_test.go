@@ -0,0 +1,110 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/fmiskovic/cash-me-if-you-can/internal/batch"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/transaction"
+)
+
+func (s *E2ETestSuite) TestBatchTransfer() {
+	tests := []struct {
+		name       string
+		input      batch.TransferRequest
+		wantCode   int
+		wantStatus batch.Status
+	}{
+		{
+			name: "atomic batch",
+			input: batch.TransferRequest{
+				Transfers: []transaction.TransferRequest{
+					{FromAccountID: "a1b2c3d4-1111-2222-3333-444455556666", ToAccountID: "c1d2e3f4-3333-4444-5555-666677778888", Amount: 1},
+					{FromAccountID: "a1b2c3d4-1111-2222-3333-444455556666", ToAccountID: "2f6f112a-a8e2-42c3-a6b0-c15e86d01704", Amount: 2},
+				},
+			},
+			wantCode:   http.StatusCreated,
+			wantStatus: batch.Completed,
+		},
+		{
+			name: "atomic batch with insufficient funds",
+			input: batch.TransferRequest{
+				Mode: batch.Atomic,
+				Transfers: []transaction.TransferRequest{
+					{FromAccountID: "a1b2c3d4-1111-2222-3333-444455556666", ToAccountID: "c1d2e3f4-3333-4444-5555-666677778888", Amount: 1},
+					{FromAccountID: "a1b2c3d4-1111-2222-3333-444455556666", ToAccountID: "2f6f112a-a8e2-42c3-a6b0-c15e86d01704", Amount: 1000000},
+				},
+			},
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name: "best effort batch with insufficient funds",
+			input: batch.TransferRequest{
+				Mode: batch.BestEffort,
+				Transfers: []transaction.TransferRequest{
+					{FromAccountID: "a1b2c3d4-1111-2222-3333-444455556666", ToAccountID: "c1d2e3f4-3333-4444-5555-666677778888", Amount: 1},
+					{FromAccountID: "a1b2c3d4-1111-2222-3333-444455556666", ToAccountID: "2f6f112a-a8e2-42c3-a6b0-c15e86d01704", Amount: 1000000},
+				},
+			},
+			wantCode:   http.StatusCreated,
+			wantStatus: batch.PartiallyCompleted,
+		},
+		{
+			name: "invalid leg",
+			input: batch.TransferRequest{
+				Transfers: []transaction.TransferRequest{
+					{FromAccountID: "a1b2c3d4-1111-2222-3333-444455556666", ToAccountID: "not-a-uuid", Amount: 1},
+				},
+			},
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "empty batch",
+			input:    batch.TransferRequest{},
+			wantCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			reqBody, err := json.Marshal(tt.input)
+			s.NoError(err)
+
+			req := httptest.NewRequest(http.MethodPost, "/transfers/batch", bytes.NewReader(reqBody))
+			w := httptest.NewRecorder()
+
+			s.router.ServeHTTP(w, req)
+
+			s.Equal(tt.wantCode, w.Code)
+
+			if tt.wantCode != http.StatusCreated {
+				return
+			}
+
+			var res batch.Details
+			err = json.NewDecoder(w.Body).Decode(&res)
+			s.NoError(err)
+			s.NotEmpty(res.BatchId)
+			s.Equal(string(tt.wantStatus), res.Status)
+			s.Len(res.Items, len(tt.input.Transfers))
+
+			// assert the batch can be queried later
+			req = httptest.NewRequest(http.MethodGet, "/transfers/batch/"+res.BatchId, nil)
+			w = httptest.NewRecorder()
+
+			s.router.ServeHTTP(w, req)
+
+			s.Equal(http.StatusOK, w.Code)
+
+			var got batch.Details
+			err = json.NewDecoder(w.Body).Decode(&got)
+			s.NoError(err)
+			s.Equal(res.BatchId, got.BatchId)
+			s.Equal(res.Status, got.Status)
+			s.Len(got.Items, len(res.Items))
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package tests
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/fmiskovic/cash-me-if-you-can/internal/transaction"
+)
+
+type sseEvent struct {
+	id    string
+	event string
+	data  string
+}
+
+func (s *E2ETestSuite) TestAccountEvents() {
+	const charlie = "c1d2e3f4-3333-4444-5555-666677778888"
+
+	server := httptest.NewServer(s.router)
+	defer server.Close()
+
+	// streams end with the test, before the server is closed
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// subscribe opens the stream and returns its events
+	subscribe := func(path, lastEventID string) <-chan sseEvent {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+path, nil)
+		s.Require().NoError(err)
+		if lastEventID != "" {
+			req.Header.Set("Last-Event-ID", lastEventID)
+		}
+
+		res, err := http.DefaultClient.Do(req)
+		s.Require().NoError(err)
+		s.Require().Equal(http.StatusOK, res.StatusCode)
+		s.Require().Equal("text/event-stream", res.Header.Get("Content-Type"))
+
+		events := make(chan sseEvent)
+		go func() {
+			defer close(events)
+			defer res.Body.Close()
+
+			var e sseEvent
+			scanner := bufio.NewScanner(res.Body)
+			for scanner.Scan() {
+				field, value, _ := strings.Cut(scanner.Text(), ": ")
+				switch field {
+				case "id":
+					e.id = value
+				case "event":
+					e.event = value
+				case "data":
+					e.data = value
+				case "":
+					if e.id != "" {
+						select {
+						case events <- e:
+						case <-ctx.Done():
+							return
+						}
+					}
+					e = sseEvent{}
+				}
+			}
+		}()
+		return events
+	}
+	next := func(events <-chan sseEvent) sseEvent {
+		select {
+		case e, ok := <-events:
+			s.Require().True(ok, "stream ended")
+			return e
+		case <-time.After(5 * time.Second):
+			s.FailNow("no event received")
+			return sseEvent{}
+		}
+	}
+	move := func(tp transaction.Type) {
+		body, _ := json.Marshal(transaction.CreateRequest{Type: tp, Amount: 7})
+		req := httptest.NewRequest(http.MethodPost, "/accounts/"+charlie+"/transactions", bytes.NewReader(body))
+		w := httptest.NewRecorder()
+		s.router.ServeHTTP(w, req)
+		s.Require().Equal(http.StatusCreated, w.Code)
+	}
+
+	account := subscribe("/accounts/"+charlie+"/events", "")
+	all := subscribe("/admin/events", "")
+
+	move(transaction.Deposit)
+	defer move(transaction.Withdrawal)
+
+	var received []sseEvent
+	for len(received) < 2 {
+		received = append(received, next(account))
+	}
+	types := map[string]string{}
+	for _, e := range received {
+		types[e.event] = e.data
+	}
+	s.Contains(types["transaction"], `"account_id": "`+charlie+`"`)
+	s.Contains(types["transaction"], `"type": "deposit"`)
+	s.Contains(types["balance"], `"previous_balance"`)
+
+	// the global stream sees the same events
+	s.Equal(received[0], next(all))
+
+	// a reconnecting client resumes after the last event it has seen
+	resumed := subscribe("/accounts/"+charlie+"/events", received[0].id)
+	s.Equal(received[1], next(resumed))
+}
+
+func (s *E2ETestSuite) TestAccountEventsErrors() {
+	tests := []struct {
+		name     string
+		path     string
+		header   string
+		wantCode int
+	}{
+		{
+			name:     "account not found",
+			path:     "/accounts/c1d2e3f4-3333-4444-5555-000000000000/events",
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name:     "invalid account id",
+			path:     "/accounts/charlie/events",
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "invalid last event id",
+			path:     "/admin/events",
+			header:   "last",
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "negative last event id",
+			path:     "/admin/events?last_event_id=-1",
+			wantCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.header != "" {
+				req.Header.Set("Last-Event-ID", tt.header)
+			}
+			w := httptest.NewRecorder()
+			s.router.ServeHTTP(w, req)
+			s.Equal(tt.wantCode, w.Code)
+		})
+	}
+}
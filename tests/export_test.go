@@ -0,0 +1,82 @@
+package tests
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+)
+
+func (s *E2ETestSuite) TestExportTransactions() {
+	const alice = "a1b2c3d4-1111-2222-3333-444455556666"
+
+	s.Run("ndjson", func() {
+		req := httptest.NewRequest(http.MethodGet, "/exports/transactions?account_id="+alice+"&from=2000-01-01", nil)
+		w := httptest.NewRecorder()
+		s.router.ServeHTTP(w, req)
+
+		s.Require().Equal(http.StatusOK, w.Code)
+		s.Equal("application/x-ndjson", w.Header().Get("Content-Type"))
+
+		lines := 0
+		scanner := bufio.NewScanner(w.Body)
+		for scanner.Scan() {
+			var t struct {
+				AccountId string `json:"account_id"`
+			}
+			s.Require().NoError(json.Unmarshal(scanner.Bytes(), &t))
+			s.Equal(alice, t.AccountId)
+			lines++
+		}
+		s.Positive(lines)
+	})
+
+	s.Run("gzipped csv", func() {
+		req := httptest.NewRequest(http.MethodGet, "/exports/transactions?format=csv&type=deposit", nil)
+		req.Header.Set("Accept-Encoding", "gzip")
+		w := httptest.NewRecorder()
+		s.router.ServeHTTP(w, req)
+
+		s.Require().Equal(http.StatusOK, w.Code)
+		s.Equal("text/csv", w.Header().Get("Content-Type"))
+		s.Equal("gzip", w.Header().Get("Content-Encoding"))
+
+		r, err := gzip.NewReader(w.Body)
+		s.Require().NoError(err)
+		records, err := csv.NewReader(r).ReadAll()
+		s.Require().NoError(err)
+		s.Require().Greater(len(records), 1)
+		for _, record := range records[1:] {
+			s.Equal("deposit", record[2])
+		}
+	})
+
+	s.Run("gzip refused by the client", func() {
+		for _, encoding := range []string{"gzip;q=0", "br, gzip; q=0.0", "*;q=0", "identity"} {
+			req := httptest.NewRequest(http.MethodGet, "/exports/transactions?format=csv&type=deposit", nil)
+			req.Header.Set("Accept-Encoding", encoding)
+			w := httptest.NewRecorder()
+			s.router.ServeHTTP(w, req)
+
+			s.Require().Equal(http.StatusOK, w.Code, encoding)
+			s.Empty(w.Header().Get("Content-Encoding"), encoding)
+		}
+
+		req := httptest.NewRequest(http.MethodGet, "/exports/transactions?format=csv&type=deposit", nil)
+		req.Header.Set("Accept-Encoding", "br;q=1, *;q=0.5")
+		w := httptest.NewRecorder()
+		s.router.ServeHTTP(w, req)
+		s.Equal("gzip", w.Header().Get("Content-Encoding"))
+	})
+
+	s.Run("invalid filters", func() {
+		for _, query := range []string{"?type=refund", "?format=xml", "?account_id=alice", "?from=yesterday"} {
+			req := httptest.NewRequest(http.MethodGet, "/exports/transactions"+query, nil)
+			w := httptest.NewRecorder()
+			s.router.ServeHTTP(w, req)
+			s.Equal(http.StatusBadRequest, w.Code, query)
+		}
+	})
+}
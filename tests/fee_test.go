@@ -0,0 +1,66 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/fmiskovic/cash-me-if-you-can/internal/fee"
+)
+
+func (s *E2ETestSuite) TestFeeQuote() {
+	tests := []struct {
+		name     string
+		query    string
+		wantCode int
+	}{
+		{
+			name:     "free transfer",
+			query:    "?account_id=a1b2c3d4-1111-2222-3333-444455556666&operation=transfer&amount=100",
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "non-existing account",
+			query:    "?account_id=a1b2c3d4-1111-2222-3333-000000000000&operation=transfer&amount=100",
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name:     "invalid operation",
+			query:    "?account_id=a1b2c3d4-1111-2222-3333-444455556666&operation=exchange&amount=100",
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "malformed amount",
+			query:    "?account_id=a1b2c3d4-1111-2222-3333-444455556666&operation=transfer&amount=abc",
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "missing amount",
+			query:    "?account_id=a1b2c3d4-1111-2222-3333-444455556666&operation=transfer",
+			wantCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/fees/quote"+tt.query, nil)
+			w := httptest.NewRecorder()
+
+			s.router.ServeHTTP(w, req)
+
+			s.Equal(tt.wantCode, w.Code)
+
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+
+			var res fee.Quote
+			err := json.NewDecoder(w.Body).Decode(&res)
+			s.NoError(err)
+			s.Equal(100.0, res.Amount)
+			s.Zero(res.Fee)
+			s.Equal(100.0, res.Total)
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/fmiskovic/cash-me-if-you-can/internal/account"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/imports"
+)
+
+func (s *E2ETestSuite) TestImport() {
+	const file = "owner,initial_balance,currency\n" +
+		"Imported Customer,12.5,EUR\n" +
+		"Alice,5,EUR\n" +
+		"X,5,EUR\n"
+
+	post := func(path, contentType, body string) (*httptest.ResponseRecorder, imports.ReportDetails) {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", contentType)
+		w := httptest.NewRecorder()
+		s.router.ServeHTTP(w, req)
+
+		var res imports.ReportDetails
+		if w.Code == http.StatusOK {
+			s.Require().NoError(json.NewDecoder(w.Body).Decode(&res))
+		}
+		return w, res
+	}
+
+	// the format is taken from the content type
+	w, res := post("/imports?kind=accounts&dry_run=true", "text/csv", file)
+	s.Require().Equal(http.StatusOK, w.Code)
+	s.True(res.DryRun)
+	s.Equal(3, res.Rows)
+	s.Equal(1, res.Valid)
+	s.Equal(2, res.Rejected)
+
+	w, res = post("/imports?kind=accounts&format=csv", "application/octet-stream", file)
+	s.Require().Equal(http.StatusOK, w.Code)
+	s.Equal(1, res.Imported)
+	s.Require().Len(res.Results, 3)
+	s.Equal("imported", res.Results[0].Status)
+	s.Equal("account with owner Alice already exists", res.Results[1].Error)
+	s.Contains(res.Results[2].Error, "'min' tag")
+
+	id := res.Results[0].Id
+	s.Require().NotEmpty(id)
+	defer func() {
+		_, err := s.dbService.Pool().Exec(s.dbContainer.Ctx, "DELETE FROM transactions WHERE account_id = $1", id)
+		s.NoError(err)
+		_, err = s.dbService.Pool().Exec(s.dbContainer.Ctx, "DELETE FROM accounts WHERE id = $1", id)
+		s.NoError(err)
+	}()
+
+	// the imported account takes deposits and withdrawals in file order
+	w, res = post("/imports?kind=transactions", "application/x-ndjson",
+		`{"account_id": "`+id+`", "type": "withdrawal", "amount": 2.5}`+"\n"+
+			`{"account_id": "`+id+`", "type": "withdrawal", "amount": 20}`+"\n")
+	s.Require().Equal(http.StatusOK, w.Code)
+	s.Equal(1, res.Imported)
+	s.Equal("withdrawal failed - insufficient funds", res.Results[1].Error)
+
+	req := httptest.NewRequest(http.MethodGet, "/accounts/"+id, nil)
+	rec := httptest.NewRecorder()
+	s.router.ServeHTTP(rec, req)
+	s.Require().Equal(http.StatusOK, rec.Code)
+
+	var acc account.Details
+	s.Require().NoError(json.NewDecoder(rec.Body).Decode(&acc))
+	s.InDelta(10, acc.Balance, 1e-9)
+
+	// files that cannot be read are rejected as a whole
+	w, _ = post("/imports?kind=accounts&format=csv", "text/csv", "owner,iban\n")
+	s.Equal(http.StatusBadRequest, w.Code)
+	w, _ = post("/imports?kind=products", "text/csv", file)
+	s.Equal(http.StatusBadRequest, w.Code)
+	w, _ = post("/imports?kind=accounts", "application/pdf", file)
+	s.Equal(http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,26 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/fmiskovic/cash-me-if-you-can/internal/ledger"
+)
+
+func (s *E2ETestSuite) TestLedgerVerify() {
+	req := httptest.NewRequest(http.MethodGet, "/admin/ledger/verify", nil)
+	w := httptest.NewRecorder()
+
+	s.router.ServeHTTP(w, req)
+
+	s.Equal(http.StatusOK, w.Code)
+
+	var res ledger.ReportDetails
+	s.Require().NoError(json.NewDecoder(w.Body).Decode(&res))
+	// every balance change made through the api is backed by transactions
+	s.True(res.OK)
+	s.Positive(res.Accounts)
+	s.Empty(res.Drifts)
+	s.Empty(res.UnmatchedLegs)
+}
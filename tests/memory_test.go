@@ -0,0 +1,70 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fmiskovic/cash-me-if-you-can/api"
+	"github.com/fmiskovic/cash-me-if-you-can/config"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/account"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/transaction"
+)
+
+// TestMemoryStorage runs without a database, so it is not part of the e2e suite.
+func TestMemoryStorage(t *testing.T) {
+	t.Parallel()
+
+	router, err := api.NewRouter(context.Background(), &config.Config{
+		App:     config.AppConfig{Environment: "test"},
+		Storage: config.StorageConfig{Driver: config.MemoryDriver},
+	})
+	require.NoError(t, err)
+
+	do := func(method, path string, body any, out any) int {
+		var buf bytes.Buffer
+		if body != nil {
+			require.NoError(t, json.NewEncoder(&buf).Encode(body))
+		}
+		req := httptest.NewRequest(method, path, &buf)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if out != nil && w.Code < 300 {
+			require.NoError(t, json.NewDecoder(w.Body).Decode(out))
+		}
+		return w.Code
+	}
+
+	var alice, bob account.Details
+	require.Equal(t, http.StatusCreated, do(http.MethodPost, "/accounts", account.CreateRequest{Owner: "Alice", Balance: 100}, &alice))
+	require.Equal(t, http.StatusCreated, do(http.MethodPost, "/accounts", account.CreateRequest{Owner: "Bob", Balance: 10}, &bob))
+
+	var deposit transaction.Details
+	assert.Equal(t, http.StatusCreated, do(http.MethodPost, "/accounts/"+alice.AccountId+"/transactions",
+		transaction.CreateRequest{Type: transaction.Deposit, Amount: 5}, &deposit))
+	assert.Equal(t, http.StatusCreated, do(http.MethodPost, "/transfer",
+		transaction.TransferRequest{FromAccountID: alice.AccountId, ToAccountID: bob.AccountId, Amount: 55}, nil))
+	assert.Equal(t, http.StatusBadRequest, do(http.MethodPost, "/transfer",
+		transaction.TransferRequest{FromAccountID: bob.AccountId, ToAccountID: alice.AccountId, Amount: 1000}, nil))
+
+	var details account.Details
+	require.Equal(t, http.StatusOK, do(http.MethodGet, "/accounts/"+alice.AccountId, nil, &details))
+	assert.InDelta(t, 50, details.Balance, 1e-9)
+	require.Equal(t, http.StatusOK, do(http.MethodGet, "/accounts/"+bob.AccountId, nil, &details))
+	assert.InDelta(t, 65, details.Balance, 1e-9)
+
+	var trs []transaction.Details
+	require.Equal(t, http.StatusOK, do(http.MethodGet, "/accounts/"+alice.AccountId+"/transactions", nil, &trs))
+	assert.Len(t, trs, 3)
+	assert.Equal(t, http.StatusOK, do(http.MethodGet, "/transactions/"+deposit.TransactionId, nil, nil))
+
+	// features depending on the database are not served
+	assert.Equal(t, http.StatusNotFound, do(http.MethodGet, "/accounts/"+alice.AccountId+"/balance", nil, nil))
+}
@@ -0,0 +1,142 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/fmiskovic/cash-me-if-you-can/internal/reconciliation"
+)
+
+func (s *E2ETestSuite) TestReconciliation() {
+	const alice = "a1b2c3d4-1111-2222-3333-444455556666"
+
+	// Alice's own camt.053 statement reconciles completely
+	req := httptest.NewRequest(http.MethodGet, "/accounts/"+alice+"/statements?from=2000-01-01&format=camt.053", nil)
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+	s.Require().Equal(http.StatusOK, w.Code)
+
+	req = httptest.NewRequest(http.MethodPost, "/accounts/"+alice+"/reconciliations?format=camt.053", bytes.NewReader(w.Body.Bytes()))
+	w = httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+	s.Require().Equal(http.StatusCreated, w.Code, w.Body.String())
+
+	var report reconciliation.ReportDetails
+	s.Require().NoError(json.NewDecoder(w.Body).Decode(&report))
+	defer func() {
+		_, err := s.dbService.Pool().Exec(s.dbContainer.Ctx, "DELETE FROM reconciliation_runs WHERE id = $1", report.RunId)
+		s.NoError(err)
+	}()
+
+	s.Equal(alice, report.AccountId)
+	s.Equal("camt.053", report.Format)
+	s.Positive(report.Lines)
+	s.Equal(report.Lines, report.Matched)
+	s.Empty(report.UnmatchedLines)
+	s.Empty(report.UnmatchedTransactions)
+
+	line := report.MatchedLines[0]
+	linePath := fmt.Sprintf("/reconciliations/%s/lines/%s/match", report.RunId, line.LineId)
+
+	// unmatch and match the first line manually
+	req = httptest.NewRequest(http.MethodDelete, linePath, nil)
+	w = httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+	s.Require().Equal(http.StatusOK, w.Code, w.Body.String())
+
+	req = httptest.NewRequest(http.MethodGet, "/reconciliations/"+report.RunId, nil)
+	w = httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+	s.Require().Equal(http.StatusOK, w.Code)
+
+	var got reconciliation.ReportDetails
+	s.Require().NoError(json.NewDecoder(w.Body).Decode(&got))
+	s.Equal(report.Lines-1, got.Matched)
+	s.Require().Len(got.UnmatchedLines, 1)
+	s.Require().Len(got.UnmatchedTransactions, 1)
+	s.Equal(line.TransactionId, got.UnmatchedTransactions[0].TransactionId)
+
+	body := fmt.Sprintf(`{"transaction_id": %q}`, line.TransactionId)
+	req = httptest.NewRequest(http.MethodPost, linePath, strings.NewReader(body))
+	w = httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+	s.Require().Equal(http.StatusOK, w.Code, w.Body.String())
+
+	var matched reconciliation.LineDetails
+	s.Require().NoError(json.NewDecoder(w.Body).Decode(&matched))
+	s.Equal(line.TransactionId, matched.TransactionId)
+	s.Equal("manual", matched.Match)
+
+	// matching it again conflicts
+	req = httptest.NewRequest(http.MethodPost, linePath, strings.NewReader(body))
+	w = httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+	s.Equal(http.StatusConflict, w.Code)
+}
+
+func (s *E2ETestSuite) TestReconciliationErrors() {
+	const alice = "a1b2c3d4-1111-2222-3333-444455556666"
+	const statement = "date,amount\n2024-08-01,10\n"
+
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		body     string
+		wantCode int
+	}{
+		{
+			name:     "unsupported format",
+			method:   http.MethodPost,
+			path:     "/accounts/" + alice + "/reconciliations?format=mt940",
+			body:     statement,
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "invalid statement",
+			method:   http.MethodPost,
+			path:     "/accounts/" + alice + "/reconciliations?format=csv",
+			body:     "date,amount\n2024-08-01,ten\n",
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "negative tolerance",
+			method:   http.MethodPost,
+			path:     "/accounts/" + alice + "/reconciliations?format=csv&amount_tolerance=-1",
+			body:     statement,
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "missing account",
+			method:   http.MethodPost,
+			path:     "/accounts/00000000-0000-0000-0000-000000000000/reconciliations?format=csv",
+			body:     statement,
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name:     "missing run",
+			method:   http.MethodGet,
+			path:     "/reconciliations/00000000-0000-0000-0000-000000000000",
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name:     "invalid line id",
+			method:   http.MethodDelete,
+			path:     "/reconciliations/00000000-0000-0000-0000-000000000000/lines/1/match",
+			wantCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			s.router.ServeHTTP(w, req)
+			s.Equal(tt.wantCode, w.Code, w.Body.String())
+		})
+	}
+}
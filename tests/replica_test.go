@@ -0,0 +1,51 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/fmiskovic/cash-me-if-you-can/api"
+	"github.com/fmiskovic/cash-me-if-you-can/config"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/account"
+	"github.com/fmiskovic/cash-me-if-you-can/internal/transaction"
+)
+
+func (s *E2ETestSuite) TestReplicaConsistencyToken() {
+	// the primary serves as its own replica
+	cfg := &config.Config{
+		App:      config.AppConfig{Environment: "test"},
+		Database: s.dbContainer.Config,
+	}
+	cfg.Database.ReplicaHost = cfg.Database.Host
+
+	router, err := api.NewRouter(s.dbContainer.Ctx, cfg)
+	s.Require().NoError(err)
+	defer router.Close()
+
+	body, _ := json.Marshal(account.CreateRequest{Owner: "Rita Replica", Balance: 10})
+	req := httptest.NewRequest(http.MethodPost, "/accounts", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	s.Require().Equal(http.StatusCreated, w.Code)
+
+	// writes return the token of their log position
+	token := w.Header().Get(api.ConsistencyTokenHeader)
+	s.Require().NotEmpty(token)
+
+	var acc account.Details
+	s.Require().NoError(json.NewDecoder(w.Body).Decode(&acc))
+
+	// reads sending the token back see the write
+	req = httptest.NewRequest(http.MethodGet, "/accounts/"+acc.AccountId+"/transactions", nil)
+	req.Header.Set(api.ConsistencyTokenHeader, token)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	s.Require().Equal(http.StatusOK, w.Code)
+	s.Assert().Empty(w.Header().Get(api.ConsistencyTokenHeader), "reads write nothing")
+
+	var trs []transaction.Details
+	s.Require().NoError(json.NewDecoder(w.Body).Decode(&trs))
+	s.Assert().Len(trs, 1)
+}
@@ -0,0 +1,155 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/fmiskovic/cash-me-if-you-can/internal/schedule"
+)
+
+func (s *E2ETestSuite) TestSchedule() {
+	startAt := time.Now().UTC().Add(24 * time.Hour).Truncate(time.Second)
+	before := startAt.Add(-time.Hour)
+
+	tests := []struct {
+		name     string
+		input    schedule.CreateRequest
+		wantCode int
+	}{
+		{
+			name: "monthly schedule",
+			input: schedule.CreateRequest{
+				FromAccountID: "a1b2c3d4-1111-2222-3333-444455556666",
+				ToAccountID:   "c1d2e3f4-3333-4444-5555-666677778888",
+				Amount:        10,
+				StartAt:       startAt,
+				Frequency:     schedule.Monthly,
+				Count:         12,
+			},
+			wantCode: http.StatusCreated,
+		},
+		{
+			name: "one-off transfer",
+			input: schedule.CreateRequest{
+				FromAccountID: "a1b2c3d4-1111-2222-3333-444455556666",
+				ToAccountID:   "2f6f112a-a8e2-42c3-a6b0-c15e86d01704",
+				Amount:        1,
+				StartAt:       startAt,
+			},
+			wantCode: http.StatusCreated,
+		},
+		{
+			name: "same accounts",
+			input: schedule.CreateRequest{
+				FromAccountID: "a1b2c3d4-1111-2222-3333-444455556666",
+				ToAccountID:   "a1b2c3d4-1111-2222-3333-444455556666",
+				Amount:        1,
+				StartAt:       startAt,
+			},
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name: "invalid frequency",
+			input: schedule.CreateRequest{
+				FromAccountID: "a1b2c3d4-1111-2222-3333-444455556666",
+				ToAccountID:   "c1d2e3f4-3333-4444-5555-666677778888",
+				Amount:        1,
+				StartAt:       startAt,
+				Frequency:     "yearly",
+			},
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name: "until before start",
+			input: schedule.CreateRequest{
+				FromAccountID: "a1b2c3d4-1111-2222-3333-444455556666",
+				ToAccountID:   "c1d2e3f4-3333-4444-5555-666677778888",
+				Amount:        1,
+				StartAt:       startAt,
+				Frequency:     schedule.Daily,
+				Until:         &before,
+			},
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name: "non-existing account",
+			input: schedule.CreateRequest{
+				FromAccountID: "a1b2c3d4-1111-2222-3333-444455556666",
+				ToAccountID:   "a1b2c3d4-1111-2222-3333-000000000000",
+				Amount:        1,
+				StartAt:       startAt,
+			},
+			wantCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			reqBody, err := json.Marshal(tt.input)
+			s.NoError(err)
+
+			req := httptest.NewRequest(http.MethodPost, "/schedules", bytes.NewReader(reqBody))
+			w := httptest.NewRecorder()
+
+			s.router.ServeHTTP(w, req)
+
+			s.Equal(tt.wantCode, w.Code)
+
+			if tt.wantCode != http.StatusCreated {
+				return
+			}
+
+			var res schedule.Details
+			err = json.NewDecoder(w.Body).Decode(&res)
+			s.NoError(err)
+			s.NotEmpty(res.ScheduleId)
+			s.Equal(string(schedule.Active), res.Status)
+			s.Require().NotNil(res.NextRunAt)
+			s.True(startAt.Equal(*res.NextRunAt))
+
+			// assert the schedule is listed for the account
+			req = httptest.NewRequest(http.MethodGet, "/accounts/"+tt.input.ToAccountID+"/schedules", nil)
+			w = httptest.NewRecorder()
+
+			s.router.ServeHTTP(w, req)
+
+			s.Equal(http.StatusOK, w.Code)
+
+			var list []schedule.Details
+			err = json.NewDecoder(w.Body).Decode(&list)
+			s.NoError(err)
+			s.NotEmpty(list)
+
+			// assert the schedule can be cancelled once
+			req = httptest.NewRequest(http.MethodDelete, "/schedules/"+res.ScheduleId, nil)
+			w = httptest.NewRecorder()
+
+			s.router.ServeHTTP(w, req)
+
+			s.Equal(http.StatusOK, w.Code)
+
+			req = httptest.NewRequest(http.MethodDelete, "/schedules/"+res.ScheduleId, nil)
+			w = httptest.NewRecorder()
+
+			s.router.ServeHTTP(w, req)
+
+			s.Equal(http.StatusConflict, w.Code)
+
+			req = httptest.NewRequest(http.MethodGet, "/schedules/"+res.ScheduleId, nil)
+			w = httptest.NewRecorder()
+
+			s.router.ServeHTTP(w, req)
+
+			s.Equal(http.StatusOK, w.Code)
+
+			var got schedule.Details
+			err = json.NewDecoder(w.Body).Decode(&got)
+			s.NoError(err)
+			s.Equal(string(schedule.Cancelled), got.Status)
+		})
+	}
+}
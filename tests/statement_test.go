@@ -0,0 +1,137 @@
+package tests
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/fmiskovic/cash-me-if-you-can/internal/account"
+)
+
+func (s *E2ETestSuite) TestAccountStatement() {
+	const alice = "a1b2c3d4-1111-2222-3333-444455556666"
+
+	balance := func() float64 {
+		req := httptest.NewRequest(http.MethodGet, "/accounts/"+alice, nil)
+		w := httptest.NewRecorder()
+		s.router.ServeHTTP(w, req)
+		s.Require().Equal(http.StatusOK, w.Code)
+
+		var res account.Details
+		s.Require().NoError(json.NewDecoder(w.Body).Decode(&res))
+		return res.Balance
+	}()
+
+	tests := []struct {
+		name            string
+		path            string
+		wantCode        int
+		wantContentType string
+		check           func(t *testing.T, w *httptest.ResponseRecorder)
+	}{
+		{
+			name:            "json",
+			path:            "/accounts/" + alice + "/statements?from=2000-01-01",
+			wantCode:        http.StatusOK,
+			wantContentType: "application/json",
+			check: func(t *testing.T, w *httptest.ResponseRecorder) {
+				var res struct {
+					AccountId      string  `json:"account_id"`
+					OpeningBalance float64 `json:"opening_balance"`
+					ClosingBalance float64 `json:"closing_balance"`
+					Entries        []struct {
+						Balance float64 `json:"balance"`
+					} `json:"entries"`
+				}
+				s.Require().NoError(json.NewDecoder(w.Body).Decode(&res))
+				s.Equal(alice, res.AccountId)
+				// all of Alice's money has been deposited
+				s.InDelta(0, res.OpeningBalance, 1e-9)
+				s.InDelta(balance, res.ClosingBalance, 1e-9)
+				s.Require().NotEmpty(res.Entries)
+				s.InDelta(res.ClosingBalance, res.Entries[len(res.Entries)-1].Balance, 1e-9)
+			},
+		},
+		{
+			name:            "csv",
+			path:            "/accounts/" + alice + "/statements?from=2000-01-01T00:00:00Z&format=csv",
+			wantCode:        http.StatusOK,
+			wantContentType: "text/csv",
+			check: func(t *testing.T, w *httptest.ResponseRecorder) {
+				records, err := csv.NewReader(w.Body).ReadAll()
+				s.Require().NoError(err)
+				s.Require().GreaterOrEqual(len(records), 4)
+				s.Equal("opening", records[1][0])
+
+				closing := records[len(records)-1]
+				s.Equal("closing", closing[0])
+				amount, err := strconv.ParseFloat(closing[len(closing)-1], 64)
+				s.Require().NoError(err)
+				s.InDelta(balance, amount, 1e-9)
+			},
+		},
+		{
+			name:            "camt.053",
+			path:            "/accounts/" + alice + "/statements?from=2000-01-01&format=camt.053",
+			wantCode:        http.StatusOK,
+			wantContentType: "application/xml",
+			check: func(t *testing.T, w *httptest.ResponseRecorder) {
+				var res struct {
+					Account string   `xml:"BkToCstmrStmt>Stmt>Acct>Id>Othr>Id"`
+					Entries []string `xml:"BkToCstmrStmt>Stmt>Ntry>AcctSvcrRef"`
+				}
+				s.Require().NoError(xml.NewDecoder(w.Body).Decode(&res))
+				s.Equal(alice, res.Account)
+				s.NotEmpty(res.Entries)
+			},
+		},
+		{
+			name:     "missing from",
+			path:     "/accounts/" + alice + "/statements",
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "malformed to",
+			path:     "/accounts/" + alice + "/statements?from=2000-01-01&to=yesterday",
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "unsupported format",
+			path:     "/accounts/" + alice + "/statements?from=2000-01-01&format=pdf",
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "period ends before it starts",
+			path:     "/accounts/" + alice + "/statements?from=2024-09-01&to=2024-08-01",
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "non-existing account",
+			path:     "/accounts/a1b2c3d4-1111-2222-3333-000000000000/statements?from=2000-01-01",
+			wantCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			w := httptest.NewRecorder()
+
+			s.router.ServeHTTP(w, req)
+
+			s.Equal(tt.wantCode, w.Code)
+
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+
+			s.Equal(tt.wantContentType, w.Header().Get("Content-Type"))
+			s.Contains(w.Header().Get("Content-Disposition"), "attachment")
+			tt.check(t, w)
+		})
+	}
+}
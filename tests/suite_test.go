@@ -30,7 +30,10 @@ func (s *E2ETestSuite) SetupSuite() {
 		s.T().Fatal("failed to start postgres container", err)
 	}
 
-	s.dbService = database.New(s.dbContainer.Config)
+	s.dbService, err = database.New(s.dbContainer.Ctx, s.dbContainer.Config)
+	if err != nil {
+		s.T().Fatal("failed to connect to postgres", err)
+	}
 
 	s.prepareDb()
 
@@ -38,7 +41,10 @@ func (s *E2ETestSuite) SetupSuite() {
 		App:      config.AppConfig{Environment: "test"},
 		Database: s.dbContainer.Config,
 	}
-	s.router = api.NewRouter(cfg)
+	s.router, err = api.NewRouter(s.dbContainer.Ctx, cfg)
+	if err != nil {
+		s.T().Fatal("failed to create router", err)
+	}
 }
 
 func (s *E2ETestSuite) prepareDb() {
@@ -46,12 +52,16 @@ func (s *E2ETestSuite) prepareDb() {
 		s.T().Fatal("failed to run migrations", err)
 	}
 
-	if err := goose.Up(s.dbService.DB(), "testdata"); err != nil {
+	if err := goose.Up(s.dbService.DB(), "testdata", goose.WithAllowMissing()); err != nil {
 		s.T().Fatal("failed to seed test data", err)
 	}
 }
 
 func (s *E2ETestSuite) TearDownSuite() {
+	if err := s.router.Close(); err != nil {
+		slogging.Slogger().Warn("failed to close router storage", "error", err)
+	}
+
 	if err := s.dbService.Close(); err != nil {
 		slogging.Slogger().Warn("failed to close db connection", "error", err)
 	}
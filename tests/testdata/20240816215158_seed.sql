@@ -11,8 +11,7 @@ INSERT INTO transactions (id, account_id, amount, type, timestamp)
 VALUES
     ('a1b2c3d4-1111-2222-3333-444455556666', 'a1b2c3d4-1111-2222-3333-444455556666', 7467.8976, 'deposit', '2024-08-16 21:51:58'),
     ('b1c2d3e4-2222-3333-4444-555566667777', 'b1c2d3e4-2222-3333-4444-555566667777', 50.0000, 'deposit', '2024-08-16 21:51:58'),
-    ('b1c2d3e4-2222-3333-4444-555566669999', 'b1c2d3e4-2222-3333-4444-555566667777', 50.0000, 'deposit', '2024-08-16 21:51:58'),
-    ('c1d2e3f4-3333-4444-5555-666677778888', 'c1d2e3f4-3333-4444-5555-666677778888', 0.0000, 'deposit', '2024-08-16 21:51:58');
+    ('b1c2d3e4-2222-3333-4444-555566669999', 'b1c2d3e4-2222-3333-4444-555566667777', 50.0000, 'deposit', '2024-08-16 21:51:58');
 -- +goose StatementEnd
 
 -- +goose Down
@@ -238,3 +238,85 @@ func (s *E2ETestSuite) TestTransfer() {
 		})
 	}
 }
+
+func (s *E2ETestSuite) TestGetTransactionDetails() {
+	tests := []struct {
+		name          string
+		transactionId string
+		wantCode      int
+	}{
+		{
+			name:          "valid request",
+			transactionId: "b1c2d3e4-2222-3333-4444-555566669999",
+			wantCode:      http.StatusOK,
+		},
+		{
+			name:          "non-existing transaction",
+			transactionId: "b1c2d3e4-2222-3333-4444-000000000000",
+			wantCode:      http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		s.T().Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/transactions/"+tt.transactionId, nil)
+			w := httptest.NewRecorder()
+
+			s.router.ServeHTTP(w, req)
+
+			s.Equal(tt.wantCode, w.Code)
+
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+
+			var res transaction.Details
+			err := json.NewDecoder(w.Body).Decode(&res)
+			s.NoError(err)
+			s.Equal(tt.transactionId, res.TransactionId)
+			s.Equal("b1c2d3e4-2222-3333-4444-555566667777", res.AccountId)
+			s.Equal(string(transaction.Deposit), res.Type)
+		})
+	}
+}
+
+func (s *E2ETestSuite) TestCreateTransactionWithReference() {
+	accountId := "b1c2d3e4-2222-3333-4444-555566667777"
+	path := "/accounts/" + accountId + "/transactions"
+
+	input := transaction.CreateRequest{
+		Type:              transaction.Deposit,
+		Amount:            10,
+		Description:       "refund",
+		ExternalReference: "refund-e2e-1",
+		Metadata:          map[string]any{"order": "A-1"},
+	}
+	reqBody, err := json.Marshal(input)
+	s.NoError(err)
+
+	// first request creates the transaction
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, path, bytes.NewReader(reqBody)))
+	s.Equal(http.StatusCreated, w.Code)
+
+	var created transaction.Details
+	s.NoError(json.NewDecoder(w.Body).Decode(&created))
+	s.Equal(input.Description, created.Description)
+	s.Equal(input.ExternalReference, created.ExternalReference)
+	s.Equal(input.Metadata, created.Metadata)
+
+	// replaying the same reference is a conflict
+	w = httptest.NewRecorder()
+	s.router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, path, bytes.NewReader(reqBody)))
+	s.Equal(http.StatusConflict, w.Code)
+
+	// the transaction can be found by its reference
+	w = httptest.NewRecorder()
+	s.router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path+"?reference="+input.ExternalReference, nil))
+	s.Equal(http.StatusOK, w.Code)
+
+	var found []transaction.Details
+	s.NoError(json.NewDecoder(w.Body).Decode(&found))
+	s.Len(found, 1)
+	s.Equal(created.TransactionId, found[0].TransactionId)
+}